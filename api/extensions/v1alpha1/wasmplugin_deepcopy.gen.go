@@ -47,6 +47,48 @@ func (in *MatchRule) DeepCopyInterface() interface{} {
 	return in.DeepCopy()
 }
 
+// DeepCopyInto supports using StringMatch within kubernetes types, where deepcopy-gen is used.
+func (in *StringMatch) DeepCopyInto(out *StringMatch) {
+	p := proto.Clone(in).(*StringMatch)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringMatch. Required by controller-gen.
+func (in *StringMatch) DeepCopy() *StringMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(StringMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new StringMatch. Required by controller-gen.
+func (in *StringMatch) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using HeaderMatch within kubernetes types, where deepcopy-gen is used.
+func (in *HeaderMatch) DeepCopyInto(out *HeaderMatch) {
+	p := proto.Clone(in).(*HeaderMatch)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderMatch. Required by controller-gen.
+func (in *HeaderMatch) DeepCopy() *HeaderMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new HeaderMatch. Required by controller-gen.
+func (in *HeaderMatch) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
 // DeepCopyInto supports using VmConfig within kubernetes types, where deepcopy-gen is used.
 func (in *VmConfig) DeepCopyInto(out *VmConfig) {
 	p := proto.Clone(in).(*VmConfig)
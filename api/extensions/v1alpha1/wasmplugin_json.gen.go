@@ -28,6 +28,28 @@ func (this *MatchRule) UnmarshalJSON(b []byte) error {
 	return WasmpluginUnmarshaler.Unmarshal(bytes.NewReader(b), this)
 }
 
+// MarshalJSON is a custom marshaler for StringMatch
+func (this *StringMatch) MarshalJSON() ([]byte, error) {
+	str, err := WasmpluginMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for StringMatch
+func (this *StringMatch) UnmarshalJSON(b []byte) error {
+	return WasmpluginUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for HeaderMatch
+func (this *HeaderMatch) MarshalJSON() ([]byte, error) {
+	str, err := WasmpluginMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for HeaderMatch
+func (this *HeaderMatch) UnmarshalJSON(b []byte) error {
+	return WasmpluginUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
 // MarshalJSON is a custom marshaler for VmConfig
 func (this *VmConfig) MarshalJSON() ([]byte, error) {
 	str, err := WasmpluginMarshaler.MarshalToString(this)
@@ -410,6 +410,18 @@ type GrpcService struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// Fully qualified name of the gRPC service, such as helloworld.Greeter.
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	// The serialized google.protobuf.FileDescriptorSet describing the service,
+	// encoded in base64. Either this or proto_descriptor_config_map is required.
+	ProtoDescriptorBin string `protobuf:"bytes,2,opt,name=proto_descriptor_bin,json=protoDescriptorBin,proto3" json:"proto_descriptor_bin,omitempty"`
+	// Reference to a ConfigMap in the same namespace whose key holds the
+	// FileDescriptorSet, stored as binaryData or as base64 encoded data.
+	ProtoDescriptorConfigMap *ProtoDescriptorConfigMap `protobuf:"bytes,3,opt,name=proto_descriptor_config_map,json=protoDescriptorConfigMap,proto3" json:"proto_descriptor_config_map,omitempty"`
+	// Mappings from HTTP requests to gRPC methods. When empty, the
+	// google.api.http annotations of the descriptor are used as is.
+	Methods []*GrpcMethod `protobuf:"bytes,4,rep,name=methods,proto3" json:"methods,omitempty"`
 }
 
 func (x *GrpcService) Reset() {
@@ -444,6 +456,173 @@ func (*GrpcService) Descriptor() ([]byte, []int) {
 	return file_networking_v1_http_2_rpc_proto_rawDescGZIP(), []int{5}
 }
 
+func (x *GrpcService) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *GrpcService) GetProtoDescriptorBin() string {
+	if x != nil {
+		return x.ProtoDescriptorBin
+	}
+	return ""
+}
+
+func (x *GrpcService) GetProtoDescriptorConfigMap() *ProtoDescriptorConfigMap {
+	if x != nil {
+		return x.ProtoDescriptorConfigMap
+	}
+	return nil
+}
+
+func (x *GrpcService) GetMethods() []*GrpcMethod {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+type ProtoDescriptorConfigMap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Key  string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *ProtoDescriptorConfigMap) Reset() {
+	*x = ProtoDescriptorConfigMap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_networking_v1_http_2_rpc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProtoDescriptorConfigMap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoDescriptorConfigMap) ProtoMessage() {}
+
+func (x *ProtoDescriptorConfigMap) ProtoReflect() protoreflect.Message {
+	mi := &file_networking_v1_http_2_rpc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoDescriptorConfigMap.ProtoReflect.Descriptor instead.
+func (*ProtoDescriptorConfigMap) Descriptor() ([]byte, []int) {
+	return file_networking_v1_http_2_rpc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProtoDescriptorConfigMap) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProtoDescriptorConfigMap) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type GrpcMethod struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the gRPC method within the service, such as SayHello.
+	ServiceMethod string `protobuf:"bytes,1,opt,name=service_method,json=serviceMethod,proto3" json:"service_method,omitempty"`
+	// HTTP path template, variables like {name} are bound to request fields.
+	HttpPath    string   `protobuf:"bytes,2,opt,name=http_path,json=httpPath,proto3" json:"http_path,omitempty"`
+	HttpMethods []string `protobuf:"bytes,3,rep,name=http_methods,json=httpMethods,proto3" json:"http_methods,omitempty"`
+	// Supported param sources are QUERY, PATH and BODY, param_key is the
+	// field path of the request message and param_type is ignored.
+	Params []*Param `protobuf:"bytes,4,rep,name=params,proto3" json:"params,omitempty"`
+	// Map the whole request body to the request message.
+	ParamFromEntireBody *ParamFromEntireBody `protobuf:"bytes,5,opt,name=paramFromEntireBody,proto3" json:"paramFromEntireBody,omitempty"`
+}
+
+func (x *GrpcMethod) Reset() {
+	*x = GrpcMethod{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_networking_v1_http_2_rpc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrpcMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrpcMethod) ProtoMessage() {}
+
+func (x *GrpcMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_networking_v1_http_2_rpc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrpcMethod.ProtoReflect.Descriptor instead.
+func (*GrpcMethod) Descriptor() ([]byte, []int) {
+	return file_networking_v1_http_2_rpc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GrpcMethod) GetServiceMethod() string {
+	if x != nil {
+		return x.ServiceMethod
+	}
+	return ""
+}
+
+func (x *GrpcMethod) GetHttpPath() string {
+	if x != nil {
+		return x.HttpPath
+	}
+	return ""
+}
+
+func (x *GrpcMethod) GetHttpMethods() []string {
+	if x != nil {
+		return x.HttpMethods
+	}
+	return nil
+}
+
+func (x *GrpcMethod) GetParams() []*Param {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *GrpcMethod) GetParamFromEntireBody() *ParamFromEntireBody {
+	if x != nil {
+		return x.ParamFromEntireBody
+	}
+	return nil
+}
+
 var File_networking_v1_http_2_rpc_proto protoreflect.FileDescriptor
 
 var file_networking_v1_http_2_rpc_proto_rawDesc = []byte{
@@ -503,12 +682,52 @@ var file_networking_v1_http_2_rpc_proto_rawDesc = []byte{
 	0x22, 0x39, 0x0a, 0x13, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x46, 0x72, 0x6f, 0x6d, 0x45, 0x6e, 0x74,
 	0x69, 0x72, 0x65, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x22, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d,
 	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x02,
-	0x52, 0x09, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x22, 0x0d, 0x0a, 0x0b, 0x47,
-	0x72, 0x70, 0x63, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6c, 0x69, 0x62, 0x61, 0x62, 0x61,
-	0x2f, 0x68, 0x69, 0x67, 0x72, 0x65, 0x73, 0x73, 0x2f, 0x76, 0x32, 0x2f, 0x61, 0x70, 0x69, 0x2f,
-	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x09, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x22, 0x9a, 0x02, 0x0a, 0x0b,
+	0x47, 0x72, 0x70, 0x63, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x07, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41,
+	0x02, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x35, 0x0a, 0x14, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x5f, 0x62,
+	0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x01, 0x52, 0x12, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x42, 0x69,
+	0x6e, 0x12, 0x73, 0x0a, 0x1b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6d, 0x61, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x68, 0x69, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x4d, 0x61, 0x70, 0x42, 0x03, 0xe0, 0x41, 0x01, 0x52, 0x18, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x4d, 0x61, 0x70, 0x12, 0x40, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x68, 0x69, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x72, 0x70, 0x63, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x42, 0x03, 0xe0, 0x41, 0x01, 0x52,
+	0x07, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x73, 0x22, 0x4a, 0x0a, 0x18, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x4d, 0x61, 0x70, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x02, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x15, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x02, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x22, 0xa0, 0x02, 0x0a, 0x0a, 0x47, 0x72, 0x70, 0x63, 0x4d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x12, 0x2a, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6d,
+	0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x02,
+	0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12,
+	0x20, 0x0a, 0x09, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x02, 0x52, 0x08, 0x68, 0x74, 0x74, 0x70, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x26, 0x0a, 0x0c, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x42, 0x03, 0xe0, 0x41, 0x02, 0x52, 0x0b, 0x68, 0x74,
+	0x74, 0x70, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x73, 0x12, 0x39, 0x0a, 0x06, 0x70, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x68, 0x69, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x42, 0x03, 0xe0, 0x41, 0x01, 0x52, 0x06, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x12, 0x61, 0x0a, 0x13, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x46, 0x72, 0x6f,
+	0x6d, 0x45, 0x6e, 0x74, 0x69, 0x72, 0x65, 0x42, 0x6f, 0x64, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2a, 0x2e, 0x68, 0x69, 0x67, 0x72, 0x65, 0x73, 0x73, 0x2e, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x46,
+	0x72, 0x6f, 0x6d, 0x45, 0x6e, 0x74, 0x69, 0x72, 0x65, 0x42, 0x6f, 0x64, 0x79, 0x42, 0x03, 0xe0,
+	0x41, 0x01, 0x52, 0x13, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x46, 0x72, 0x6f, 0x6d, 0x45, 0x6e, 0x74,
+	0x69, 0x72, 0x65, 0x42, 0x6f, 0x64, 0x79, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6c, 0x69, 0x62, 0x61, 0x62, 0x61, 0x2f, 0x68, 0x69,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x2f, 0x76, 0x32, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -523,14 +742,16 @@ func file_networking_v1_http_2_rpc_proto_rawDescGZIP() []byte {
 	return file_networking_v1_http_2_rpc_proto_rawDescData
 }
 
-var file_networking_v1_http_2_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_networking_v1_http_2_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_networking_v1_http_2_rpc_proto_goTypes = []interface{}{
-	(*Http2Rpc)(nil),            // 0: higress.networking.v1.Http2Rpc
-	(*DubboService)(nil),        // 1: higress.networking.v1.DubboService
-	(*Method)(nil),              // 2: higress.networking.v1.Method
-	(*Param)(nil),               // 3: higress.networking.v1.Param
-	(*ParamFromEntireBody)(nil), // 4: higress.networking.v1.ParamFromEntireBody
-	(*GrpcService)(nil),         // 5: higress.networking.v1.GrpcService
+	(*Http2Rpc)(nil),                 // 0: higress.networking.v1.Http2Rpc
+	(*DubboService)(nil),             // 1: higress.networking.v1.DubboService
+	(*Method)(nil),                   // 2: higress.networking.v1.Method
+	(*Param)(nil),                    // 3: higress.networking.v1.Param
+	(*ParamFromEntireBody)(nil),      // 4: higress.networking.v1.ParamFromEntireBody
+	(*GrpcService)(nil),              // 5: higress.networking.v1.GrpcService
+	(*ProtoDescriptorConfigMap)(nil), // 6: higress.networking.v1.ProtoDescriptorConfigMap
+	(*GrpcMethod)(nil),               // 7: higress.networking.v1.GrpcMethod
 }
 var file_networking_v1_http_2_rpc_proto_depIdxs = []int32{
 	1, // 0: higress.networking.v1.Http2Rpc.dubbo:type_name -> higress.networking.v1.DubboService
@@ -538,11 +759,15 @@ var file_networking_v1_http_2_rpc_proto_depIdxs = []int32{
 	2, // 2: higress.networking.v1.DubboService.methods:type_name -> higress.networking.v1.Method
 	3, // 3: higress.networking.v1.Method.params:type_name -> higress.networking.v1.Param
 	4, // 4: higress.networking.v1.Method.paramFromEntireBody:type_name -> higress.networking.v1.ParamFromEntireBody
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	6, // 5: higress.networking.v1.GrpcService.proto_descriptor_config_map:type_name -> higress.networking.v1.ProtoDescriptorConfigMap
+	7, // 6: higress.networking.v1.GrpcService.methods:type_name -> higress.networking.v1.GrpcMethod
+	3, // 7: higress.networking.v1.GrpcMethod.params:type_name -> higress.networking.v1.Param
+	4, // 8: higress.networking.v1.GrpcMethod.paramFromEntireBody:type_name -> higress.networking.v1.ParamFromEntireBody
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_networking_v1_http_2_rpc_proto_init() }
@@ -623,6 +848,30 @@ func file_networking_v1_http_2_rpc_proto_init() {
 				return nil
 			}
 		}
+		file_networking_v1_http_2_rpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProtoDescriptorConfigMap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_networking_v1_http_2_rpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrpcMethod); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_networking_v1_http_2_rpc_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*Http2Rpc_Dubbo)(nil),
@@ -634,7 +883,7 @@ func file_networking_v1_http_2_rpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_networking_v1_http_2_rpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -130,3 +130,45 @@ func (in *GrpcService) DeepCopy() *GrpcService {
 func (in *GrpcService) DeepCopyInterface() interface{} {
 	return in.DeepCopy()
 }
+
+// DeepCopyInto supports using ProtoDescriptorConfigMap within kubernetes types, where deepcopy-gen is used.
+func (in *ProtoDescriptorConfigMap) DeepCopyInto(out *ProtoDescriptorConfigMap) {
+	p := proto.Clone(in).(*ProtoDescriptorConfigMap)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtoDescriptorConfigMap. Required by controller-gen.
+func (in *ProtoDescriptorConfigMap) DeepCopy() *ProtoDescriptorConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtoDescriptorConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new ProtoDescriptorConfigMap. Required by controller-gen.
+func (in *ProtoDescriptorConfigMap) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using GrpcMethod within kubernetes types, where deepcopy-gen is used.
+func (in *GrpcMethod) DeepCopyInto(out *GrpcMethod) {
+	p := proto.Clone(in).(*GrpcMethod)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcMethod. Required by controller-gen.
+func (in *GrpcMethod) DeepCopy() *GrpcMethod {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcMethod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new GrpcMethod. Required by controller-gen.
+func (in *GrpcMethod) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
@@ -72,6 +72,28 @@ func (this *GrpcService) UnmarshalJSON(b []byte) error {
 	return Http_2RpcUnmarshaler.Unmarshal(bytes.NewReader(b), this)
 }
 
+// MarshalJSON is a custom marshaler for ProtoDescriptorConfigMap
+func (this *ProtoDescriptorConfigMap) MarshalJSON() ([]byte, error) {
+	str, err := Http_2RpcMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for ProtoDescriptorConfigMap
+func (this *ProtoDescriptorConfigMap) UnmarshalJSON(b []byte) error {
+	return Http_2RpcUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for GrpcMethod
+func (this *GrpcMethod) MarshalJSON() ([]byte, error) {
+	str, err := Http_2RpcMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for GrpcMethod
+func (this *GrpcMethod) UnmarshalJSON(b []byte) error {
+	return Http_2RpcUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
 var (
 	Http_2RpcMarshaler   = &jsonpb.Marshaler{}
 	Http_2RpcUnmarshaler = &jsonpb.Unmarshaler{AllowUnknownFields: true}
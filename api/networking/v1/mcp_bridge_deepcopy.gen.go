@@ -89,6 +89,27 @@ func (in *RegistryConfig_VPort_Services) DeepCopyInterface() interface{} {
 	return in.DeepCopy()
 }
 
+// DeepCopyInto supports using OpenApiSource within kubernetes types, where deepcopy-gen is used.
+func (in *OpenApiSource) DeepCopyInto(out *OpenApiSource) {
+	p := proto.Clone(in).(*OpenApiSource)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenApiSource. Required by controller-gen.
+func (in *OpenApiSource) DeepCopy() *OpenApiSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenApiSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new OpenApiSource. Required by controller-gen.
+func (in *OpenApiSource) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
 // DeepCopyInto supports using ProxyConfig within kubernetes types, where deepcopy-gen is used.
 func (in *ProxyConfig) DeepCopyInto(out *ProxyConfig) {
 	p := proto.Clone(in).(*ProxyConfig)
@@ -45,6 +45,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &metav1.TypeMetaApplyConfiguration{}
 
 		// Group=networking.higress.io, Version=v1
+	case networkingv1.SchemeGroupVersion.WithKind("Consumer"):
+		return &applyconfigurationnetworkingv1.ConsumerApplyConfiguration{}
 	case networkingv1.SchemeGroupVersion.WithKind("Http2Rpc"):
 		return &applyconfigurationnetworkingv1.Http2RpcApplyConfiguration{}
 	case networkingv1.SchemeGroupVersion.WithKind("McpBridge"):
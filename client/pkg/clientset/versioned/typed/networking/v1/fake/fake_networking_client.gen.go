@@ -26,6 +26,10 @@ type FakeNetworkingV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeNetworkingV1) Consumers(namespace string) v1.ConsumerInterface {
+	return &FakeConsumers{c, namespace}
+}
+
 func (c *FakeNetworkingV1) Http2Rpcs(namespace string) v1.Http2RpcInterface {
 	return &FakeHttp2Rpcs{c, namespace}
 }
@@ -16,6 +16,8 @@
 
 package v1
 
+type ConsumerExpansion interface{}
+
 type Http2RpcExpansion interface{}
 
 type McpBridgeExpansion interface{}
@@ -26,6 +26,7 @@ import (
 
 type NetworkingV1Interface interface {
 	RESTClient() rest.Interface
+	ConsumersGetter
 	Http2RpcsGetter
 	McpBridgesGetter
 }
@@ -35,6 +36,10 @@ type NetworkingV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *NetworkingV1Client) Consumers(namespace string) ConsumerInterface {
+	return newConsumers(c, namespace)
+}
+
 func (c *NetworkingV1Client) Http2Rpcs(namespace string) Http2RpcInterface {
 	return newHttp2Rpcs(c, namespace)
 }
@@ -22,6 +22,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Consumers returns a ConsumerInformer.
+	Consumers() ConsumerInformer
 	// Http2Rpcs returns a Http2RpcInformer.
 	Http2Rpcs() Http2RpcInformer
 	// McpBridges returns a McpBridgeInformer.
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Consumers returns a ConsumerInformer.
+func (v *version) Consumers() ConsumerInformer {
+	return &consumerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Http2Rpcs returns a Http2RpcInformer.
 func (v *version) Http2Rpcs() Http2RpcInformer {
 	return &http2RpcInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -16,6 +16,14 @@
 
 package v1
 
+// ConsumerListerExpansion allows custom methods to be added to
+// ConsumerLister.
+type ConsumerListerExpansion interface{}
+
+// ConsumerNamespaceListerExpansion allows custom methods to be added to
+// ConsumerNamespaceLister.
+type ConsumerNamespaceListerExpansion interface{}
+
 // Http2RpcListerExpansion allows custom methods to be added to
 // Http2RpcLister.
 type Http2RpcListerExpansion interface{}
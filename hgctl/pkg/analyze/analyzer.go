@@ -0,0 +1,333 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyze
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	networkingv1 "k8s.io/api/networking/v1"
+
+	extensionsv1alpha1 "github.com/alibaba/higress/v2/api/extensions/v1alpha1"
+	extv1alpha1 "github.com/alibaba/higress/v2/client/pkg/apis/extensions/v1alpha1"
+	higressv1 "github.com/alibaba/higress/v2/client/pkg/apis/networking/v1"
+	"github.com/alibaba/higress/v2/pkg/config/constants"
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/annotations"
+	"github.com/alibaba/higress/v2/registry"
+)
+
+// Codes of the issues found across resources, the codes of the annotation issues are defined in the annotations package
+const (
+	CodeMissingReference = "HIG0004"
+	CodeDuplicateRoute   = "HIG0005"
+	CodeInvalidResource  = "HIG0006"
+	CodeUnsupportedMatch = "HIG0007"
+)
+
+const (
+	KindIngress    = "Ingress"
+	KindMcpBridge  = "McpBridge"
+	KindWasmPlugin = "WasmPlugin"
+	KindHttp2Rpc   = "Http2Rpc"
+
+	ingressClassAnnotation   = "kubernetes.io/ingress.class"
+	canaryAnnotation         = "canary"
+	rpcDestinationAnnotation = "rpc-destination-name"
+)
+
+var supportedRegistryTypes = map[string]bool{
+	string(registry.Nacos):     true,
+	string(registry.Nacos2):    true,
+	string(registry.Nacos3):    true,
+	string(registry.Zookeeper): true,
+	string(registry.Consul):    true,
+	string(registry.Eureka):    true,
+	string(registry.Static):    true,
+	string(registry.DNS):       true,
+	string(registry.OpenAPI):   true,
+}
+
+// Resource is a manifest to analyze, Object is one of the typed resources of the supported kinds
+type Resource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Location is the source of the resource, e.g. file.yaml:12, empty for the resources read from the cluster
+	Location string
+	Object   interface{}
+}
+
+func (r *Resource) String() string {
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// Message is an issue reported by the analyzer
+type Message struct {
+	Code     string               `json:"code"`
+	Level    annotations.Severity `json:"level"`
+	Resource string               `json:"resource"`
+	Location string               `json:"location,omitempty"`
+	Field    string               `json:"field,omitempty"`
+	Message  string               `json:"message"`
+}
+
+func (m Message) String() string {
+	origin := m.Resource
+	if m.Location != "" {
+		origin += " " + m.Location
+	}
+	if m.Field != "" {
+		return fmt.Sprintf("%s [%s] (%s) %s: %s", m.Level, m.Code, origin, m.Field, m.Message)
+	}
+	return fmt.Sprintf("%s [%s] (%s) %s", m.Level, m.Code, origin, m.Message)
+}
+
+// Analyze validates the resources one by one with the parsers of the controller, then checks the references
+// and conflicts between them
+func Analyze(resources []*Resource) []Message {
+	a := &analyzer{
+		http2rpcs: map[string]bool{},
+		ingresses: map[string]bool{},
+		routes:    map[string]*Resource{},
+	}
+	for _, r := range resources {
+		switch obj := r.Object.(type) {
+		case *higressv1.Http2Rpc:
+			a.http2rpcs[obj.Name] = true
+			a.hasHttp2Rpc = true
+		case *networkingv1.Ingress:
+			a.ingresses[obj.Namespace+"/"+obj.Name] = true
+		}
+	}
+	for _, r := range resources {
+		switch obj := r.Object.(type) {
+		case *networkingv1.Ingress:
+			a.analyzeIngress(r, obj)
+		case *higressv1.McpBridge:
+			a.analyzeMcpBridge(r, obj)
+		case *higressv1.Http2Rpc:
+			a.analyzeHttp2Rpc(r, obj)
+		case *extv1alpha1.WasmPlugin:
+			a.analyzeWasmPlugin(r, obj)
+		}
+	}
+	return a.messages
+}
+
+// HasErrors checks whether any of the messages is an error
+func HasErrors(messages []Message) bool {
+	for _, m := range messages {
+		if m.Level == annotations.SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type analyzer struct {
+	messages    []Message
+	http2rpcs   map[string]bool
+	hasHttp2Rpc bool
+	ingresses   map[string]bool
+	// routes maps ingress class, host and path to the first ingress defining it
+	routes map[string]*Resource
+}
+
+func (a *analyzer) report(r *Resource, code string, level annotations.Severity, field, format string, args ...interface{}) {
+	a.messages = append(a.messages, Message{
+		Code:     code,
+		Level:    level,
+		Resource: r.String(),
+		Location: r.Location,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (a *analyzer) analyzeIngress(r *Resource, ingress *networkingv1.Ingress) {
+	for _, issue := range annotations.Validate(ingress.Annotations) {
+		a.report(r, issue.Code, issue.Severity, issue.Annotation, "%s", issue.Message)
+	}
+
+	// the http2rpc resources are looked up by name in the namespace of higress
+	ingressAnnotations := annotations.Annotations(ingress.Annotations)
+	if name, err := ingressAnnotations.ParseStringForHigress(rpcDestinationAnnotation); err == nil && !a.http2rpcs[name] {
+		level := annotations.SeverityError
+		if !a.hasHttp2Rpc {
+			// the Http2Rpc resources may just not be part of the analyzed files
+			level = annotations.SeverityWarning
+		}
+		a.report(r, CodeMissingReference, level, annotations.HigressAnnotationsPrefix+"/"+rpcDestinationAnnotation,
+			"Http2Rpc %s is not found", name)
+	}
+
+	// canary ingresses share the routes of the ingresses they belong to
+	if canary, _ := ingressAnnotations.ParseBoolASAP(canaryAnnotation); canary {
+		return
+	}
+	class := ingress.Annotations[ingressClassAnnotation]
+	if ingress.Spec.IngressClassName != nil {
+		class = *ingress.Spec.IngressClassName
+	}
+	for _, rule := range ingress.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			pathType := string(networkingv1.PathTypeImplementationSpecific)
+			if p.PathType != nil {
+				pathType = string(*p.PathType)
+			}
+			key := fmt.Sprintf("%s|%s|%s|%s", class, host, pathType, p.Path)
+			first, exist := a.routes[key]
+			if !exist {
+				a.routes[key] = r
+				continue
+			}
+			if first == r {
+				a.report(r, CodeDuplicateRoute, annotations.SeverityWarning, "spec.rules",
+					"host %s path %s (%s) is defined more than once", host, p.Path, pathType)
+				continue
+			}
+			a.report(r, CodeDuplicateRoute, annotations.SeverityError, "spec.rules",
+				"host %s path %s (%s) is also defined by %s, only one of them takes effect", host, p.Path, pathType, first)
+		}
+	}
+}
+
+func (a *analyzer) analyzeMcpBridge(r *Resource, bridge *higressv1.McpBridge) {
+	names := map[string]bool{}
+	for i, reg := range bridge.Spec.Registries {
+		field := fmt.Sprintf("spec.registries[%d]", i)
+		if reg.Name == "" {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, field+".name", "name is required")
+		} else if names[reg.Name] {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, field+".name", "registry %s is defined more than once", reg.Name)
+		}
+		names[reg.Name] = true
+		if !supportedRegistryTypes[reg.Type] {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, field+".type", "unsupported registry type %q", reg.Type)
+			continue
+		}
+		if reg.Domain == "" && reg.NacosAddressServer == "" {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, field+".domain", "domain is required")
+		}
+		if reg.Port == 0 && reg.Type != string(registry.Static) && reg.NacosAddressServer == "" {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, field+".port", "port is required")
+		}
+		if reg.Type == string(registry.OpenAPI) {
+			source := reg.OpenApiSource
+			if source == nil || (source.SpecUrl == "" && source.SpecConfigMap == "") {
+				a.report(r, CodeInvalidResource, annotations.SeverityError, field+".openApiSource",
+					"specUrl or specConfigMap is required for openapi registry")
+			} else if source.SpecUrl == "" && source.SpecConfigMapKey == "" {
+				a.report(r, CodeInvalidResource, annotations.SeverityError, field+".openApiSource.specConfigMapKey",
+					"specConfigMapKey is required when the specification is read from a configmap")
+			}
+		}
+	}
+}
+
+func (a *analyzer) analyzeHttp2Rpc(r *Resource, http2rpc *higressv1.Http2Rpc) {
+	if http2rpc.Spec.GetDubbo() == nil && http2rpc.Spec.GetGrpc() == nil {
+		a.report(r, CodeInvalidResource, annotations.SeverityError, "spec", "one of dubbo and grpc is required")
+	}
+}
+
+func (a *analyzer) analyzeWasmPlugin(r *Resource, plugin *extv1alpha1.WasmPlugin) {
+	if plugin.Spec.Url == "" {
+		a.report(r, CodeInvalidResource, annotations.SeverityError, "spec.url", "url is required")
+	}
+	for i, rule := range plugin.Spec.MatchRules {
+		field := fmt.Sprintf("spec.matchRules[%d]", i)
+		if len(rule.Ingress) == 0 && len(rule.Domain) == 0 && len(rule.Service) == 0 {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, field,
+				"rule without ingress, domain or service is rejected, path, header and method can only narrow them down")
+		}
+		a.analyzeRequestMatches(r, field, rule, plugin.Annotations[constants.WasmPluginRequestMatchAnnotation] == "true")
+		// only the http routes are named after the ingresses
+		if len(a.ingresses) == 0 || rule.GetRouteType() != extensionsv1alpha1.RouteType_HTTP {
+			continue
+		}
+		for _, ing := range rule.Ingress {
+			if !a.ingresses[ing] {
+				a.report(r, CodeMissingReference, annotations.SeverityWarning, field+".ingress",
+					"ingress %s is not found", ing)
+			}
+		}
+	}
+}
+
+// analyzeRequestMatches checks the path, header and method conditions of the rule the same way as the controller
+func (a *analyzer) analyzeRequestMatches(r *Resource, field string, rule *extensionsv1alpha1.MatchRule, requestMatchSupported bool) {
+	if len(rule.Path) == 0 && len(rule.Header) == 0 && len(rule.Method) == 0 {
+		return
+	}
+	for i, path := range rule.Path {
+		if err := validateStringMatch(path); err != nil {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, fmt.Sprintf("%s.path[%d]", field, i), "%v", err)
+		}
+	}
+	for i, header := range rule.Header {
+		headerField := fmt.Sprintf("%s.header[%d]", field, i)
+		if header.Name == "" {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, headerField+".name", "name is required")
+		}
+		if header.Value != nil {
+			if err := validateStringMatch(header.Value); err != nil {
+				a.report(r, CodeInvalidResource, annotations.SeverityError, headerField+".value", "%v", err)
+			}
+		}
+	}
+	for i, method := range rule.Method {
+		if method == "" {
+			a.report(r, CodeInvalidResource, annotations.SeverityError, fmt.Sprintf("%s.method[%d]", field, i), "method can not be empty")
+		}
+	}
+	if !requestMatchSupported {
+		a.report(r, CodeUnsupportedMatch, annotations.SeverityError, field,
+			"path, header and method are only supported by C++ and Rust plugins, set the annotation %s: \"true\" if the plugin supports them",
+			constants.WasmPluginRequestMatchAnnotation)
+	}
+}
+
+func validateStringMatch(match *extensionsv1alpha1.StringMatch) error {
+	switch m := match.GetMatchType().(type) {
+	case *extensionsv1alpha1.StringMatch_Exact, *extensionsv1alpha1.StringMatch_Prefix:
+		return nil
+	case *extensionsv1alpha1.StringMatch_Regex:
+		if _, err := regexp.Compile(m.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q: %v", m.Regex, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("one of exact, prefix and regex is required")
+	}
+}
+
+// SortMessages orders the messages by resource and location, keeping the order of the messages of a resource
+func SortMessages(messages []Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].Resource != messages[j].Resource {
+			return messages[i].Resource < messages[j].Resource
+		}
+		return messages[i].Location < messages[j].Location
+	})
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyze
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/annotations"
+)
+
+const manifests = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    higress.io/canary-weight: "abc"
+    higress.io/rpc-destination-name: missing
+spec:
+  ingressClassName: higress
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /api
+        pathType: Prefix
+        backend:
+          service:
+            name: foo
+            port:
+              number: 80
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: bar
+  namespace: default
+spec:
+  ingressClassName: higress
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /api
+        pathType: Prefix
+        backend:
+          service:
+            name: bar
+            port:
+              number: 80
+---
+apiVersion: networking.higress.io/v1
+kind: Http2Rpc
+metadata:
+  name: echo
+  namespace: higress-system
+spec:
+  dubbo:
+    service: com.example.EchoService
+    version: 1.0.0
+    group: dev
+    methods:
+    - serviceMethod: echo
+      httpMethods:
+      - GET
+      httpPath: /echo
+---
+apiVersion: networking.higress.io/v1
+kind: McpBridge
+metadata:
+  name: default
+  namespace: higress-system
+spec:
+  registries:
+  - name: my-nacos
+    type: nacos2
+    domain: 127.0.0.1
+    port: 8848
+  - name: my-nacos
+    type: etcd
+---
+apiVersion: extensions.higress.io/v1alpha1
+kind: WasmPlugin
+metadata:
+  name: key-auth
+  namespace: higress-system
+spec:
+  url: oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/key-auth:1.0.0
+  matchRules:
+  - ingress:
+    - default/foo
+    - default/unknown
+    config: {}
+  - domain:
+    - example.com
+    path:
+    - prefix: /api
+    - regex: "/v1/(.*"
+    header:
+    - value:
+        exact: "1"
+    method:
+    - GET
+    config: {}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+func TestParseManifests(t *testing.T) {
+	resources, err := ParseManifests("test.yaml", []byte(manifests))
+	require.NoError(t, err)
+	require.Len(t, resources, 5)
+	assert.Equal(t, "Ingress default/foo", resources[0].String())
+	assert.Equal(t, "test.yaml:1", resources[0].Location)
+	assert.Equal(t, "test.yaml:23", resources[1].Location)
+	assert.Equal(t, KindWasmPlugin, resources[4].Kind)
+
+	_, err = ParseManifests("test.yaml", []byte("kind: Ingress\nspec: [invalid"))
+	assert.Error(t, err)
+}
+
+func TestAnalyze(t *testing.T) {
+	resources, err := ParseManifests("test.yaml", []byte(manifests))
+	require.NoError(t, err)
+
+	messages := Analyze(resources)
+	assert.True(t, HasErrors(messages))
+
+	type issue struct {
+		code     string
+		level    annotations.Severity
+		resource string
+		field    string
+	}
+	var issues []issue
+	for _, m := range messages {
+		issues = append(issues, issue{m.Code, m.Level, m.Resource, m.Field})
+	}
+	assert.ElementsMatch(t, []issue{
+		{annotations.CodeInvalidValue, annotations.SeverityError, "Ingress default/foo", "higress.io/canary-weight"},
+		{CodeMissingReference, annotations.SeverityError, "Ingress default/foo", "higress.io/rpc-destination-name"},
+		{CodeDuplicateRoute, annotations.SeverityError, "Ingress default/bar", "spec.rules"},
+		{CodeInvalidResource, annotations.SeverityError, "McpBridge higress-system/default", "spec.registries[1].name"},
+		{CodeInvalidResource, annotations.SeverityError, "McpBridge higress-system/default", "spec.registries[1].type"},
+		{CodeMissingReference, annotations.SeverityWarning, "WasmPlugin higress-system/key-auth", "spec.matchRules[0].ingress"},
+		{CodeInvalidResource, annotations.SeverityError, "WasmPlugin higress-system/key-auth", "spec.matchRules[1].path[1]"},
+		{CodeInvalidResource, annotations.SeverityError, "WasmPlugin higress-system/key-auth", "spec.matchRules[1].header[0].name"},
+		{CodeUnsupportedMatch, annotations.SeverityError, "WasmPlugin higress-system/key-auth", "spec.matchRules[1]"},
+	}, issues)
+}
+
+func TestAnalyzeRequestMatchAnnotation(t *testing.T) {
+	resources, err := ParseManifests("test.yaml", []byte(`apiVersion: extensions.higress.io/v1alpha1
+kind: WasmPlugin
+metadata:
+  name: request-block
+  namespace: higress-system
+  annotations:
+    higress.io/wasm-plugin-request-match: "true"
+spec:
+  url: oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/request-block:1.0.0
+  matchRules:
+  - domain:
+    - example.com
+    path:
+    - prefix: /api
+    config: {}
+`))
+	require.NoError(t, err)
+	assert.Empty(t, Analyze(resources))
+}
+
+func TestAnalyzeWithoutHttp2Rpc(t *testing.T) {
+	resources, err := ParseManifests("test.yaml", []byte(`apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+  annotations:
+    higress.io/rpc-destination-name: echo
+spec:
+  rules:
+  - host: example.com
+`))
+	require.NoError(t, err)
+	messages := Analyze(resources)
+	require.Len(t, messages, 1)
+	assert.Equal(t, annotations.SeverityWarning, messages[0].Level)
+	assert.False(t, HasErrors(messages))
+}
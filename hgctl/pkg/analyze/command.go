@@ -0,0 +1,141 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/yaml"
+
+	"github.com/alibaba/higress/hgctl/pkg/kubernetes"
+	"github.com/alibaba/higress/v2/pkg/cmd/options"
+)
+
+const (
+	summaryOutput = "short"
+	jsonOutput    = "json"
+	yamlOutput    = "yaml"
+)
+
+type analyzeOptions struct {
+	files            []string
+	namespace        string
+	allNamespaces    bool
+	higressNamespace string
+	output           string
+}
+
+func NewCommand() *cobra.Command {
+	opts := &analyzeOptions{}
+	analyzeCmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Analyze Higress resources and report potential issues",
+		Long: `Analyze Ingress, McpBridge, WasmPlugin and Http2Rpc resources with the parsers used by the Higress controller,
+reporting the annotation values that would be ignored, the references to missing resources and the routes defined by more
+than one ingress. The resources are read from the files if any, otherwise from the cluster.`,
+		Example: `  # Analyze the manifests before applying them
+  hgctl analyze -f ingress.yaml -f plugins/
+
+  # Analyze the resources in the cluster
+  hgctl analyze -A
+
+  # Output the messages as JSON
+  hgctl analyze -f ingress.yaml -o json
+`,
+		Run: func(c *cobra.Command, args []string) {
+			cmdutil.CheckErr(runAnalyze(c, opts))
+		},
+	}
+
+	flags := analyzeCmd.Flags()
+	flags.StringArrayVarP(&opts.files, "file", "f", nil, "Manifest files or directories to analyze, use - to read from stdin")
+	flags.StringVarP(&opts.namespace, "namespace", "n", "default", "Namespace of the ingresses to analyze in the cluster")
+	flags.BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false, "Analyze the ingresses in all the namespaces of the cluster")
+	flags.StringVar(&opts.higressNamespace, "higress-namespace", kubernetes.DefaultHigressNamespace, "Namespace where Higress was installed")
+	flags.StringVarP(&opts.output, "output", "o", summaryOutput, "Output format: one of short|json|yaml")
+	options.AddKubeConfigFlags(flags)
+
+	return analyzeCmd
+}
+
+func runAnalyze(c *cobra.Command, opts *analyzeOptions) error {
+	if opts.output != summaryOutput && opts.output != jsonOutput && opts.output != yamlOutput {
+		return fmt.Errorf("output format %q not supported", opts.output)
+	}
+
+	var resources []*Resource
+	var err error
+	if len(opts.files) > 0 {
+		resources, err = ReadFiles(opts.files, c.InOrStdin())
+	} else {
+		var client kubernetes.CLIClient
+		client, err = kubernetes.NewCLIClient(options.DefaultConfigFlags.ToRawKubeConfigLoader())
+		if err != nil {
+			return fmt.Errorf("failed to build kubernetes client: %v", err)
+		}
+		namespace := opts.namespace
+		if opts.allNamespaces {
+			namespace = ""
+		}
+		resources, err = ReadCluster(client, namespace, opts.higressNamespace)
+	}
+	if err != nil {
+		return err
+	}
+
+	messages := Analyze(resources)
+	SortMessages(messages)
+	if err = printMessages(c.OutOrStdout(), messages, len(resources), opts.output); err != nil {
+		return err
+	}
+	if HasErrors(messages) {
+		return errors.New("analyzers found issues with level Error")
+	}
+	return nil
+}
+
+func printMessages(w io.Writer, messages []Message, analyzed int, output string) error {
+	switch output {
+	case jsonOutput:
+		if messages == nil {
+			messages = []Message{}
+		}
+		data, err := json.MarshalIndent(messages, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	case yamlOutput:
+		data, err := yaml.Marshal(messages)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, string(data))
+	default:
+		if len(messages) == 0 {
+			fmt.Fprintf(w, "No issues found when analyzing %d resources.\n", analyzed)
+			return nil
+		}
+		for _, m := range messages {
+			fmt.Fprintln(w, m.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyze
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/alibaba/higress/hgctl/pkg/kubernetes"
+	extv1alpha1 "github.com/alibaba/higress/v2/client/pkg/apis/extensions/v1alpha1"
+	higressv1 "github.com/alibaba/higress/v2/client/pkg/apis/networking/v1"
+	"github.com/alibaba/higress/v2/client/pkg/clientset/versioned"
+)
+
+// ReadFiles reads the resources from the files, directories are walked for the yaml and json files,
+// "-" reads from the standard input
+func ReadFiles(paths []string, stdin io.Reader) ([]*Resource, error) {
+	var resources []*Resource
+	for _, p := range paths {
+		if p == "-" {
+			data, err := io.ReadAll(stdin)
+			if err != nil {
+				return nil, err
+			}
+			parsed, err := ParseManifests("stdin", data)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, parsed...)
+			continue
+		}
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			if path != p && !isManifestFile(path) {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			parsed, err := ParseManifests(path, data)
+			if err != nil {
+				return err
+			}
+			resources = append(resources, parsed...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+func isManifestFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// ParseManifests parses the multi-document yaml, the documents of unsupported kinds are skipped
+func ParseManifests(file string, data []byte) ([]*Resource, error) {
+	var resources []*Resource
+	for _, doc := range splitDocuments(data) {
+		if len(bytes.TrimSpace(doc.content)) == 0 {
+			continue
+		}
+		location := fmt.Sprintf("%s:%d", file, doc.line)
+		typeMeta := &metav1.TypeMeta{}
+		if err := yaml.Unmarshal(doc.content, typeMeta); err != nil {
+			return nil, fmt.Errorf("%s: %v", location, err)
+		}
+		var obj metav1.Object
+		switch typeMeta.Kind {
+		case KindIngress:
+			obj = &networkingv1.Ingress{}
+		case KindMcpBridge:
+			obj = &higressv1.McpBridge{}
+		case KindHttp2Rpc:
+			obj = &higressv1.Http2Rpc{}
+		case KindWasmPlugin:
+			obj = &extv1alpha1.WasmPlugin{}
+		default:
+			continue
+		}
+		if err := yaml.Unmarshal(doc.content, obj); err != nil {
+			return nil, fmt.Errorf("%s: invalid %s: %v", location, typeMeta.Kind, err)
+		}
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+			if typeMeta.Kind != KindIngress {
+				namespace = kubernetes.DefaultHigressNamespace
+			}
+		}
+		resources = append(resources, &Resource{
+			Kind:      typeMeta.Kind,
+			Namespace: namespace,
+			Name:      obj.GetName(),
+			Location:  location,
+			Object:    obj,
+		})
+	}
+	return resources, nil
+}
+
+type document struct {
+	// line is the first line of the document in the file
+	line    int
+	content []byte
+}
+
+func splitDocuments(data []byte) []document {
+	var docs []document
+	current := document{line: 1}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if strings.HasPrefix(line, "---") && strings.TrimSpace(strings.TrimLeft(line, "-")) == "" {
+			docs = append(docs, current)
+			current = document{line: lineNumber + 1}
+			continue
+		}
+		current.content = append(current.content, line...)
+		current.content = append(current.content, '\n')
+	}
+	return append(docs, current)
+}
+
+// ReadCluster reads the resources from the cluster, ingresses are read from ingressNamespace (all the namespaces
+// if empty) and the higress resources from higressNamespace
+func ReadCluster(client kubernetes.CLIClient, ingressNamespace, higressNamespace string) ([]*Resource, error) {
+	ctx := context.Background()
+	var resources []*Resource
+	ingresses, err := client.KubernetesInterface().NetworkingV1().Ingresses(ingressNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %v", err)
+	}
+	for i := range ingresses.Items {
+		resources = append(resources, clusterResource(KindIngress, &ingresses.Items[i]))
+	}
+
+	higressClient, err := newHigressClient(client.RESTConfig())
+	if err != nil {
+		return nil, err
+	}
+	bridges, err := higressClient.NetworkingV1().McpBridges(higressNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mcpbridges: %v", err)
+	}
+	for i := range bridges.Items {
+		resources = append(resources, clusterResource(KindMcpBridge, &bridges.Items[i]))
+	}
+	http2rpcs, err := higressClient.NetworkingV1().Http2Rpcs(higressNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list http2rpcs: %v", err)
+	}
+	for i := range http2rpcs.Items {
+		resources = append(resources, clusterResource(KindHttp2Rpc, &http2rpcs.Items[i]))
+	}
+	plugins, err := higressClient.ExtensionsV1alpha1().WasmPlugins(higressNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wasmplugins: %v", err)
+	}
+	for i := range plugins.Items {
+		resources = append(resources, clusterResource(KindWasmPlugin, &plugins.Items[i]))
+	}
+	return resources, nil
+}
+
+func newHigressClient(config *rest.Config) (versioned.Interface, error) {
+	if config == nil {
+		return nil, fmt.Errorf("kubernetes config is not available")
+	}
+	return versioned.NewForConfig(config)
+}
+
+func clusterResource(kind string, obj metav1.Object) *Resource {
+	return &Resource{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Object:    obj,
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/alibaba/higress/hgctl/pkg/kubernetes"
+)
+
+const (
+	// ArchiveVersion is the version of the archive layout, restore refuses the archives of the other versions
+	ArchiveVersion = "v1"
+
+	metadataFile = "metadata.yaml"
+	resourcesDir = "resources"
+)
+
+type resourceType struct {
+	kind string
+	gvr  schema.GroupVersionResource
+}
+
+func (t resourceType) gvk() schema.GroupVersionKind {
+	return t.gvr.GroupVersion().WithKind(t.kind)
+}
+
+var (
+	secretType     = resourceType{"Secret", schema.GroupVersionResource{Version: "v1", Resource: "secrets"}}
+	configMapType  = resourceType{"ConfigMap", schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}}
+	mcpBridgeType  = resourceType{"McpBridge", schema.GroupVersionResource{Group: "networking.higress.io", Version: "v1", Resource: "mcpbridges"}}
+	http2RpcType   = resourceType{"Http2Rpc", schema.GroupVersionResource{Group: "networking.higress.io", Version: "v1", Resource: "http2rpcs"}}
+	ingressType    = resourceType{"Ingress", schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}}
+	wasmPluginType = resourceType{kubernetes.WasmPluginKind, kubernetes.WasmPluginGVR}
+
+	// resourceTypes are ordered so that the referenced resources are restored first
+	resourceTypes = []resourceType{secretType, configMapType, mcpBridgeType, http2RpcType, ingressType, wasmPluginType}
+)
+
+func typeOf(kind string) (resourceType, bool) {
+	for _, t := range resourceTypes {
+		if t.kind == kind {
+			return t, true
+		}
+	}
+	return resourceType{}, false
+}
+
+// Metadata describes the content of the archive
+type Metadata struct {
+	Version          string        `json:"version"`
+	CreatedAt        string        `json:"createdAt"`
+	HigressNamespace string        `json:"higressNamespace"`
+	Resources        []ResourceRef `json:"resources"`
+}
+
+// ResourceRef is a resource stored in the archive
+type ResourceRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	File      string `json:"file"`
+}
+
+func (r ResourceRef) String() string {
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// Archive is the snapshot of the configuration of a gateway
+type Archive struct {
+	Metadata Metadata
+	Objects  []*unstructured.Unstructured
+}
+
+// NewArchive sorts the objects in the order of restoring and builds the metadata
+func NewArchive(higressNamespace string, objects []*unstructured.Unstructured) *Archive {
+	a := &Archive{
+		Metadata: Metadata{
+			Version:          ArchiveVersion,
+			CreatedAt:        time.Now().UTC().Format(time.RFC3339),
+			HigressNamespace: higressNamespace,
+		},
+		Objects: objects,
+	}
+	a.sort()
+	return a
+}
+
+func (a *Archive) sort() {
+	order := map[string]int{}
+	for i, t := range resourceTypes {
+		order[t.kind] = i
+	}
+	sort.SliceStable(a.Objects, func(i, j int) bool {
+		oi, oj := a.Objects[i], a.Objects[j]
+		if oi.GetKind() != oj.GetKind() {
+			return order[oi.GetKind()] < order[oj.GetKind()]
+		}
+		if oi.GetNamespace() != oj.GetNamespace() {
+			return oi.GetNamespace() < oj.GetNamespace()
+		}
+		return oi.GetName() < oj.GetName()
+	})
+	a.Metadata.Resources = nil
+	for _, obj := range a.Objects {
+		a.Metadata.Resources = append(a.Metadata.Resources, refOf(obj))
+	}
+}
+
+func refOf(obj *unstructured.Unstructured) ResourceRef {
+	return ResourceRef{
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		File:      path.Join(resourcesDir, strings.ToLower(obj.GetKind()), obj.GetNamespace(), obj.GetName()+".yaml"),
+	}
+}
+
+// Write writes the archive as a gzipped tarball holding the metadata and a yaml file for each resource
+func (a *Archive) Write(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	metadata, err := yaml.Marshal(a.Metadata)
+	if err != nil {
+		return err
+	}
+	if err = writeFile(tw, metadataFile, metadata); err != nil {
+		return err
+	}
+	for i, obj := range a.Objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if err = writeFile(tw, a.Metadata.Resources[i].File, data); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ReadArchive reads the archive written by Write
+func ReadArchive(r io.Reader) (*Archive, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %v", err)
+	}
+	defer gr.Close()
+	files := map[string][]byte{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = data
+	}
+
+	data, ok := files[metadataFile]
+	if !ok {
+		return nil, fmt.Errorf("invalid archive: %s is not found", metadataFile)
+	}
+	a := &Archive{}
+	if err = yaml.Unmarshal(data, &a.Metadata); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", metadataFile, err)
+	}
+	if a.Metadata.Version != ArchiveVersion {
+		return nil, fmt.Errorf("archive version %q is not supported, expected %s", a.Metadata.Version, ArchiveVersion)
+	}
+	for _, ref := range a.Metadata.Resources {
+		data, ok := files[ref.File]
+		if !ok {
+			return nil, fmt.Errorf("invalid archive: %s of %s is not found", ref.File, ref)
+		}
+		obj := &unstructured.Unstructured{}
+		if err = yaml.Unmarshal(data, &obj.Object); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", ref.File, err)
+		}
+		if _, ok := typeOf(obj.GetKind()); !ok {
+			return nil, fmt.Errorf("invalid %s: kind %q is not supported", ref.File, obj.GetKind())
+		}
+		a.Objects = append(a.Objects, obj)
+	}
+	return a, nil
+}
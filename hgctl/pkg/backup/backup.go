@@ -0,0 +1,250 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/annotations"
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/util"
+)
+
+const (
+	higressConfigMapName = "higress-config"
+	httpsConfigMapName   = "higress-https"
+	httpsConfigKey       = "cert"
+
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+	lastAppliedAnnotation  = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// Options selects the resources to back up
+type Options struct {
+	// IngressNamespace is the namespace of the ingresses, all the namespaces if empty
+	IngressNamespace string
+	HigressNamespace string
+	IngressClass     string
+}
+
+// httpsConfig holds the secret references of the config in the higress-https configmap
+type httpsConfig struct {
+	CredentialConfig []struct {
+		TLSSecret    string `json:"tlsSecret,omitempty"`
+		CACertSecret string `json:"cacertSecret,omitempty"`
+		DNS01        *struct {
+			CredentialSecret string `json:"credentialSecret,omitempty"`
+		} `json:"dns01,omitempty"`
+	} `json:"credentialConfig"`
+}
+
+type collector struct {
+	client  dynamic.Interface
+	opts    *Options
+	objects []*unstructured.Unstructured
+	secrets map[types.NamespacedName]bool
+	// warnings are the referenced resources not found
+	warnings []string
+}
+
+// Collect reads the resources to back up from the cluster: the ingresses of the ingress class or with higress
+// annotations, the higress resources, the higress configmaps and the secrets referenced by all of them
+func Collect(client dynamic.Interface, opts *Options) (*Archive, []string, error) {
+	c := &collector{
+		client:  client,
+		opts:    opts,
+		secrets: map[types.NamespacedName]bool{},
+	}
+	ctx := context.Background()
+
+	ingresses, err := c.list(ctx, ingressType, opts.IngressNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ing := range ingresses {
+		if !c.isHigressIngress(ing) {
+			continue
+		}
+		c.add(ing)
+		c.addIngressSecrets(ing)
+	}
+
+	for _, t := range []resourceType{mcpBridgeType, http2RpcType, wasmPluginType} {
+		objects, err := c.list(ctx, t, opts.HigressNamespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, obj := range objects {
+			c.add(obj)
+			if t == mcpBridgeType {
+				c.addMcpBridgeSecrets(obj)
+			}
+		}
+	}
+
+	for _, name := range []string{higressConfigMapName, httpsConfigMapName} {
+		cm, err := c.get(ctx, configMapType, opts.HigressNamespace, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cm == nil {
+			continue
+		}
+		c.add(cm)
+		if name == httpsConfigMapName {
+			c.addHttpsSecrets(cm)
+		}
+	}
+
+	for nn := range c.secrets {
+		secret, err := c.get(ctx, secretType, nn.Namespace, nn.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if secret == nil {
+			c.warnings = append(c.warnings, fmt.Sprintf("secret %s is referenced but not found", nn))
+			continue
+		}
+		c.add(secret)
+	}
+	sort.Strings(c.warnings)
+	return NewArchive(opts.HigressNamespace, c.objects), c.warnings, nil
+}
+
+func (c *collector) list(ctx context.Context, t resourceType, namespace string) ([]*unstructured.Unstructured, error) {
+	list, err := c.client.Resource(t.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %v", t.gvr.Resource, err)
+	}
+	var objects []*unstructured.Unstructured
+	for i := range list.Items {
+		obj := &list.Items[i]
+		obj.SetGroupVersionKind(t.gvk())
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// get returns nil if the resource is not found
+func (c *collector) get(ctx context.Context, t resourceType, namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := c.client.Resource(t.gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %v", t.kind, namespace, name, err)
+	}
+	obj.SetGroupVersionKind(t.gvk())
+	return obj, nil
+}
+
+func (c *collector) add(obj *unstructured.Unstructured) {
+	sanitize(obj)
+	c.objects = append(c.objects, obj)
+}
+
+func (c *collector) addSecret(ref, defaultNamespace string) {
+	nn := util.SplitNamespacedName(ref)
+	if nn.Name == "" {
+		return
+	}
+	if nn.Namespace == "" {
+		nn.Namespace = defaultNamespace
+	}
+	c.secrets[nn] = true
+}
+
+func (c *collector) isHigressIngress(ing *unstructured.Unstructured) bool {
+	class, _, _ := unstructured.NestedString(ing.Object, "spec", "ingressClassName")
+	if class == "" {
+		class = ing.GetAnnotations()[ingressClassAnnotation]
+	}
+	if class == c.opts.IngressClass {
+		return true
+	}
+	for key := range ing.GetAnnotations() {
+		if strings.HasPrefix(key, annotations.HigressAnnotationsPrefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *collector) addIngressSecrets(ing *unstructured.Unstructured) {
+	tls, _, _ := unstructured.NestedSlice(ing.Object, "spec", "tls")
+	for _, item := range tls {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name, ok := m["secretName"].(string); ok {
+				c.addSecret(name, ing.GetNamespace())
+			}
+		}
+	}
+	ingAnnotations := ing.GetAnnotations()
+	for _, key := range annotations.SecretAnnotationKeys(ingAnnotations) {
+		c.addSecret(ingAnnotations[key], ing.GetNamespace())
+	}
+}
+
+func (c *collector) addMcpBridgeSecrets(bridge *unstructured.Unstructured) {
+	registries, _, _ := unstructured.NestedSlice(bridge.Object, "spec", "registries")
+	for _, item := range registries {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name, ok := m["authSecretName"].(string); ok {
+				c.addSecret(name, bridge.GetNamespace())
+			}
+		}
+	}
+}
+
+func (c *collector) addHttpsSecrets(cm *unstructured.Unstructured) {
+	data, _, _ := unstructured.NestedString(cm.Object, "data", httpsConfigKey)
+	config := &httpsConfig{}
+	if err := yaml.Unmarshal([]byte(data), config); err != nil {
+		c.warnings = append(c.warnings, fmt.Sprintf("failed to parse configmap %s/%s: %v", cm.GetNamespace(), cm.GetName(), err))
+		return
+	}
+	for _, credential := range config.CredentialConfig {
+		c.addSecret(credential.TLSSecret, cm.GetNamespace())
+		c.addSecret(credential.CACertSecret, cm.GetNamespace())
+		if credential.DNS01 != nil {
+			c.addSecret(credential.DNS01.CredentialSecret, cm.GetNamespace())
+		}
+	}
+}
+
+// sanitize removes the fields assigned by the cluster so that the resources can be applied to another cluster
+func sanitize(obj *unstructured.Unstructured) {
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp",
+		"managedFields", "selfLink", "ownerReferences"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+	if objAnnotations := obj.GetAnnotations(); objAnnotations != nil {
+		delete(objAnnotations, lastAppliedAnnotation)
+		if len(objAnnotations) == 0 {
+			objAnnotations = nil
+		}
+		obj.SetAnnotations(objAnnotations)
+	}
+}
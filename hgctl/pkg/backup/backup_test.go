@@ -0,0 +1,200 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+)
+
+const clusterManifests = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+  namespace: default
+  resourceVersion: "100"
+  uid: 6a1b4f5e-0000-0000-0000-000000000000
+  annotations:
+    higress.io/proxy-ssl-secret: default/client
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+spec:
+  ingressClassName: higress
+  tls:
+  - hosts:
+    - example.com
+    secretName: foo-tls
+  rules:
+  - host: example.com
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: other
+  namespace: default
+spec:
+  ingressClassName: nginx
+---
+apiVersion: extensions.higress.io/v1alpha1
+kind: WasmPlugin
+metadata:
+  name: key-auth
+  namespace: higress-system
+spec:
+  url: oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/key-auth:1.0.0
+  matchRules:
+  - ingress:
+    - default/foo
+    service:
+    - foo.default.svc.cluster.local:80
+    config: {}
+status:
+  phase: Ready
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: higress-https
+  namespace: higress-system
+data:
+  cert: |
+    automaticHttps: true
+    credentialConfig:
+    - domains:
+      - example.com
+      tlsSecret: default/foo-tls
+    - domains:
+      - example.org
+      tlsSecret: missing
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo-tls
+  namespace: default
+type: kubernetes.io/tls
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: client
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: unreferenced
+  namespace: default
+`
+
+func newFakeClient(t *testing.T, manifests string) *fake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, rt := range resourceTypes {
+		listKinds[rt.gvr] = rt.kind + "List"
+	}
+	var objects []runtime.Object
+	for _, doc := range bytes.Split([]byte(manifests), []byte("\n---\n")) {
+		obj := &unstructured.Unstructured{}
+		require.NoError(t, yaml.Unmarshal(doc, &obj.Object))
+		objects = append(objects, obj)
+	}
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+func refNames(refs []ResourceRef) []string {
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.String())
+	}
+	return names
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	client := newFakeClient(t, clusterManifests)
+	archive, warnings, err := Collect(client, &Options{
+		HigressNamespace: "higress-system",
+		IngressClass:     defaultIngressClass,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"secret higress-system/missing is referenced but not found"}, warnings)
+	assert.Equal(t, []string{
+		"Secret default/client",
+		"Secret default/foo-tls",
+		"ConfigMap higress-system/higress-https",
+		"Ingress default/foo",
+		"WasmPlugin higress-system/key-auth",
+	}, refNames(archive.Metadata.Resources))
+
+	ingress := archive.Objects[3]
+	assert.Empty(t, ingress.GetResourceVersion())
+	assert.Empty(t, ingress.GetUID())
+	assert.Equal(t, map[string]string{"higress.io/proxy-ssl-secret": "default/client"}, ingress.GetAnnotations())
+	_, found, _ := unstructured.NestedMap(archive.Objects[4].Object, "status")
+	assert.False(t, found)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, archive.Write(buf))
+	restored, err := ReadArchive(buf)
+	require.NoError(t, err)
+	assert.Equal(t, archive.Metadata, restored.Metadata)
+	require.Len(t, restored.Objects, len(archive.Objects))
+
+	changes, err := Plan(client, restored)
+	require.NoError(t, err)
+	for _, change := range changes {
+		assert.Equal(t, ActionUnchanged, change.Action, change.Resource.String())
+	}
+
+	require.NoError(t, RemapNamespaces(restored, map[string]string{"default": "prod", "higress-system": "gateway"}))
+	assert.Equal(t, "gateway", restored.Metadata.HigressNamespace)
+	assert.Equal(t, []string{
+		"Secret prod/client",
+		"Secret prod/foo-tls",
+		"ConfigMap gateway/higress-https",
+		"Ingress prod/foo",
+		"WasmPlugin gateway/key-auth",
+	}, refNames(restored.Metadata.Resources))
+	assert.Equal(t, "prod/client", restored.Objects[3].GetAnnotations()["higress.io/proxy-ssl-secret"])
+	rules, _, _ := unstructured.NestedSlice(restored.Objects[4].Object, "spec", "matchRules")
+	rule := rules[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"prod/foo"}, rule["ingress"])
+	assert.Equal(t, []interface{}{"foo.prod.svc.cluster.local:80"}, rule["service"])
+	cert, _, _ := unstructured.NestedString(restored.Objects[2].Object, "data", "cert")
+	assert.Contains(t, cert, "tlsSecret: prod/foo-tls")
+	assert.Contains(t, cert, "tlsSecret: missing")
+
+	changes, err = Plan(client, restored)
+	require.NoError(t, err)
+	for _, change := range changes {
+		assert.Equal(t, ActionCreate, change.Action, change.Resource.String())
+		assert.NotEmpty(t, change.Diff)
+	}
+}
+
+func TestReadArchiveVersion(t *testing.T) {
+	archive := NewArchive("higress-system", nil)
+	archive.Metadata.Version = "v0"
+	buf := &bytes.Buffer{}
+	require.NoError(t, archive.Write(buf))
+	_, err := ReadArchive(buf)
+	assert.EqualError(t, err, `archive version "v0" is not supported, expected v1`)
+}
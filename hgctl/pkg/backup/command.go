@@ -0,0 +1,200 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/alibaba/higress/hgctl/pkg/kubernetes"
+	"github.com/alibaba/higress/v2/pkg/cmd/options"
+)
+
+const defaultIngressClass = "higress"
+
+type backupOptions struct {
+	Options
+	allNamespaces bool
+	file          string
+}
+
+type restoreOptions struct {
+	file             string
+	namespaceMapping map[string]string
+	dryRun           bool
+	diff             bool
+}
+
+func NewBackupCmd() *cobra.Command {
+	opts := &backupOptions{}
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the configuration resources of Higress to an archive",
+		Long: `Export the Ingresses of the Higress ingress class or with Higress annotations, the McpBridge, Http2Rpc and WasmPlugin
+resources, the higress-config and higress-https ConfigMaps and the Secrets referenced by them into a versioned archive,
+which can be applied to a cluster with hgctl restore.`,
+		Example: `  # Back up the ingresses of all the namespaces
+  hgctl backup -A -f higress-backup.tar.gz
+
+  # Back up the ingresses of the default namespace only
+  hgctl backup -n default
+`,
+		Run: func(c *cobra.Command, args []string) {
+			cmdutil.CheckErr(runBackup(c.OutOrStdout(), c.ErrOrStderr(), opts))
+		},
+	}
+
+	flags := backupCmd.Flags()
+	flags.StringVarP(&opts.file, "file", "f", "", "Path of the archive, higress-backup-<timestamp>.tar.gz by default")
+	flags.StringVarP(&opts.IngressNamespace, "namespace", "n", "default", "Namespace of the ingresses to back up")
+	flags.BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false, "Back up the ingresses of all the namespaces")
+	flags.StringVar(&opts.HigressNamespace, "higress-namespace", kubernetes.DefaultHigressNamespace, "Namespace where Higress was installed")
+	flags.StringVar(&opts.IngressClass, "ingress-class", defaultIngressClass, "Ingress class watched by Higress")
+	options.AddKubeConfigFlags(flags)
+
+	return backupCmd
+}
+
+func NewRestoreCmd() *cobra.Command {
+	opts := &restoreOptions{}
+	restoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the configuration resources of Higress from an archive",
+		Long: `Apply the resources of an archive created by hgctl backup to the cluster, the resources are created if absent
+and updated otherwise. The namespaces of the resources and of the references between them can be remapped.`,
+		Example: `  # Preview the changes with the differences
+  hgctl restore -f higress-backup.tar.gz --dry-run --diff
+
+  # Restore to another cluster where Higress is installed in the gateway namespace
+  hgctl restore -f higress-backup.tar.gz --namespace-mapping higress-system=gateway --kubeconfig ~/.kube/target
+`,
+		Run: func(c *cobra.Command, args []string) {
+			cmdutil.CheckErr(runRestore(c.OutOrStdout(), opts))
+		},
+	}
+
+	flags := restoreCmd.Flags()
+	flags.StringVarP(&opts.file, "file", "f", "", "Path of the archive")
+	flags.StringToStringVar(&opts.namespaceMapping, "namespace-mapping", nil, "Namespaces to remap in the form of source=target")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Only print the changes without applying them")
+	flags.BoolVar(&opts.diff, "diff", false, "Print the differences between the live resources and the ones to apply")
+	options.AddKubeConfigFlags(flags)
+	_ = restoreCmd.MarkFlagRequired("file")
+
+	return restoreCmd
+}
+
+func runBackup(stdout, stderr io.Writer, opts *backupOptions) error {
+	if opts.allNamespaces {
+		opts.IngressNamespace = ""
+	}
+	if opts.file == "" {
+		opts.file = fmt.Sprintf("higress-backup-%s.tar.gz", time.Now().Format("20060102150405"))
+	}
+	client, err := newDynamicClient()
+	if err != nil {
+		return err
+	}
+	archive, warnings, err := Collect(client, &opts.Options)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(stderr, "Warning: %s\n", w)
+	}
+
+	f, err := os.OpenFile(opts.file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err = archive.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	for _, ref := range archive.Metadata.Resources {
+		fmt.Fprintln(stdout, ref)
+	}
+	fmt.Fprintf(stdout, "Backed up %d resources to %s\n", len(archive.Objects), opts.file)
+	return nil
+}
+
+func runRestore(stdout io.Writer, opts *restoreOptions) error {
+	f, err := os.Open(opts.file)
+	if err != nil {
+		return err
+	}
+	archive, err := ReadArchive(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if err = RemapNamespaces(archive, opts.namespaceMapping); err != nil {
+		return err
+	}
+
+	cliClient, err := kubernetes.NewCLIClient(options.DefaultConfigFlags.ToRawKubeConfigLoader())
+	if err != nil {
+		return fmt.Errorf("failed to build kubernetes client: %v", err)
+	}
+	client, err := dynamic.NewForConfig(cliClient.RESTConfig())
+	if err != nil {
+		return err
+	}
+	changes, err := Plan(client, archive)
+	if err != nil {
+		return err
+	}
+
+	suffix := ""
+	if opts.dryRun {
+		suffix = " (dry run)"
+	}
+	namespaces := map[string]bool{}
+	for _, change := range changes {
+		if !opts.dryRun && change.Action != ActionUnchanged {
+			if ns := change.Resource.Namespace; !namespaces[ns] {
+				if err = cliClient.CreateNamespace(ns); err != nil {
+					return err
+				}
+				namespaces[ns] = true
+			}
+			if err = cliClient.ApplyObject(change.Object); err != nil {
+				return fmt.Errorf("failed to apply %s: %v", change.Resource, err)
+			}
+		}
+		fmt.Fprintf(stdout, "%s %s%s\n", change.Resource, change.Action, suffix)
+		if opts.diff && change.Diff != "" {
+			fmt.Fprintln(stdout, change.Diff)
+		}
+	}
+	return nil
+}
+
+func newDynamicClient() (dynamic.Interface, error) {
+	client, err := kubernetes.NewCLIClient(options.DefaultConfigFlags.ToRawKubeConfigLoader())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes client: %v", err)
+	}
+	return dynamic.NewForConfig(client.RESTConfig())
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"github.com/alibaba/higress/hgctl/pkg/util"
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/annotations"
+)
+
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Change is the change to apply to a resource of the cluster
+type Change struct {
+	Resource ResourceRef
+	Action   Action
+	// Diff is the difference between the live resource and the one in the archive
+	Diff   string
+	Object *unstructured.Unstructured
+}
+
+// RemapNamespaces moves the resources of the archive to the mapped namespaces, the references to other resources
+// in the form of namespace/name are remapped as well
+func RemapNamespaces(a *Archive, mapping map[string]string) error {
+	if len(mapping) == 0 {
+		return nil
+	}
+	remap := func(namespace string) string {
+		if target, ok := mapping[namespace]; ok {
+			return target
+		}
+		return namespace
+	}
+	remapRef := func(ref string) string {
+		if ns, name, ok := strings.Cut(ref, "/"); ok {
+			return remap(ns) + "/" + name
+		}
+		return ref
+	}
+
+	for _, obj := range a.Objects {
+		obj.SetNamespace(remap(obj.GetNamespace()))
+		switch obj.GetKind() {
+		case ingressType.kind:
+			objAnnotations := obj.GetAnnotations()
+			for _, key := range annotations.SecretAnnotationKeys(objAnnotations) {
+				objAnnotations[key] = remapRef(objAnnotations[key])
+			}
+			obj.SetAnnotations(objAnnotations)
+		case wasmPluginType.kind:
+			rules, _, _ := unstructured.NestedSlice(obj.Object, "spec", "matchRules")
+			for _, item := range rules {
+				rule, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				remapStrings(rule, "ingress", remapRef)
+				remapStrings(rule, "service", func(service string) string {
+					return remapService(service, remap)
+				})
+			}
+			if rules != nil {
+				if err := unstructured.SetNestedSlice(obj.Object, rules, "spec", "matchRules"); err != nil {
+					return err
+				}
+			}
+		case configMapType.kind:
+			if obj.GetName() != httpsConfigMapName {
+				continue
+			}
+			if err := remapHttpsConfig(obj, remapRef); err != nil {
+				return err
+			}
+		}
+	}
+	if target, ok := mapping[a.Metadata.HigressNamespace]; ok {
+		a.Metadata.HigressNamespace = target
+	}
+	a.sort()
+	return nil
+}
+
+func remapStrings(m map[string]interface{}, key string, remap func(string) string) {
+	items, ok := m[key].([]interface{})
+	if !ok {
+		return
+	}
+	for i, item := range items {
+		if s, ok := item.(string); ok {
+			items[i] = remap(s)
+		}
+	}
+}
+
+// remapService remaps the namespace of the service in the form of name.namespace.svc.cluster.local[:port]
+func remapService(service string, remap func(string) string) string {
+	parts := strings.SplitN(service, ".", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[2], "svc.") {
+		return service
+	}
+	parts[1] = remap(parts[1])
+	return strings.Join(parts, ".")
+}
+
+func remapHttpsConfig(cm *unstructured.Unstructured, remapRef func(string) string) error {
+	data, found, _ := unstructured.NestedString(cm.Object, "data", httpsConfigKey)
+	if !found {
+		return nil
+	}
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		return fmt.Errorf("failed to parse configmap %s: %v", cm.GetName(), err)
+	}
+	credentials, _ := config["credentialConfig"].([]interface{})
+	changed := false
+	remapField := func(m map[string]interface{}, key string) {
+		if ref, ok := m[key].(string); ok && remapRef(ref) != ref {
+			m[key] = remapRef(ref)
+			changed = true
+		}
+	}
+	for _, item := range credentials {
+		credential, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		remapField(credential, "tlsSecret")
+		remapField(credential, "cacertSecret")
+		if dns01, ok := credential["dns01"].(map[string]interface{}); ok {
+			remapField(dns01, "credentialSecret")
+		}
+	}
+	// keep the original formatting if nothing is remapped
+	if !changed {
+		return nil
+	}
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(cm.Object, string(out), "data", httpsConfigKey)
+}
+
+// Plan compares the resources of the archive with the live ones in the cluster
+func Plan(client dynamic.Interface, a *Archive) ([]Change, error) {
+	ctx := context.Background()
+	var changes []Change
+	for _, obj := range a.Objects {
+		t, ok := typeOf(obj.GetKind())
+		if !ok {
+			return nil, fmt.Errorf("kind %q is not supported", obj.GetKind())
+		}
+		change := Change{Resource: refOf(obj), Object: obj}
+		desired, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		live, err := client.Resource(t.gvr).Namespace(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			change.Action = ActionCreate
+			change.Diff = util.YAMLDiff("", string(desired))
+		case err != nil:
+			return nil, fmt.Errorf("failed to get %s: %v", change.Resource, err)
+		default:
+			live.SetGroupVersionKind(t.gvk())
+			sanitize(live)
+			current, err := yaml.Marshal(live.Object)
+			if err != nil {
+				return nil, err
+			}
+			change.Action = ActionUnchanged
+			if !util.IsYAMLEqual(string(current), string(desired)) {
+				change.Action = ActionUpdate
+				change.Diff = util.YAMLDiff(string(current), string(desired))
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -40,6 +40,9 @@ metadata:
     higress.io/wasm-plugin-title: {{ .Title }}
     higress.io/wasm-plugin-description: {{ .Description }}
     higress.io/wasm-plugin-icon: {{ .IconUrl }}
+{{- if .ConfigSchema }}
+    higress.io/wasm-plugin-config-schema: {{ .ConfigSchema }}
+{{- end }}
   labels:
     higress.io/wasm-plugin-name: {{ .Name }}
     higress.io/wasm-plugin-category: {{ .Category }}
@@ -55,17 +58,18 @@ spec:
 `
 
 type PluginConf struct {
-	Name        string
-	Namespace   string
-	Title       string
-	Description string
-	IconUrl     string
-	Version     string
-	Category    string
-	Phase       string
-	Priority    int64
-	Config      string
-	Url         string
+	Name         string
+	Namespace    string
+	Title        string
+	Description  string
+	IconUrl      string
+	Version      string
+	Category     string
+	Phase        string
+	Priority     int64
+	Config       string
+	Url          string
+	ConfigSchema string
 }
 
 func (pc *PluginConf) String() string {
@@ -113,24 +117,50 @@ func ExtractPluginConfFrom(spec *types.WasmPluginMeta, config, url string) (*Plu
 		config = string(b)
 	}
 
+	configSchema, err := marshalConfigSchema(spec)
+	if err != nil {
+		return nil, err
+	}
+
 	pc := &PluginConf{
-		Name:        spec.Info.Name,
-		Namespace:   "higress-system",
-		Title:       spec.Info.Title,
-		Description: spec.Info.Description,
-		IconUrl:     spec.Info.IconUrl,
-		Version:     spec.Info.Version,
-		Category:    string(spec.Info.Category),
-		Phase:       string(spec.Spec.Phase),
-		Priority:    spec.Spec.Priority,
-		Config:      utils.AddIndent(config, strings.Repeat(" ", 2)),
-		Url:         url,
+		Name:         spec.Info.Name,
+		Namespace:    "higress-system",
+		Title:        spec.Info.Title,
+		Description:  spec.Info.Description,
+		IconUrl:      spec.Info.IconUrl,
+		Version:      spec.Info.Version,
+		Category:     string(spec.Info.Category),
+		Phase:        string(spec.Spec.Phase),
+		Priority:     spec.Spec.Priority,
+		Config:       utils.AddIndent(config, strings.Repeat(" ", 2)),
+		Url:          url,
+		ConfigSchema: configSchema,
 	}
 	pc.withDefaultValue()
 
 	return pc, nil
 }
 
+// marshalConfigSchema marshals the config schema in spec.yaml into a quoted JSON string, which is a valid
+// YAML scalar for the config schema annotation.
+func marshalConfigSchema(spec *types.WasmPluginMeta) (string, error) {
+	if spec.Spec.ConfigSchema.OpenAPIV3Schema == nil {
+		return "", nil
+	}
+	// the example is only shown to the users and may be written in YAML
+	schema := *spec.Spec.ConfigSchema.OpenAPIV3Schema
+	schema.Example = nil
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config schema: %v", err)
+	}
+	quoted, err := json.Marshal(string(b))
+	if err != nil {
+		return "", err
+	}
+	return string(quoted), nil
+}
+
 func (pc *PluginConf) withDefaultValue() {
 	if pc.Name == "" {
 		pc.Name = "Unnamed"
@@ -18,7 +18,10 @@ import (
 	"os"
 
 	"github.com/alibaba/higress/hgctl/pkg/agent"
+	"github.com/alibaba/higress/hgctl/pkg/analyze"
+	"github.com/alibaba/higress/hgctl/pkg/backup"
 	"github.com/alibaba/higress/hgctl/pkg/plugin"
+	"github.com/alibaba/higress/hgctl/pkg/route"
 	"github.com/spf13/cobra"
 )
 
@@ -43,6 +46,10 @@ func GetRootCommand() *cobra.Command {
 	rootCmd.AddCommand(plugin.NewCommand())
 	rootCmd.AddCommand(newCompletionCmd(os.Stdout))
 	rootCmd.AddCommand(newCodeDebugCmd())
+	rootCmd.AddCommand(analyze.NewCommand())
+	rootCmd.AddCommand(route.NewCommand())
+	rootCmd.AddCommand(backup.NewBackupCmd())
+	rootCmd.AddCommand(backup.NewRestoreCmd())
 	rootCmd.AddCommand(agent.NewMCPCmd())
 	rootCmd.AddCommand(agent.NewAgentCmd())
 
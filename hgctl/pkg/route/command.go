@@ -0,0 +1,253 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/yaml"
+
+	"github.com/alibaba/higress/hgctl/cmd/hgctl/config"
+	"github.com/alibaba/higress/hgctl/pkg/kubernetes"
+	extv1alpha1 "github.com/alibaba/higress/v2/client/pkg/apis/extensions/v1alpha1"
+	"github.com/alibaba/higress/v2/pkg/cmd/options"
+)
+
+const (
+	summaryOutput = "short"
+	jsonOutput    = "json"
+	yamlOutput    = "yaml"
+)
+
+type explainOptions struct {
+	host        string
+	path        string
+	method      string
+	headers     []string
+	file        string
+	pluginFiles []string
+	routeConfig string
+	podName     string
+	namespace   string
+	bindAddress string
+	output      string
+}
+
+func NewCommand() *cobra.Command {
+	routeCmd := &cobra.Command{
+		Use:   "route",
+		Short: "Inspect the routes of Higress Gateway",
+	}
+	routeCmd.AddCommand(newExplainCmd())
+	return routeCmd
+}
+
+func newExplainCmd() *cobra.Command {
+	opts := &explainOptions{}
+	explainCmd := &cobra.Command{
+		Use:   "explain [<pod-name>]",
+		Short: "Explain which route and plugins serve a request",
+		Long: `Evaluate a request against the route configuration of Higress Gateway with the matching semantics of Envoy,
+printing the matched virtual host, route, destination clusters with their weights and the WasmPlugins applied to the route.
+The route configuration is read from the file if any, otherwise from the config dump of the gateway pod.`,
+		Example: `  # Explain a request with the route configuration of the gateway
+  hgctl route explain --host example.com --path /api/v1/users -H x-user-id:1
+
+  # Explain a request with a saved config dump and the WasmPlugin manifests
+  hgctl route explain --host example.com --path /api --method POST -f config_dump.json --plugin-file plugins/
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			if len(args) != 0 {
+				opts.podName = args[0]
+			}
+			cmdutil.CheckErr(runExplain(c, opts))
+		},
+	}
+
+	flags := explainCmd.Flags()
+	flags.StringVar(&opts.host, "host", "", "Host of the request")
+	flags.StringVar(&opts.path, "path", "/", "Path of the request, including the query string")
+	flags.StringVar(&opts.method, "method", "GET", "Method of the request")
+	flags.StringArrayVarP(&opts.headers, "header", "H", nil, "Header of the request in the format name:value")
+	flags.StringVarP(&opts.file, "file", "f", "", "Config dump or route configuration of the gateway in JSON or YAML")
+	flags.StringArrayVar(&opts.pluginFiles, "plugin-file", nil, "WasmPlugin manifest files or directories, "+
+		"the WasmPlugins are read from the cluster if the route configuration is read from the gateway pod")
+	flags.StringVar(&opts.routeConfig, "route-config", "", "Only evaluate the route configuration with the name, e.g. http.80")
+	flags.StringVarP(&opts.namespace, "namespace", "n", kubernetes.DefaultHigressNamespace, "Namespace where Higress was installed")
+	flags.StringVar(&opts.bindAddress, "bind-address", "localhost", "Address to which the port forwarding binds")
+	flags.StringVarP(&opts.output, "output", "o", summaryOutput, "Output format: one of short|json|yaml")
+	options.AddKubeConfigFlags(flags)
+	_ = explainCmd.MarkFlagRequired("host")
+
+	return explainCmd
+}
+
+func runExplain(c *cobra.Command, opts *explainOptions) error {
+	if opts.output != summaryOutput && opts.output != jsonOutput && opts.output != yamlOutput {
+		return fmt.Errorf("output format %q not supported", opts.output)
+	}
+	headers := map[string]string{}
+	for _, h := range opts.headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid header %q, expected name:value", h)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	var data []byte
+	var err error
+	if opts.file != "" {
+		data, err = os.ReadFile(opts.file)
+	} else {
+		data, err = config.GetEnvoyConfig(&config.GetEnvoyConfigOptions{
+			PodName:         opts.podName,
+			PodNamespace:    opts.namespace,
+			BindAddress:     opts.bindAddress,
+			EnvoyConfigType: config.AllEnvoyConfigType,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	routeConfigs, err := LoadRouteConfigs(data)
+	if err != nil {
+		return err
+	}
+	if opts.routeConfig != "" {
+		var filtered []*routev3.RouteConfiguration
+		for _, rc := range routeConfigs {
+			if rc.GetName() == opts.routeConfig {
+				filtered = append(filtered, rc)
+			}
+		}
+		if len(filtered) == 0 {
+			return fmt.Errorf("route configuration %s is not found", opts.routeConfig)
+		}
+		routeConfigs = filtered
+	}
+
+	var plugins []*extv1alpha1.WasmPlugin
+	if len(opts.pluginFiles) > 0 {
+		plugins, err = ReadPluginFiles(opts.pluginFiles, c.InOrStdin())
+	} else if opts.file == "" {
+		var client kubernetes.CLIClient
+		client, err = kubernetes.NewCLIClient(options.DefaultConfigFlags.ToRawKubeConfigLoader())
+		if err != nil {
+			return fmt.Errorf("failed to build kubernetes client: %v", err)
+		}
+		plugins, err = ReadClusterPlugins(client, opts.namespace)
+	}
+	if err != nil {
+		return err
+	}
+
+	req := NewRequest(opts.host, opts.path, opts.method, headers)
+	explanations, err := Explain(routeConfigs, req)
+	if err != nil {
+		return err
+	}
+	if len(explanations) == 0 {
+		return fmt.Errorf("no virtual host found for host %s", opts.host)
+	}
+	for _, e := range explanations {
+		e.Plugins = MatchPlugins(plugins, e, req.Host, opts.namespace)
+	}
+	if err = printExplanations(c.OutOrStdout(), explanations, opts.output); err != nil {
+		return err
+	}
+	for _, e := range explanations {
+		if e.Route != "" {
+			return nil
+		}
+	}
+	return errors.New("no route matches the request")
+}
+
+func printExplanations(w io.Writer, explanations []*Explanation, output string) error {
+	switch output {
+	case jsonOutput:
+		data, err := json.MarshalIndent(explanations, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	case yamlOutput:
+		data, err := yaml.Marshal(explanations)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, string(data))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for i, e := range explanations {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "ROUTE CONFIG:\t%s\n", e.RouteConfig)
+		fmt.Fprintf(tw, "VIRTUAL HOST:\t%s (domain %s)\n", e.VirtualHost, e.Domain)
+		if e.Route == "" {
+			fmt.Fprintf(tw, "ROUTE:\tnone, the request is rejected with 404\n")
+			continue
+		}
+		fmt.Fprintf(tw, "ROUTE:\t%s\n", e.Route)
+		fmt.Fprintf(tw, "MATCH:\t%s\n", e.Match)
+		switch {
+		case len(e.Clusters) > 0:
+			for j, cluster := range e.Clusters {
+				title := "DESTINATION:"
+				if j > 0 {
+					title = ""
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%.2f%%\n", title, cluster.Name, cluster.Percent)
+			}
+		case e.ClusterHeader != "":
+			fmt.Fprintf(tw, "DESTINATION:\tcluster from header %s\n", e.ClusterHeader)
+		case e.Redirect != "":
+			fmt.Fprintf(tw, "REDIRECT:\t%s\n", e.Redirect)
+		case e.DirectResponse != 0:
+			fmt.Fprintf(tw, "DIRECT RESPONSE:\t%d\n", e.DirectResponse)
+		}
+		if len(e.Plugins) == 0 {
+			fmt.Fprintf(tw, "PLUGINS:\tnone\n")
+			continue
+		}
+		for j, p := range e.Plugins {
+			title := "PLUGINS:"
+			if j > 0 {
+				title = ""
+			}
+			matchedBy := p.MatchedBy
+			if p.Rule >= 0 {
+				matchedBy = fmt.Sprintf("matchRules[%d] %s %s", p.Rule, p.MatchedBy, p.Value)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s/%d\t%s\n", title, p.Name, p.Phase, p.Priority, matchedBy)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,448 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+)
+
+// Request is the request to explain, the keys of Headers are in lower case
+type Request struct {
+	Host    string
+	Path    string
+	Method  string
+	Headers map[string]string
+}
+
+// NewRequest builds the request with the pseudo headers used by the route matching
+func NewRequest(host, path, method string, headers map[string]string) *Request {
+	if path == "" {
+		path = "/"
+	}
+	r := &Request{
+		Host:    strings.ToLower(host),
+		Path:    path,
+		Method:  strings.ToUpper(method),
+		Headers: map[string]string{},
+	}
+	for k, v := range headers {
+		r.Headers[strings.ToLower(k)] = v
+	}
+	r.Headers[":authority"] = r.Host
+	r.Headers[":path"] = r.Path
+	r.Headers[":method"] = r.Method
+	return r
+}
+
+// Cluster is a destination of the matched route
+type Cluster struct {
+	Name    string  `json:"name"`
+	Weight  uint32  `json:"weight,omitempty"`
+	Percent float64 `json:"percent"`
+}
+
+// Explanation is the result of the route matching in a route configuration
+type Explanation struct {
+	RouteConfig string `json:"routeConfig"`
+	VirtualHost string `json:"virtualHost"`
+	Domain      string `json:"domain"`
+	// Route is empty if no route of the virtual host matches the request
+	Route          string        `json:"route,omitempty"`
+	Match          string        `json:"match,omitempty"`
+	Clusters       []Cluster     `json:"clusters,omitempty"`
+	ClusterHeader  string        `json:"clusterHeader,omitempty"`
+	Redirect       string        `json:"redirect,omitempty"`
+	DirectResponse uint32        `json:"directResponse,omitempty"`
+	Plugins        []PluginMatch `json:"plugins,omitempty"`
+}
+
+// Explain evaluates the request against the route configurations with the matching semantics of envoy,
+// an explanation is returned for each route configuration having a virtual host for the host of the request
+func Explain(routeConfigs []*routev3.RouteConfiguration, req *Request) ([]*Explanation, error) {
+	var explanations []*Explanation
+	for _, rc := range routeConfigs {
+		vh, domain := selectVirtualHost(rc.GetVirtualHosts(), req.Host)
+		if vh == nil {
+			continue
+		}
+		e := &Explanation{
+			RouteConfig: rc.GetName(),
+			VirtualHost: vh.GetName(),
+			Domain:      domain,
+		}
+		for _, r := range vh.GetRoutes() {
+			matched, err := matchRoute(r.GetMatch(), req)
+			if err != nil {
+				return nil, fmt.Errorf("route %s of virtual host %s: %v", r.GetName(), vh.GetName(), err)
+			}
+			if !matched {
+				continue
+			}
+			e.Route = r.GetName()
+			e.Match = describeMatch(r.GetMatch())
+			setAction(e, r)
+			break
+		}
+		explanations = append(explanations, e)
+	}
+	return explanations, nil
+}
+
+// selectVirtualHost picks the virtual host in the order of envoy: the exact domains, the longest suffix wildcards,
+// the longest prefix wildcards, then the catch-all, the port of the host is ignored if nothing matches with it
+func selectVirtualHost(vhosts []*routev3.VirtualHost, host string) (*routev3.VirtualHost, string) {
+	vh, domain := selectVirtualHostByDomain(vhosts, host)
+	if vh != nil {
+		return vh, domain
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return selectVirtualHostByDomain(vhosts, h)
+	}
+	return nil, ""
+}
+
+func selectVirtualHostByDomain(vhosts []*routev3.VirtualHost, host string) (*routev3.VirtualHost, string) {
+	var suffix, prefix, catchAll *routev3.VirtualHost
+	var suffixDomain, prefixDomain string
+	for _, vh := range vhosts {
+		for _, d := range vh.GetDomains() {
+			domain := strings.ToLower(d)
+			switch {
+			case domain == "*":
+				if catchAll == nil {
+					catchAll = vh
+				}
+			case domain == host:
+				return vh, d
+			case strings.HasPrefix(domain, "*"):
+				if len(domain) > len(suffixDomain) && len(host) > len(domain)-1 && strings.HasSuffix(host, domain[1:]) {
+					suffix, suffixDomain = vh, d
+				}
+			case strings.HasSuffix(domain, "*"):
+				if len(domain) > len(prefixDomain) && len(host) > len(domain)-1 && strings.HasPrefix(host, domain[:len(domain)-1]) {
+					prefix, prefixDomain = vh, d
+				}
+			}
+		}
+	}
+	switch {
+	case suffix != nil:
+		return suffix, suffixDomain
+	case prefix != nil:
+		return prefix, prefixDomain
+	case catchAll != nil:
+		return catchAll, "*"
+	}
+	return nil, ""
+}
+
+func matchRoute(m *routev3.RouteMatch, req *Request) (bool, error) {
+	matched, err := matchPath(m, req.Path)
+	if err != nil || !matched {
+		return false, err
+	}
+	for _, h := range m.GetHeaders() {
+		matched, err = matchHeader(h, req.Headers)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	if len(m.GetQueryParameters()) == 0 {
+		return true, nil
+	}
+	query := url.Values{}
+	if i := strings.Index(req.Path, "?"); i >= 0 {
+		query, _ = url.ParseQuery(req.Path[i+1:])
+	}
+	for _, q := range m.GetQueryParameters() {
+		matched, err = matchQueryParameter(q, query)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchPath(m *routev3.RouteMatch, path string) (bool, error) {
+	caseSensitive := m.GetCaseSensitive() == nil || m.GetCaseSensitive().GetValue()
+	// only the prefix matching takes the query string into account
+	pathOnly := path
+	if i := strings.IndexAny(pathOnly, "?#"); i >= 0 {
+		pathOnly = pathOnly[:i]
+	}
+	switch spec := m.GetPathSpecifier().(type) {
+	case *routev3.RouteMatch_Prefix:
+		if caseSensitive {
+			return strings.HasPrefix(path, spec.Prefix), nil
+		}
+		return strings.HasPrefix(strings.ToLower(path), strings.ToLower(spec.Prefix)), nil
+	case *routev3.RouteMatch_Path:
+		if caseSensitive {
+			return pathOnly == spec.Path, nil
+		}
+		return strings.EqualFold(pathOnly, spec.Path), nil
+	case *routev3.RouteMatch_SafeRegex:
+		return matchRegex(spec.SafeRegex.GetRegex(), pathOnly)
+	case *routev3.RouteMatch_PathSeparatedPrefix:
+		prefix := spec.PathSeparatedPrefix
+		if !caseSensitive {
+			prefix = strings.ToLower(prefix)
+			pathOnly = strings.ToLower(pathOnly)
+		}
+		return pathOnly == prefix || strings.HasPrefix(pathOnly, prefix+"/"), nil
+	}
+	return false, fmt.Errorf("path specifier %T is not supported", m.GetPathSpecifier())
+}
+
+// matchHeader follows the semantics of envoy, a missing header only matches the present matcher
+func matchHeader(h *routev3.HeaderMatcher, headers map[string]string) (bool, error) {
+	value, exist := headers[strings.ToLower(h.GetName())]
+	if !exist && h.GetTreatMissingHeaderAsEmpty() {
+		exist = true
+	}
+	if !exist {
+		if present, ok := h.GetHeaderMatchSpecifier().(*routev3.HeaderMatcher_PresentMatch); ok {
+			return present.PresentMatch == h.GetInvertMatch(), nil
+		}
+		return false, nil
+	}
+	var matched bool
+	var err error
+	switch spec := h.GetHeaderMatchSpecifier().(type) {
+	case *routev3.HeaderMatcher_ExactMatch:
+		matched = value == spec.ExactMatch
+	case *routev3.HeaderMatcher_SafeRegexMatch:
+		matched, err = matchRegex(spec.SafeRegexMatch.GetRegex(), value)
+	case *routev3.HeaderMatcher_RangeMatch:
+		n, parseErr := strconv.ParseInt(value, 10, 64)
+		matched = parseErr == nil && n >= spec.RangeMatch.GetStart() && n < spec.RangeMatch.GetEnd()
+	case *routev3.HeaderMatcher_PresentMatch:
+		matched = spec.PresentMatch
+	case *routev3.HeaderMatcher_PrefixMatch:
+		matched = strings.HasPrefix(value, spec.PrefixMatch)
+	case *routev3.HeaderMatcher_SuffixMatch:
+		matched = strings.HasSuffix(value, spec.SuffixMatch)
+	case *routev3.HeaderMatcher_ContainsMatch:
+		matched = strings.Contains(value, spec.ContainsMatch)
+	case *routev3.HeaderMatcher_StringMatch:
+		matched, err = matchString(spec.StringMatch, value)
+	default:
+		// no specifier means the header is only required to be present
+		matched = true
+	}
+	if err != nil {
+		return false, err
+	}
+	return matched != h.GetInvertMatch(), nil
+}
+
+func matchQueryParameter(q *routev3.QueryParameterMatcher, query url.Values) (bool, error) {
+	values, exist := query[q.GetName()]
+	if !exist {
+		return false, nil
+	}
+	switch spec := q.GetQueryParameterMatchSpecifier().(type) {
+	case *routev3.QueryParameterMatcher_StringMatch:
+		value := ""
+		if len(values) > 0 {
+			value = values[0]
+		}
+		return matchString(spec.StringMatch, value)
+	case *routev3.QueryParameterMatcher_PresentMatch:
+		return spec.PresentMatch, nil
+	}
+	return true, nil
+}
+
+func matchString(m *matcherv3.StringMatcher, value string) (bool, error) {
+	ignoreCase := m.GetIgnoreCase()
+	equal := func(a, b string) bool {
+		if ignoreCase {
+			return strings.EqualFold(a, b)
+		}
+		return a == b
+	}
+	switch spec := m.GetMatchPattern().(type) {
+	case *matcherv3.StringMatcher_Exact:
+		return equal(value, spec.Exact), nil
+	case *matcherv3.StringMatcher_Prefix:
+		return len(value) >= len(spec.Prefix) && equal(value[:len(spec.Prefix)], spec.Prefix), nil
+	case *matcherv3.StringMatcher_Suffix:
+		return len(value) >= len(spec.Suffix) && equal(value[len(value)-len(spec.Suffix):], spec.Suffix), nil
+	case *matcherv3.StringMatcher_Contains:
+		if ignoreCase {
+			return strings.Contains(strings.ToLower(value), strings.ToLower(spec.Contains)), nil
+		}
+		return strings.Contains(value, spec.Contains), nil
+	case *matcherv3.StringMatcher_SafeRegex:
+		return matchRegex(spec.SafeRegex.GetRegex(), value)
+	}
+	return false, fmt.Errorf("string matcher %T is not supported", m.GetMatchPattern())
+}
+
+// matchRegex matches the whole value as envoy does, both envoy and go use the RE2 syntax
+func matchRegex(expr, value string) (bool, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return false, fmt.Errorf("invalid regex %q: %v", expr, err)
+	}
+	return re.MatchString(value), nil
+}
+
+func setAction(e *Explanation, r *routev3.Route) {
+	switch action := r.GetAction().(type) {
+	case *routev3.Route_Route:
+		switch spec := action.Route.GetClusterSpecifier().(type) {
+		case *routev3.RouteAction_Cluster:
+			e.Clusters = []Cluster{{Name: spec.Cluster, Percent: 100}}
+		case *routev3.RouteAction_ClusterHeader:
+			e.ClusterHeader = spec.ClusterHeader
+		case *routev3.RouteAction_WeightedClusters:
+			var total uint32
+			for _, c := range spec.WeightedClusters.GetClusters() {
+				total += c.GetWeight().GetValue()
+			}
+			for _, c := range spec.WeightedClusters.GetClusters() {
+				weight := c.GetWeight().GetValue()
+				var percent float64
+				if total > 0 {
+					percent = float64(weight) * 100 / float64(total)
+				}
+				e.Clusters = append(e.Clusters, Cluster{Name: c.GetName(), Weight: weight, Percent: percent})
+			}
+		}
+	case *routev3.Route_Redirect:
+		e.Redirect = describeRedirect(action.Redirect)
+	case *routev3.Route_DirectResponse:
+		e.DirectResponse = action.DirectResponse.GetStatus()
+	}
+}
+
+func describeRedirect(r *routev3.RedirectAction) string {
+	var parts []string
+	if r.GetHttpsRedirect() {
+		parts = append(parts, "scheme=https")
+	}
+	if r.GetSchemeRedirect() != "" {
+		parts = append(parts, "scheme="+r.GetSchemeRedirect())
+	}
+	if r.GetHostRedirect() != "" {
+		parts = append(parts, "host="+r.GetHostRedirect())
+	}
+	if r.GetPortRedirect() != 0 {
+		parts = append(parts, fmt.Sprintf("port=%d", r.GetPortRedirect()))
+	}
+	if r.GetPathRedirect() != "" {
+		parts = append(parts, "path="+r.GetPathRedirect())
+	}
+	if r.GetPrefixRewrite() != "" {
+		parts = append(parts, "prefix="+r.GetPrefixRewrite())
+	}
+	parts = append(parts, fmt.Sprintf("code=%s", strings.ToLower(r.GetResponseCode().String())))
+	return strings.Join(parts, " ")
+}
+
+func describeMatch(m *routev3.RouteMatch) string {
+	var parts []string
+	switch spec := m.GetPathSpecifier().(type) {
+	case *routev3.RouteMatch_Prefix:
+		parts = append(parts, "prefix "+spec.Prefix)
+	case *routev3.RouteMatch_Path:
+		parts = append(parts, "path "+spec.Path)
+	case *routev3.RouteMatch_SafeRegex:
+		parts = append(parts, "regex "+spec.SafeRegex.GetRegex())
+	case *routev3.RouteMatch_PathSeparatedPrefix:
+		parts = append(parts, "path separated prefix "+spec.PathSeparatedPrefix)
+	}
+	if len(parts) > 0 && m.GetCaseSensitive() != nil && !m.GetCaseSensitive().GetValue() {
+		parts[len(parts)-1] += " (ignore case)"
+	}
+	for _, h := range m.GetHeaders() {
+		parts = append(parts, "header "+describeHeader(h))
+	}
+	var queries []string
+	for _, q := range m.GetQueryParameters() {
+		desc := q.GetName() + " present"
+		if sm := q.GetStringMatch(); sm != nil {
+			desc = q.GetName() + " " + describeString(sm)
+		}
+		queries = append(queries, desc)
+	}
+	sort.Strings(queries)
+	for _, q := range queries {
+		parts = append(parts, "query "+q)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeHeader(h *routev3.HeaderMatcher) string {
+	var desc string
+	switch spec := h.GetHeaderMatchSpecifier().(type) {
+	case *routev3.HeaderMatcher_ExactMatch:
+		desc = fmt.Sprintf("exact %q", spec.ExactMatch)
+	case *routev3.HeaderMatcher_SafeRegexMatch:
+		desc = fmt.Sprintf("regex %q", spec.SafeRegexMatch.GetRegex())
+	case *routev3.HeaderMatcher_RangeMatch:
+		desc = fmt.Sprintf("in [%d, %d)", spec.RangeMatch.GetStart(), spec.RangeMatch.GetEnd())
+	case *routev3.HeaderMatcher_PresentMatch:
+		desc = "present"
+		if !spec.PresentMatch {
+			desc = "absent"
+		}
+	case *routev3.HeaderMatcher_PrefixMatch:
+		desc = fmt.Sprintf("prefix %q", spec.PrefixMatch)
+	case *routev3.HeaderMatcher_SuffixMatch:
+		desc = fmt.Sprintf("suffix %q", spec.SuffixMatch)
+	case *routev3.HeaderMatcher_ContainsMatch:
+		desc = fmt.Sprintf("contains %q", spec.ContainsMatch)
+	case *routev3.HeaderMatcher_StringMatch:
+		desc = describeString(spec.StringMatch)
+	default:
+		desc = "present"
+	}
+	if h.GetInvertMatch() {
+		desc = "not " + desc
+	}
+	return h.GetName() + " " + desc
+}
+
+func describeString(m *matcherv3.StringMatcher) string {
+	var desc string
+	switch spec := m.GetMatchPattern().(type) {
+	case *matcherv3.StringMatcher_Exact:
+		desc = fmt.Sprintf("exact %q", spec.Exact)
+	case *matcherv3.StringMatcher_Prefix:
+		desc = fmt.Sprintf("prefix %q", spec.Prefix)
+	case *matcherv3.StringMatcher_Suffix:
+		desc = fmt.Sprintf("suffix %q", spec.Suffix)
+	case *matcherv3.StringMatcher_Contains:
+		desc = fmt.Sprintf("contains %q", spec.Contains)
+	case *matcherv3.StringMatcher_SafeRegex:
+		desc = fmt.Sprintf("regex %q", spec.SafeRegex.GetRegex())
+	}
+	if m.GetIgnoreCase() {
+		desc += " (ignore case)"
+	}
+	return desc
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alibaba/higress/hgctl/pkg/analyze"
+)
+
+const routeDump = `[
+  {
+    "name": "http.80",
+    "virtual_hosts": [
+      {
+        "name": "example.com:80",
+        "domains": ["example.com"],
+        "routes": [
+          {
+            "name": "default/foo-canary",
+            "match": {
+              "prefix": "/api",
+              "headers": [{"name": "x-canary", "string_match": {"exact": "true"}}]
+            },
+            "route": {"cluster": "outbound|80||foo-canary.default.svc.cluster.local"}
+          },
+          {
+            "name": "default/foo",
+            "match": {
+              "prefix": "/api",
+              "query_parameters": [{"name": "debug", "present_match": true}]
+            },
+            "route": {"cluster": "outbound|80||foo-debug.default.svc.cluster.local"}
+          },
+          {
+            "name": "default/foo",
+            "match": {"prefix": "/api"},
+            "route": {
+              "weighted_clusters": {
+                "clusters": [
+                  {"name": "outbound|80||foo.default.svc.cluster.local", "weight": 80},
+                  {"name": "outbound|80||foo-canary.default.svc.cluster.local", "weight": 20}
+                ]
+              }
+            }
+          },
+          {
+            "name": "bar",
+            "match": {"safe_regex": {"regex": "/v[0-9]+/.*"}, "case_sensitive": false},
+            "route": {"cluster": "outbound|8080||bar.higress-system.svc.cluster.local"}
+          }
+        ]
+      },
+      {
+        "name": "wildcard.example.com:80",
+        "domains": ["*.example.com"],
+        "routes": [
+          {
+            "name": "default/redirect",
+            "match": {"path": "/login"},
+            "redirect": {"https_redirect": true}
+          }
+        ]
+      }
+    ]
+  }
+]`
+
+const pluginManifests = `apiVersion: extensions.higress.io/v1alpha1
+kind: WasmPlugin
+metadata:
+  name: key-auth
+  namespace: higress-system
+spec:
+  phase: AUTHN
+  priority: 310
+  url: oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/key-auth:1.0.0
+  matchRules:
+  - domain:
+    - "*.example.com"
+    config: {}
+  - ingress:
+    - default/foo
+    config: {}
+---
+apiVersion: extensions.higress.io/v1alpha1
+kind: WasmPlugin
+metadata:
+  name: request-block
+  namespace: higress-system
+spec:
+  url: oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/request-block:1.0.0
+  defaultConfig:
+    block_urls:
+    - swagger.html
+---
+apiVersion: extensions.higress.io/v1alpha1
+kind: WasmPlugin
+metadata:
+  name: bar-auth
+  namespace: higress-system
+spec:
+  phase: AUTHN
+  priority: 100
+  url: oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/basic-auth:1.0.0
+  matchRules:
+  - service:
+    - bar.higress-system.svc.cluster.local:8080
+    config: {}
+`
+
+func TestExplain(t *testing.T) {
+	routeConfigs, err := LoadRouteConfigs([]byte(routeDump))
+	require.NoError(t, err)
+	require.Len(t, routeConfigs, 1)
+
+	testCases := []struct {
+		name        string
+		req         *Request
+		virtualHost string
+		route       string
+		clusters    []Cluster
+		redirect    string
+	}{
+		{
+			name:        "header match",
+			req:         NewRequest("example.com", "/api/users", "GET", map[string]string{"X-Canary": "true"}),
+			virtualHost: "example.com:80",
+			route:       "default/foo-canary",
+			clusters:    []Cluster{{Name: "outbound|80||foo-canary.default.svc.cluster.local", Percent: 100}},
+		},
+		{
+			name:        "query match",
+			req:         NewRequest("example.com:80", "/api/users?debug", "GET", nil),
+			virtualHost: "example.com:80",
+			route:       "default/foo",
+			clusters:    []Cluster{{Name: "outbound|80||foo-debug.default.svc.cluster.local", Percent: 100}},
+		},
+		{
+			name:        "weighted clusters",
+			req:         NewRequest("example.com", "/api/users", "GET", map[string]string{"x-canary": "false"}),
+			virtualHost: "example.com:80",
+			route:       "default/foo",
+			clusters: []Cluster{
+				{Name: "outbound|80||foo.default.svc.cluster.local", Weight: 80, Percent: 80},
+				{Name: "outbound|80||foo-canary.default.svc.cluster.local", Weight: 20, Percent: 20},
+			},
+		},
+		{
+			name:        "regex ignoring case",
+			req:         NewRequest("example.com", "/V2/items?page=1", "GET", nil),
+			virtualHost: "example.com:80",
+			route:       "bar",
+			clusters:    []Cluster{{Name: "outbound|8080||bar.higress-system.svc.cluster.local", Percent: 100}},
+		},
+		{
+			name:        "no route",
+			req:         NewRequest("example.com", "/v2", "GET", nil),
+			virtualHost: "example.com:80",
+		},
+		{
+			name:        "wildcard domain",
+			req:         NewRequest("www.example.com", "/login", "GET", nil),
+			virtualHost: "wildcard.example.com:80",
+			route:       "default/redirect",
+			redirect:    "scheme=https code=moved_permanently",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			explanations, err := Explain(routeConfigs, testCase.req)
+			require.NoError(t, err)
+			require.Len(t, explanations, 1)
+			e := explanations[0]
+			assert.Equal(t, "http.80", e.RouteConfig)
+			assert.Equal(t, testCase.virtualHost, e.VirtualHost)
+			assert.Equal(t, testCase.route, e.Route)
+			assert.Equal(t, testCase.clusters, e.Clusters)
+			assert.Equal(t, testCase.redirect, e.Redirect)
+		})
+	}
+
+	explanations, err := Explain(routeConfigs, NewRequest("other.com", "/", "GET", nil))
+	require.NoError(t, err)
+	assert.Empty(t, explanations)
+}
+
+func TestMatchPlugins(t *testing.T) {
+	routeConfigs, err := LoadRouteConfigs([]byte(routeDump))
+	require.NoError(t, err)
+	resources, err := analyze.ParseManifests("plugins.yaml", []byte(pluginManifests))
+	require.NoError(t, err)
+	plugins := wasmPlugins(resources)
+
+	explain := func(host, path string) *Explanation {
+		explanations, err := Explain(routeConfigs, NewRequest(host, path, "GET", nil))
+		require.NoError(t, err)
+		require.Len(t, explanations, 1)
+		return explanations[0]
+	}
+
+	e := explain("example.com", "/api")
+	assert.Equal(t, []PluginMatch{
+		{Name: "higress-system/key-auth", Phase: "AUTHN", Priority: 310, Rule: 1, MatchedBy: MatchedByIngress, Value: "default/foo"},
+		{Name: "higress-system/request-block", Phase: "UNSPECIFIED_PHASE", Rule: -1, MatchedBy: MatchedByDefault},
+	}, MatchPlugins(plugins, e, "example.com", "higress-system"))
+
+	e = explain("example.com", "/v1/items")
+	assert.Equal(t, []PluginMatch{
+		{Name: "higress-system/bar-auth", Phase: "AUTHN", Priority: 100, Rule: 0, MatchedBy: MatchedByService, Value: "bar.higress-system.svc.cluster.local:8080"},
+		{Name: "higress-system/request-block", Phase: "UNSPECIFIED_PHASE", Rule: -1, MatchedBy: MatchedByDefault},
+	}, MatchPlugins(plugins, e, "example.com", "higress-system"))
+
+	e = explain("www.example.com:80", "/login")
+	assert.Equal(t, []PluginMatch{
+		{Name: "higress-system/key-auth", Phase: "AUTHN", Priority: 310, Rule: 0, MatchedBy: MatchedByDomain, Value: "*.example.com"},
+		{Name: "higress-system/request-block", Phase: "UNSPECIFIED_PHASE", Rule: -1, MatchedBy: MatchedByDefault},
+	}, MatchPlugins(plugins, e, "www.example.com:80", "higress-system"))
+}
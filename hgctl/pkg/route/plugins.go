@@ -0,0 +1,152 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	extensionsv1alpha1 "github.com/alibaba/higress/v2/api/extensions/v1alpha1"
+	extv1alpha1 "github.com/alibaba/higress/v2/client/pkg/apis/extensions/v1alpha1"
+)
+
+const (
+	MatchedByIngress = "ingress"
+	MatchedByService = "service"
+	MatchedByDomain  = "domain"
+	MatchedByDefault = "default"
+)
+
+// PluginMatch is a WasmPlugin applied to the matched route
+type PluginMatch struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Priority int32  `json:"priority"`
+	// Rule is the index of the effective match rule, -1 if the default config takes effect
+	Rule      int    `json:"rule"`
+	MatchedBy string `json:"matchedBy"`
+	Value     string `json:"value,omitempty"`
+}
+
+// MatchPlugins finds the WasmPlugins applied to the route of the explanation. As the plugin sdk does, the ingress
+// and service rules take precedence over the domain rules, then the default config applies if nothing matches.
+// The routes of the ingresses in systemNamespace are named without namespace.
+func MatchPlugins(plugins []*extv1alpha1.WasmPlugin, e *Explanation, host, systemNamespace string) []PluginMatch {
+	if e.Route == "" {
+		return nil
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	var matches []PluginMatch
+	for _, p := range plugins {
+		m, ok := matchPlugin(p, e, host, systemNamespace)
+		if !ok {
+			continue
+		}
+		m.Name = p.Namespace + "/" + p.Name
+		m.Phase = p.Spec.GetPhase().String()
+		m.Priority = p.Spec.GetPriority().GetValue()
+		matches = append(matches, m)
+	}
+	sortPlugins(matches)
+	return matches
+}
+
+func matchPlugin(p *extv1alpha1.WasmPlugin, e *Explanation, host, systemNamespace string) (PluginMatch, bool) {
+	rules := p.Spec.GetMatchRules()
+	for i, rule := range rules {
+		if rule.GetConfigDisable().GetValue() {
+			continue
+		}
+		for _, ing := range rule.GetIngress() {
+			if rule.GetRouteType() == extensionsv1alpha1.RouteType_HTTP && routeOfIngress(ing, systemNamespace) == e.Route {
+				return PluginMatch{Rule: i, MatchedBy: MatchedByIngress, Value: ing}, true
+			}
+		}
+		for _, svc := range rule.GetService() {
+			if matchService(svc, e.Clusters) {
+				return PluginMatch{Rule: i, MatchedBy: MatchedByService, Value: svc}, true
+			}
+		}
+	}
+	for i, rule := range rules {
+		if rule.GetConfigDisable().GetValue() {
+			continue
+		}
+		for _, domain := range rule.GetDomain() {
+			if matchDomain(domain, host) {
+				return PluginMatch{Rule: i, MatchedBy: MatchedByDomain, Value: domain}, true
+			}
+		}
+	}
+	if p.Spec.GetDefaultConfig() != nil && !p.Spec.GetDefaultConfigDisable().GetValue() {
+		return PluginMatch{Rule: -1, MatchedBy: MatchedByDefault}, true
+	}
+	return PluginMatch{}, false
+}
+
+func routeOfIngress(ingress, systemNamespace string) string {
+	if ns, name, ok := strings.Cut(ingress, "/"); ok && ns == systemNamespace {
+		return name
+	}
+	return ingress
+}
+
+// matchService matches the service of the rule, e.g. foo.default.svc.cluster.local or with the port,
+// against the clusters named like outbound|80||foo.default.svc.cluster.local
+func matchService(service string, clusters []Cluster) bool {
+	host, port := service, ""
+	if h, p, err := net.SplitHostPort(service); err == nil {
+		host, port = h, p
+	}
+	for _, c := range clusters {
+		parts := strings.Split(c.Name, "|")
+		if len(parts) != 4 {
+			continue
+		}
+		if parts[3] == host && (port == "" || parts[1] == port) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDomain(domain, host string) bool {
+	domain = strings.ToLower(domain)
+	if strings.HasPrefix(domain, "*") {
+		return strings.HasSuffix(host, domain[1:])
+	}
+	return domain == host
+}
+
+// sortPlugins orders the plugins as they are executed, by phase then by priority in descending order,
+// the plugins without phase are executed last
+func sortPlugins(matches []PluginMatch) {
+	phaseOrder := func(phase string) int32 {
+		v := extensionsv1alpha1.PluginPhase_value[phase]
+		if v == int32(extensionsv1alpha1.PluginPhase_UNSPECIFIED_PHASE) {
+			return int32(len(extensionsv1alpha1.PluginPhase_value))
+		}
+		return v
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Phase != matches[j].Phase {
+			return phaseOrder(matches[i].Phase) < phaseOrder(matches[j].Phase)
+		}
+		return matches[i].Priority > matches[j].Priority
+	})
+}
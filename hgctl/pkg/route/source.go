@@ -0,0 +1,169 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"istio.io/istio/pkg/util/configdump"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/alibaba/higress/hgctl/pkg/analyze"
+	"github.com/alibaba/higress/hgctl/pkg/kubernetes"
+	extv1alpha1 "github.com/alibaba/higress/v2/client/pkg/apis/extensions/v1alpha1"
+	"github.com/alibaba/higress/v2/client/pkg/clientset/versioned"
+)
+
+const (
+	routesConfigDumpType   = "type.googleapis.com/envoy.admin.v3.RoutesConfigDump"
+	routeConfigurationType = "type.googleapis.com/envoy.config.route.v3.RouteConfiguration"
+)
+
+// LoadRouteConfigs reads the route configurations from the config dump of the gateway. The route dump printed by
+// hgctl gateway-config route -o json|yaml and a single route configuration are accepted as well.
+func LoadRouteConfigs(data []byte) ([]*routev3.RouteConfiguration, error) {
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	data, err = normalizeConfigDump(data)
+	if err != nil {
+		return nil, err
+	}
+	wrapper := &configdump.Wrapper{}
+	if err = json.Unmarshal(data, wrapper); err != nil {
+		return nil, fmt.Errorf("invalid config dump: %v", err)
+	}
+	routeDump, err := wrapper.GetRouteConfigDump()
+	if err != nil {
+		return nil, err
+	}
+	var routeConfigs []*routev3.RouteConfiguration
+	for _, rc := range routeDump.GetStaticRouteConfigs() {
+		routeConfig := &routev3.RouteConfiguration{}
+		if err = rc.GetRouteConfig().UnmarshalTo(routeConfig); err != nil {
+			return nil, err
+		}
+		routeConfigs = append(routeConfigs, routeConfig)
+	}
+	for _, rc := range routeDump.GetDynamicRouteConfigs() {
+		routeConfig := &routev3.RouteConfiguration{}
+		if err = rc.GetRouteConfig().UnmarshalTo(routeConfig); err != nil {
+			return nil, err
+		}
+		routeConfigs = append(routeConfigs, routeConfig)
+	}
+	return routeConfigs, nil
+}
+
+// normalizeConfigDump wraps the route dumps into a config dump so that the any types are resolved the same way
+func normalizeConfigDump(data []byte) ([]byte, error) {
+	var routeConfigs []map[string]json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(data, &routeConfigs); err != nil {
+			return nil, err
+		}
+	} else {
+		obj := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		if _, ok := obj["configs"]; ok {
+			return data, nil
+		}
+		if !hasAnyKey(obj, "dynamic_route_configs", "dynamicRouteConfigs", "static_route_configs", "staticRouteConfigs") {
+			if !hasAnyKey(obj, "virtual_hosts", "virtualHosts") {
+				return nil, fmt.Errorf("no route configuration found")
+			}
+			routeConfigs = append(routeConfigs, obj)
+		} else {
+			obj["@type"] = json.RawMessage(`"` + routesConfigDumpType + `"`)
+			return wrapConfigs(obj)
+		}
+	}
+
+	var staticRouteConfigs []map[string]interface{}
+	for _, rc := range routeConfigs {
+		rc["@type"] = json.RawMessage(`"` + routeConfigurationType + `"`)
+		staticRouteConfigs = append(staticRouteConfigs, map[string]interface{}{"route_config": rc})
+	}
+	return wrapConfigs(map[string]interface{}{
+		"@type":                routesConfigDumpType,
+		"static_route_configs": staticRouteConfigs,
+	})
+}
+
+func wrapConfigs(routeDump interface{}) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"configs": []interface{}{routeDump},
+	})
+}
+
+func hasAnyKey(obj map[string]json.RawMessage, keys ...string) bool {
+	for _, k := range keys {
+		if _, ok := obj[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadPluginFiles reads the WasmPlugins from the manifest files, the resources of the other kinds are skipped
+func ReadPluginFiles(paths []string, stdin io.Reader) ([]*extv1alpha1.WasmPlugin, error) {
+	resources, err := analyze.ReadFiles(paths, stdin)
+	if err != nil {
+		return nil, err
+	}
+	return wasmPlugins(resources), nil
+}
+
+func wasmPlugins(resources []*analyze.Resource) []*extv1alpha1.WasmPlugin {
+	var plugins []*extv1alpha1.WasmPlugin
+	for _, r := range resources {
+		if p, ok := r.Object.(*extv1alpha1.WasmPlugin); ok {
+			if p.Namespace == "" {
+				p.Namespace = r.Namespace
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// ReadClusterPlugins lists the WasmPlugins in the namespace of higress
+func ReadClusterPlugins(client kubernetes.CLIClient, namespace string) ([]*extv1alpha1.WasmPlugin, error) {
+	if client.RESTConfig() == nil {
+		return nil, fmt.Errorf("kubernetes config is not available")
+	}
+	higressClient, err := versioned.NewForConfig(client.RESTConfig())
+	if err != nil {
+		return nil, err
+	}
+	list, err := higressClient.ExtensionsV1alpha1().WasmPlugins(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wasmplugins: %v", err)
+	}
+	var plugins []*extv1alpha1.WasmPlugin
+	for i := range list.Items {
+		plugins = append(plugins, &list.Items[i])
+	}
+	return plugins, nil
+}
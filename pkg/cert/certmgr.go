@@ -33,6 +33,9 @@ const (
 
 var (
 	cfg *certmagic.Config
+	// dnsCfg issues certificates through DNS-01 challenge, since certmagic
+	// disables the other challenges once the DNS-01 solver is set.
+	dnsCfg *certmagic.Config
 )
 
 type CertMgr struct {
@@ -44,6 +47,9 @@ type CertMgr struct {
 	cache         *certmagic.Cache
 	myACME        *certmagic.ACMEIssuer
 	ingressSolver acmez.Solver
+	dnsCfg        *certmagic.Config
+	dnsACME       *certmagic.ACMEIssuer
+	dnsSolver     acmez.Solver
 	configMgr     *ConfigMgr
 	secretMgr     *SecretMgr
 	XDSUpdater    istiomodel.XDSUpdater
@@ -69,12 +75,20 @@ func InitCertMgr(opts *Option, clientSet kubernetes.Interface, config *Config, X
 			// Here we use New to get a valid Config associated with the same cache.
 			// The provided Config is used as a template and will be completed with
 			// any defaults that are set in the Default config.
+			if currentConfig := configMgr.GetConfig(); currentConfig != nil {
+				for _, name := range cert.Names {
+					if currentConfig.GetDNS01ConfigByDomain(name) != nil {
+						return dnsCfg, nil
+					}
+				}
+			}
 			return cfg, nil
 		},
 		Logger: logger,
 	})
 	// init certmagic
 	cfg = certmagic.New(cache, magicConfig)
+	dnsCfg = certmagic.New(cache, magicConfig)
 
 	// Init certmagic acme
 	issuer := config.GetIssuer(IssuerTypeLetsencrypt)
@@ -97,6 +111,16 @@ func InitCertMgr(opts *Option, clientSet kubernetes.Interface, config *Config, X
 	// init issuers
 	cfg.Issuers = []certmagic.Issuer{myACME}
 
+	dnsACME := certmagic.NewACMEIssuer(dnsCfg, certmagic.ACMEIssuer{
+		CA:     certmagic.LetsEncryptProductionCA,
+		Email:  issuer.Email,
+		Agreed: true,
+	})
+	// inject dns01 solver, which picks the dns provider by domain
+	dnsSolver, _ := NewDNS01Solver(opts.Namespace, clientSet, configMgr)
+	dnsACME.DNS01Solver = dnsSolver
+	dnsCfg.Issuers = []certmagic.Issuer{dnsACME}
+
 	secretMgr, _ := NewSecretMgr(opts.Namespace, clientSet)
 
 	certMgr := &CertMgr{
@@ -105,12 +129,16 @@ func InitCertMgr(opts *Option, clientSet kubernetes.Interface, config *Config, X
 		namespace:     opts.Namespace,
 		myACME:        myACME,
 		ingressSolver: ingressSolver,
+		dnsCfg:        dnsCfg,
+		dnsACME:       dnsACME,
+		dnsSolver:     dnsSolver,
 		configMgr:     configMgr,
 		secretMgr:     secretMgr,
 		cache:         cache,
 		XDSUpdater:    XDSUpdater,
 	}
 	certMgr.cfg.OnEvent = certMgr.OnEvent
+	certMgr.dnsCfg.OnEvent = certMgr.OnEvent
 	return certMgr, nil
 }
 func (s *CertMgr) Reconcile(ctx context.Context, oldConfig *Config, newConfig *Config) error {
@@ -126,6 +154,7 @@ func (s *CertMgr) Reconcile(ctx context.Context, oldConfig *Config, newConfig *C
 
 	// sync domains
 	newDomains := make([]string, 0)
+	newDNSDomains := make([]string, 0)
 	newDomainsMap := make(map[string]string, 0)
 	removeDomains := make([]string, 0)
 
@@ -133,7 +162,11 @@ func (s *CertMgr) Reconcile(ctx context.Context, oldConfig *Config, newConfig *C
 		for _, config := range newConfig.CredentialConfig {
 			if config.TLSIssuer == IssuerTypeLetsencrypt {
 				for _, newDomain := range config.Domains {
-					newDomains = append(newDomains, newDomain)
+					if config.DNS01 != nil {
+						newDNSDomains = append(newDNSDomains, newDomain)
+					} else {
+						newDomains = append(newDomains, newDomain)
+					}
 					newDomainsMap[newDomain] = newDomain
 				}
 
@@ -160,15 +193,19 @@ func (s *CertMgr) Reconcile(ctx context.Context, oldConfig *Config, newConfig *C
 		s.cleanSync(context.Background(), removeDomains)
 		// sync email
 		s.myACME.Email = newIssuer.Email
+		s.dnsACME.Email = newIssuer.Email
 		// sync RenewalWindowRatio
 		renewalWindowRatio := float64(newConfig.RenewBeforeDays) / float64(RenewMaxDays)
 		s.cfg.RenewalWindowRatio = renewalWindowRatio
+		s.dnsCfg.RenewalWindowRatio = renewalWindowRatio
 		// start cache
 		s.cache.Start()
 		// sync domains
 		s.configMgr.SetConfig(newConfig)
 		CertLog.Infof("certMgr start to manageSync domains: %+v", newDomains)
 		s.manageSync(context.Background(), newDomains)
+		CertLog.Infof("certMgr start to manageSync dns01 domains: %+v", newDNSDomains)
+		s.manageDNSSync(context.Background(), newDNSDomains)
 		CertLog.Infof("certMgr manageSync domains done")
 	} else {
 		// stop cache  maintainAssets
@@ -194,6 +231,11 @@ func (s *CertMgr) manageSync(ctx context.Context, domainNames []string) error {
 	return s.cfg.ManageSync(ctx, domainNames)
 }
 
+func (s *CertMgr) manageDNSSync(ctx context.Context, domainNames []string) error {
+	CertLog.Infof("cert manage dns01 sync domains:%v", domainNames)
+	return s.dnsCfg.ManageSync(ctx, domainNames)
+}
+
 func (s *CertMgr) cleanSync(ctx context.Context, domainNames []string) error {
 	//TODO implement clean up domains
 	CertLog.Infof("cert clean sync domains:%v", domainNames)
@@ -86,6 +86,34 @@ func (c *Config) GetSecretNameByDomain(issuerName IssuerName, domain string) str
 	return ""
 }
 
+// GetDNS01ConfigByDomain returns the dns01 config of the credential entry which
+// issues certificate for the domain through DNS-01 challenge. The challenge of a
+// wildcard authorization is identified by the base domain, so the credential entry
+// of the wildcard domain is used when none matches the domain itself.
+func (c *Config) GetDNS01ConfigByDomain(domain string) *DNS01Config {
+	if dns01 := c.getDNS01ConfigByDomain(domain); dns01 != nil {
+		return dns01
+	}
+	if strings.HasPrefix(domain, "*.") {
+		return nil
+	}
+	return c.getDNS01ConfigByDomain("*." + domain)
+}
+
+func (c *Config) getDNS01ConfigByDomain(domain string) *DNS01Config {
+	for _, credential := range c.CredentialConfig {
+		if credential.TLSIssuer != IssuerTypeLetsencrypt || credential.DNS01 == nil {
+			continue
+		}
+		for _, credDomain := range credential.Domains {
+			if host.Name(strings.ToLower(domain)).SubsetOf(host.Name(strings.ToLower(credDomain))) {
+				return credential.DNS01
+			}
+		}
+	}
+	return nil
+}
+
 func ParseTLSSecret(tlsSecret string) (string, string) {
 	secrets := strings.Split(tlsSecret, "/")
 	switch len(secrets) {
@@ -135,6 +163,17 @@ func (c *Config) Validate() error {
 			if len(credential.Domains) > 1 {
 				return fmt.Errorf("credentialConfig tlsIssuer %s only support one domain", credential.TLSIssuer)
 			}
+			if credential.DNS01 == nil && strings.HasPrefix(credential.Domains[0], "*") {
+				return fmt.Errorf("credentialConfig wildcard domain %s requires dns01 config", credential.Domains[0])
+			}
+		}
+		if credential.DNS01 != nil {
+			if credential.TLSIssuer != IssuerTypeLetsencrypt {
+				return fmt.Errorf("credentialConfig dns01 is only supported by tlsIssuer %s", IssuerTypeLetsencrypt)
+			}
+			if err := credential.DNS01.Validate(); err != nil {
+				return err
+			}
 		}
 		if credential.TLSIssuer != IssuerTypeLetsencrypt && len(credential.TLSIssuer) > 0 {
 			return fmt.Errorf("credential tls issuer %s is not supported", credential.TLSIssuer)
@@ -156,6 +195,9 @@ type CredentialEntry struct {
 	TLSIssuer    IssuerName `json:"tlsIssuer,omitempty"`
 	TLSSecret    string     `json:"tlsSecret,omitempty"`
 	CACertSecret string     `json:"cacertSecret,omitempty"`
+	// DNS01 switches the ACME challenge of the domains from HTTP-01 to DNS-01,
+	// which is required by wildcard domains.
+	DNS01 *DNS01Config `json:"dns01,omitempty"`
 }
 
 type ACMEIssuerEntry struct {
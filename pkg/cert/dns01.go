@@ -0,0 +1,217 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caddyserver/certmagic"
+	"github.com/mholt/acmez"
+	"github.com/mholt/acmez/acme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type DNSProviderType string
+
+const (
+	DNSProviderRFC2136    DNSProviderType = "rfc2136"
+	DNSProviderAliDNS     DNSProviderType = "alidns"
+	DNSProviderCloudflare DNSProviderType = "cloudflare"
+
+	DefaultDNSPropagationTimeoutSeconds = 120
+)
+
+// DNSProvider creates and deletes the TXT records of the ACME DNS-01 challenge.
+type DNSProvider = certmagic.ACMEDNSProvider
+
+// DNSProviderFactory creates a DNSProvider with the options from the credential
+// config and the data of the credential secret.
+type DNSProviderFactory func(config map[string]string, credential map[string][]byte) (DNSProvider, error)
+
+var dnsProviderFactories = map[DNSProviderType]DNSProviderFactory{
+	DNSProviderRFC2136:    NewRFC2136Provider,
+	DNSProviderAliDNS:     NewAliDNSProvider,
+	DNSProviderCloudflare: NewCloudflareProvider,
+}
+
+// DNS01Config enables the ACME DNS-01 challenge for the domains of a credential entry.
+type DNS01Config struct {
+	// Provider is the type of the DNS provider.
+	Provider DNSProviderType `json:"provider"`
+	// CredentialSecret is the secret holding the provider credentials, in the form of name or namespace/name.
+	CredentialSecret string `json:"credentialSecret,omitempty"`
+	// Config holds the non-sensitive provider options.
+	Config map[string]string `json:"config,omitempty"`
+	// PropagationTimeoutSeconds is the maximum time to wait for the TXT record to appear.
+	PropagationTimeoutSeconds int `json:"propagationTimeoutSeconds,omitempty"`
+	// Resolvers are the DNS resolvers used for propagation checks, the system ones are used if empty.
+	Resolvers []string `json:"resolvers,omitempty"`
+}
+
+func (d *DNS01Config) Validate() error {
+	if _, ok := dnsProviderFactories[d.Provider]; !ok {
+		return fmt.Errorf("dns01 provider %s is not supported", d.Provider)
+	}
+	if d.CredentialSecret != "" {
+		ns, secret := ParseTLSSecret(d.CredentialSecret)
+		if ns == "" && secret == "" {
+			return fmt.Errorf("dns01 credentialSecret %s is not supported", d.CredentialSecret)
+		}
+	}
+	if d.PropagationTimeoutSeconds < 0 {
+		return fmt.Errorf("dns01 propagationTimeoutSeconds should not be less than zero")
+	}
+	return nil
+}
+
+// DNS01Solver dispatches the DNS-01 challenges to the DNS provider configured
+// for the challenge domain in the credential config.
+type DNS01Solver struct {
+	client    kubernetes.Interface
+	namespace string
+	configMgr *ConfigMgr
+	solversMu sync.Mutex
+	// solvers holds the solvers of the active challenges, since the records
+	// presented by a solver can only be cleaned up by itself.
+	solvers map[string]*certmagic.DNS01Solver
+}
+
+func NewDNS01Solver(namespace string, client kubernetes.Interface, configMgr *ConfigMgr) (acmez.Solver, error) {
+	solver := &DNS01Solver{
+		namespace: namespace,
+		client:    client,
+		configMgr: configMgr,
+		solvers:   make(map[string]*certmagic.DNS01Solver),
+	}
+	return solver, nil
+}
+
+func (s *DNS01Solver) Present(ctx context.Context, challenge acme.Challenge) error {
+	CertLog.Infof("dns01 solver present challenge:%+v", challenge)
+	solver, err := s.newSolver(ctx, challenge.Identifier.Value)
+	if err != nil {
+		return err
+	}
+	if err := solver.Present(ctx, challenge); err != nil {
+		return err
+	}
+	s.solversMu.Lock()
+	s.solvers[s.getSolverKey(challenge)] = solver
+	s.solversMu.Unlock()
+	return nil
+}
+
+func (s *DNS01Solver) Wait(ctx context.Context, challenge acme.Challenge) error {
+	CertLog.Infof("dns01 solver wait challenge:%+v", challenge)
+	solver := s.getSolver(challenge)
+	if solver == nil {
+		return fmt.Errorf("no dns01 challenge presented for domain %s", challenge.Identifier.Value)
+	}
+	return solver.Wait(ctx, challenge)
+}
+
+func (s *DNS01Solver) CleanUp(ctx context.Context, challenge acme.Challenge) error {
+	CertLog.Infof("dns01 solver cleanup challenge:%+v", challenge)
+	solver := s.getSolver(challenge)
+	if solver == nil {
+		return nil
+	}
+	s.solversMu.Lock()
+	delete(s.solvers, s.getSolverKey(challenge))
+	s.solversMu.Unlock()
+	return solver.CleanUp(ctx, challenge)
+}
+
+func (s *DNS01Solver) getSolverKey(challenge acme.Challenge) string {
+	return challenge.Identifier.Value + "/" + challenge.Token
+}
+
+func (s *DNS01Solver) getSolver(challenge acme.Challenge) *certmagic.DNS01Solver {
+	s.solversMu.Lock()
+	defer s.solversMu.Unlock()
+	return s.solvers[s.getSolverKey(challenge)]
+}
+
+func (s *DNS01Solver) newSolver(ctx context.Context, domain string) (*certmagic.DNS01Solver, error) {
+	config := s.configMgr.GetConfig()
+	if config == nil {
+		return nil, fmt.Errorf("no config found for dns01 challenge of domain %s", domain)
+	}
+	dns01 := config.GetDNS01ConfigByDomain(domain)
+	if dns01 == nil {
+		return nil, fmt.Errorf("no dns01 config found for domain %s", domain)
+	}
+	credential, err := s.getCredential(ctx, dns01.CredentialSecret)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := NewDNSProvider(dns01, credential)
+	if err != nil {
+		return nil, err
+	}
+	propagationTimeout := dns01.PropagationTimeoutSeconds
+	if propagationTimeout == 0 {
+		propagationTimeout = DefaultDNSPropagationTimeoutSeconds
+	}
+	return &certmagic.DNS01Solver{
+		DNSProvider:        provider,
+		PropagationTimeout: time.Duration(propagationTimeout) * time.Second,
+		Resolvers:          dns01.Resolvers,
+	}, nil
+}
+
+func (s *DNS01Solver) getCredential(ctx context.Context, credentialSecret string) (map[string][]byte, error) {
+	if credentialSecret == "" {
+		return nil, nil
+	}
+	namespace, name := ParseTLSSecret(credentialSecret)
+	if namespace == "" {
+		namespace = s.namespace
+	}
+	secret, err := s.client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get dns01 credential secret %s/%s error: %v", namespace, name, err)
+	}
+	return secret.Data, nil
+}
+
+// NewDNSProvider creates the DNS provider of the dns01 config.
+func NewDNSProvider(dns01 *DNS01Config, credential map[string][]byte) (DNSProvider, error) {
+	factory, ok := dnsProviderFactories[dns01.Provider]
+	if !ok {
+		return nil, fmt.Errorf("dns01 provider %s is not supported", dns01.Provider)
+	}
+	config := dns01.Config
+	if config == nil {
+		config = map[string]string{}
+	}
+	if credential == nil {
+		credential = map[string][]byte{}
+	}
+	return factory(config, credential)
+}
+
+func getDNSProviderCredential(credential map[string][]byte, key string) (string, error) {
+	value := strings.TrimSpace(string(credential[key]))
+	if value == "" {
+		return "", fmt.Errorf("%s is not found in dns01 credential secret", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDNS01Config(t *testing.T) {
+	tests := []struct {
+		name       string
+		credential CredentialEntry
+		expectErr  bool
+	}{
+		{
+			name: "wildcard domain with dns01",
+			credential: CredentialEntry{
+				Domains:   []string{"*.example.com"},
+				TLSIssuer: IssuerTypeLetsencrypt,
+				TLSSecret: "wildcard-example-com-tls",
+				DNS01: &DNS01Config{
+					Provider:         DNSProviderRFC2136,
+					CredentialSecret: "higress-system/rfc2136-credential",
+					Config:           map[string]string{RFC2136ConfigNameserver: "127.0.0.1:53"},
+				},
+			},
+		},
+		{
+			name: "wildcard domain without dns01",
+			credential: CredentialEntry{
+				Domains:   []string{"*.example.com"},
+				TLSIssuer: IssuerTypeLetsencrypt,
+				TLSSecret: "wildcard-example-com-tls",
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown dns provider",
+			credential: CredentialEntry{
+				Domains:   []string{"example.com"},
+				TLSIssuer: IssuerTypeLetsencrypt,
+				TLSSecret: "example-com-tls",
+				DNS01:     &DNS01Config{Provider: "unknown"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "dns01 without issuer",
+			credential: CredentialEntry{
+				Domains:   []string{"example.com"},
+				TLSSecret: "example-com-tls",
+				DNS01:     &DNS01Config{Provider: DNSProviderCloudflare},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid credential secret",
+			credential: CredentialEntry{
+				Domains:   []string{"example.com"},
+				TLSIssuer: IssuerTypeLetsencrypt,
+				TLSSecret: "example-com-tls",
+				DNS01:     &DNS01Config{Provider: DNSProviderAliDNS, CredentialSecret: "a/b/c"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newDefaultConfig("test@example.com")
+			cfg.CredentialConfig = []CredentialEntry{tt.credential}
+			err := cfg.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetDNS01ConfigByDomain(t *testing.T) {
+	dns01 := &DNS01Config{Provider: DNSProviderCloudflare, CredentialSecret: "cloudflare-credential"}
+	cfg := Config{
+		CredentialConfig: []CredentialEntry{
+			{
+				Domains:   []string{"example.com"},
+				TLSIssuer: IssuerTypeLetsencrypt,
+				TLSSecret: "example-com-tls",
+			},
+			{
+				Domains:   []string{"*.example.com"},
+				TLSIssuer: IssuerTypeLetsencrypt,
+				TLSSecret: "wildcard-example-com-tls",
+				DNS01:     dns01,
+			},
+		},
+	}
+	// acmez identifies the challenge of the wildcard authorization by the base domain
+	assert.Equal(t, dns01, cfg.GetDNS01ConfigByDomain("example.com"))
+	assert.Equal(t, dns01, cfg.GetDNS01ConfigByDomain("*.example.com"))
+	assert.Equal(t, dns01, cfg.GetDNS01ConfigByDomain("www.Example.com"))
+	assert.Nil(t, cfg.GetDNS01ConfigByDomain("example.org"))
+	assert.Nil(t, cfg.GetDNS01ConfigByDomain("*.example.org"))
+
+	// the credential entry of the domain itself takes precedence
+	apexDNS01 := &DNS01Config{Provider: DNSProviderRFC2136}
+	cfg.CredentialConfig[0].DNS01 = apexDNS01
+	assert.Equal(t, apexDNS01, cfg.GetDNS01ConfigByDomain("example.com"))
+	assert.Equal(t, dns01, cfg.GetDNS01ConfigByDomain("www.example.com"))
+}
+
+func TestNewDNSProvider(t *testing.T) {
+	_, err := NewDNSProvider(&DNS01Config{Provider: DNSProviderRFC2136}, nil)
+	assert.Error(t, err, "nameserver is required")
+
+	_, err = NewDNSProvider(&DNS01Config{
+		Provider: DNSProviderRFC2136,
+		Config: map[string]string{
+			RFC2136ConfigNameserver:  "127.0.0.1",
+			RFC2136ConfigTsigKeyName: "acme",
+		},
+	}, nil)
+	assert.Error(t, err, "tsig secret is required")
+
+	_, err = NewDNSProvider(&DNS01Config{Provider: DNSProviderAliDNS}, map[string][]byte{
+		AliDNSCredentialAccessKeyId: []byte("ak"),
+	})
+	assert.Error(t, err, "access key secret is required")
+
+	provider, err := NewDNSProvider(&DNS01Config{Provider: DNSProviderCloudflare}, map[string][]byte{
+		CloudflareCredentialToken: []byte("token\n"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "token", provider.(*CloudflareProvider).apiToken)
+}
+
+// fakeRFC2136Server is a minimal authoritative server accepting TSIG signed updates.
+type fakeRFC2136Server struct {
+	mu      sync.Mutex
+	records map[string]string
+}
+
+func (s *fakeRFC2136Server) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	reply := new(dns.Msg)
+	reply.SetReply(req)
+	if req.IsTsig() == nil || w.TsigStatus() != nil {
+		reply.Rcode = dns.RcodeNotAuth
+	} else {
+		s.mu.Lock()
+		for _, rr := range req.Ns {
+			txt, ok := rr.(*dns.TXT)
+			if !ok {
+				continue
+			}
+			if txt.Hdr.Class == dns.ClassNONE {
+				delete(s.records, txt.Hdr.Name)
+			} else {
+				s.records[txt.Hdr.Name] = txt.Txt[0]
+			}
+		}
+		s.mu.Unlock()
+	}
+	if tsig := req.IsTsig(); tsig != nil {
+		reply.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+	}
+	_ = w.WriteMsg(reply)
+}
+
+func TestRFC2136Provider(t *testing.T) {
+	tsigSecret := base64.StdEncoding.EncodeToString([]byte("higress-test-secret"))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	handler := &fakeRFC2136Server{records: map[string]string{}}
+	server := &dns.Server{
+		Listener:   listener,
+		Net:        "tcp",
+		Handler:    handler,
+		TsigSecret: map[string]string{"acme.": tsigSecret},
+		MsgAcceptFunc: func(dh dns.Header) dns.MsgAcceptAction {
+			return dns.MsgAccept
+		},
+	}
+	go server.ActivateAndServe()
+	defer server.Shutdown()
+
+	provider, err := NewDNSProvider(&DNS01Config{
+		Provider: DNSProviderRFC2136,
+		Config: map[string]string{
+			RFC2136ConfigNameserver:  listener.Addr().String(),
+			RFC2136ConfigTsigKeyName: "acme",
+		},
+	}, map[string][]byte{RFC2136CredentialTsigKey: []byte(tsigSecret)})
+	require.NoError(t, err)
+
+	record := libdns.Record{Type: "TXT", Name: "_acme-challenge", Value: "token"}
+	_, err = provider.AppendRecords(context.Background(), "example.com.", []libdns.Record{record})
+	require.NoError(t, err)
+	handler.mu.Lock()
+	assert.Equal(t, "token", handler.records["_acme-challenge.example.com."])
+	handler.mu.Unlock()
+
+	_, err = provider.DeleteRecords(context.Background(), "example.com.", []libdns.Record{record})
+	require.NoError(t, err)
+	handler.mu.Lock()
+	assert.Empty(t, handler.records)
+	handler.mu.Unlock()
+
+	badProvider, err := NewDNSProvider(&DNS01Config{
+		Provider: DNSProviderRFC2136,
+		Config: map[string]string{
+			RFC2136ConfigNameserver:  listener.Addr().String(),
+			RFC2136ConfigTsigKeyName: "acme",
+		},
+	}, map[string][]byte{RFC2136CredentialTsigKey: []byte(base64.StdEncoding.EncodeToString([]byte("wrong")))})
+	require.NoError(t, err)
+	_, err = badProvider.AppendRecords(context.Background(), "example.com.", []libdns.Record{record})
+	assert.Error(t, err)
+}
+
+func TestAliDNSProvider(t *testing.T) {
+	var actions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		actions = append(actions, query.Get("Action"))
+		params := map[string]string{}
+		for k := range query {
+			if k != "Signature" {
+				params[k] = query.Get(k)
+			}
+		}
+		if aliDNSSignature(http.MethodGet, params, "sk") != query.Get("Signature") {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]string{"Code": "SignatureDoesNotMatch"})
+			return
+		}
+		switch query.Get("Action") {
+		case "AddDomainRecord":
+			assert.Equal(t, "example.com", query.Get("DomainName"))
+			assert.Equal(t, "_acme-challenge", query.Get("RR"))
+			assert.Equal(t, "600", query.Get("TTL"))
+			_ = json.NewEncoder(w).Encode(map[string]string{"RecordId": "1001"})
+		case "DescribeDomainRecords":
+			_, _ = w.Write([]byte(`{"DomainRecords":{"Record":[{"RecordId":"1002","RR":"_acme-challenge","Type":"TXT","Value":"token"}]}}`))
+		case "DeleteDomainRecord":
+			_ = json.NewEncoder(w).Encode(map[string]string{"RecordId": query.Get("RecordId")})
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewDNSProvider(&DNS01Config{
+		Provider: DNSProviderAliDNS,
+		Config:   map[string]string{AliDNSConfigEndpoint: server.URL + "/"},
+	}, map[string][]byte{
+		AliDNSCredentialAccessKeyId:     []byte("ak"),
+		AliDNSCredentialAccessKeySecret: []byte("sk"),
+	})
+	require.NoError(t, err)
+
+	record := libdns.Record{Type: "TXT", Name: "_acme-challenge", Value: "token"}
+	appended, err := provider.AppendRecords(context.Background(), "example.com.", []libdns.Record{record})
+	require.NoError(t, err)
+	require.Len(t, appended, 1)
+	assert.Equal(t, "1001", appended[0].ID)
+
+	_, err = provider.DeleteRecords(context.Background(), "example.com.", appended)
+	require.NoError(t, err)
+	_, err = provider.DeleteRecords(context.Background(), "example.com.", []libdns.Record{record})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AddDomainRecord", "DeleteDomainRecord", "DescribeDomainRecords", "DeleteDomainRecord"}, actions)
+}
+
+func TestCloudflareProvider(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"success":false,"errors":[{"code":10000,"message":"Authentication error"}]}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/zones":
+			assert.Equal(t, "example.com", r.URL.Query().Get("name"))
+			_, _ = w.Write([]byte(`{"success":true,"result":[{"id":"zone1"}]}`))
+		case r.Method == http.MethodPost:
+			record := cloudflareRecord{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&record))
+			assert.Equal(t, "_acme-challenge.example.com", record.Name)
+			assert.Equal(t, "token", record.Content)
+			_, _ = w.Write([]byte(`{"success":true,"result":{"id":"record1"}}`))
+		case r.Method == http.MethodDelete:
+			_, _ = w.Write([]byte(`{"success":true,"result":{"id":"record1"}}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewDNSProvider(&DNS01Config{
+		Provider: DNSProviderCloudflare,
+		Config:   map[string]string{CloudflareConfigEndpoint: server.URL},
+	}, map[string][]byte{CloudflareCredentialToken: []byte("token")})
+	require.NoError(t, err)
+
+	record := libdns.Record{Type: "TXT", Name: "_acme-challenge", Value: "token"}
+	appended, err := provider.AppendRecords(context.Background(), "example.com.", []libdns.Record{record})
+	require.NoError(t, err)
+	require.Len(t, appended, 1)
+	assert.Equal(t, "record1", appended[0].ID)
+
+	_, err = provider.DeleteRecords(context.Background(), "example.com.", appended)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /zones",
+		"POST /zones/zone1/dns_records",
+		"GET /zones",
+		"DELETE /zones/zone1/dns_records/record1",
+	}, requests)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+const (
+	AliDNSConfigEndpoint            = "endpoint"
+	AliDNSCredentialAccessKeyId     = "accessKeyId"
+	AliDNSCredentialAccessKeySecret = "accessKeySecret"
+
+	defaultAliDNSEndpoint  = "https://alidns.aliyuncs.com/"
+	defaultAliDNSRecordTTL = 600 * time.Second
+	aliDNSAPIVersion       = "2015-01-09"
+)
+
+// AliDNSProvider manages the challenge records through the Alibaba Cloud DNS OpenAPI.
+type AliDNSProvider struct {
+	endpoint        string
+	accessKeyId     string
+	accessKeySecret string
+	httpClient      *http.Client
+}
+
+func NewAliDNSProvider(config map[string]string, credential map[string][]byte) (DNSProvider, error) {
+	accessKeyId, err := getDNSProviderCredential(credential, AliDNSCredentialAccessKeyId)
+	if err != nil {
+		return nil, err
+	}
+	accessKeySecret, err := getDNSProviderCredential(credential, AliDNSCredentialAccessKeySecret)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := config[AliDNSConfigEndpoint]
+	if endpoint == "" {
+		endpoint = defaultAliDNSEndpoint
+	}
+	return &AliDNSProvider{
+		endpoint:        endpoint,
+		accessKeyId:     accessKeyId,
+		accessKeySecret: accessKeySecret,
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+type aliDNSRecord struct {
+	RecordId string `json:"RecordId"`
+	RR       string `json:"RR"`
+	Type     string `json:"Type"`
+	Value    string `json:"Value"`
+}
+
+type aliDNSResponse struct {
+	RequestId     string `json:"RequestId"`
+	RecordId      string `json:"RecordId"`
+	Code          string `json:"Code"`
+	Message       string `json:"Message"`
+	DomainRecords struct {
+		Record []aliDNSRecord `json:"Record"`
+	} `json:"DomainRecords"`
+}
+
+func (p *AliDNSProvider) AppendRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	var appended []libdns.Record
+	for _, rec := range recs {
+		ttl := rec.TTL
+		if ttl < defaultAliDNSRecordTTL {
+			ttl = defaultAliDNSRecordTTL
+		}
+		resp, err := p.call(ctx, "AddDomainRecord", map[string]string{
+			"DomainName": strings.TrimSuffix(zone, "."),
+			"RR":         aliDNSRR(rec.Name),
+			"Type":       rec.Type,
+			"Value":      rec.Value,
+			"TTL":        strconv.Itoa(int(ttl.Seconds())),
+		})
+		if err != nil {
+			return appended, err
+		}
+		rec.ID = resp.RecordId
+		appended = append(appended, rec)
+	}
+	return appended, nil
+}
+
+func (p *AliDNSProvider) DeleteRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	var deleted []libdns.Record
+	for _, rec := range recs {
+		recordIds := []string{rec.ID}
+		if rec.ID == "" {
+			ids, err := p.findRecordIds(ctx, zone, rec)
+			if err != nil {
+				return deleted, err
+			}
+			recordIds = ids
+		}
+		for _, recordId := range recordIds {
+			if _, err := p.call(ctx, "DeleteDomainRecord", map[string]string{"RecordId": recordId}); err != nil {
+				return deleted, err
+			}
+		}
+		deleted = append(deleted, rec)
+	}
+	return deleted, nil
+}
+
+func (p *AliDNSProvider) findRecordIds(ctx context.Context, zone string, rec libdns.Record) ([]string, error) {
+	rr := aliDNSRR(rec.Name)
+	resp, err := p.call(ctx, "DescribeDomainRecords", map[string]string{
+		"DomainName":   strings.TrimSuffix(zone, "."),
+		"RRKeyWord":    rr,
+		"TypeKeyWord":  rec.Type,
+		"ValueKeyWord": rec.Value,
+		"PageSize":     "100",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var recordIds []string
+	for _, record := range resp.DomainRecords.Record {
+		if record.RR == rr && record.Type == rec.Type && record.Value == rec.Value {
+			recordIds = append(recordIds, record.RecordId)
+		}
+	}
+	return recordIds, nil
+}
+
+func (p *AliDNSProvider) call(ctx context.Context, action string, params map[string]string) (*aliDNSResponse, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	query := map[string]string{
+		"Action":           action,
+		"Format":           "JSON",
+		"Version":          aliDNSAPIVersion,
+		"AccessKeyId":      p.accessKeyId,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureVersion": "1.0",
+		"SignatureNonce":   hex.EncodeToString(nonce),
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	for k, v := range params {
+		query[k] = v
+	}
+	query["Signature"] = aliDNSSignature(http.MethodGet, query, p.accessKeySecret)
+	values := url.Values{}
+	for k, v := range query {
+		values.Set(k, v)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+"?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	httpResp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("alidns %s error: %v", action, err)
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp := &aliDNSResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("alidns %s invalid response: %s", action, string(body))
+	}
+	if httpResp.StatusCode != http.StatusOK || resp.Code != "" {
+		return nil, fmt.Errorf("alidns %s failed, status:%d, code:%s, message:%s", action, httpResp.StatusCode, resp.Code, resp.Message)
+	}
+	return resp, nil
+}
+
+// aliDNSSignature signs the request with the RPC signature algorithm of Alibaba Cloud OpenAPI.
+func aliDNSSignature(method string, query map[string]string, accessKeySecret string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, aliDNSPercentEncode(k)+"="+aliDNSPercentEncode(query[k]))
+	}
+	stringToSign := method + "&" + aliDNSPercentEncode("/") + "&" + aliDNSPercentEncode(strings.Join(pairs, "&"))
+	mac := hmac.New(sha1.New, []byte(accessKeySecret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func aliDNSPercentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	s = strings.ReplaceAll(s, "%7E", "~")
+	return s
+}
+
+func aliDNSRR(name string) string {
+	if name == "" {
+		return "@"
+	}
+	return name
+}
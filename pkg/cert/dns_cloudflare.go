@@ -0,0 +1,192 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+const (
+	CloudflareConfigEndpoint   = "endpoint"
+	CloudflareConfigZoneId     = "zoneId"
+	CloudflareCredentialToken  = "apiToken"
+	defaultCloudflareEndpoint  = "https://api.cloudflare.com/client/v4"
+	defaultCloudflareRecordTTL = 120 * time.Second
+)
+
+// CloudflareProvider manages the challenge records through the Cloudflare API
+// with an API token which has the Zone.DNS edit permission.
+type CloudflareProvider struct {
+	endpoint   string
+	zoneId     string
+	apiToken   string
+	httpClient *http.Client
+}
+
+func NewCloudflareProvider(config map[string]string, credential map[string][]byte) (DNSProvider, error) {
+	apiToken, err := getDNSProviderCredential(credential, CloudflareCredentialToken)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := config[CloudflareConfigEndpoint]
+	if endpoint == "" {
+		endpoint = defaultCloudflareEndpoint
+	}
+	return &CloudflareProvider{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		zoneId:     config[CloudflareConfigZoneId],
+		apiToken:   apiToken,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+type cloudflareRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl,omitempty"`
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+func (p *CloudflareProvider) AppendRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	zoneId, err := p.getZoneId(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	var appended []libdns.Record
+	for _, rec := range recs {
+		ttl := rec.TTL
+		if ttl < defaultCloudflareRecordTTL {
+			ttl = defaultCloudflareRecordTTL
+		}
+		record := cloudflareRecord{
+			Type:    rec.Type,
+			Name:    strings.TrimSuffix(libdns.AbsoluteName(rec.Name, zone), "."),
+			Content: rec.Value,
+			TTL:     int(ttl.Seconds()),
+		}
+		result := cloudflareRecord{}
+		if err := p.call(ctx, http.MethodPost, "/zones/"+zoneId+"/dns_records", record, &result); err != nil {
+			return appended, err
+		}
+		rec.ID = result.ID
+		appended = append(appended, rec)
+	}
+	return appended, nil
+}
+
+func (p *CloudflareProvider) DeleteRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	zoneId, err := p.getZoneId(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []libdns.Record
+	for _, rec := range recs {
+		recordIds := []string{rec.ID}
+		if rec.ID == "" {
+			query := url.Values{}
+			query.Set("type", rec.Type)
+			query.Set("name", strings.TrimSuffix(libdns.AbsoluteName(rec.Name, zone), "."))
+			query.Set("content", rec.Value)
+			var records []cloudflareRecord
+			if err := p.call(ctx, http.MethodGet, "/zones/"+zoneId+"/dns_records?"+query.Encode(), nil, &records); err != nil {
+				return deleted, err
+			}
+			recordIds = recordIds[:0]
+			for _, record := range records {
+				recordIds = append(recordIds, record.ID)
+			}
+		}
+		for _, recordId := range recordIds {
+			if err := p.call(ctx, http.MethodDelete, "/zones/"+zoneId+"/dns_records/"+recordId, nil, nil); err != nil {
+				return deleted, err
+			}
+		}
+		deleted = append(deleted, rec)
+	}
+	return deleted, nil
+}
+
+func (p *CloudflareProvider) getZoneId(ctx context.Context, zone string) (string, error) {
+	if p.zoneId != "" {
+		return p.zoneId, nil
+	}
+	var zones []struct {
+		ID string `json:"id"`
+	}
+	if err := p.call(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(strings.TrimSuffix(zone, ".")), nil, &zones); err != nil {
+		return "", err
+	}
+	if len(zones) == 0 {
+		return "", fmt.Errorf("cloudflare zone %s is not found", zone)
+	}
+	return zones[0].ID, nil
+}
+
+func (p *CloudflareProvider) call(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.endpoint+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+	httpResp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudflare %s %s error: %v", method, path, err)
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	resp := &cloudflareResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("cloudflare %s %s invalid response: %s", method, path, string(data))
+	}
+	if !resp.Success {
+		return fmt.Errorf("cloudflare %s %s failed, status:%d, errors:%+v", method, path, httpResp.StatusCode, resp.Errors)
+	}
+	if result != nil && len(resp.Result) > 0 {
+		return json.Unmarshal(resp.Result, result)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+)
+
+const (
+	RFC2136ConfigNameserver    = "nameserver"
+	RFC2136ConfigTsigKeyName   = "tsigKeyName"
+	RFC2136ConfigTsigAlgorithm = "tsigAlgorithm"
+	RFC2136CredentialTsigKey   = "tsigSecret"
+
+	defaultRFC2136RecordTTL = 60 * time.Second
+	rfc2136TsigFudge        = 300
+)
+
+// RFC2136Provider manages the challenge records through RFC 2136 dynamic DNS updates,
+// which is supported by BIND, PowerDNS, Knot and most of the authoritative servers.
+type RFC2136Provider struct {
+	nameserver    string
+	tsigKeyName   string
+	tsigAlgorithm string
+	tsigSecret    string
+	timeout       time.Duration
+}
+
+func NewRFC2136Provider(config map[string]string, credential map[string][]byte) (DNSProvider, error) {
+	nameserver := config[RFC2136ConfigNameserver]
+	if nameserver == "" {
+		return nil, fmt.Errorf("rfc2136 %s is empty", RFC2136ConfigNameserver)
+	}
+	if _, _, err := net.SplitHostPort(nameserver); err != nil {
+		nameserver = net.JoinHostPort(nameserver, "53")
+	}
+	provider := &RFC2136Provider{
+		nameserver: nameserver,
+		timeout:    10 * time.Second,
+	}
+	if keyName := config[RFC2136ConfigTsigKeyName]; keyName != "" {
+		secret, err := getDNSProviderCredential(credential, RFC2136CredentialTsigKey)
+		if err != nil {
+			return nil, err
+		}
+		provider.tsigKeyName = dns.Fqdn(keyName)
+		provider.tsigSecret = secret
+		provider.tsigAlgorithm = dns.HmacSHA256
+		if algorithm := config[RFC2136ConfigTsigAlgorithm]; algorithm != "" {
+			provider.tsigAlgorithm = dns.Fqdn(strings.ToLower(algorithm))
+		}
+	}
+	return provider, nil
+}
+
+func (p *RFC2136Provider) AppendRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	rrs, err := p.toRRs(zone, recs)
+	if err != nil {
+		return nil, err
+	}
+	msg := new(dns.Msg)
+	msg.SetUpdate(dns.Fqdn(zone))
+	msg.Insert(rrs)
+	if err := p.exchange(ctx, msg); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+func (p *RFC2136Provider) DeleteRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	rrs, err := p.toRRs(zone, recs)
+	if err != nil {
+		return nil, err
+	}
+	msg := new(dns.Msg)
+	msg.SetUpdate(dns.Fqdn(zone))
+	msg.Remove(rrs)
+	if err := p.exchange(ctx, msg); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+func (p *RFC2136Provider) toRRs(zone string, recs []libdns.Record) ([]dns.RR, error) {
+	rrs := make([]dns.RR, 0, len(recs))
+	for _, rec := range recs {
+		if rec.Type != "TXT" {
+			return nil, fmt.Errorf("rfc2136 record type %s is not supported", rec.Type)
+		}
+		ttl := rec.TTL
+		if ttl == 0 {
+			ttl = defaultRFC2136RecordTTL
+		}
+		rrs = append(rrs, &dns.TXT{
+			Hdr: dns.RR_Header{
+				Name:   dns.Fqdn(libdns.AbsoluteName(rec.Name, dns.Fqdn(zone))),
+				Rrtype: dns.TypeTXT,
+				Class:  dns.ClassINET,
+				Ttl:    uint32(ttl.Seconds()),
+			},
+			Txt: []string{rec.Value},
+		})
+	}
+	return rrs, nil
+}
+
+func (p *RFC2136Provider) exchange(ctx context.Context, msg *dns.Msg) error {
+	client := &dns.Client{
+		Net:     "tcp",
+		Timeout: p.timeout,
+	}
+	if p.tsigKeyName != "" {
+		msg.SetTsig(p.tsigKeyName, p.tsigAlgorithm, rfc2136TsigFudge, time.Now().Unix())
+		client.TsigSecret = map[string]string{p.tsigKeyName: p.tsigSecret}
+	}
+	reply, _, err := client.ExchangeContext(ctx, msg, p.nameserver)
+	if err != nil {
+		return fmt.Errorf("rfc2136 update to %s error: %v", p.nameserver, err)
+	}
+	if reply.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("rfc2136 update to %s failed: %s", p.nameserver, dns.RcodeToString[reply.Rcode])
+	}
+	return nil
+}
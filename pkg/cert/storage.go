@@ -19,15 +19,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"os"
 	"path"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/caddyserver/certmagic"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -35,6 +38,15 @@ const (
 	CertificatesPrefix              = "certificates"
 	ConfigmapStoreCertficatesPrefix = "higress-cert-store-certificates-"
 	ConfigmapStoreDefaultName       = "higress-cert-store-default"
+	LeaseStoreLockPrefix            = "higress-cert-lock-"
+	// LockLeaseDurationSeconds is the TTL of a lock, a lock which is not renewed
+	// within the TTL is considered stale and can be taken over by other holders.
+	LockLeaseDurationSeconds = 60
+)
+
+var (
+	lockRenewInterval = 20 * time.Second
+	lockPollInterval  = 1 * time.Second
 )
 
 var _ certmagic.Storage = (*ConfigmapStorage)(nil)
@@ -43,6 +55,11 @@ type ConfigmapStorage struct {
 	namespace string
 	client    kubernetes.Interface
 	mux       sync.RWMutex
+	// identity is the holder identity of the locks obtained by this storage.
+	identity string
+	locksMu  sync.Mutex
+	// locks holds the cancel functions of the renewal goroutines of the obtained leases.
+	locks map[string]context.CancelFunc
 }
 
 type HashValue struct {
@@ -54,6 +71,8 @@ func NewConfigmapStorage(namespace string, client kubernetes.Interface) (certmag
 	storage := &ConfigmapStorage{
 		namespace: namespace,
 		client:    client,
+		identity:  newLockIdentity(),
+		locks:     make(map[string]context.CancelFunc),
 	}
 	return storage, nil
 }
@@ -276,15 +295,192 @@ func (s *ConfigmapStorage) Stat(_ context.Context, key string) (certmagic.KeyInf
 
 // Lock obtains a lock named by the given name. It blocks
 // until the lock can be obtained or an error is returned.
+// The lock is backed by a coordination.k8s.io Lease, so that only one replica
+// of the controller can issue or renew the certificate of a domain at a time.
 func (s *ConfigmapStorage) Lock(ctx context.Context, name string) error {
-	return nil
+	leaseName := s.getLeaseNameByLockName(name)
+	for {
+		acquired, err := s.tryAcquireLease(ctx, leaseName)
+		if err != nil {
+			CertLog.Errorf("acquire lock %s lease %s/%s error: %v", name, s.namespace, leaseName, err)
+		}
+		if acquired {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("obtain lock %s error: %v", name, ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
 }
 
 // Unlock releases the lock for name.
-func (s *ConfigmapStorage) Unlock(_ context.Context, name string) error {
+func (s *ConfigmapStorage) Unlock(ctx context.Context, name string) error {
+	leaseName := s.getLeaseNameByLockName(name)
+	s.stopLockRenewal(leaseName)
+	lease, err := s.client.CoordinationV1().Leases(s.namespace).Get(ctx, leaseName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if getLeaseHolder(lease) != s.identity {
+		return fmt.Errorf("lock %s is held by %s", name, getLeaseHolder(lease))
+	}
+	err = s.client.CoordinationV1().Leases(s.namespace).Delete(ctx, leaseName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
 	return nil
 }
 
+// tryAcquireLease creates the lease, or takes it over when it is stale. The lease held by
+// this storage is not acquired again until it is unlocked, since the lock is not reentrant.
+// locksMu is held until the acquired lease is registered, so that the concurrent callers of
+// this storage can not take over the lease which is created or updated but not registered yet.
+func (s *ConfigmapStorage) tryAcquireLease(ctx context.Context, leaseName string) (bool, error) {
+	s.locksMu.Lock()
+	defer s.locksMu.Unlock()
+	if _, ok := s.locks[leaseName]; ok {
+		return false, nil
+	}
+	now := metav1.NewMicroTime(time.Now())
+	leaseDurationSeconds := int32(LockLeaseDurationSeconds)
+	leases := s.client.CoordinationV1().Leases(s.namespace)
+	lease, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return false, err
+		}
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.namespace,
+				Name:      leaseName,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &s.identity,
+				LeaseDurationSeconds: &leaseDurationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		if err != nil {
+			if errors.IsAlreadyExists(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		s.startLockRenewal(leaseName)
+		return true, nil
+	}
+
+	holder := getLeaseHolder(lease)
+	if holder != "" && holder != s.identity && !isLeaseExpired(lease, now.Time) {
+		return false, nil
+	}
+	if holder != s.identity {
+		CertLog.Infof("take over lock lease %s/%s from holder %s", s.namespace, leaseName, holder)
+		lease.Spec.AcquireTime = &now
+		transitions := int32(0)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions
+		}
+		transitions++
+		lease.Spec.LeaseTransitions = &transitions
+	}
+	lease.Spec.HolderIdentity = &s.identity
+	lease.Spec.LeaseDurationSeconds = &leaseDurationSeconds
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	if err != nil {
+		if errors.IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	s.startLockRenewal(leaseName)
+	return true, nil
+}
+
+// renewLease refreshes the renew time of the lease held by this storage.
+func (s *ConfigmapStorage) renewLease(ctx context.Context, leaseName string) error {
+	leases := s.client.CoordinationV1().Leases(s.namespace)
+	lease, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if holder := getLeaseHolder(lease); holder != s.identity {
+		return fmt.Errorf("lease %s/%s is held by %s", s.namespace, leaseName, holder)
+	}
+	now := metav1.NewMicroTime(time.Now())
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// startLockRenewal registers the acquired lease and keeps renewing it until it is unlocked,
+// the caller must hold locksMu.
+func (s *ConfigmapStorage) startLockRenewal(leaseName string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.locks[leaseName] = cancel
+	go func() {
+		ticker := time.NewTicker(lockRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.renewLease(ctx, leaseName); err != nil && ctx.Err() == nil {
+					CertLog.Errorf("renew lock lease %s/%s error: %v", s.namespace, leaseName, err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *ConfigmapStorage) stopLockRenewal(leaseName string) {
+	s.locksMu.Lock()
+	defer s.locksMu.Unlock()
+	if cancel, ok := s.locks[leaseName]; ok {
+		cancel()
+		delete(s.locks, leaseName)
+	}
+}
+
+// getLeaseNameByLockName maps the lock name, which is usually a storage key, to a valid lease name.
+func (s *ConfigmapStorage) getLeaseNameByLockName(name string) string {
+	return LeaseStoreLockPrefix + fastHash([]byte(name))
+}
+
+func getLeaseHolder(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+func isLeaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expireTime := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expireTime)
+}
+
+func newLockIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "higress-controller"
+	}
+	return hostname + "_" + string(uuid.NewUUID())
+}
+
 func (s *ConfigmapStorage) String() string {
 	return "ConfigmapStorage"
 }
@@ -17,9 +17,14 @@ package cert
 import (
 	"context"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -330,3 +335,137 @@ func TestList(t *testing.T) {
 		})
 	}
 }
+
+func TestLock(t *testing.T) {
+	lockPollInterval = 10 * time.Millisecond
+	fakeClient := fake.NewSimpleClientset()
+	namespace := "your-namespace"
+	storage1, err := NewConfigmapStorage(namespace, fakeClient)
+	assert.NoError(t, err)
+	storage2, err := NewConfigmapStorage(namespace, fakeClient)
+	assert.NoError(t, err)
+	lockName := "issue_cert_example.com"
+
+	// storage1 obtains the lock
+	err = storage1.Lock(context.Background(), lockName)
+	assert.NoError(t, err)
+	lease, err := fakeClient.CoordinationV1().Leases(namespace).Get(context.Background(), LeaseStoreLockPrefix+fastHash([]byte(lockName)), metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, storage1.(*ConfigmapStorage).identity, *lease.Spec.HolderIdentity)
+	assert.Equal(t, int32(LockLeaseDurationSeconds), *lease.Spec.LeaseDurationSeconds)
+
+	// the lock is neither reentrant nor shared with storage2
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, storage1.Lock(ctx, lockName))
+	assert.Error(t, storage2.Lock(ctx, lockName))
+
+	// the other lock names are not affected
+	assert.NoError(t, storage2.Lock(context.Background(), "issue_cert_example.org"))
+	assert.NoError(t, storage2.Unlock(context.Background(), "issue_cert_example.org"))
+
+	// storage2 can not release the lock held by storage1
+	assert.Error(t, storage2.Unlock(context.Background(), lockName))
+
+	// storage2 obtains the lock after storage1 releases it
+	locked := make(chan error)
+	go func() {
+		locked <- storage2.Lock(context.Background(), lockName)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, storage1.Unlock(context.Background(), lockName))
+	select {
+	case err := <-locked:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("storage2 failed to obtain the lock released by storage1")
+	}
+	assert.NoError(t, storage2.Unlock(context.Background(), lockName))
+	_, err = fakeClient.CoordinationV1().Leases(namespace).Get(context.Background(), LeaseStoreLockPrefix+fastHash([]byte(lockName)), metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestLockConcurrently(t *testing.T) {
+	lockPollInterval = 10 * time.Millisecond
+	fakeClient := fake.NewSimpleClientset()
+	storage, err := NewConfigmapStorage("your-namespace", fakeClient)
+	assert.NoError(t, err)
+	lockName := "issue_cert_example.com"
+
+	// only one of the concurrent callers of the same storage obtains the lock
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	var obtained int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if storage.Lock(ctx, lockName) == nil {
+				atomic.AddInt32(&obtained, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), obtained)
+	assert.NoError(t, storage.Unlock(context.Background(), lockName))
+}
+
+func TestLockTakeOverStaleLease(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	namespace := "your-namespace"
+	storage, err := NewConfigmapStorage(namespace, fakeClient)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		renewTime time.Time
+		takeOver  bool
+	}{
+		{
+			name:      "stale lease",
+			renewTime: time.Now().Add(-2 * LockLeaseDurationSeconds * time.Second),
+			takeOver:  true,
+		},
+		{
+			name:      "fresh lease",
+			renewTime: time.Now(),
+			takeOver:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lockName := "issue_cert_" + test.name
+			holder := "crashed-replica"
+			leaseDurationSeconds := int32(LockLeaseDurationSeconds)
+			renewTime := metav1.NewMicroTime(test.renewTime)
+			_, err := fakeClient.CoordinationV1().Leases(namespace).Create(context.Background(), &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      LeaseStoreLockPrefix + fastHash([]byte(lockName)),
+				},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:       &holder,
+					LeaseDurationSeconds: &leaseDurationSeconds,
+					RenewTime:            &renewTime,
+				},
+			}, metav1.CreateOptions{})
+			assert.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err = storage.Lock(ctx, lockName)
+			if !test.takeOver {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			lease, err := fakeClient.CoordinationV1().Leases(namespace).Get(context.Background(), LeaseStoreLockPrefix+fastHash([]byte(lockName)), metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, storage.(*ConfigmapStorage).identity, *lease.Spec.HolderIdentity)
+			assert.Equal(t, int32(1), *lease.Spec.LeaseTransitions)
+			assert.NoError(t, storage.Unlock(context.Background(), lockName))
+		})
+	}
+}
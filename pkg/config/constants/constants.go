@@ -27,3 +27,11 @@ const ManagedGatewayController = "higress.io/gateway-controller"
 const RegistryTypeLabelKey = "higress-registry-type"
 
 const RegistryNameLabelKey = "higress-registry-name"
+
+// WasmPluginRequestMatchAnnotation declares that the WasmPlugin is built with an SDK evaluating the path, header and
+// method conditions of the match rules, i.e. C++ or Rust, the wasm-go plugins would apply the rules to all requests.
+const WasmPluginRequestMatchAnnotation = "higress.io/wasm-plugin-request-match"
+
+// WasmPluginConfigSchemaAnnotation carries the openAPIV3Schema of the plugin config in the spec.yaml of the plugin,
+// in JSON, the config of the WasmPlugin is validated against it.
+const WasmPluginConfigSchemaAnnotation = "higress.io/wasm-plugin-config-schema"
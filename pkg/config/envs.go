@@ -24,4 +24,6 @@ var (
 	// and is the value used by the "istio.io/rev" label.
 	Revision              = env.Register("REVISION", "", "").Get()
 	McpServerWasmImageUrl = env.RegisterStringVar("MCP_SERVER_WASM_IMAGE_URL", "oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/mcp-server/all-in-one:1.0.0", "").Get()
+	// EnableH3 is whether the QUIC listeners are built for the gateway, i.e. global.enableH3 of the helm chart.
+	EnableH3 = env.RegisterBoolVar("ENABLE_H3", false, "").Get()
 )
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -43,14 +44,18 @@ import (
 	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/schema/collection"
 	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/log"
 	"istio.io/istio/pkg/util/sets"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	listersv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 
 	higressext "github.com/alibaba/higress/v2/api/extensions/v1alpha1"
 	higressv1 "github.com/alibaba/higress/v2/api/networking/v1"
+	extv1alpha1 "github.com/alibaba/higress/v2/client/pkg/apis/extensions/v1alpha1"
 	extlisterv1 "github.com/alibaba/higress/v2/client/pkg/listers/extensions/v1alpha1"
 	netlisterv1 "github.com/alibaba/higress/v2/client/pkg/listers/networking/v1"
 	"github.com/alibaba/higress/v2/pkg/cert"
@@ -58,6 +63,7 @@ import (
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/annotations"
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/common"
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/configmap"
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/consumer"
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/gateway"
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/http2rpc"
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/ingress"
@@ -133,12 +139,38 @@ type IngressConfig struct {
 
 	wasmPlugins map[string]*extensions.WasmPlugin
 
+	// wasmPluginErrors records why the wasm plugins are not accepted.
+	wasmPluginErrors map[string]error
+
+	// wasmPluginValidated records the wasm plugins whose config is validated against the schema of the plugin.
+	wasmPluginValidated map[string]bool
+
 	http2rpcController http2rpc.Http2RpcController
 
 	http2rpcLister netlisterv1.Http2RpcLister
 
 	http2rpcs map[string]*higressv1.Http2Rpc
 
+	http2rpcGrpcDescriptors map[string]string
+
+	// http2rpcErrors records why the grpc descriptors of the http2rpcs can not be resolved.
+	http2rpcErrors map[string]error
+
+	// http2rpcReferences records the http2rpcs referenced by the ingresses,
+	// with the error of constructing their EnvoyFilters.
+	http2rpcReferences map[string]error
+
+	// mcpbridgeError records the error of the last reconciliation of the mcpbridge.
+	mcpbridgeError error
+
+	statusSyncer *statusSyncer
+
+	consumerController consumer.ConsumerController
+
+	consumerLister netlisterv1.ConsumerLister
+
+	secretLister listersv1.SecretLister
+
 	configmapMgr *configmap.ConfigmapMgr
 
 	XDSUpdater istiomodel.XDSUpdater
@@ -200,7 +232,12 @@ func NewIngressConfig(localKubeClient kube.Client, xdsUpdater istiomodel.XDSUpda
 		watchedSecretSet:         sets.New[string](),
 		namespace:                namespace,
 		wasmPlugins:              make(map[string]*extensions.WasmPlugin),
+		wasmPluginErrors:         make(map[string]error),
+		wasmPluginValidated:      make(map[string]bool),
 		http2rpcs:                make(map[string]*higressv1.Http2Rpc),
+		http2rpcGrpcDescriptors:  make(map[string]string),
+		http2rpcErrors:           make(map[string]error),
+		http2rpcReferences:       make(map[string]error),
 		commonOptions:            options,
 	}
 
@@ -225,13 +262,24 @@ func NewIngressConfig(localKubeClient kube.Client, xdsUpdater istiomodel.XDSUpda
 	config.http2rpcController = http2rpcController
 	config.http2rpcLister = http2rpcController.Lister()
 
+	consumerController := consumer.NewController(localKubeClient, options)
+	consumerController.AddEventHandler(config.AddOrUpdateConsumer, config.DeleteConsumer)
+	config.consumerController = consumerController
+	config.consumerLister = consumerController.Lister()
+
 	higressConfigController := configmap.NewController(localKubeClient, clusterId, namespace)
 	config.configmapMgr = configmap.NewConfigmapMgr(xdsUpdater, namespace, higressConfigController, higressConfigController.Lister())
 	config.configmapMgr.RegisterMcpServerProvider(&config.mcpServerCache)
+	// The proto descriptors of the http2rpcs are read from the configmaps in the same namespace.
+	_, _ = higressConfigController.Informer().AddEventHandler(controllers.ObjectHandler(config.onHttp2RpcConfigMapChange))
 
 	httpsConfigMgr, _ := cert.NewConfigMgr(namespace, localKubeClient.Kube())
 	config.httpsConfigMgr = httpsConfigMgr
 
+	if options.EnableStatus {
+		config.statusSyncer = newStatusSyncer(config, localKubeClient.Higress(), localKubeClient.Kube())
+	}
+
 	return config
 }
 
@@ -269,6 +317,8 @@ func (m *IngressConfig) AddLocalCluster(options common.Options) {
 	secretController := secret.NewController(m.localKubeClient, options)
 	secretController.AddEventHandler(m.ReflectSecretChanges)
 	secretController.AddEventHandler(m.secretConfigMgr.HandleSecretChange)
+	secretController.AddEventHandler(m.ReflectConsumerSecretChanges)
+	m.secretLister = secretController.Lister()
 
 	var ingressController common.IngressController
 	v1 := common.V1Available(m.localKubeClient)
@@ -630,7 +680,9 @@ func (m *IngressConfig) convertEnvoyFilter(convertOptions *common.ConvertOptions
 	mappings := map[string]*common.Rule{}
 
 	initHttp2RpcGlobalConfig := true
+	initGrpcTranscoderGlobalConfig := true
 	initMcpSseGlobalFilter := true
+	http2rpcReferences := map[string]error{}
 	for _, routes := range convertOptions.HTTPRoutes {
 		for _, route := range routes {
 			if strings.HasSuffix(route.HTTPRoute.Name, "app-root") {
@@ -640,13 +692,20 @@ func (m *IngressConfig) convertEnvoyFilter(convertOptions *common.ConvertOptions
 			http2rpc := route.WrapperConfig.AnnotationsConfig.Http2Rpc
 			if http2rpc != nil {
 				IngressLog.Infof("Found http2rpc for name %s", http2rpc.Name)
-				envoyFilter, err := m.constructHttp2RpcEnvoyFilter(http2rpc, route, m.namespace, initHttp2RpcGlobalConfig)
+				envoyFilter, err := m.constructHttp2RpcEnvoyFilter(http2rpc, route, m.namespace, initHttp2RpcGlobalConfig, initGrpcTranscoderGlobalConfig)
+				if _, exist := http2rpcReferences[http2rpc.Name]; !exist || err != nil {
+					http2rpcReferences[http2rpc.Name] = err
+				}
 				if err != nil {
 					IngressLog.Infof("Construct http2rpc EnvoyFilter error %v", err)
 				} else {
 					IngressLog.Infof("Append http2rpc EnvoyFilter for name %s", http2rpc.Name)
 					envoyFilters = append(envoyFilters, *envoyFilter)
-					initHttp2RpcGlobalConfig = false
+					if m.http2rpcs[http2rpc.Name].GetGrpc() != nil {
+						initGrpcTranscoderGlobalConfig = false
+					} else {
+						initHttp2RpcGlobalConfig = false
+					}
 				}
 			}
 
@@ -708,6 +767,7 @@ func (m *IngressConfig) convertEnvoyFilter(convertOptions *common.ConvertOptions
 	IngressLog.Infof("Found %d number of envoyFilters", len(envoyFilters))
 	m.mutex.Lock()
 	m.cachedEnvoyFilters = envoyFilters
+	m.http2rpcReferences = http2rpcReferences
 	m.mutex.Unlock()
 }
 
@@ -996,7 +1056,9 @@ func (m *IngressConfig) applyInternalActiveRedirect(convertOptions *common.Conve
 	}
 }
 
-func (m *IngressConfig) convertIstioWasmPlugin(obj *higressext.WasmPlugin) (*extensions.WasmPlugin, error) {
+// convertIstioWasmPlugin converts the WasmPlugin spec, the path, header and method conditions of the match rules
+// are rejected unless requestMatchSupported is set, since only the C++ and Rust plugins evaluate them.
+func (m *IngressConfig) convertIstioWasmPlugin(obj *higressext.WasmPlugin, requestMatchSupported bool) (*extensions.WasmPlugin, error) {
 	result := &extensions.WasmPlugin{
 		Selector: &istiotype.WorkloadSelector{
 			MatchLabels: map[string]string{
@@ -1119,10 +1181,27 @@ func (m *IngressConfig) convertIstioWasmPlugin(obj *higressext.WasmPlugin) (*ext
 					},
 				}
 			}
+			// match request path, header and method
+			// the request conditions only narrow down the routes, domains or services matched by the rule,
+			// since the rule matcher of wasm-go rejects the rule without any of them
+			requestMatches, err := convertRequestMatches(rule)
+			if err != nil {
+				return nil, err
+			}
+			if len(requestMatches) > 0 && !requestMatchSupported {
+				return nil, fmt.Errorf("invalid match rule has path, header or method conditions, which are only supported by "+
+					"the C++ and Rust plugins, set the annotation %s: \"true\" if the plugin supports them, rule:%v",
+					higressconst.WasmPluginRequestMatchAnnotation, rule)
+			}
+			for key, value := range requestMatches {
+				v.StructValue.Fields[key] = value
+			}
 			if validRule {
 				ruleValues = append(ruleValues, &_struct.Value{
 					Kind: v,
 				})
+			} else if len(requestMatches) > 0 {
+				return nil, fmt.Errorf("invalid match rule has request conditions without ingress, domain or service, rule:%v", rule)
 			} else {
 				return nil, fmt.Errorf("invalid match rule has no match condition, rule:%v", rule)
 			}
@@ -1144,6 +1223,116 @@ func (m *IngressConfig) convertIstioWasmPlugin(obj *higressext.WasmPlugin) (*ext
 	return result, nil
 }
 
+// convertRequestMatches converts the request conditions of the match rule to the
+// _match_path_, _match_header_ and _match_method_ fields of the rule config, which
+// are evaluated by the plugin for each request together with the other match fields.
+func convertRequestMatches(rule *higressext.MatchRule) (map[string]*_struct.Value, error) {
+	fields := map[string]*_struct.Value{}
+	var matchItems []*_struct.Value
+	for _, pathMatch := range rule.Path {
+		item, err := convertStringMatch(pathMatch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path match of rule:%v, err:%v", rule, err)
+		}
+		matchItems = append(matchItems, &_struct.Value{
+			Kind: &_struct.Value_StructValue{
+				StructValue: item,
+			},
+		})
+	}
+	if len(matchItems) > 0 {
+		fields["_match_path_"] = &_struct.Value{
+			Kind: &_struct.Value_ListValue{
+				ListValue: &_struct.ListValue{
+					Values: matchItems,
+				},
+			},
+		}
+	}
+	matchItems = nil
+	for _, headerMatch := range rule.Header {
+		if headerMatch.Name == "" {
+			return nil, fmt.Errorf("invalid header match of rule:%v, err:name can not be empty", rule)
+		}
+		item := &_struct.Struct{
+			Fields: map[string]*_struct.Value{},
+		}
+		if headerMatch.Value != nil {
+			var err error
+			item, err = convertStringMatch(headerMatch.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid header match of rule:%v, err:%v", rule, err)
+			}
+		}
+		item.Fields["name"] = &_struct.Value{
+			Kind: &_struct.Value_StringValue{
+				StringValue: strings.ToLower(headerMatch.Name),
+			},
+		}
+		matchItems = append(matchItems, &_struct.Value{
+			Kind: &_struct.Value_StructValue{
+				StructValue: item,
+			},
+		})
+	}
+	if len(matchItems) > 0 {
+		fields["_match_header_"] = &_struct.Value{
+			Kind: &_struct.Value_ListValue{
+				ListValue: &_struct.ListValue{
+					Values: matchItems,
+				},
+			},
+		}
+	}
+	matchItems = nil
+	for _, method := range rule.Method {
+		if method == "" {
+			return nil, fmt.Errorf("invalid method match of rule:%v, err:method can not be empty", rule)
+		}
+		matchItems = append(matchItems, &_struct.Value{
+			Kind: &_struct.Value_StringValue{
+				StringValue: strings.ToUpper(method),
+			},
+		})
+	}
+	if len(matchItems) > 0 {
+		fields["_match_method_"] = &_struct.Value{
+			Kind: &_struct.Value_ListValue{
+				ListValue: &_struct.ListValue{
+					Values: matchItems,
+				},
+			},
+		}
+	}
+	return fields, nil
+}
+
+func convertStringMatch(match *higressext.StringMatch) (*_struct.Struct, error) {
+	var matchType, value string
+	switch m := match.GetMatchType().(type) {
+	case *higressext.StringMatch_Exact:
+		matchType, value = "exact", m.Exact
+	case *higressext.StringMatch_Prefix:
+		matchType, value = "prefix", m.Prefix
+	case *higressext.StringMatch_Regex:
+		if _, err := regexp.Compile(m.Regex); err != nil {
+			return nil, err
+		}
+		matchType, value = "regex", m.Regex
+	default:
+		return nil, errors.New("one of exact, prefix and regex need be set")
+	}
+	return &_struct.Struct{
+		Fields: map[string]*_struct.Value{
+			matchType: {
+				Kind: &_struct.Value_StringValue{
+					StringValue: value,
+				},
+			},
+		},
+	}, nil
+}
+
 func isBoolValueTrue(b *wrappers.BoolValue) bool {
 	return b != nil && b.Value
 }
@@ -1169,7 +1358,25 @@ func (m *IngressConfig) AddOrUpdateWasmPlugin(clusterNamespacedName util.Cluster
 		IngressLog.Debug("WasmPlugin triggered update")
 		f(config.Config{Meta: metadata}, config.Config{Meta: metadata}, istiomodel.EventUpdate)
 	}
-	istioWasmPlugin, err := m.convertIstioWasmPlugin(&wasmPlugin.Spec)
+	// the conversion fills the match fields into the rule configs, so the cached object should not be touched
+	wasmPlugin = wasmPlugin.DeepCopy()
+	var istioWasmPlugin *extensions.WasmPlugin
+	var validated bool
+	if err = validateWasmPlugin(&wasmPlugin.Spec); err == nil {
+		validated, err = validateWasmPluginConfig(wasmPlugin.Annotations, &wasmPlugin.Spec)
+	}
+	if err == nil {
+		requestMatchSupported := wasmPlugin.Annotations[higressconst.WasmPluginRequestMatchAnnotation] == "true"
+		istioWasmPlugin, err = m.convertIstioWasmPlugin(&wasmPlugin.Spec, requestMatchSupported)
+	}
+	m.mutex.Lock()
+	if err != nil {
+		m.wasmPluginErrors[clusterNamespacedName.Name] = err
+	} else {
+		delete(m.wasmPluginErrors, clusterNamespacedName.Name)
+	}
+	m.wasmPluginValidated[clusterNamespacedName.Name] = validated
+	m.mutex.Unlock()
 	if err != nil {
 		IngressLog.Errorf("invalid wasmPlugin:%s, err:%v", clusterNamespacedName.Name, err)
 		return
@@ -1182,6 +1389,7 @@ func (m *IngressConfig) AddOrUpdateWasmPlugin(clusterNamespacedName util.Cluster
 		m.mutex.Unlock()
 		return
 	}
+	m.injectConsumers(wasmPlugin, istioWasmPlugin)
 	IngressLog.Debugf("wasmPlugin:%s convert to istioWasmPlugin:%v", clusterNamespacedName.Name, istioWasmPlugin)
 	m.mutex.Lock()
 	m.wasmPlugins[clusterNamespacedName.Name] = istioWasmPlugin
@@ -1194,6 +1402,8 @@ func (m *IngressConfig) DeleteWasmPlugin(clusterNamespacedName util.ClusterNames
 	}
 	var hit bool
 	m.mutex.Lock()
+	delete(m.wasmPluginErrors, clusterNamespacedName.Name)
+	delete(m.wasmPluginValidated, clusterNamespacedName.Name)
 	if _, ok := m.wasmPlugins[clusterNamespacedName.Name]; ok {
 		delete(m.wasmPlugins, clusterNamespacedName.Name)
 		hit = true
@@ -1214,6 +1424,95 @@ func (m *IngressConfig) DeleteWasmPlugin(clusterNamespacedName util.ClusterNames
 	}
 }
 
+// injectConsumers injects the consumers defined by Consumer resources into the config of the auth plugin.
+func (m *IngressConfig) injectConsumers(wasmPlugin *extv1alpha1.WasmPlugin, istioWasmPlugin *extensions.WasmPlugin) {
+	credentialType := consumer.GetCredentialType(wasmPlugin)
+	if credentialType == "" {
+		return
+	}
+	consumers, err := m.consumerLister.Consumers(m.namespace).List(labels.Everything())
+	if err != nil {
+		IngressLog.Errorf("list consumers for wasmPlugin:%s failed, err:%v", wasmPlugin.Name, err)
+		return
+	}
+	consumers, err = consumer.SelectConsumers(wasmPlugin, consumers)
+	if err != nil {
+		IngressLog.Errorf("wasmPlugin:%s has invalid consumer selector, err:%v", wasmPlugin.Name, err)
+		return
+	}
+	entries, err := consumer.BuildConsumerConfigs(credentialType, consumers, m.getConsumerSecret)
+	if err != nil {
+		IngressLog.Errorf("skip invalid consumers of wasmPlugin:%s, err:%v", wasmPlugin.Name, err)
+	}
+	if len(entries) == 0 {
+		return
+	}
+	IngressLog.Infof("inject %d consumers into wasmPlugin:%s", len(entries), wasmPlugin.Name)
+	istioWasmPlugin.PluginConfig = consumer.InjectConsumers(istioWasmPlugin.PluginConfig, entries)
+}
+
+func (m *IngressConfig) getConsumerSecret(namespace, name string) (*v1.Secret, error) {
+	if m.secretLister == nil {
+		return nil, fmt.Errorf("secret %s/%s not found", namespace, name)
+	}
+	return m.secretLister.Secrets(namespace).Get(name)
+}
+
+// syncConsumerWasmPlugins converts the auth plugins again after the consumers or their secrets are changed.
+func (m *IngressConfig) syncConsumerWasmPlugins(clusterId cluster.ID) {
+	wasmPlugins, err := m.wasmPluginLister.WasmPlugins(m.namespace).List(labels.Everything())
+	if err != nil {
+		IngressLog.Errorf("list wasmPlugins failed, err:%v", err)
+		return
+	}
+	for _, wasmPlugin := range wasmPlugins {
+		if consumer.GetCredentialType(wasmPlugin) == "" {
+			continue
+		}
+		m.AddOrUpdateWasmPlugin(util.ClusterNamespacedName{
+			NamespacedName: types.NamespacedName{
+				Namespace: wasmPlugin.Namespace,
+				Name:      wasmPlugin.Name,
+			},
+			ClusterId: clusterId,
+		})
+	}
+}
+
+func (m *IngressConfig) AddOrUpdateConsumer(clusterNamespacedName util.ClusterNamespacedName) {
+	if clusterNamespacedName.Namespace != m.namespace {
+		return
+	}
+	IngressLog.Infof("Consumer triggered update event %s", clusterNamespacedName.Name)
+	m.syncConsumerWasmPlugins(clusterNamespacedName.ClusterId)
+}
+
+func (m *IngressConfig) DeleteConsumer(clusterNamespacedName util.ClusterNamespacedName) {
+	if clusterNamespacedName.Namespace != m.namespace {
+		return
+	}
+	IngressLog.Infof("Consumer triggered deleted event %s", clusterNamespacedName.Name)
+	m.syncConsumerWasmPlugins(clusterNamespacedName.ClusterId)
+}
+
+// ReflectConsumerSecretChanges resyncs the auth plugins when a secret referenced by consumers is changed.
+func (m *IngressConfig) ReflectConsumerSecretChanges(clusterNamespacedName util.ClusterNamespacedName) {
+	if clusterNamespacedName.Namespace != m.namespace {
+		return
+	}
+	consumers, err := m.consumerLister.Consumers(m.namespace).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, c := range consumers {
+		if consumer.ReferencesSecret(c, clusterNamespacedName.Name) {
+			IngressLog.Infof("Secret %s referenced by consumer %s is changed", clusterNamespacedName.Name, c.Name)
+			m.syncConsumerWasmPlugins(clusterNamespacedName.ClusterId)
+			return
+		}
+	}
+}
+
 func (m *IngressConfig) AddOrUpdateMcpBridge(clusterNamespacedName util.ClusterNamespacedName) {
 	// TODO: get resource name from config
 	if clusterNamespacedName.Name != DefaultMcpbridgeName || clusterNamespacedName.Namespace != m.namespace {
@@ -1288,6 +1587,9 @@ func (m *IngressConfig) AddOrUpdateMcpBridge(clusterNamespacedName util.ClusterN
 	}
 	reconciler := m.RegistryReconciler
 	err = reconciler.Reconcile(mcpbridge)
+	m.mutex.Lock()
+	m.mcpbridgeError = err
+	m.mutex.Unlock()
 	if err != nil {
 		IngressLog.Errorf("Mcpbridge reconcile failed, err:%v", err)
 		return
@@ -1304,6 +1606,9 @@ func (m *IngressConfig) DeleteMcpBridge(clusterNamespacedName util.ClusterNamesp
 		go m.RegistryReconciler.Reconcile(nil)
 		m.RegistryReconciler = nil
 	}
+	m.mutex.Lock()
+	m.mcpbridgeError = nil
+	m.mutex.Unlock()
 }
 
 func (m *IngressConfig) AddOrUpdateHttp2Rpc(clusterNamespacedName util.ClusterNamespacedName) {
@@ -1316,8 +1621,26 @@ func (m *IngressConfig) AddOrUpdateHttp2Rpc(clusterNamespacedName util.ClusterNa
 			clusterNamespacedName.Namespace, clusterNamespacedName.Name)
 		return
 	}
+	var grpcDescriptor string
+	if grpc := http2rpc.Spec.GetGrpc(); grpc != nil {
+		grpcDescriptor, err = m.buildHttp2RpcGrpcDescriptor(clusterNamespacedName.Namespace, grpc)
+		if err != nil {
+			IngressLog.Errorf("http2rpc %s/%s has invalid grpc descriptor, err %v",
+				clusterNamespacedName.Namespace, clusterNamespacedName.Name, err)
+		}
+	}
 	m.mutex.Lock()
 	m.http2rpcs[clusterNamespacedName.Name] = &http2rpc.Spec
+	if grpcDescriptor != "" {
+		m.http2rpcGrpcDescriptors[clusterNamespacedName.Name] = grpcDescriptor
+	} else {
+		delete(m.http2rpcGrpcDescriptors, clusterNamespacedName.Name)
+	}
+	if err != nil {
+		m.http2rpcErrors[clusterNamespacedName.Name] = err
+	} else {
+		delete(m.http2rpcErrors, clusterNamespacedName.Name)
+	}
 	m.mutex.Unlock()
 	IngressLog.Infof("AddOrUpdateHttp2Rpc http2rpc ingress name %s", clusterNamespacedName.Name)
 	push := func(GVK config.GroupVersionKind) {
@@ -1335,6 +1658,31 @@ func (m *IngressConfig) AddOrUpdateHttp2Rpc(clusterNamespacedName util.ClusterNa
 	push(gvk.EnvoyFilter)
 }
 
+// onHttp2RpcConfigMapChange resolves the grpc descriptors of the http2rpcs referring to the changed configmap again.
+func (m *IngressConfig) onHttp2RpcConfigMapChange(obj controllers.Object) {
+	if obj.GetNamespace() != m.namespace {
+		return
+	}
+	var names []string
+	m.mutex.RLock()
+	for name, http2rpc := range m.http2rpcs {
+		if configMapRef := http2rpc.GetGrpc().GetProtoDescriptorConfigMap(); configMapRef != nil && configMapRef.GetName() == obj.GetName() {
+			names = append(names, name)
+		}
+	}
+	m.mutex.RUnlock()
+	for _, name := range names {
+		IngressLog.Infof("proto descriptor configmap %s of http2rpc %s changed", obj.GetName(), name)
+		m.AddOrUpdateHttp2Rpc(util.ClusterNamespacedName{
+			NamespacedName: types.NamespacedName{
+				Namespace: m.namespace,
+				Name:      name,
+			},
+			ClusterId: m.clusterId,
+		})
+	}
+}
+
 func (m *IngressConfig) DeleteHttp2Rpc(clusterNamespacedName util.ClusterNamespacedName) {
 	IngressLog.Infof("Http2Rpc triggered deleted event %s", clusterNamespacedName.Name)
 	if clusterNamespacedName.Namespace != m.namespace {
@@ -1344,6 +1692,8 @@ func (m *IngressConfig) DeleteHttp2Rpc(clusterNamespacedName util.ClusterNamespa
 	m.mutex.Lock()
 	if _, ok := m.http2rpcs[clusterNamespacedName.Name]; ok {
 		delete(m.http2rpcs, clusterNamespacedName.Name)
+		delete(m.http2rpcGrpcDescriptors, clusterNamespacedName.Name)
+		delete(m.http2rpcErrors, clusterNamespacedName.Name)
 		hit = true
 	}
 	m.mutex.Unlock()
@@ -1449,7 +1799,7 @@ func (m *IngressConfig) applyCanaryIngresses(convertOptions *common.ConvertOptio
 	}
 }
 
-func (m *IngressConfig) constructHttp2RpcEnvoyFilter(http2rpcConfig *annotations.Http2RpcConfig, route *common.WrapperHTTPRoute, namespace string, initHttp2RpcGlobalConfig bool) (*config.Config, error) {
+func (m *IngressConfig) constructHttp2RpcEnvoyFilter(http2rpcConfig *annotations.Http2RpcConfig, route *common.WrapperHTTPRoute, namespace string, initHttp2RpcGlobalConfig bool, initGrpcTranscoderGlobalConfig bool) (*config.Config, error) {
 	mappings := m.http2rpcs
 	IngressLog.Infof("Found http2rpc mappings %v", mappings)
 	if _, exist := mappings[http2rpcConfig.Name]; !exist {
@@ -1458,8 +1808,12 @@ func (m *IngressConfig) constructHttp2RpcEnvoyFilter(http2rpcConfig *annotations
 	}
 	http2rpcCRD := mappings[http2rpcConfig.Name]
 
+	if http2rpcCRD.GetGrpc() != nil {
+		return m.constructHttp2RpcGrpcEnvoyFilter(http2rpcConfig, route, namespace, initGrpcTranscoderGlobalConfig)
+	}
+
 	if http2rpcCRD.GetDubbo() == nil {
-		IngressLog.Errorf("Http2RpcConfig name %s, only support Http2Rpc CRD Dubbo Service type", http2rpcConfig.Name)
+		IngressLog.Errorf("Http2RpcConfig name %s, only support Http2Rpc CRD Dubbo or gRPC Service type", http2rpcConfig.Name)
 		return nil, errors.New("invalid http2rpcConfig has no usable http2rpc")
 	}
 
@@ -1636,6 +1990,168 @@ func (m *IngressConfig) constructHttp2RpcMethods(dubbo *higressv1.DubboService)
 	return result, nil
 }
 
+func (m *IngressConfig) constructHttp2RpcGrpcEnvoyFilter(http2rpcConfig *annotations.Http2RpcConfig, route *common.WrapperHTTPRoute, namespace string, initGrpcTranscoderGlobalConfig bool) (*config.Config, error) {
+	grpcService := m.http2rpcs[http2rpcConfig.Name].GetGrpc()
+	grpcDescriptor, exist := m.http2rpcGrpcDescriptors[http2rpcConfig.Name]
+	if !exist {
+		IngressLog.Errorf("Http2RpcConfig name %s, not found usable grpc proto descriptor", http2rpcConfig.Name)
+		return nil, errors.New("invalid http2rpcConfig has no usable grpc proto descriptor")
+	}
+
+	httpRoute := route.HTTPRoute
+	httpRouteDestination := httpRoute.Route[0]
+	typeStruct, err := m.constructHttp2RpcGrpcTranscoder(grpcService, grpcDescriptor)
+	if err != nil {
+		return nil, err
+	}
+	configPatches := []*networking.EnvoyFilter_EnvoyConfigObjectPatch{
+		{
+			ApplyTo: networking.EnvoyFilter_HTTP_ROUTE,
+			Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: networking.EnvoyFilter_GATEWAY,
+				ObjectTypes: &networking.EnvoyFilter_EnvoyConfigObjectMatch_RouteConfiguration{
+					RouteConfiguration: &networking.EnvoyFilter_RouteConfigurationMatch{
+						Vhost: &networking.EnvoyFilter_RouteConfigurationMatch_VirtualHostMatch{
+							Route: &networking.EnvoyFilter_RouteConfigurationMatch_RouteMatch{
+								Name: httpRoute.Name,
+							},
+						},
+					},
+				},
+			},
+			Patch: &networking.EnvoyFilter_Patch{
+				Operation: networking.EnvoyFilter_Patch_MERGE,
+				Value:     typeStruct,
+			},
+		},
+		{
+			ApplyTo: networking.EnvoyFilter_CLUSTER,
+			Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: networking.EnvoyFilter_GATEWAY,
+				ObjectTypes: &networking.EnvoyFilter_EnvoyConfigObjectMatch_Cluster{
+					Cluster: &networking.EnvoyFilter_ClusterMatch{
+						Service: httpRouteDestination.Destination.Host,
+					},
+				},
+			},
+			Patch: &networking.EnvoyFilter_Patch{
+				Operation: networking.EnvoyFilter_Patch_MERGE,
+				Value: buildPatchStruct(`{
+							"typed_extension_protocol_options": {
+								"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": {
+									"@type": "type.googleapis.com/envoy.extensions.upstreams.http.v3.HttpProtocolOptions",
+									"explicit_http_config": {
+										"http2_protocol_options": {}
+									}
+								}
+							}
+						}`),
+			},
+		},
+	}
+	if initGrpcTranscoderGlobalConfig {
+		// The transcoder is configured per route, so the global filter is disabled by default and only enabled by
+		// the routes. It still requires a valid descriptor, which is the resolved one of the first route.
+		globalTranscoder, err := json.Marshal(map[string]interface{}{
+			"name":     "envoy.filters.http.grpc_json_transcoder",
+			"disabled": true,
+			"typed_config": map[string]interface{}{
+				"@type":                "type.googleapis.com/envoy.extensions.filters.http.grpc_json_transcoder.v3.GrpcJsonTranscoder",
+				"proto_descriptor_bin": grpcDescriptor,
+				"services":             []string{grpcService.GetService()},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		configPatches = append(configPatches, &networking.EnvoyFilter_EnvoyConfigObjectPatch{
+			ApplyTo: networking.EnvoyFilter_HTTP_FILTER,
+			Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: networking.EnvoyFilter_GATEWAY,
+				ObjectTypes: &networking.EnvoyFilter_EnvoyConfigObjectMatch_Listener{
+					Listener: &networking.EnvoyFilter_ListenerMatch{
+						FilterChain: &networking.EnvoyFilter_ListenerMatch_FilterChainMatch{
+							Filter: &networking.EnvoyFilter_ListenerMatch_FilterMatch{
+								Name: "envoy.filters.network.http_connection_manager",
+								SubFilter: &networking.EnvoyFilter_ListenerMatch_SubFilterMatch{
+									Name: "envoy.filters.http.router",
+								},
+							},
+						},
+					},
+				},
+			},
+			Patch: &networking.EnvoyFilter_Patch{
+				Operation: networking.EnvoyFilter_Patch_INSERT_BEFORE,
+				Value:     buildPatchStruct(string(globalTranscoder)),
+			},
+		})
+	}
+	return &config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.EnvoyFilter,
+			Name:             common.CreateConvertedName(constants.IstioIngressGatewayName, "http2rpc", http2rpcConfig.Name, "route", common.ConvertToDNSLabelValid(httpRoute.Name)),
+			Namespace:        namespace,
+		},
+		Spec: &networking.EnvoyFilter{
+			ConfigPatches: configPatches,
+		},
+	}, nil
+}
+
+func (m *IngressConfig) constructHttp2RpcGrpcTranscoder(grpc *higressv1.GrpcService, grpcDescriptor string) (*_struct.Struct, error) {
+	transcoder := map[string]interface{}{
+		"@type":                "type.googleapis.com/envoy.extensions.filters.http.grpc_json_transcoder.v3.GrpcJsonTranscoder",
+		"proto_descriptor_bin": grpcDescriptor,
+		"services":             []string{grpc.GetService()},
+		"print_options": map[string]interface{}{
+			"always_print_primitive_fields": true,
+		},
+		"convert_grpc_status": true,
+		"request_validation_options": map[string]interface{}{
+			"reject_unknown_method": true,
+		},
+		"url_unescape_spec": "ALL_CHARACTERS_EXCEPT_RESERVED",
+	}
+	routeConfig := map[string]interface{}{
+		"typed_per_filter_config": map[string]interface{}{
+			"envoy.filters.http.grpc_json_transcoder": transcoder,
+		},
+	}
+	routeConfigJsonStr, err := json.Marshal(routeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return buildPatchStruct(string(routeConfigJsonStr)), nil
+}
+
+func (m *IngressConfig) buildHttp2RpcGrpcDescriptor(namespace string, grpc *higressv1.GrpcService) (string, error) {
+	var descriptor []byte
+	if grpc.GetProtoDescriptorBin() != "" {
+		descriptor = []byte(grpc.GetProtoDescriptorBin())
+	} else if configMapRef := grpc.GetProtoDescriptorConfigMap(); configMapRef != nil {
+		// Only the configmaps in the namespace of the http2rpcs are watched.
+		configMap, err := m.configmapMgr.HigressConfigLister.Get(configMapRef.GetName())
+		if err != nil {
+			return "", fmt.Errorf("get proto descriptor configmap %s/%s failed: %v", namespace, configMapRef.GetName(), err)
+		}
+		if data, exist := configMap.BinaryData[configMapRef.GetKey()]; exist {
+			descriptor = data
+		} else if data, exist := configMap.Data[configMapRef.GetKey()]; exist {
+			descriptor = []byte(data)
+		} else {
+			return "", fmt.Errorf("key %s not found in proto descriptor configmap %s/%s", configMapRef.GetKey(), namespace, configMapRef.GetName())
+		}
+	} else {
+		return "", errors.New("neither proto_descriptor_bin nor proto_descriptor_config_map is set")
+	}
+	descriptor, err := http2rpc.DecodeGrpcDescriptor(descriptor)
+	if err != nil {
+		return "", err
+	}
+	return http2rpc.BuildGrpcDescriptor(grpc, descriptor)
+}
+
 func buildPatchStruct(config string) *_struct.Struct {
 	val := &_struct.Struct{}
 	err := jsonpb.Unmarshal(strings.NewReader(config), val)
@@ -1886,7 +2402,11 @@ func (m *IngressConfig) Run(stop <-chan struct{}) {
 	go m.mcpbridgeController.Run(stop)
 	go m.wasmPluginController.Run(stop)
 	go m.http2rpcController.Run(stop)
+	go m.consumerController.Run(stop)
 	go m.configmapMgr.HigressConfigController.Run(stop)
+	if m.statusSyncer != nil {
+		go m.statusSyncer.run(stop)
+	}
 }
 
 func (m *IngressConfig) HasSynced() bool {
@@ -1911,6 +2431,9 @@ func (m *IngressConfig) HasSynced() bool {
 	if !m.http2rpcController.HasSynced() {
 		return false
 	}
+	if !m.consumerController.HasSynced() {
+		return false
+	}
 	if !m.configmapMgr.HigressConfigController.HasSynced() {
 		return false
 	}
@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	httppb "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/jsonpb"
+	_struct "github.com/golang/protobuf/ptypes/struct"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
 	networking "istio.io/api/networking/v1alpha3"
@@ -28,6 +30,8 @@ import (
 	ingress "k8s.io/api/networking/v1"
 	ingressv1beta1 "k8s.io/api/networking/v1beta1"
 
+	higressext "github.com/alibaba/higress/v2/api/extensions/v1alpha1"
+	higressv1 "github.com/alibaba/higress/v2/api/networking/v1"
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/annotations"
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/common"
 	controllerv1beta1 "github.com/alibaba/higress/v2/pkg/ingress/kube/ingress"
@@ -616,3 +620,145 @@ func TestConstructBasicAuthEnvoyFilter(t *testing.T) {
 	target := proto.Clone(pb).(*httppb.HttpFilter)
 	t.Log(target)
 }
+
+func TestConvertRequestMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   *higressext.MatchRule
+		expect string
+		err    string
+	}{
+		{
+			name:   "no request match",
+			rule:   &higressext.MatchRule{Domain: []string{"example.com"}},
+			expect: `{}`,
+		},
+		{
+			name: "path, header and method",
+			rule: &higressext.MatchRule{
+				Path: []*higressext.StringMatch{
+					{MatchType: &higressext.StringMatch_Prefix{Prefix: "/admin/"}},
+					{MatchType: &higressext.StringMatch_Regex{Regex: "/api/v[0-9]+/users"}},
+				},
+				Header: []*higressext.HeaderMatch{
+					{Name: "X-Env", Value: &higressext.StringMatch{MatchType: &higressext.StringMatch_Exact{Exact: "gray"}}},
+					{Name: "Authorization"},
+				},
+				Method: []string{"post", "PUT"},
+			},
+			expect: `{
+				"_match_path_": [{"prefix": "/admin/"}, {"regex": "/api/v[0-9]+/users"}],
+				"_match_header_": [{"name": "x-env", "exact": "gray"}, {"name": "authorization"}],
+				"_match_method_": ["POST", "PUT"]
+			}`,
+		},
+		{
+			name: "empty string match",
+			rule: &higressext.MatchRule{
+				Path: []*higressext.StringMatch{{}},
+			},
+			err: "one of exact, prefix and regex need be set",
+		},
+		{
+			name: "invalid regex",
+			rule: &higressext.MatchRule{
+				Header: []*higressext.HeaderMatch{
+					{Name: "x-env", Value: &higressext.StringMatch{MatchType: &higressext.StringMatch_Regex{Regex: "gray("}}},
+				},
+			},
+			err: "missing closing )",
+		},
+		{
+			name: "header without name",
+			rule: &higressext.MatchRule{
+				Header: []*higressext.HeaderMatch{{}},
+			},
+			err: "name can not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := convertRequestMatches(tt.rule)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			actual, err := (&jsonpb.Marshaler{}).MarshalToString(&_struct.Struct{Fields: fields})
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expect, actual)
+		})
+	}
+}
+
+func TestConvertIstioWasmPluginRequestMatches(t *testing.T) {
+	m := &IngressConfig{}
+	path := []*higressext.StringMatch{{MatchType: &higressext.StringMatch_Prefix{Prefix: "/admin/"}}}
+
+	_, err := m.convertIstioWasmPlugin(&higressext.WasmPlugin{
+		MatchRules: []*higressext.MatchRule{{Path: path}},
+	}, true)
+	assert.ErrorContains(t, err, "request conditions without ingress, domain or service")
+
+	_, err = m.convertIstioWasmPlugin(&higressext.WasmPlugin{
+		MatchRules: []*higressext.MatchRule{{Domain: []string{"example.com"}, Path: path}},
+	}, false)
+	assert.ErrorContains(t, err, "only supported by the C++ and Rust plugins")
+
+	plugin, err := m.convertIstioWasmPlugin(&higressext.WasmPlugin{
+		MatchRules: []*higressext.MatchRule{{Domain: []string{"example.com"}}},
+	}, false)
+	assert.NoError(t, err)
+	actual, err := (&jsonpb.Marshaler{}).MarshalToString(plugin.PluginConfig)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"_rules_": [{"_match_domain_": ["example.com"]}]}`, actual)
+
+	plugin, err = m.convertIstioWasmPlugin(&higressext.WasmPlugin{
+		MatchRules: []*higressext.MatchRule{{Domain: []string{"example.com"}, Path: path}},
+	}, true)
+	assert.NoError(t, err)
+	actual, err = (&jsonpb.Marshaler{}).MarshalToString(plugin.PluginConfig)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"_rules_": [{"_match_domain_": ["example.com"], "_match_path_": [{"prefix": "/admin/"}]}]}`, actual)
+}
+
+func TestConstructHttp2RpcGrpcEnvoyFilter(t *testing.T) {
+	m := &IngressConfig{
+		http2rpcs: map[string]*higressv1.Http2Rpc{
+			"greeter": {Destination: &higressv1.Http2Rpc_Grpc{Grpc: &higressv1.GrpcService{Service: "helloworld.Greeter"}}},
+			"missing": {Destination: &higressv1.Http2Rpc_Grpc{Grpc: &higressv1.GrpcService{Service: "helloworld.Greeter"}}},
+		},
+		http2rpcGrpcDescriptors: map[string]string{"greeter": "ZGVzY3JpcHRvcg=="},
+	}
+	route := &common.WrapperHTTPRoute{
+		HTTPRoute: &networking.HTTPRoute{
+			Name:  "greeter-route",
+			Route: []*networking.HTTPRouteDestination{{Destination: &networking.Destination{Host: "greeter.default.svc.cluster.local"}}},
+		},
+	}
+
+	_, err := m.constructHttp2RpcGrpcEnvoyFilter(&annotations.Http2RpcConfig{Name: "missing"}, route, "higress-system", true)
+	assert.ErrorContains(t, err, "no usable grpc proto descriptor")
+
+	envoyFilter, err := m.constructHttp2RpcGrpcEnvoyFilter(&annotations.Http2RpcConfig{Name: "greeter"}, route, "higress-system", false)
+	assert.NoError(t, err)
+	assert.Len(t, envoyFilter.Spec.(*networking.EnvoyFilter).ConfigPatches, 2)
+
+	envoyFilter, err = m.constructHttp2RpcGrpcEnvoyFilter(&annotations.Http2RpcConfig{Name: "greeter"}, route, "higress-system", true)
+	assert.NoError(t, err)
+	configPatches := envoyFilter.Spec.(*networking.EnvoyFilter).ConfigPatches
+	assert.Len(t, configPatches, 3)
+	// The global transcoder is disabled by default, and it carries the resolved descriptor instead of an empty one.
+	actual, err := (&jsonpb.Marshaler{}).MarshalToString(configPatches[2].Patch.Value)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "envoy.filters.http.grpc_json_transcoder",
+		"disabled": true,
+		"typed_config": {
+			"@type": "type.googleapis.com/envoy.extensions.filters.http.grpc_json_transcoder.v3.GrpcJsonTranscoder",
+			"proto_descriptor_bin": "ZGVzY3JpcHRvcg==",
+			"services": ["helloworld.Greeter"]
+		}
+	}`, actual)
+}
@@ -0,0 +1,438 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1alpha1 "istio.io/api/meta/v1alpha1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	higressext "github.com/alibaba/higress/v2/api/extensions/v1alpha1"
+	higressv1 "github.com/alibaba/higress/v2/api/networking/v1"
+	higressclient "github.com/alibaba/higress/v2/client/pkg/clientset/versioned"
+	higressconfig "github.com/alibaba/higress/v2/pkg/config"
+	higressconst "github.com/alibaba/higress/v2/pkg/config/constants"
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/common"
+	. "github.com/alibaba/higress/v2/pkg/ingress/log"
+	"github.com/alibaba/higress/v2/registry/reconcile"
+)
+
+const (
+	// ConditionAccepted indicates whether the spec of the resource is valid.
+	ConditionAccepted = "Accepted"
+	// ConditionProgrammed indicates whether the resource has been translated into the gateway configuration.
+	ConditionProgrammed = "Programmed"
+	// ConditionResolvedRefs indicates whether the resources referenced by the resource are resolved.
+	ConditionResolvedRefs = "ResolvedRefs"
+
+	ConditionStatusTrue    = "True"
+	ConditionStatusFalse   = "False"
+	ConditionStatusUnknown = "Unknown"
+
+	ReasonAccepted          = "Accepted"
+	ReasonInvalid           = "Invalid"
+	ReasonProgrammed        = "Programmed"
+	ReasonPending           = "Pending"
+	ReasonDisabled          = "Disabled"
+	ReasonNotReferenced     = "NotReferenced"
+	ReasonResolvedRefs      = "ResolvedRefs"
+	ReasonRegistryUnhealthy = "RegistryUnhealthy"
+	ReasonInvalidDescriptor = "InvalidDescriptor"
+	ReasonSchemaUnavailable = "SchemaUnavailable"
+
+	// StatusLeaderElectionName is the name of the lease electing the controller replica which writes the status.
+	StatusLeaderElectionName = "higress-controller-status-leader"
+)
+
+var wasmPluginSha256Regex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+type condition struct {
+	conditionType string
+	// status is one of ConditionStatusTrue, ConditionStatusFalse and ConditionStatusUnknown.
+	status  string
+	reason  string
+	message string
+}
+
+// validateWasmPlugin checks the fields of the WasmPlugin spec which can not be checked by the CRD schema.
+// The plugin config is checked by validateWasmPluginConfig against the schema published by the plugin.
+func validateWasmPlugin(spec *higressext.WasmPlugin) error {
+	if spec.Url == "" {
+		return errors.New("url can not be empty")
+	}
+	// The url without scheme is treated as an oci image.
+	if scheme, _, found := strings.Cut(spec.Url, "://"); found {
+		switch scheme {
+		case "oci", "file", "http", "https":
+		default:
+			return fmt.Errorf("unsupported url scheme %s", scheme)
+		}
+	}
+	if spec.Sha256 != "" && !wasmPluginSha256Regex.MatchString(spec.Sha256) {
+		return fmt.Errorf("invalid sha256 %s, it should be 64 lowercase hex characters", spec.Sha256)
+	}
+	for i, rule := range spec.MatchRules {
+		if rule.Config == nil {
+			continue
+		}
+		for key := range rule.Config.Fields {
+			if strings.HasPrefix(key, "_match_") {
+				return fmt.Errorf("invalid config of matchRules[%d], key %s is reserved", i, key)
+			}
+		}
+	}
+	return nil
+}
+
+// validateWasmPluginConfig validates the default config and the configs of the match rules against the
+// openAPIV3Schema published in the spec.yaml of the plugin, which is carried by the config schema annotation.
+// It returns false if the plugin publishes no schema, in which case the config is only checked by the plugin
+// when it is loaded.
+func validateWasmPluginConfig(annotations map[string]string, spec *higressext.WasmPlugin) (bool, error) {
+	raw := annotations[higressconst.WasmPluginConfigSchemaAnnotation]
+	if raw == "" {
+		return false, nil
+	}
+	schema := &apiextensionsv1.JSONSchemaProps{}
+	if err := json.Unmarshal([]byte(raw), schema); err != nil {
+		return false, fmt.Errorf("invalid annotation %s, err:%v", higressconst.WasmPluginConfigSchemaAnnotation, err)
+	}
+	internalSchema := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, internalSchema, nil); err != nil {
+		return false, fmt.Errorf("invalid annotation %s, err:%v", higressconst.WasmPluginConfigSchemaAnnotation, err)
+	}
+	validator, _, err := validation.NewSchemaValidator(internalSchema)
+	if err != nil {
+		return false, fmt.Errorf("invalid annotation %s, err:%v", higressconst.WasmPluginConfigSchemaAnnotation, err)
+	}
+	var errs field.ErrorList
+	if spec.DefaultConfig != nil && !isBoolValueTrue(spec.DefaultConfigDisable) {
+		errs = append(errs, validation.ValidateCustomResource(field.NewPath("defaultConfig"), spec.DefaultConfig.AsMap(), validator)...)
+	}
+	for i, rule := range spec.MatchRules {
+		if rule.Config == nil || isBoolValueTrue(rule.ConfigDisable) {
+			continue
+		}
+		fldPath := field.NewPath("matchRules").Index(i).Child("config")
+		errs = append(errs, validation.ValidateCustomResource(fldPath, rule.Config.AsMap(), validator)...)
+	}
+	return true, errs.ToAggregate()
+}
+
+func validateHttp2Rpc(spec *higressv1.Http2Rpc) error {
+	if spec.GetDubbo() == nil && spec.GetGrpc() == nil {
+		return errors.New("one of dubbo and grpc need be set")
+	}
+	return nil
+}
+
+// wasmPluginConditions returns the conditions of the wasm plugin, it is not reported as accepted unless the
+// config has been validated against the schema of the plugin.
+func wasmPluginConditions(err error, validated bool, programmed bool) []condition {
+	if err != nil {
+		return []condition{
+			{ConditionAccepted, ConditionStatusFalse, ReasonInvalid, err.Error()},
+			{ConditionProgrammed, ConditionStatusFalse, ReasonInvalid, "wasm plugin is not accepted"},
+		}
+	}
+	conditions := []condition{{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "wasm plugin is accepted"}}
+	if !validated {
+		conditions = []condition{{ConditionAccepted, ConditionStatusUnknown, ReasonSchemaUnavailable,
+			"wasm plugin config is not validated since the plugin publishes no config schema"}}
+	}
+	if programmed {
+		return append(conditions, condition{ConditionProgrammed, ConditionStatusTrue, ReasonProgrammed, "wasm plugin is programmed"})
+	}
+	return append(conditions, condition{ConditionProgrammed, ConditionStatusFalse, ReasonDisabled, "wasm plugin is disabled"})
+}
+
+func mcpBridgeConditions(err error, watchers []reconcile.RegistryWatcherStatus) []condition {
+	if err != nil {
+		return []condition{
+			{ConditionAccepted, ConditionStatusFalse, ReasonInvalid, err.Error()},
+			{ConditionProgrammed, ConditionStatusFalse, ReasonInvalid, "mcpbridge is not accepted"},
+		}
+	}
+	var pending, unhealthy []string
+	for _, watcher := range watchers {
+		if !watcher.Ready {
+			pending = append(pending, watcher.Name)
+		}
+		if !watcher.Healthy {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s(%s)", watcher.Name, watcher.Type))
+		}
+	}
+	conditions := []condition{{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "mcpbridge is accepted"}}
+	if len(pending) > 0 {
+		conditions = append(conditions, condition{ConditionProgrammed, ConditionStatusFalse, ReasonPending,
+			"registries are not ready: " + strings.Join(pending, ", ")})
+	} else {
+		conditions = append(conditions, condition{ConditionProgrammed, ConditionStatusTrue, ReasonProgrammed, "all registries are ready"})
+	}
+	if len(unhealthy) > 0 {
+		conditions = append(conditions, condition{ConditionResolvedRefs, ConditionStatusFalse, ReasonRegistryUnhealthy,
+			"registries are unhealthy: " + strings.Join(unhealthy, ", ")})
+	} else {
+		conditions = append(conditions, condition{ConditionResolvedRefs, ConditionStatusTrue, ReasonResolvedRefs, "all registries are healthy"})
+	}
+	return conditions
+}
+
+func http2RpcConditions(spec *higressv1.Http2Rpc, descriptorErr error, referenced bool, constructErr error) []condition {
+	if err := validateHttp2Rpc(spec); err != nil {
+		return []condition{
+			{ConditionAccepted, ConditionStatusFalse, ReasonInvalid, err.Error()},
+			{ConditionResolvedRefs, ConditionStatusFalse, ReasonInvalid, "http2rpc is not accepted"},
+			{ConditionProgrammed, ConditionStatusFalse, ReasonInvalid, "http2rpc is not accepted"},
+		}
+	}
+	conditions := []condition{{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "http2rpc is accepted"}}
+	if descriptorErr != nil {
+		conditions = append(conditions, condition{ConditionResolvedRefs, ConditionStatusFalse, ReasonInvalidDescriptor, descriptorErr.Error()})
+	} else {
+		conditions = append(conditions, condition{ConditionResolvedRefs, ConditionStatusTrue, ReasonResolvedRefs, "http2rpc refs are resolved"})
+	}
+	switch {
+	case !referenced:
+		conditions = append(conditions, condition{ConditionProgrammed, ConditionStatusFalse, ReasonNotReferenced, "http2rpc is not referenced by any ingress"})
+	case constructErr != nil:
+		conditions = append(conditions, condition{ConditionProgrammed, ConditionStatusFalse, ReasonInvalid, constructErr.Error()})
+	default:
+		conditions = append(conditions, condition{ConditionProgrammed, ConditionStatusTrue, ReasonProgrammed, "http2rpc is programmed"})
+	}
+	return conditions
+}
+
+// setConditions writes the conditions into the status, the last transition time of a condition
+// is kept unless its status changes. It returns whether the status is changed.
+func setConditions(status *metav1alpha1.IstioStatus, generation int64, conditions []condition, now time.Time) bool {
+	existing := map[string]*metav1alpha1.IstioCondition{}
+	for _, c := range status.Conditions {
+		existing[c.Type] = c
+	}
+	changed := status.ObservedGeneration != generation || len(status.Conditions) != len(conditions)
+	result := make([]*metav1alpha1.IstioCondition, 0, len(conditions))
+	for _, c := range conditions {
+		conditionStatus := c.status
+		old := existing[c.conditionType]
+		if old != nil && old.Status == conditionStatus && old.Reason == c.reason &&
+			old.Message == c.message && old.ObservedGeneration == generation {
+			result = append(result, old)
+			continue
+		}
+		changed = true
+		lastTransitionTime := timestamppb.New(now)
+		if old != nil && old.Status == conditionStatus && old.LastTransitionTime != nil {
+			lastTransitionTime = old.LastTransitionTime
+		}
+		result = append(result, &metav1alpha1.IstioCondition{
+			Type:               c.conditionType,
+			Status:             conditionStatus,
+			LastProbeTime:      timestamppb.New(now),
+			LastTransitionTime: lastTransitionTime,
+			Reason:             c.reason,
+			Message:            c.message,
+			ObservedGeneration: generation,
+		})
+	}
+	if !changed {
+		return false
+	}
+	status.Conditions = result
+	status.ObservedGeneration = generation
+	return true
+}
+
+// statusSyncer keeps the conditions in the status of WasmPlugin, McpBridge and Http2Rpc resources updated.
+// Only the leader writes the status, so that the replicas do not overwrite each other.
+type statusSyncer struct {
+	client        higressclient.Interface
+	kubeClient    kubernetes.Interface
+	ingressConfig *IngressConfig
+	identity      string
+}
+
+func newStatusSyncer(ingressConfig *IngressConfig, client higressclient.Interface, kubeClient kubernetes.Interface) *statusSyncer {
+	identity := higressconfig.PodName
+	if identity == "" {
+		identity, _ = os.Hostname()
+	}
+	return &statusSyncer{
+		client:        client,
+		kubeClient:    kubeClient,
+		ingressConfig: ingressConfig,
+		identity:      identity,
+	}
+}
+
+func (s *statusSyncer) run(stopCh <-chan struct{}) {
+	cache.WaitForCacheSync(stopCh, s.ingressConfig.HasSynced)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: s.ingressConfig.namespace,
+			Name:      StatusLeaderElectionName,
+		},
+		Client:     s.kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: s.identity},
+	}
+	// campaign again after the leadership is lost until stopped
+	for ctx.Err() == nil {
+		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   15 * time.Second,
+			RenewDeadline:   10 * time.Second,
+			RetryPeriod:     2 * time.Second,
+			ReleaseOnCancel: true,
+			Name:            StatusLeaderElectionName,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(ctx context.Context) {
+					IngressLog.Infof("%s starts to update status of higress resources", s.identity)
+					s.syncStatus(ctx.Done())
+				},
+				OnStoppedLeading: func() {
+					IngressLog.Infof("%s stops updating status of higress resources", s.identity)
+				},
+			},
+		})
+	}
+}
+
+func (s *statusSyncer) syncStatus(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(common.DefaultStatusUpdateInterval)
+	for {
+		select {
+		case <-stopCh:
+			ticker.Stop()
+			return
+		case <-ticker.C:
+			if err := s.runUpdateStatus(); err != nil {
+				IngressLog.Errorf("update status of higress resources fail, err %v", err)
+			}
+		}
+	}
+}
+
+func (s *statusSyncer) runUpdateStatus() error {
+	return errors.Join(s.updateWasmPluginStatus(), s.updateMcpBridgeStatus(), s.updateHttp2RpcStatus())
+}
+
+func (s *statusSyncer) updateWasmPluginStatus() error {
+	m := s.ingressConfig
+	wasmPlugins, err := m.wasmPluginLister.WasmPlugins(m.namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, wasmPlugin := range wasmPlugins {
+		m.mutex.RLock()
+		err := m.wasmPluginErrors[wasmPlugin.Name]
+		validated := m.wasmPluginValidated[wasmPlugin.Name]
+		_, programmed := m.wasmPlugins[wasmPlugin.Name]
+		m.mutex.RUnlock()
+
+		wasmPlugin = wasmPlugin.DeepCopy()
+		conditions := wasmPluginConditions(err, validated, programmed)
+		if !setConditions(&wasmPlugin.Status, wasmPlugin.Generation, conditions, time.Now()) {
+			continue
+		}
+		_, err = s.client.ExtensionsV1alpha1().WasmPlugins(wasmPlugin.Namespace).UpdateStatus(context.TODO(), wasmPlugin, metav1.UpdateOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("update status of wasmPlugin %s/%s failed: %v", wasmPlugin.Namespace, wasmPlugin.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *statusSyncer) updateMcpBridgeStatus() error {
+	m := s.ingressConfig
+	mcpbridges, err := m.mcpbridgeLister.McpBridges(m.namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, mcpbridge := range mcpbridges {
+		// Only the default mcpbridge is reconciled.
+		if mcpbridge.Name != DefaultMcpbridgeName {
+			continue
+		}
+		m.mutex.RLock()
+		err := m.mcpbridgeError
+		m.mutex.RUnlock()
+		var watchers []reconcile.RegistryWatcherStatus
+		if reconciler := m.RegistryReconciler; reconciler != nil {
+			watchers = reconciler.GetRegistryWatcherStatusList()
+		}
+
+		mcpbridge = mcpbridge.DeepCopy()
+		if !setConditions(&mcpbridge.Status, mcpbridge.Generation, mcpBridgeConditions(err, watchers), time.Now()) {
+			continue
+		}
+		_, err = s.client.NetworkingV1().McpBridges(mcpbridge.Namespace).UpdateStatus(context.TODO(), mcpbridge, metav1.UpdateOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("update status of mcpbridge %s/%s failed: %v", mcpbridge.Namespace, mcpbridge.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *statusSyncer) updateHttp2RpcStatus() error {
+	m := s.ingressConfig
+	http2rpcs, err := m.http2rpcLister.Http2Rpcs(m.namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, http2rpc := range http2rpcs {
+		m.mutex.RLock()
+		descriptorErr := m.http2rpcErrors[http2rpc.Name]
+		constructErr, referenced := m.http2rpcReferences[http2rpc.Name]
+		m.mutex.RUnlock()
+
+		http2rpc = http2rpc.DeepCopy()
+		conditions := http2RpcConditions(&http2rpc.Spec, descriptorErr, referenced, constructErr)
+		if !setConditions(&http2rpc.Status, http2rpc.Generation, conditions, time.Now()) {
+			continue
+		}
+		_, err = s.client.NetworkingV1().Http2Rpcs(http2rpc.Namespace).UpdateStatus(context.TODO(), http2rpc, metav1.UpdateOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("update status of http2rpc %s/%s failed: %v", http2rpc.Namespace, http2rpc.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/stretchr/testify/assert"
+	metav1alpha1 "istio.io/api/meta/v1alpha1"
+
+	higressext "github.com/alibaba/higress/v2/api/extensions/v1alpha1"
+	higressv1 "github.com/alibaba/higress/v2/api/networking/v1"
+	higressconst "github.com/alibaba/higress/v2/pkg/config/constants"
+	"github.com/alibaba/higress/v2/registry/reconcile"
+)
+
+func TestValidateWasmPlugin(t *testing.T) {
+	testCases := []struct {
+		name   string
+		spec   *higressext.WasmPlugin
+		errMsg string
+	}{
+		{
+			name: "oci url",
+			spec: &higressext.WasmPlugin{Url: "oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/key-auth:1.0.0"},
+		},
+		{
+			name: "url without scheme",
+			spec: &higressext.WasmPlugin{Url: "localhost:5000/plugins/key-auth:1.0.0"},
+		},
+		{
+			name: "valid sha256",
+			spec: &higressext.WasmPlugin{Url: "file:///opt/plugins/key-auth.wasm", Sha256: strings.Repeat("a1", 32)},
+		},
+		{
+			name:   "empty url",
+			spec:   &higressext.WasmPlugin{},
+			errMsg: "url can not be empty",
+		},
+		{
+			name:   "unsupported scheme",
+			spec:   &higressext.WasmPlugin{Url: "ftp://example.com/plugin.wasm"},
+			errMsg: "unsupported url scheme ftp",
+		},
+		{
+			name:   "invalid sha256",
+			spec:   &higressext.WasmPlugin{Url: "oci://example.com/plugin:1.0.0", Sha256: "abc"},
+			errMsg: "invalid sha256 abc",
+		},
+		{
+			name: "reserved config key",
+			spec: &higressext.WasmPlugin{
+				Url: "oci://example.com/plugin:1.0.0",
+				MatchRules: []*higressext.MatchRule{
+					{
+						Config: &_struct.Struct{Fields: map[string]*_struct.Value{
+							"_match_route_": {Kind: &_struct.Value_StringValue{StringValue: "test"}},
+						}},
+					},
+				},
+			},
+			errMsg: "key _match_route_ is reserved",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateWasmPlugin(testCase.spec)
+			if testCase.errMsg != "" {
+				assert.ErrorContains(t, err, testCase.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateWasmPluginConfig(t *testing.T) {
+	schema := `{"type": "object", "properties": {"keys": {"type": "array", "items": {"type": "string"}},` +
+		` "in_query": {"type": "boolean"}}, "required": ["keys"]}`
+	annotations := map[string]string{higressconst.WasmPluginConfigSchemaAnnotation: schema}
+	config := func(fields map[string]interface{}) *_struct.Struct {
+		s, err := _struct.NewStruct(fields)
+		assert.NoError(t, err)
+		return s
+	}
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		spec        *higressext.WasmPlugin
+		validated   bool
+		errMsg      string
+	}{
+		{
+			name: "no schema",
+			spec: &higressext.WasmPlugin{DefaultConfig: config(map[string]interface{}{"in_query": "yes"})},
+		},
+		{
+			name:        "valid config",
+			annotations: annotations,
+			spec: &higressext.WasmPlugin{
+				DefaultConfig: config(map[string]interface{}{"keys": []interface{}{"x-api-key"}, "in_query": true}),
+				MatchRules: []*higressext.MatchRule{
+					{Domain: []string{"example.com"}, Config: config(map[string]interface{}{"keys": []interface{}{"apikey"}})},
+				},
+			},
+			validated: true,
+		},
+		{
+			name:        "invalid default config",
+			annotations: annotations,
+			spec:        &higressext.WasmPlugin{DefaultConfig: config(map[string]interface{}{"in_query": "yes"})},
+			validated:   true,
+			errMsg:      "defaultConfig.keys: Required value",
+		},
+		{
+			name:        "invalid rule config",
+			annotations: annotations,
+			spec: &higressext.WasmPlugin{
+				MatchRules: []*higressext.MatchRule{
+					{Domain: []string{"example.com"}, Config: config(map[string]interface{}{"keys": "apikey"})},
+				},
+			},
+			validated: true,
+			errMsg:    "matchRules[0].config.keys: Invalid value",
+		},
+		{
+			name:        "invalid schema",
+			annotations: map[string]string{higressconst.WasmPluginConfigSchemaAnnotation: "{"},
+			spec:        &higressext.WasmPlugin{},
+			errMsg:      "invalid annotation " + higressconst.WasmPluginConfigSchemaAnnotation,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			validated, err := validateWasmPluginConfig(testCase.annotations, testCase.spec)
+			assert.Equal(t, testCase.validated, validated)
+			if testCase.errMsg != "" {
+				assert.ErrorContains(t, err, testCase.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWasmPluginConditions(t *testing.T) {
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "wasm plugin is accepted"},
+		{ConditionProgrammed, ConditionStatusTrue, ReasonProgrammed, "wasm plugin is programmed"},
+	}, wasmPluginConditions(nil, true, true))
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "wasm plugin is accepted"},
+		{ConditionProgrammed, ConditionStatusFalse, ReasonDisabled, "wasm plugin is disabled"},
+	}, wasmPluginConditions(nil, true, false))
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusUnknown, ReasonSchemaUnavailable,
+			"wasm plugin config is not validated since the plugin publishes no config schema"},
+		{ConditionProgrammed, ConditionStatusTrue, ReasonProgrammed, "wasm plugin is programmed"},
+	}, wasmPluginConditions(nil, false, true))
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusFalse, ReasonInvalid, "invalid config"},
+		{ConditionProgrammed, ConditionStatusFalse, ReasonInvalid, "wasm plugin is not accepted"},
+	}, wasmPluginConditions(errors.New("invalid config"), true, true))
+}
+
+func TestMcpBridgeConditions(t *testing.T) {
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "mcpbridge is accepted"},
+		{ConditionProgrammed, ConditionStatusTrue, ReasonProgrammed, "all registries are ready"},
+		{ConditionResolvedRefs, ConditionStatusTrue, ReasonResolvedRefs, "all registries are healthy"},
+	}, mcpBridgeConditions(nil, []reconcile.RegistryWatcherStatus{
+		{Name: "nacos", Type: "nacos2", Healthy: true, Ready: true},
+	}))
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "mcpbridge is accepted"},
+		{ConditionProgrammed, ConditionStatusFalse, ReasonPending, "registries are not ready: consul"},
+		{ConditionResolvedRefs, ConditionStatusFalse, ReasonRegistryUnhealthy, "registries are unhealthy: consul(consul), eureka(eureka)"},
+	}, mcpBridgeConditions(nil, []reconcile.RegistryWatcherStatus{
+		{Name: "nacos", Type: "nacos2", Healthy: true, Ready: true},
+		{Name: "consul", Type: "consul", Healthy: false, Ready: false},
+		{Name: "eureka", Type: "eureka", Healthy: false, Ready: true},
+	}))
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusFalse, ReasonInvalid, "reconcile failed"},
+		{ConditionProgrammed, ConditionStatusFalse, ReasonInvalid, "mcpbridge is not accepted"},
+	}, mcpBridgeConditions(errors.New("reconcile failed"), nil))
+}
+
+func TestHttp2RpcConditions(t *testing.T) {
+	grpc := &higressv1.Http2Rpc{Destination: &higressv1.Http2Rpc_Grpc{Grpc: &higressv1.GrpcService{}}}
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "http2rpc is accepted"},
+		{ConditionResolvedRefs, ConditionStatusTrue, ReasonResolvedRefs, "http2rpc refs are resolved"},
+		{ConditionProgrammed, ConditionStatusTrue, ReasonProgrammed, "http2rpc is programmed"},
+	}, http2RpcConditions(grpc, nil, true, nil))
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "http2rpc is accepted"},
+		{ConditionResolvedRefs, ConditionStatusFalse, ReasonInvalidDescriptor, "invalid descriptor"},
+		{ConditionProgrammed, ConditionStatusFalse, ReasonNotReferenced, "http2rpc is not referenced by any ingress"},
+	}, http2RpcConditions(grpc, errors.New("invalid descriptor"), false, nil))
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusTrue, ReasonAccepted, "http2rpc is accepted"},
+		{ConditionResolvedRefs, ConditionStatusTrue, ReasonResolvedRefs, "http2rpc refs are resolved"},
+		{ConditionProgrammed, ConditionStatusFalse, ReasonInvalid, "construct failed"},
+	}, http2RpcConditions(grpc, nil, true, errors.New("construct failed")))
+	assert.Equal(t, []condition{
+		{ConditionAccepted, ConditionStatusFalse, ReasonInvalid, "one of dubbo and grpc need be set"},
+		{ConditionResolvedRefs, ConditionStatusFalse, ReasonInvalid, "http2rpc is not accepted"},
+		{ConditionProgrammed, ConditionStatusFalse, ReasonInvalid, "http2rpc is not accepted"},
+	}, http2RpcConditions(&higressv1.Http2Rpc{}, nil, true, nil))
+}
+
+func TestSetConditions(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	status := &metav1alpha1.IstioStatus{}
+	conditions := wasmPluginConditions(nil, true, true)
+
+	assert.True(t, setConditions(status, 1, conditions, start))
+	assert.Equal(t, int64(1), status.ObservedGeneration)
+	assert.Len(t, status.Conditions, 2)
+	for _, c := range status.Conditions {
+		assert.Equal(t, ConditionStatusTrue, c.Status)
+		assert.Equal(t, int64(1), c.ObservedGeneration)
+		assert.Equal(t, start.Unix(), c.LastTransitionTime.AsTime().Unix())
+	}
+
+	// Nothing changed, the status should be kept as is.
+	assert.False(t, setConditions(status, 1, conditions, start.Add(time.Minute)))
+
+	// The status of Programmed changes, the last transition time of Accepted should be kept.
+	later := start.Add(2 * time.Minute)
+	assert.True(t, setConditions(status, 2, wasmPluginConditions(nil, true, false), later))
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	accepted, programmed := status.Conditions[0], status.Conditions[1]
+	assert.Equal(t, ConditionAccepted, accepted.Type)
+	assert.Equal(t, int64(2), accepted.ObservedGeneration)
+	assert.Equal(t, start.Unix(), accepted.LastTransitionTime.AsTime().Unix())
+	assert.Equal(t, ConditionProgrammed, programmed.Type)
+	assert.Equal(t, ConditionStatusFalse, programmed.Status)
+	assert.Equal(t, ReasonDisabled, programmed.Reason)
+	assert.Equal(t, later.Unix(), programmed.LastTransitionTime.AsTime().Unix())
+}
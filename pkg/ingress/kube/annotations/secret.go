@@ -0,0 +1,28 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotations
+
+// annotations referring to a secret in the form of name or namespace/name
+var secretAnnotations = []string{authTLSSecret, proxySSLSecret, authSecretAnn}
+
+// SecretAnnotationKeys returns the keys of the present annotations referring to a secret, the values are in the form
+// of name or namespace/name, the secret is in the namespace of the ingress if the namespace is omitted
+func SecretAnnotationKeys(annotations Annotations) []string {
+	var keys []string
+	for _, name := range secretAnnotations {
+		keys = append(keys, presentKeys(annotations, name)...)
+	}
+	return keys
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotations
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity is the level of a validation issue
+type Severity string
+
+const (
+	SeverityError   Severity = "Error"
+	SeverityWarning Severity = "Warning"
+)
+
+// Codes of the validation issues, shared with the offline analyzer of hgctl
+const (
+	CodeInvalidValue          = "HIG0001"
+	CodeConflictingAnnotation = "HIG0002"
+	CodeUnknownAnnotation     = "HIG0003"
+)
+
+// ValidationIssue is a problem found in the annotations, which the parsers would ignore silently
+type ValidationIssue struct {
+	Code       string
+	Severity   Severity
+	Annotation string
+	Message    string
+}
+
+var (
+	boolAnnotations = []string{
+		enableCanary, enableCors, allowCredentials, enableIgnoreCase, enableMcpServer,
+		mcpServerEnablePathRewrite, sslRedirect, forceSSLRedirect, useRegex, fullPathRegex,
+	}
+
+	intAnnotations = []string{
+		canaryWeight, canaryWeightTotal, maxAge, sessionCookieMaxAge, sessionCookieExpires,
+		limitBurstMultiplier, limitRPM, limitRPS, mirrorTargetFQDNPort, mirrorPercentage,
+		permanentRedirectCode, retryCount, perRetryTimeout, timeoutAnnotation,
+	}
+
+	knownAnnotations = map[string]struct{}{}
+
+	// prefixes of the annotations carrying the matched name in the key, e.g. higress.io/exact-match-header-foo
+	knownAnnotationInfixes = []string{MatchHeader + "-", MatchQuery + "-", MatchPseudoHeader + "-"}
+)
+
+func init() {
+	for _, key := range append(append([]string{
+		authType, authRealm, authSecretAnn, authSecretTypeAnn,
+		canaryByHeader, canaryByHeaderValue, canaryByHeaderPattern, canaryByCookie,
+		allowOrigin, allowMethods, allowHeaders, exposeHeaders,
+		annDefaultBackend, customHTTPError, destinationKey,
+		authTLSSecret, sslCipher, annotationMinTLSVersion, annotationMaxTLSVersion,
+		requestHeaderAdd, requestHeaderUpdate, requestHeaderRemove,
+		responseHeaderAdd, responseHeaderUpdate, responseHeaderRemove,
+		http2rpcKey, rpcDestinationName, whitelist,
+		loadBalanceAnnotation, upstreamHashBy, affinity, affinityMode, affinityCanaryBehavior,
+		sessionCookieName, sessionCookiePath, mcpSseStatefulKey,
+		MatchMethod,
+		mcpServerMatchRuleDomains, mcpServerMatchRuleType, mcpServerMatchRuleValue,
+		mcpServerUpstreamType, mcpServerPathRewritePrefix,
+		mirrorTargetService, mirrorTargetFQDN,
+		appRoot, temporalRedirect, permanentRedirect,
+		retryOn, retryStatusCode,
+		rewritePath, rewriteTarget, upstreamVhost,
+		backendProtocol, proxySSLSecret, proxySSLVerify, proxySSLName, proxySSLServerName,
+	}, boolAnnotations...), intAnnotations...) {
+		knownAnnotations[key] = struct{}{}
+	}
+}
+
+// Validate checks the annotations of an ingress, reporting the values ignored by the parsers,
+// conflicting annotations and unknown higress annotations
+func Validate(annotations Annotations) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, key := range sortedKeys(annotations) {
+		if name, ok := strings.CutPrefix(key, HigressAnnotationsPrefix+"/"); ok && !isKnownAnnotation(name) {
+			issues = append(issues, ValidationIssue{
+				Code:       CodeUnknownAnnotation,
+				Severity:   SeverityWarning,
+				Annotation: key,
+				Message:    fmt.Sprintf("unknown annotation %s is ignored", key),
+			})
+		}
+	}
+
+	for _, name := range boolAnnotations {
+		for _, key := range presentKeys(annotations, name) {
+			if _, err := strconv.ParseBool(annotations[key]); err != nil {
+				issues = append(issues, invalidValue(key, annotations[key], "a boolean"))
+			}
+		}
+	}
+	for _, name := range intAnnotations {
+		for _, key := range presentKeys(annotations, name) {
+			if _, err := strconv.Atoi(annotations[key]); err != nil {
+				issues = append(issues, invalidValue(key, annotations[key], "an integer"))
+			}
+		}
+	}
+
+	issues = append(issues, validateCanary(annotations)...)
+	issues = append(issues, validateMatchMethod(annotations)...)
+
+	if annotations.HasHigress(rewritePath) && annotations.HasASAP(rewriteTarget) {
+		issues = append(issues, ValidationIssue{
+			Code:       CodeConflictingAnnotation,
+			Severity:   SeverityWarning,
+			Annotation: buildHigressAnnotationKey(rewritePath),
+			Message:    fmt.Sprintf("both %s and %s are set, %s is ignored", rewritePath, rewriteTarget, rewriteTarget),
+		})
+	}
+	return issues
+}
+
+func validateCanary(annotations Annotations) []ValidationIssue {
+	if !needCanaryConfig(annotations) {
+		return nil
+	}
+	var issues []ValidationIssue
+	weightTotal := defaultCanaryWeightTotal
+	if total, err := annotations.ParseIntASAP(canaryWeightTotal); err == nil {
+		if total <= 0 {
+			issues = append(issues, invalidValue(annotationKeyASAP(annotations, canaryWeightTotal), strconv.Itoa(total), "a positive integer"))
+		} else {
+			weightTotal = total
+		}
+	}
+	if weight, err := annotations.ParseIntASAP(canaryWeight); err == nil && (weight < 0 || weight > weightTotal) {
+		issues = append(issues, ValidationIssue{
+			Code:       CodeInvalidValue,
+			Severity:   SeverityError,
+			Annotation: annotationKeyASAP(annotations, canaryWeight),
+			Message:    fmt.Sprintf("canary weight %d is out of range [0, %d]", weight, weightTotal),
+		})
+	}
+	if annotations.HasASAP(canaryByHeaderValue) && annotations.HasASAP(canaryByHeaderPattern) {
+		issues = append(issues, ValidationIssue{
+			Code:       CodeConflictingAnnotation,
+			Severity:   SeverityWarning,
+			Annotation: annotationKeyASAP(annotations, canaryByHeaderPattern),
+			Message:    fmt.Sprintf("both %s and %s are set, %s is ignored", canaryByHeaderValue, canaryByHeaderPattern, canaryByHeaderPattern),
+		})
+	}
+	if enabled, _ := annotations.ParseBoolASAP(enableCanary); !enabled {
+		for _, name := range []string{canaryByHeader, canaryByCookie, canaryWeight} {
+			if annotations.HasASAP(name) {
+				issues = append(issues, ValidationIssue{
+					Code:       CodeConflictingAnnotation,
+					Severity:   SeverityWarning,
+					Annotation: annotationKeyASAP(annotations, name),
+					Message:    fmt.Sprintf("%s is ignored as %s is not enabled", name, enableCanary),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+func validateMatchMethod(annotations Annotations) []ValidationIssue {
+	str, err := annotations.ParseStringForHigress(MatchMethod)
+	if err != nil {
+		return nil
+	}
+	var issues []ValidationIssue
+	for _, method := range strings.Split(str, sep) {
+		if method != "" && !isMethod(strings.ToUpper(method)) {
+			issues = append(issues, invalidValue(buildHigressAnnotationKey(MatchMethod), method, "an HTTP method"))
+		}
+	}
+	return issues
+}
+
+func isKnownAnnotation(name string) bool {
+	if _, ok := knownAnnotations[name]; ok {
+		return true
+	}
+	for _, infix := range knownAnnotationInfixes {
+		if strings.Contains(name, infix) {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidValue(key, value, expected string) ValidationIssue {
+	return ValidationIssue{
+		Code:       CodeInvalidValue,
+		Severity:   SeverityError,
+		Annotation: key,
+		Message:    fmt.Sprintf("invalid value %q, expected %s", value, expected),
+	}
+}
+
+// presentKeys returns the nginx and higress keys of the annotation present in annotations
+func presentKeys(annotations Annotations, name string) []string {
+	var keys []string
+	for _, key := range []string{buildNginxAnnotationKey(name), buildHigressAnnotationKey(name)} {
+		if _, ok := annotations[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// annotationKeyASAP returns the key read by the ParseXxxASAP functions
+func annotationKeyASAP(annotations Annotations, name string) string {
+	if annotations.Has(name) {
+		return buildNginxAnnotationKey(name)
+	}
+	return buildHigressAnnotationKey(name)
+}
+
+func sortedKeys(annotations Annotations) []string {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
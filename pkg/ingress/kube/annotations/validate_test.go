@@ -0,0 +1,135 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  Annotations
+		expect []ValidationIssue
+	}{
+		{
+			name: "valid annotations",
+			input: Annotations{
+				buildNginxAnnotationKey(enableCanary):             "true",
+				buildNginxAnnotationKey(canaryWeight):             "20",
+				buildHigressAnnotationKey(timeoutAnnotation):      "10",
+				buildHigressAnnotationKey("exact-match-header-a"): "b",
+				buildHigressAnnotationKey(MatchMethod):            "GET post",
+			},
+		},
+		{
+			name: "invalid canary weight",
+			input: Annotations{
+				buildNginxAnnotationKey(enableCanary): "true",
+				buildNginxAnnotationKey(canaryWeight): "abc",
+			},
+			expect: []ValidationIssue{
+				{
+					Code:       CodeInvalidValue,
+					Severity:   SeverityError,
+					Annotation: buildNginxAnnotationKey(canaryWeight),
+					Message:    `invalid value "abc", expected an integer`,
+				},
+			},
+		},
+		{
+			name: "canary weight out of range",
+			input: Annotations{
+				buildHigressAnnotationKey(enableCanary):      "true",
+				buildHigressAnnotationKey(canaryWeight):      "60",
+				buildHigressAnnotationKey(canaryWeightTotal): "50",
+			},
+			expect: []ValidationIssue{
+				{
+					Code:       CodeInvalidValue,
+					Severity:   SeverityError,
+					Annotation: buildHigressAnnotationKey(canaryWeight),
+					Message:    "canary weight 60 is out of range [0, 50]",
+				},
+			},
+		},
+		{
+			name: "canary not enabled",
+			input: Annotations{
+				buildNginxAnnotationKey(enableCanary): "false",
+				buildNginxAnnotationKey(canaryWeight): "10",
+			},
+			expect: []ValidationIssue{
+				{
+					Code:       CodeConflictingAnnotation,
+					Severity:   SeverityWarning,
+					Annotation: buildNginxAnnotationKey(canaryWeight),
+					Message:    "canary-weight is ignored as canary is not enabled",
+				},
+			},
+		},
+		{
+			name: "rewrite-path and rewrite-target",
+			input: Annotations{
+				buildHigressAnnotationKey(rewritePath):   "/v2",
+				buildNginxAnnotationKey(rewriteTarget):   "/$1",
+				buildHigressAnnotationKey("no-such-key"): "x",
+			},
+			expect: []ValidationIssue{
+				{
+					Code:       CodeUnknownAnnotation,
+					Severity:   SeverityWarning,
+					Annotation: buildHigressAnnotationKey("no-such-key"),
+					Message:    "unknown annotation higress.io/no-such-key is ignored",
+				},
+				{
+					Code:       CodeConflictingAnnotation,
+					Severity:   SeverityWarning,
+					Annotation: buildHigressAnnotationKey(rewritePath),
+					Message:    "both rewrite-path and rewrite-target are set, rewrite-target is ignored",
+				},
+			},
+		},
+		{
+			name: "invalid bool and method",
+			input: Annotations{
+				buildNginxAnnotationKey(enableCors):    "yes",
+				buildHigressAnnotationKey(MatchMethod): "GET FETCH",
+			},
+			expect: []ValidationIssue{
+				{
+					Code:       CodeInvalidValue,
+					Severity:   SeverityError,
+					Annotation: buildNginxAnnotationKey(enableCors),
+					Message:    `invalid value "yes", expected a boolean`,
+				},
+				{
+					Code:       CodeInvalidValue,
+					Severity:   SeverityError,
+					Annotation: buildHigressAnnotationKey(MatchMethod),
+					Message:    `invalid value "FETCH", expected an HTTP method`,
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expect, Validate(testCase.input))
+		})
+	}
+}
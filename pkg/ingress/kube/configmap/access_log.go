@@ -0,0 +1,522 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/schema/gvk"
+
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/util"
+	. "github.com/alibaba/higress/v2/pkg/ingress/log"
+)
+
+const (
+	higressAccessLogEnvoyFilterName = "higress-config-access-log"
+
+	AccessLogFormatText = "text"
+	AccessLogFormatJSON = "json"
+
+	// defaultAccessLogTextFormat is the default format of envoy
+	defaultAccessLogTextFormat = `[%START_TIME%] "%REQ(:METHOD)% %REQ(X-ENVOY-ORIGINAL-PATH?:PATH)% %PROTOCOL%" ` +
+		`%RESPONSE_CODE% %RESPONSE_FLAGS% %BYTES_RECEIVED% %BYTES_SENT% %DURATION% %RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)% ` +
+		`"%REQ(X-FORWARDED-FOR)%" "%REQ(USER-AGENT)%" "%REQ(X-REQUEST-ID)%" "%REQ(:AUTHORITY)%" "%UPSTREAM_HOST%"` + "\n"
+	// wasmFilterStatePrefix is the prefix of the filter state keys set by the wasm plugins
+	wasmFilterStatePrefix = "wasm."
+	accessLogName         = "higress-gateway"
+)
+
+// defaultAccessLogJSONFields are the fields of the default access log of the gateway
+var defaultAccessLogJSONFields = map[string]string{
+	"authority":                         "%REQ(X-ENVOY-ORIGINAL-HOST?:AUTHORITY)%",
+	"bytes_received":                    "%BYTES_RECEIVED%",
+	"bytes_sent":                        "%BYTES_SENT%",
+	"downstream_local_address":          "%DOWNSTREAM_LOCAL_ADDRESS%",
+	"downstream_remote_address":         "%DOWNSTREAM_REMOTE_ADDRESS%",
+	"duration":                          "%DURATION%",
+	"method":                            "%REQ(:METHOD)%",
+	"path":                              "%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%",
+	"protocol":                          "%PROTOCOL%",
+	"request_id":                        "%REQ(X-REQUEST-ID)%",
+	"requested_server_name":             "%REQUESTED_SERVER_NAME%",
+	"response_code":                     "%RESPONSE_CODE%",
+	"response_code_details":             "%RESPONSE_CODE_DETAILS%",
+	"response_flags":                    "%RESPONSE_FLAGS%",
+	"route_name":                        "%ROUTE_NAME%",
+	"start_time":                        "%START_TIME%",
+	"trace_id":                          "%REQ(X-B3-TRACEID)%",
+	"upstream_cluster":                  "%UPSTREAM_CLUSTER%",
+	"upstream_host":                     "%UPSTREAM_HOST%",
+	"upstream_local_address":            "%UPSTREAM_LOCAL_ADDRESS%",
+	"upstream_service_time":             "%RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)%",
+	"upstream_transport_failure_reason": "%UPSTREAM_TRANSPORT_FAILURE_REASON%",
+	"user_agent":                        "%REQ(USER-AGENT)%",
+	"x_forwarded_for":                   "%REQ(X-FORWARDED-FOR)%",
+}
+
+// AccessLog is appended to the access logs of the gateway configured by the mesh config.
+type AccessLog struct {
+	// Flag to control access log
+	Enable bool `json:"enable,omitempty"`
+	// Format of the log entries, text or json. Default is text.
+	Format string `json:"format,omitempty"`
+	// The format string of the text format, envoy command operators such as %RESPONSE_CODE% can be used.
+	// Default is the format of envoy.
+	Text string `json:"text,omitempty"`
+	// The fields of the json format, the values are envoy command operators.
+	// Default is the fields of the access log of the gateway.
+	Fields map[string]string `json:"fields,omitempty"`
+	// The filter state keys to log, e.g. ai_log written by the ai-statistics plugin.
+	// The keys without dots are prefixed with "wasm." as the wasm plugins do,
+	// they are logged as the json fields or appended to the text format.
+	FilterStateKeys []string `json:"filterStateKeys,omitempty"`
+	// Only the requests matching the filter are logged
+	Filter *AccessLogFilter `json:"filter,omitempty"`
+	// The sinks of the log entries, stdout is used if none is set
+	Stdout        bool                    `json:"stdout,omitempty"`
+	File          *AccessLogFile          `json:"file,omitempty"`
+	OpenTelemetry *AccessLogOpenTelemetry `json:"opentelemetry,omitempty"`
+}
+
+// AccessLogFilter defines the conditions of the requests to log, all the conditions set must be met.
+type AccessLogFilter struct {
+	// The range of the response status codes, e.g. 400 - 599 for the errors only
+	MinStatusCode uint32 `json:"minStatusCode,omitempty"`
+	MaxStatusCode uint32 `json:"maxStatusCode,omitempty"`
+	// The minimum duration of the requests in milliseconds
+	MinDuration uint32 `json:"minDuration,omitempty"`
+	// The names of the routes, as logged by %ROUTE_NAME%
+	Routes []string `json:"routes,omitempty"`
+}
+
+// AccessLogFile defines a file sink.
+type AccessLogFile struct {
+	// Absolute path of the file
+	Path string `json:"path,omitempty"`
+}
+
+// AccessLogOpenTelemetry defines an OpenTelemetry gRPC sink.
+type AccessLogOpenTelemetry struct {
+	// Address of the OpenTelemetry collector.
+	Service string `json:"service,omitempty"`
+	Port    string `json:"port,omitempty"`
+}
+
+func validAccessLog(a *AccessLog) error {
+	if a == nil {
+		return nil
+	}
+
+	if a.Format != AccessLogFormatText && a.Format != AccessLogFormatJSON {
+		return fmt.Errorf("format need be one of %s,%s", AccessLogFormatText, AccessLogFormatJSON)
+	}
+
+	for _, key := range a.FilterStateKeys {
+		if key == "" {
+			return errors.New("filter state key can not be empty")
+		}
+	}
+
+	if f := a.Filter; f != nil {
+		for _, code := range []uint32{f.MinStatusCode, f.MaxStatusCode} {
+			if code != 0 && (code < 100 || code > 599) {
+				return errors.New("status code need be between 100 and 599")
+			}
+		}
+		if f.MinStatusCode != 0 && f.MaxStatusCode != 0 && f.MinStatusCode > f.MaxStatusCode {
+			return errors.New("minStatusCode can not be greater than maxStatusCode")
+		}
+		for _, route := range f.Routes {
+			if route == "" || strings.ContainsAny(route, `'\`) {
+				return fmt.Errorf("invalid route name %q", route)
+			}
+		}
+	}
+
+	if a.File != nil && !filepath.IsAbs(a.File.Path) {
+		return errors.New("file path need be an absolute path")
+	}
+
+	if a.OpenTelemetry != nil && !validServiceAndPort(a.OpenTelemetry.Service, a.OpenTelemetry.Port) {
+		return errors.New("opentelemetry service and port can not be empty")
+	}
+	return nil
+}
+
+func compareAccessLog(old *AccessLog, new *AccessLog) (Result, error) {
+	if old == nil && new == nil {
+		return ResultNothing, nil
+	}
+
+	if new == nil {
+		return ResultDelete, nil
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		return ResultReplace, nil
+	}
+
+	return ResultNothing, nil
+}
+
+func deepCopyAccessLog(accessLog *AccessLog) (*AccessLog, error) {
+	newAccessLog := NewDefaultAccessLog()
+	bytes, err := json.Marshal(accessLog)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(bytes, newAccessLog)
+	return newAccessLog, err
+}
+
+func NewDefaultAccessLog() *AccessLog {
+	accessLog := &AccessLog{
+		Enable: false,
+		Format: AccessLogFormatText,
+	}
+	return accessLog
+}
+
+type AccessLogController struct {
+	Namespace    string
+	accessLog    atomic.Value
+	Name         string
+	eventHandler ItemEventHandler
+}
+
+func NewAccessLogController(namespace string) *AccessLogController {
+	accessLogController := &AccessLogController{
+		Namespace: namespace,
+		accessLog: atomic.Value{},
+		Name:      "accessLog",
+	}
+	accessLogController.SetAccessLog(NewDefaultAccessLog())
+	return accessLogController
+}
+
+func (a *AccessLogController) GetName() string {
+	return a.Name
+}
+
+func (a *AccessLogController) SetAccessLog(accessLog *AccessLog) {
+	a.accessLog.Store(accessLog)
+}
+
+func (a *AccessLogController) GetAccessLog() *AccessLog {
+	value := a.accessLog.Load()
+	if value != nil {
+		if accessLog, ok := value.(*AccessLog); ok {
+			return accessLog
+		}
+	}
+	return nil
+}
+
+func (a *AccessLogController) AddOrUpdateHigressConfig(name util.ClusterNamespacedName, old *HigressConfig, new *HigressConfig) error {
+	if err := validAccessLog(new.AccessLog); err != nil {
+		IngressLog.Errorf("data:%+v convert to access log, error: %+v", new.AccessLog, err)
+		return nil
+	}
+
+	result, _ := compareAccessLog(old.AccessLog, new.AccessLog)
+
+	switch result {
+	case ResultReplace:
+		if newAccessLog, err := deepCopyAccessLog(new.AccessLog); err != nil {
+			IngressLog.Infof("access log deepcopy error:%v", err)
+		} else {
+			a.SetAccessLog(newAccessLog)
+			IngressLog.Infof("AddOrUpdate Higress config access log")
+			a.eventHandler(higressAccessLogEnvoyFilterName)
+			IngressLog.Infof("send event with filter name:%s", higressAccessLogEnvoyFilterName)
+		}
+	case ResultDelete:
+		a.SetAccessLog(NewDefaultAccessLog())
+		IngressLog.Infof("Delete Higress config access log")
+		a.eventHandler(higressAccessLogEnvoyFilterName)
+		IngressLog.Infof("send event with filter name:%s", higressAccessLogEnvoyFilterName)
+	}
+
+	return nil
+}
+
+func (a *AccessLogController) ValidHigressConfig(higressConfig *HigressConfig) error {
+	if higressConfig == nil {
+		return nil
+	}
+	if higressConfig.AccessLog == nil {
+		return nil
+	}
+
+	return validAccessLog(higressConfig.AccessLog)
+}
+
+func (a *AccessLogController) RegisterItemEventHandler(eventHandler ItemEventHandler) {
+	a.eventHandler = eventHandler
+}
+
+func (a *AccessLogController) ConstructEnvoyFilters() ([]*config.Config, error) {
+	configs := make([]*config.Config, 0)
+	accessLog := a.GetAccessLog()
+	namespace := a.Namespace
+
+	if accessLog == nil {
+		return configs, nil
+	}
+
+	if accessLog.Enable == false {
+		return configs, nil
+	}
+
+	accessLogStruct, err := constructAccessLogStruct(accessLog)
+	if err != nil {
+		return configs, err
+	}
+
+	configPatches := []*networking.EnvoyFilter_EnvoyConfigObjectPatch{
+		{
+			ApplyTo: networking.EnvoyFilter_NETWORK_FILTER,
+			Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: networking.EnvoyFilter_GATEWAY,
+				ObjectTypes: &networking.EnvoyFilter_EnvoyConfigObjectMatch_Listener{
+					Listener: &networking.EnvoyFilter_ListenerMatch{
+						FilterChain: &networking.EnvoyFilter_ListenerMatch_FilterChainMatch{
+							Filter: &networking.EnvoyFilter_ListenerMatch_FilterMatch{
+								Name: "envoy.filters.network.http_connection_manager",
+							},
+						},
+					},
+				},
+			},
+			Patch: &networking.EnvoyFilter_Patch{
+				Operation: networking.EnvoyFilter_Patch_MERGE,
+				Value:     util.BuildPatchStruct(accessLogStruct),
+			},
+		},
+	}
+	if otel := accessLog.OpenTelemetry; otel != nil {
+		configPatches = append(configPatches, constructHTTP2ProtocolOptionsPatch(otel.Port, otel.Service))
+	}
+
+	config := &config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.EnvoyFilter,
+			Name:             higressAccessLogEnvoyFilterName,
+			Namespace:        namespace,
+		},
+		Spec: &networking.EnvoyFilter{
+			ConfigPatches: configPatches,
+		},
+	}
+
+	configs = append(configs, config)
+	return configs, nil
+}
+
+// constructAccessLogStruct builds the http connection manager patch with an access log for each sink.
+// The json is marshalled from maps since the formats may contain any characters.
+func constructAccessLogStruct(accessLog *AccessLog) (string, error) {
+	filter := constructAccessLogFilter(accessLog.Filter)
+	var text string
+	var fields map[string]string
+	if accessLog.Format == AccessLogFormatJSON {
+		fields = accessLogJSONFields(accessLog)
+	} else {
+		text = accessLogText(accessLog)
+	}
+
+	var accessLogs []interface{}
+	addAccessLog := func(name, typeURL string, typedConfig map[string]interface{}) {
+		typedConfig["@type"] = typeURL
+		entry := map[string]interface{}{
+			"name":         name,
+			"typed_config": typedConfig,
+		}
+		if filter != nil {
+			entry["filter"] = filter
+		}
+		accessLogs = append(accessLogs, entry)
+	}
+
+	if accessLog.Stdout || (accessLog.File == nil && accessLog.OpenTelemetry == nil) {
+		addAccessLog("envoy.access_loggers.stdout",
+			"type.googleapis.com/envoy.extensions.access_loggers.stream.v3.StdoutAccessLog",
+			map[string]interface{}{"log_format": substitutionFormat(text, fields)})
+	}
+	if file := accessLog.File; file != nil {
+		addAccessLog("envoy.access_loggers.file",
+			"type.googleapis.com/envoy.extensions.access_loggers.file.v3.FileAccessLog",
+			map[string]interface{}{
+				"path":       file.Path,
+				"log_format": substitutionFormat(text, fields),
+			})
+	}
+	if otel := accessLog.OpenTelemetry; otel != nil {
+		otelConfig := map[string]interface{}{
+			"common_config": map[string]interface{}{
+				"log_name": accessLogName,
+				"grpc_service": map[string]interface{}{
+					"envoy_grpc": map[string]interface{}{
+						"cluster_name": tracingClusterName(otel.Port, otel.Service),
+					},
+				},
+				"transport_api_version": "V3",
+			},
+		}
+		if fields != nil {
+			otelConfig["attributes"] = otelKeyValueList(fields)
+		} else {
+			otelConfig["body"] = map[string]interface{}{"string_value": strings.TrimSuffix(text, "\n")}
+		}
+		addAccessLog("envoy.access_loggers.open_telemetry",
+			"type.googleapis.com/envoy.extensions.access_loggers.open_telemetry.v3.OpenTelemetryAccessLogConfig",
+			otelConfig)
+	}
+
+	patch := map[string]interface{}{
+		"name": "envoy.filters.network.http_connection_manager",
+		"typed_config": map[string]interface{}{
+			"@type":      "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+			"access_log": accessLogs,
+		},
+	}
+	bytes, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+func filterStateOperator(key string) string {
+	if !strings.Contains(key, ".") {
+		key = wasmFilterStatePrefix + key
+	}
+	return fmt.Sprintf("%%FILTER_STATE(%s:PLAIN)%%", key)
+}
+
+func accessLogJSONFields(accessLog *AccessLog) map[string]string {
+	source := accessLog.Fields
+	if len(source) == 0 {
+		source = defaultAccessLogJSONFields
+	}
+	fields := make(map[string]string, len(source)+len(accessLog.FilterStateKeys))
+	for k, v := range source {
+		fields[k] = v
+	}
+	for _, key := range accessLog.FilterStateKeys {
+		fields[key] = filterStateOperator(key)
+	}
+	return fields
+}
+
+func accessLogText(accessLog *AccessLog) string {
+	text := accessLog.Text
+	if text == "" {
+		text = defaultAccessLogTextFormat
+	}
+	text = strings.TrimSuffix(text, "\n")
+	for _, key := range accessLog.FilterStateKeys {
+		text += fmt.Sprintf(` "%s"`, filterStateOperator(key))
+	}
+	return text + "\n"
+}
+
+func substitutionFormat(text string, fields map[string]string) map[string]interface{} {
+	if fields != nil {
+		return map[string]interface{}{"json_format": fields}
+	}
+	return map[string]interface{}{
+		"text_format_source": map[string]interface{}{"inline_string": text},
+	}
+}
+
+func otelKeyValueList(fields map[string]string) map[string]interface{} {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, map[string]interface{}{
+			"key":   k,
+			"value": map[string]interface{}{"string_value": fields[k]},
+		})
+	}
+	return map[string]interface{}{"values": values}
+}
+
+// constructAccessLogFilter returns nil if no condition is set, the conditions are combined by an and filter.
+func constructAccessLogFilter(f *AccessLogFilter) map[string]interface{} {
+	if f == nil {
+		return nil
+	}
+	comparison := func(op string, value uint32, runtimeKey string) map[string]interface{} {
+		return map[string]interface{}{
+			"comparison": map[string]interface{}{
+				"op": op,
+				"value": map[string]interface{}{
+					"default_value": value,
+					"runtime_key":   runtimeKey,
+				},
+			},
+		}
+	}
+	var filters []interface{}
+	if f.MinStatusCode != 0 {
+		filters = append(filters, map[string]interface{}{
+			"status_code_filter": comparison("GE", f.MinStatusCode, "access_log.higress.min_status_code"),
+		})
+	}
+	if f.MaxStatusCode != 0 {
+		filters = append(filters, map[string]interface{}{
+			"status_code_filter": comparison("LE", f.MaxStatusCode, "access_log.higress.max_status_code"),
+		})
+	}
+	if f.MinDuration != 0 {
+		filters = append(filters, map[string]interface{}{
+			"duration_filter": comparison("GE", f.MinDuration, "access_log.higress.min_duration"),
+		})
+	}
+	if len(f.Routes) > 0 {
+		filters = append(filters, map[string]interface{}{
+			"extension_filter": map[string]interface{}{
+				"name": "envoy.access_loggers.extension_filters.cel",
+				"typed_config": map[string]interface{}{
+					"@type":      "type.googleapis.com/envoy.extensions.access_loggers.filters.cel.v3.ExpressionFilter",
+					"expression": fmt.Sprintf("xds.route_name in ['%s']", strings.Join(f.Routes, "', '")),
+				},
+			},
+		})
+	}
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0].(map[string]interface{})
+	}
+	return map[string]interface{}{
+		"and_filter": map[string]interface{}{"filters": filters},
+	}
+}
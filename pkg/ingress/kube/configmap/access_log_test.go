@@ -0,0 +1,294 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validAccessLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		accessLog *AccessLog
+		wantErr   error
+	}{
+		{
+			name:      "default",
+			accessLog: NewDefaultAccessLog(),
+			wantErr:   nil,
+		},
+		{
+			name:      "nil",
+			accessLog: nil,
+			wantErr:   nil,
+		},
+		{
+			name: "unknown format",
+			accessLog: &AccessLog{
+				Enable: true,
+				Format: "xml",
+			},
+			wantErr: errors.New("format need be one of text,json"),
+		},
+		{
+			name: "status code out of range",
+			accessLog: &AccessLog{
+				Enable: true,
+				Format: AccessLogFormatJSON,
+				Filter: &AccessLogFilter{MinStatusCode: 600},
+			},
+			wantErr: errors.New("status code need be between 100 and 599"),
+		},
+		{
+			name: "min status code greater than max",
+			accessLog: &AccessLog{
+				Enable: true,
+				Format: AccessLogFormatJSON,
+				Filter: &AccessLogFilter{MinStatusCode: 500, MaxStatusCode: 400},
+			},
+			wantErr: errors.New("minStatusCode can not be greater than maxStatusCode"),
+		},
+		{
+			name: "route with quote",
+			accessLog: &AccessLog{
+				Enable: true,
+				Format: AccessLogFormatText,
+				Filter: &AccessLogFilter{Routes: []string{"foo'"}},
+			},
+			wantErr: errors.New(`invalid route name "foo'"`),
+		},
+		{
+			name: "relative file path",
+			accessLog: &AccessLog{
+				Enable: true,
+				Format: AccessLogFormatText,
+				File:   &AccessLogFile{Path: "access.log"},
+			},
+			wantErr: errors.New("file path need be an absolute path"),
+		},
+		{
+			name: "opentelemetry without port",
+			accessLog: &AccessLog{
+				Enable:        true,
+				Format:        AccessLogFormatText,
+				OpenTelemetry: &AccessLogOpenTelemetry{Service: "otel-collector.monitoring.svc.cluster.local"},
+			},
+			wantErr: errors.New("opentelemetry service and port can not be empty"),
+		},
+		{
+			name: "all sinks",
+			accessLog: &AccessLog{
+				Enable:          true,
+				Format:          AccessLogFormatJSON,
+				FilterStateKeys: []string{"ai_log"},
+				Filter:          &AccessLogFilter{MinStatusCode: 400, MaxStatusCode: 599, MinDuration: 1000, Routes: []string{"foo"}},
+				Stdout:          true,
+				File:            &AccessLogFile{Path: "/var/log/proxy/higress.log"},
+				OpenTelemetry:   &AccessLogOpenTelemetry{Service: "otel-collector.monitoring.svc.cluster.local", Port: "4317"},
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validAccessLog(tt.accessLog)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_constructAccessLogStruct(t *testing.T) {
+	t.Run("default text to stdout", func(t *testing.T) {
+		accessLog := NewDefaultAccessLog()
+		accessLog.Enable = true
+		accessLog.FilterStateKeys = []string{"ai_log"}
+		accessLogStruct, err := constructAccessLogStruct(accessLog)
+		require.NoError(t, err)
+
+		patch := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(accessLogStruct), &patch))
+		accessLogs := patch["typed_config"].(map[string]interface{})["access_log"].([]interface{})
+		require.Len(t, accessLogs, 1)
+		entry := accessLogs[0].(map[string]interface{})
+		assert.Equal(t, "envoy.access_loggers.stdout", entry["name"])
+		assert.Nil(t, entry["filter"])
+		text := entry["typed_config"].(map[string]interface{})["log_format"].(map[string]interface{})["text_format_source"].(map[string]interface{})["inline_string"]
+		assert.Contains(t, text, `"%UPSTREAM_HOST%" "%FILTER_STATE(wasm.ai_log:PLAIN)%"`+"\n")
+	})
+
+	t.Run("json with filter to file and opentelemetry", func(t *testing.T) {
+		accessLog := &AccessLog{
+			Enable: true,
+			Format: AccessLogFormatJSON,
+			Fields: map[string]string{
+				"code": "%RESPONSE_CODE%",
+			},
+			FilterStateKeys: []string{"ai_log", "envoy.custom"},
+			Filter:          &AccessLogFilter{MinStatusCode: 500, MinDuration: 1000, Routes: []string{"foo", "default/bar"}},
+			File:            &AccessLogFile{Path: "/var/log/proxy/higress.log"},
+			OpenTelemetry:   &AccessLogOpenTelemetry{Service: "otel-collector.monitoring.svc.cluster.local", Port: "4317"},
+		}
+		accessLogStruct, err := constructAccessLogStruct(accessLog)
+		require.NoError(t, err)
+
+		expected := `{
+	"name": "envoy.filters.network.http_connection_manager",
+	"typed_config": {
+		"@type": "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+		"access_log": [
+			{
+				"name": "envoy.access_loggers.file",
+				"filter": {"and_filter": {"filters": [
+					{"status_code_filter": {"comparison": {"op": "GE", "value": {"default_value": 500, "runtime_key": "access_log.higress.min_status_code"}}}},
+					{"duration_filter": {"comparison": {"op": "GE", "value": {"default_value": 1000, "runtime_key": "access_log.higress.min_duration"}}}},
+					{"extension_filter": {
+						"name": "envoy.access_loggers.extension_filters.cel",
+						"typed_config": {
+							"@type": "type.googleapis.com/envoy.extensions.access_loggers.filters.cel.v3.ExpressionFilter",
+							"expression": "xds.route_name in ['foo', 'default/bar']"
+						}
+					}}
+				]}},
+				"typed_config": {
+					"@type": "type.googleapis.com/envoy.extensions.access_loggers.file.v3.FileAccessLog",
+					"path": "/var/log/proxy/higress.log",
+					"log_format": {"json_format": {
+						"code": "%RESPONSE_CODE%",
+						"ai_log": "%FILTER_STATE(wasm.ai_log:PLAIN)%",
+						"envoy.custom": "%FILTER_STATE(envoy.custom:PLAIN)%"
+					}}
+				}
+			},
+			{
+				"name": "envoy.access_loggers.open_telemetry",
+				"filter": {"and_filter": {"filters": [
+					{"status_code_filter": {"comparison": {"op": "GE", "value": {"default_value": 500, "runtime_key": "access_log.higress.min_status_code"}}}},
+					{"duration_filter": {"comparison": {"op": "GE", "value": {"default_value": 1000, "runtime_key": "access_log.higress.min_duration"}}}},
+					{"extension_filter": {
+						"name": "envoy.access_loggers.extension_filters.cel",
+						"typed_config": {
+							"@type": "type.googleapis.com/envoy.extensions.access_loggers.filters.cel.v3.ExpressionFilter",
+							"expression": "xds.route_name in ['foo', 'default/bar']"
+						}
+					}}
+				]}},
+				"typed_config": {
+					"@type": "type.googleapis.com/envoy.extensions.access_loggers.open_telemetry.v3.OpenTelemetryAccessLogConfig",
+					"common_config": {
+						"log_name": "higress-gateway",
+						"grpc_service": {"envoy_grpc": {"cluster_name": "outbound|4317||otel-collector.monitoring.svc.cluster.local"}},
+						"transport_api_version": "V3"
+					},
+					"attributes": {"values": [
+						{"key": "ai_log", "value": {"string_value": "%FILTER_STATE(wasm.ai_log:PLAIN)%"}},
+						{"key": "code", "value": {"string_value": "%RESPONSE_CODE%"}},
+						{"key": "envoy.custom", "value": {"string_value": "%FILTER_STATE(envoy.custom:PLAIN)%"}}
+					]}
+				}
+			}
+		]
+	}
+}`
+		assert.JSONEq(t, expected, accessLogStruct)
+	})
+}
+
+func Test_deepCopyAccessLog(t *testing.T) {
+	accessLog := &AccessLog{
+		Enable:          true,
+		Format:          AccessLogFormatJSON,
+		Fields:          map[string]string{"code": "%RESPONSE_CODE%"},
+		FilterStateKeys: []string{"ai_log"},
+		Filter:          &AccessLogFilter{MinStatusCode: 500},
+		Stdout:          true,
+	}
+	newAccessLog, err := deepCopyAccessLog(accessLog)
+	require.NoError(t, err)
+	assert.Equal(t, accessLog, newAccessLog)
+	newAccessLog.Fields["code"] = "%RESPONSE_FLAGS%"
+	assert.Equal(t, "%RESPONSE_CODE%", accessLog.Fields["code"])
+}
+
+func TestAccessLogController_AddOrUpdateHigressConfig(t *testing.T) {
+	eventPush := "default"
+	defaultHandler := func(name string) {
+		eventPush = "push"
+	}
+
+	defaultName := util.ClusterNamespacedName{}
+
+	enabled := &AccessLog{
+		Enable: true,
+		Format: AccessLogFormatText,
+		Filter: &AccessLogFilter{MinStatusCode: 400},
+	}
+
+	tests := []struct {
+		name          string
+		old           *HigressConfig
+		new           *HigressConfig
+		wantEventPush string
+		wantAccessLog *AccessLog
+	}{
+		{
+			name:          "default",
+			old:           &HigressConfig{AccessLog: NewDefaultAccessLog()},
+			new:           &HigressConfig{AccessLog: NewDefaultAccessLog()},
+			wantEventPush: "default",
+			wantAccessLog: NewDefaultAccessLog(),
+		},
+		{
+			name:          "replace and push",
+			old:           &HigressConfig{AccessLog: NewDefaultAccessLog()},
+			new:           &HigressConfig{AccessLog: enabled},
+			wantEventPush: "push",
+			wantAccessLog: enabled,
+		},
+		{
+			name:          "delete and push",
+			old:           &HigressConfig{AccessLog: enabled},
+			new:           &HigressConfig{AccessLog: nil},
+			wantEventPush: "push",
+			wantAccessLog: NewDefaultAccessLog(),
+		},
+		{
+			name:          "invalid config is ignored",
+			old:           &HigressConfig{AccessLog: NewDefaultAccessLog()},
+			new:           &HigressConfig{AccessLog: &AccessLog{Enable: true, Format: "xml"}},
+			wantEventPush: "default",
+			wantAccessLog: NewDefaultAccessLog(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAccessLogController("higress-system")
+			a.eventHandler = defaultHandler
+			eventPush = "default"
+			err := a.AddOrUpdateHigressConfig(defaultName, tt.old, tt.new)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEventPush, eventPush)
+			assert.Equal(t, tt.wantAccessLog, a.GetAccessLog())
+		})
+	}
+}
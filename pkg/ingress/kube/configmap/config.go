@@ -41,6 +41,7 @@ type HigressConfig struct {
 	DisableXEnvoyHeaders bool        `json:"disableXEnvoyHeaders,omitempty"`
 	AddXRealIpHeader     bool        `json:"addXRealIpHeader,omitempty"`
 	McpServer            *McpServer  `json:"mcpServer,omitempty"`
+	AccessLog            *AccessLog  `json:"accessLog,omitempty"`
 }
 
 func NewDefaultHigressConfig() *HigressConfig {
@@ -53,6 +54,7 @@ func NewDefaultHigressConfig() *HigressConfig {
 		DisableXEnvoyHeaders: globalOption.DisableXEnvoyHeaders,
 		AddXRealIpHeader:     globalOption.AddXRealIpHeader,
 		McpServer:            NewDefaultMcpServer(),
+		AccessLog:            NewDefaultAccessLog(),
 	}
 	return higressConfig
 }
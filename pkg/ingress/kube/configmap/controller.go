@@ -93,6 +93,9 @@ func NewConfigmapMgr(XDSUpdater model.XDSUpdater, namespace string, higressConfi
 	mcpServerController := NewMcpServerController(namespace)
 	configmapMgr.AddItemControllers(mcpServerController)
 
+	accessLogController := NewAccessLogController(namespace)
+	configmapMgr.AddItemControllers(accessLogController)
+
 	configmapMgr.initEventHandlers()
 
 	return configmapMgr
@@ -16,9 +16,13 @@ package configmap
 
 import (
 	"fmt"
+	"net/netip"
 	"reflect"
+	"regexp"
+	"strings"
 	"sync/atomic"
 
+	higressconfig "github.com/alibaba/higress/v2/pkg/config"
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/util"
 	. "github.com/alibaba/higress/v2/pkg/ingress/log"
 	networking "istio.io/api/networking/v1alpha3"
@@ -48,8 +52,18 @@ const (
 	defaultInitialConnectionWindowSize    = 1048576
 	defaultAddXRealIpHeader               = false
 	defaultDisableXEnvoyHeaders           = false
+
+	defaultHttp3Port         = 443
+	defaultHttp3AltSvcMaxAge = 86400
+	maxQuicStreamWindowSize  = 16777216
+	maxQuicConnWindowSize    = 25165824
+
+	proxyProtocolV1 = "v1"
+	proxyProtocolV2 = "v2"
 )
 
+var headerNameRegex = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+.^_`|~-]+$")
+
 // Global configures the behavior of the downstream connection, x-real-ip header and x-envoy headers.
 type Global struct {
 	Downstream           *Downstream `json:"downstream,omitempty"`
@@ -70,6 +84,61 @@ type Downstream struct {
 	Http2 *Http2 `json:"http2,omitempty"`
 	// RouteTimeout limits the time that timeout for the route.
 	RouteTimeout uint32 `json:"routeTimeout"`
+	// ClientIp configures how the address of the client is extracted.
+	ClientIp *ClientIp `json:"clientIp,omitempty"`
+	// Http3 configures HTTP/3 specific options.
+	Http3 *Http3 `json:"http3,omitempty"`
+}
+
+// Http3 configures HTTP/3 specific options. The QUIC listeners are built by pilot for the HTTPS gateways with the
+// same certificates only when global.enableH3 is set in the helm chart, which is also the switch of the UDP port
+// of the gateway service. Http3 does not create or remove any listener, and it is ignored if global.enableH3 is
+// not set, so that HTTP/3 is never advertised without the QUIC listeners.
+type Http3 struct {
+	// Enable advertises HTTP/3 with the alt-svc header and applies the QUIC options to the listeners on the port
+	Enable bool `json:"enable,omitempty"`
+	// Port is the UDP port of the QUIC listeners, which is the same as the HTTPS port. Default is 443.
+	// The helm chart exposes the same port in the gateway service.
+	Port uint32 `json:"port,omitempty"`
+	// AltSvcMaxAge is the max age in seconds of the alt-svc header advertising HTTP/3 in the responses
+	// over HTTP/1.1 and HTTP/2. Default is 86400.
+	AltSvcMaxAge uint32 `json:"altSvcMaxAge,omitempty"`
+	// MaxConcurrentStreams limits the number of concurrent streams allowed.
+	MaxConcurrentStreams uint32 `json:"maxConcurrentStreams,omitempty"`
+	// InitialStreamWindowSize limits the initial window size of stream.
+	InitialStreamWindowSize uint32 `json:"initialStreamWindowSize,omitempty"`
+	// InitialConnectionWindowSize limits the initial window size of connection.
+	InitialConnectionWindowSize uint32 `json:"initialConnectionWindowSize,omitempty"`
+}
+
+// ClientIp configures how the address of the client is extracted from the proxies in front of the gateway,
+// the extracted address is used as the remote address seen by the plugins and in the access logs.
+type ClientIp struct {
+	// XffNumTrustedHops is the number of the trusted proxies appending to the x-forwarded-for header,
+	// the address at this position from the right of the header is used. It conflicts with TrustedCidrs.
+	XffNumTrustedHops uint32 `json:"xffNumTrustedHops,omitempty"`
+	// TrustedCidrs are the addresses of the trusted proxies, the rightmost address of the x-forwarded-for
+	// header out of them is used.
+	TrustedCidrs []string `json:"trustedCidrs,omitempty"`
+	// OriginalIpHeader is the header carrying the address of the client set by the trusted proxy,
+	// e.g. x-real-ip, it takes precedence over the x-forwarded-for header.
+	// The header is taken from any peer since envoy can't check it against TrustedCidrs, so the proxy
+	// in front of the gateway must overwrite the header sent by the clients, or they can spoof their
+	// addresses. The address from the header is never taken as a trusted internal address.
+	OriginalIpHeader string `json:"originalIpHeader,omitempty"`
+	// ProxyProtocol configures the PROXY protocol on the listeners.
+	ProxyProtocol *ProxyProtocol `json:"proxyProtocol,omitempty"`
+}
+
+// ProxyProtocol configures the PROXY protocol on the listeners.
+type ProxyProtocol struct {
+	// Flag to control the PROXY protocol
+	Enable bool `json:"enable,omitempty"`
+	// Versions are the accepted versions, v1 and v2. Default is both.
+	Versions []string `json:"versions,omitempty"`
+	// AllowRequestsWithoutProxyProtocol accepts the connections without the PROXY protocol header,
+	// e.g. the health checks of the load balancer.
+	AllowRequestsWithoutProxyProtocol bool `json:"allowRequestsWithoutProxyProtocol,omitempty"`
 }
 
 // Upstream configures the behavior of the upstream connection.
@@ -124,10 +193,79 @@ func validGlobal(global *Global) error {
 			return fmt.Errorf("http2.initialConnectionWindowSize must be between 65535 and 2147483647")
 		}
 	}
+	// check clientIp
+	if downStream.ClientIp != nil {
+		if err := validClientIp(downStream.ClientIp); err != nil {
+			return err
+		}
+	}
+	// check http3
+	if downStream.Http3 != nil {
+		if err := validHttp3(downStream.Http3); err != nil {
+			return err
+		}
+		// the proxy protocol listener filter can not be applied to the QUIC listeners
+		if downStream.Http3.Enable && downStream.ClientIp != nil && downStream.ClientIp.ProxyProtocol != nil &&
+			downStream.ClientIp.ProxyProtocol.Enable {
+			return fmt.Errorf("http3 and clientIp.proxyProtocol can not be enabled at the same time")
+		}
+	}
+
+	return nil
+}
+
+// validHttp3 validates the http3 config.
+func validHttp3(http3 *Http3) error {
+	if http3.Port > 65535 {
+		return fmt.Errorf("http3.port must be between 1 and 65535")
+	}
+	if http3.MaxConcurrentStreams > maxMaxConcurrentStreams {
+		return fmt.Errorf("http3.maxConcurrentStreams must be between 1 and 2147483647")
+	}
+	if http3.InitialStreamWindowSize > maxQuicStreamWindowSize {
+		return fmt.Errorf("http3.initialStreamWindowSize must be between 1 and 16777216")
+	}
+	if http3.InitialConnectionWindowSize > maxQuicConnWindowSize {
+		return fmt.Errorf("http3.initialConnectionWindowSize must be between 1 and 25165824")
+	}
+	return nil
+}
 
+// validClientIp validates the client ip config.
+func validClientIp(clientIp *ClientIp) error {
+	if clientIp.XffNumTrustedHops > 0 && len(clientIp.TrustedCidrs) > 0 {
+		return fmt.Errorf("clientIp.xffNumTrustedHops and clientIp.trustedCidrs can not be set at the same time")
+	}
+	for _, cidr := range clientIp.TrustedCidrs {
+		if _, err := parseCidr(cidr); err != nil {
+			return fmt.Errorf("clientIp.trustedCidrs has invalid cidr %q", cidr)
+		}
+	}
+	if clientIp.OriginalIpHeader != "" && !headerNameRegex.MatchString(clientIp.OriginalIpHeader) {
+		return fmt.Errorf("clientIp.originalIpHeader has invalid header name %q", clientIp.OriginalIpHeader)
+	}
+	if clientIp.ProxyProtocol != nil {
+		for _, version := range clientIp.ProxyProtocol.Versions {
+			if version != proxyProtocolV1 && version != proxyProtocolV2 {
+				return fmt.Errorf("clientIp.proxyProtocol.versions must be v1 or v2")
+			}
+		}
+	}
 	return nil
 }
 
+// parseCidr parses the cidr, a single address is taken as the cidr of the address only.
+func parseCidr(cidr string) (netip.Prefix, error) {
+	if prefix, err := netip.ParsePrefix(cidr); err == nil {
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(cidr)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
 // compareGlobal compares the old and new global option.
 func compareGlobal(old *Global, new *Global) (Result, error) {
 	if old == nil && new == nil {
@@ -162,6 +300,24 @@ func deepCopyGlobal(global *Global) (*Global, error) {
 			newGlobal.Downstream.Http2.InitialConnectionWindowSize = global.Downstream.Http2.InitialConnectionWindowSize
 		}
 		newGlobal.Downstream.RouteTimeout = global.Downstream.RouteTimeout
+		if http3 := global.Downstream.Http3; http3 != nil {
+			newHttp3 := *http3
+			newGlobal.Downstream.Http3 = &newHttp3
+		}
+		if clientIp := global.Downstream.ClientIp; clientIp != nil {
+			newGlobal.Downstream.ClientIp = &ClientIp{
+				XffNumTrustedHops: clientIp.XffNumTrustedHops,
+				TrustedCidrs:      append([]string(nil), clientIp.TrustedCidrs...),
+				OriginalIpHeader:  clientIp.OriginalIpHeader,
+			}
+			if proxyProtocol := clientIp.ProxyProtocol; proxyProtocol != nil {
+				newGlobal.Downstream.ClientIp.ProxyProtocol = &ProxyProtocol{
+					Enable:                            proxyProtocol.Enable,
+					Versions:                          append([]string(nil), proxyProtocol.Versions...),
+					AllowRequestsWithoutProxyProtocol: proxyProtocol.AllowRequestsWithoutProxyProtocol,
+				}
+			}
+		}
 	}
 	if global.Upstream != nil {
 		newGlobal.Upstream.IdleTimeout = global.Upstream.IdleTimeout
@@ -216,14 +372,17 @@ type GlobalOptionController struct {
 	global       atomic.Value
 	Name         string
 	eventHandler ItemEventHandler
+	// quicListenersEnabled is whether the QUIC listeners are built, which is required by downstream.http3.
+	quicListenersEnabled bool
 }
 
 // NewGlobalOptionController returns a GlobalOptionController.
 func NewGlobalOptionController(namespace string) *GlobalOptionController {
 	globalOptionController := &GlobalOptionController{
-		Namespace: namespace,
-		global:    atomic.Value{},
-		Name:      "global-option",
+		Namespace:            namespace,
+		global:               atomic.Value{},
+		Name:                 "global-option",
+		quicListenersEnabled: higressconfig.EnableH3,
 	}
 	globalOptionController.SetGlobal(NewDefaultGlobalOption())
 	return globalOptionController
@@ -338,6 +497,20 @@ func (g *GlobalOptionController) ConstructEnvoyFilters() ([]*config.Config, erro
 		if downstreamConfig != nil {
 			configPatch = append(configPatch, downstreamConfig...)
 		}
+		if http3 := global.Downstream.Http3; http3 != nil && http3.Enable {
+			if g.quicListenersEnabled {
+				altSvcStruct := g.constructHttp3AltSvc(http3)
+				http3Struct := g.constructHttp3(http3)
+				configPatch = append(configPatch, g.generateHttp3EnvoyFilter(http3Port(http3), altSvcStruct, http3Struct)...)
+			} else {
+				IngressLog.Warnf("downstream.http3 is ignored since there is no QUIC listener, set global.enableH3 to enable HTTP/3")
+			}
+		}
+		if global.Downstream.ClientIp != nil {
+			clientIpStruct := g.constructClientIp(global.Downstream.ClientIp)
+			proxyProtocolStruct := g.constructProxyProtocol(global.Downstream.ClientIp.ProxyProtocol)
+			configPatch = append(configPatch, g.generateClientIpEnvoyFilter(clientIpStruct, proxyProtocolStruct)...)
+		}
 	}
 
 	if global.Upstream != nil {
@@ -440,6 +613,96 @@ func (g *GlobalOptionController) generateDownstreamEnvoyFilter(downstreamValueSt
 	return downstreamConfig
 }
 
+// generateHttp3EnvoyFilter generates the http3 envoy filter, which applies to the HTTPS listeners and routes
+// as well as the QUIC listeners on the same port.
+func (g *GlobalOptionController) generateHttp3EnvoyFilter(port uint32, altSvcStruct string, http3Struct string) []*networking.EnvoyFilter_EnvoyConfigObjectPatch {
+	var http3Config []*networking.EnvoyFilter_EnvoyConfigObjectPatch
+
+	http3Config = append(http3Config, &networking.EnvoyFilter_EnvoyConfigObjectPatch{
+		ApplyTo: networking.EnvoyFilter_ROUTE_CONFIGURATION,
+		Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+			Context: networking.EnvoyFilter_GATEWAY,
+			ObjectTypes: &networking.EnvoyFilter_EnvoyConfigObjectMatch_RouteConfiguration{
+				RouteConfiguration: &networking.EnvoyFilter_RouteConfigurationMatch{
+					PortNumber: port,
+				},
+			},
+		},
+		Patch: &networking.EnvoyFilter_Patch{
+			Operation: networking.EnvoyFilter_Patch_MERGE,
+			Value:     util.BuildPatchStruct(altSvcStruct),
+		},
+	})
+
+	if len(http3Struct) != 0 {
+		http3Config = append(http3Config, &networking.EnvoyFilter_EnvoyConfigObjectPatch{
+			ApplyTo: networking.EnvoyFilter_NETWORK_FILTER,
+			Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: networking.EnvoyFilter_GATEWAY,
+				ObjectTypes: &networking.EnvoyFilter_EnvoyConfigObjectMatch_Listener{
+					Listener: &networking.EnvoyFilter_ListenerMatch{
+						PortNumber: port,
+						FilterChain: &networking.EnvoyFilter_ListenerMatch_FilterChainMatch{
+							Filter: &networking.EnvoyFilter_ListenerMatch_FilterMatch{
+								Name: "envoy.filters.network.http_connection_manager",
+							},
+						},
+					},
+				},
+			},
+			Patch: &networking.EnvoyFilter_Patch{
+				Operation: networking.EnvoyFilter_Patch_MERGE,
+				Value:     util.BuildPatchStruct(http3Struct),
+			},
+		})
+	}
+
+	return http3Config
+}
+
+// generateClientIpEnvoyFilter generates the client ip envoy filter.
+func (g *GlobalOptionController) generateClientIpEnvoyFilter(clientIpStruct string, proxyProtocolStruct string) []*networking.EnvoyFilter_EnvoyConfigObjectPatch {
+	var clientIpConfig []*networking.EnvoyFilter_EnvoyConfigObjectPatch
+
+	if len(clientIpStruct) != 0 {
+		clientIpConfig = append(clientIpConfig, &networking.EnvoyFilter_EnvoyConfigObjectPatch{
+			ApplyTo: networking.EnvoyFilter_NETWORK_FILTER,
+			Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: networking.EnvoyFilter_GATEWAY,
+				ObjectTypes: &networking.EnvoyFilter_EnvoyConfigObjectMatch_Listener{
+					Listener: &networking.EnvoyFilter_ListenerMatch{
+						FilterChain: &networking.EnvoyFilter_ListenerMatch_FilterChainMatch{
+							Filter: &networking.EnvoyFilter_ListenerMatch_FilterMatch{
+								Name: "envoy.filters.network.http_connection_manager",
+							},
+						},
+					},
+				},
+			},
+			Patch: &networking.EnvoyFilter_Patch{
+				Operation: networking.EnvoyFilter_Patch_MERGE,
+				Value:     util.BuildPatchStruct(clientIpStruct),
+			},
+		})
+	}
+
+	// the PROXY protocol header must be read before any other listener filter
+	if len(proxyProtocolStruct) != 0 {
+		clientIpConfig = append(clientIpConfig, &networking.EnvoyFilter_EnvoyConfigObjectPatch{
+			ApplyTo: networking.EnvoyFilter_LISTENER_FILTER,
+			Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: networking.EnvoyFilter_GATEWAY,
+			},
+			Patch: &networking.EnvoyFilter_Patch{
+				Operation: networking.EnvoyFilter_Patch_INSERT_FIRST,
+				Value:     util.BuildPatchStruct(proxyProtocolStruct),
+			},
+		})
+	}
+
+	return clientIpConfig
+}
+
 func (g *GlobalOptionController) generateUpstreamEnvoyFilter(upstreamValueStruct string, bufferLimit string, namespace string) []*networking.EnvoyFilter_EnvoyConfigObjectPatch {
 	var upstreamConfig []*networking.EnvoyFilter_EnvoyConfigObjectPatch
 
@@ -567,6 +830,163 @@ func (g *GlobalOptionController) constructDownstream(downstream *Downstream) str
 	return downstreamConfig
 }
 
+func http3Port(http3 *Http3) uint32 {
+	if http3.Port == 0 {
+		return defaultHttp3Port
+	}
+	return http3.Port
+}
+
+// constructHttp3AltSvc constructs the alt-svc header config advertising HTTP/3.
+func (g *GlobalOptionController) constructHttp3AltSvc(http3 *Http3) string {
+	maxAge := http3.AltSvcMaxAge
+	if maxAge == 0 {
+		maxAge = defaultHttp3AltSvcMaxAge
+	}
+	return fmt.Sprintf(`
+		{
+			"response_headers_to_add": [
+				{
+					"append_action": "OVERWRITE_IF_EXISTS_OR_ADD",
+					"header": {
+						"key": "alt-svc",
+						"value": "h3=\":%d\"; ma=%d"
+					}
+				}
+			]
+		}
+`, http3Port(http3), maxAge)
+}
+
+// constructHttp3 constructs the http3 protocol options of the http connection manager.
+func (g *GlobalOptionController) constructHttp3(http3 *Http3) string {
+	var options []string
+	if http3.MaxConcurrentStreams != 0 {
+		options = append(options, fmt.Sprintf(`"max_concurrent_streams": %d`, http3.MaxConcurrentStreams))
+	}
+	if http3.InitialStreamWindowSize != 0 {
+		options = append(options, fmt.Sprintf(`"initial_stream_window_size": %d`, http3.InitialStreamWindowSize))
+	}
+	if http3.InitialConnectionWindowSize != 0 {
+		options = append(options, fmt.Sprintf(`"initial_connection_window_size": %d`, http3.InitialConnectionWindowSize))
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+		{
+			"name": "envoy.filters.network.http_connection_manager",
+			"typed_config": {
+				"@type": "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+				"http3_protocol_options": {
+					"quic_protocol_options": {
+						%s
+					}
+				}
+			}
+		}
+`, strings.Join(options, ",\n\t\t\t\t\t\t"))
+}
+
+// constructClientIp constructs the client ip config of the http connection manager.
+// The original ip detection extensions are used for the trusted cidrs and the original ip header,
+// the custom header is checked before the x-forwarded-for header. Envoy rejects the extensions
+// mixed with use_remote_address, so it is turned off and the trusted hops are set in the xff extension.
+// The custom header can be sent by anyone, so the address from it is not allowed to be trusted.
+func (g *GlobalOptionController) constructClientIp(clientIp *ClientIp) string {
+	if len(clientIp.TrustedCidrs) == 0 && clientIp.OriginalIpHeader == "" {
+		if clientIp.XffNumTrustedHops == 0 {
+			return ""
+		}
+		return fmt.Sprintf(`
+		{
+			"name": "envoy.filters.network.http_connection_manager",
+			"typed_config": {
+				"@type": "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+				"xff_num_trusted_hops": %d
+			}
+		}
+`, clientIp.XffNumTrustedHops)
+	}
+
+	var extensions []string
+	if clientIp.OriginalIpHeader != "" {
+		extensions = append(extensions, fmt.Sprintf(`
+					{
+						"name": "envoy.http.original_ip_detection.custom_header",
+						"typed_config": {
+							"@type": "type.googleapis.com/envoy.extensions.http.original_ip_detection.custom_header.v3.CustomHeaderConfig",
+							"header_name": "%s",
+							"allow_extension_to_set_address_as_trusted": false
+						}
+					}`, strings.ToLower(clientIp.OriginalIpHeader)))
+	}
+	xffConfig := fmt.Sprintf(`"xff_num_trusted_hops": %d`, clientIp.XffNumTrustedHops)
+	if len(clientIp.TrustedCidrs) > 0 {
+		var cidrs []string
+		for _, cidr := range clientIp.TrustedCidrs {
+			prefix, err := parseCidr(cidr)
+			if err != nil {
+				continue
+			}
+			cidrs = append(cidrs, fmt.Sprintf(`{"address_prefix": "%s", "prefix_len": %d}`, prefix.Addr(), prefix.Bits()))
+		}
+		xffConfig = fmt.Sprintf(`"xff_trusted_cidrs": {"cidrs": [%s]}`, strings.Join(cidrs, ", "))
+	}
+	extensions = append(extensions, fmt.Sprintf(`
+					{
+						"name": "envoy.http.original_ip_detection.xff",
+						"typed_config": {
+							"@type": "type.googleapis.com/envoy.extensions.http.original_ip_detection.xff.v3.XffConfig",
+							%s
+						}
+					}`, xffConfig))
+
+	return fmt.Sprintf(`
+		{
+			"name": "envoy.filters.network.http_connection_manager",
+			"typed_config": {
+				"@type": "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+				"use_remote_address": false,
+				"original_ip_detection_extensions": [%s
+				]
+			}
+		}
+`, strings.Join(extensions, ","))
+}
+
+// constructProxyProtocol constructs the proxy protocol listener filter config.
+func (g *GlobalOptionController) constructProxyProtocol(proxyProtocol *ProxyProtocol) string {
+	if proxyProtocol == nil || !proxyProtocol.Enable {
+		return ""
+	}
+	var disallowedVersions []string
+	if len(proxyProtocol.Versions) > 0 {
+		for _, version := range []string{proxyProtocolV1, proxyProtocolV2} {
+			allowed := false
+			for _, v := range proxyProtocol.Versions {
+				if v == version {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				disallowedVersions = append(disallowedVersions, fmt.Sprintf(`"%s"`, strings.ToUpper(version)))
+			}
+		}
+	}
+	return fmt.Sprintf(`
+		{
+			"name": "envoy.filters.listener.proxy_protocol",
+			"typed_config": {
+				"@type": "type.googleapis.com/envoy.extensions.filters.listener.proxy_protocol.v3.ProxyProtocol",
+				"allow_requests_without_proxy_protocol": %t,
+				"disallowed_versions": [%s]
+			}
+		}
+`, proxyProtocol.AllowRequestsWithoutProxyProtocol, strings.Join(disallowedVersions, ", "))
+}
+
 // constructUpstream constructs the upstream config.
 func (g *GlobalOptionController) constructUpstream(upstream *Upstream) string {
 	upstreamConfig := ""
@@ -15,10 +15,12 @@
 package configmap
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/alibaba/higress/v2/pkg/ingress/kube/util"
 	"github.com/stretchr/testify/assert"
+	networking "istio.io/api/networking/v1alpha3"
 )
 
 func Test_validGlobal(t *testing.T) {
@@ -47,6 +49,92 @@ func Test_validGlobal(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "client ip",
+			global: &Global{
+				Downstream: &Downstream{
+					ClientIp: &ClientIp{
+						TrustedCidrs:     []string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"},
+						OriginalIpHeader: "X-Real-IP",
+						ProxyProtocol:    &ProxyProtocol{Enable: true, Versions: []string{"v2"}},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "client ip with both trusted hops and cidrs",
+			global: &Global{
+				Downstream: &Downstream{
+					ClientIp: &ClientIp{
+						XffNumTrustedHops: 1,
+						TrustedCidrs:      []string{"10.0.0.0/8"},
+					},
+				},
+			},
+			wantErr: errors.New("clientIp.xffNumTrustedHops and clientIp.trustedCidrs can not be set at the same time"),
+		},
+		{
+			name: "client ip with invalid cidr",
+			global: &Global{
+				Downstream: &Downstream{
+					ClientIp: &ClientIp{
+						TrustedCidrs: []string{"10.0.0.0/33"},
+					},
+				},
+			},
+			wantErr: errors.New(`clientIp.trustedCidrs has invalid cidr "10.0.0.0/33"`),
+		},
+		{
+			name: "client ip with invalid header",
+			global: &Global{
+				Downstream: &Downstream{
+					ClientIp: &ClientIp{
+						OriginalIpHeader: "x real ip",
+					},
+				},
+			},
+			wantErr: errors.New(`clientIp.originalIpHeader has invalid header name "x real ip"`),
+		},
+		{
+			name: "client ip with invalid proxy protocol version",
+			global: &Global{
+				Downstream: &Downstream{
+					ClientIp: &ClientIp{
+						ProxyProtocol: &ProxyProtocol{Enable: true, Versions: []string{"v3"}},
+					},
+				},
+			},
+			wantErr: errors.New("clientIp.proxyProtocol.versions must be v1 or v2"),
+		},
+		{
+			name: "http3",
+			global: &Global{
+				Downstream: &Downstream{
+					Http3: &Http3{Enable: true, MaxConcurrentStreams: 100, InitialStreamWindowSize: 65536},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "http3 with invalid stream window size",
+			global: &Global{
+				Downstream: &Downstream{
+					Http3: &Http3{Enable: true, InitialStreamWindowSize: 16777217},
+				},
+			},
+			wantErr: errors.New("http3.initialStreamWindowSize must be between 1 and 16777216"),
+		},
+		{
+			name: "http3 with proxy protocol",
+			global: &Global{
+				Downstream: &Downstream{
+					ClientIp: &ClientIp{ProxyProtocol: &ProxyProtocol{Enable: true}},
+					Http3:    &Http3{Enable: true},
+				},
+			},
+			wantErr: errors.New("http3 and clientIp.proxyProtocol can not be enabled at the same time"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -254,3 +342,201 @@ func Test_AddOrUpdateHigressConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_deepCopyGlobalClientIp(t *testing.T) {
+	global := &Global{
+		Downstream: &Downstream{
+			ClientIp: &ClientIp{
+				TrustedCidrs:     []string{"10.0.0.0/8"},
+				OriginalIpHeader: "x-real-ip",
+				ProxyProtocol:    &ProxyProtocol{Enable: true, Versions: []string{"v1"}},
+			},
+		},
+	}
+	newGlobal, err := deepCopyGlobal(global)
+	assert.NoError(t, err)
+	assert.Equal(t, global.Downstream.ClientIp, newGlobal.Downstream.ClientIp)
+	newGlobal.Downstream.ClientIp.TrustedCidrs[0] = "172.16.0.0/12"
+	assert.Equal(t, "10.0.0.0/8", global.Downstream.ClientIp.TrustedCidrs[0])
+}
+
+func Test_constructClientIp(t *testing.T) {
+	g := NewGlobalOptionController("higress-system")
+	tests := []struct {
+		name     string
+		clientIp *ClientIp
+		want     string
+	}{
+		{
+			name:     "nothing to set",
+			clientIp: &ClientIp{},
+			want:     "",
+		},
+		{
+			name:     "trusted hops",
+			clientIp: &ClientIp{XffNumTrustedHops: 2},
+			want: `{
+				"name": "envoy.filters.network.http_connection_manager",
+				"typed_config": {
+					"@type": "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+					"xff_num_trusted_hops": 2
+				}
+			}`,
+		},
+		{
+			name: "trusted cidrs and original ip header",
+			clientIp: &ClientIp{
+				TrustedCidrs:     []string{"10.1.2.3/8", "192.168.1.1", "fd00::/8"},
+				OriginalIpHeader: "X-Real-IP",
+			},
+			want: `{
+				"name": "envoy.filters.network.http_connection_manager",
+				"typed_config": {
+					"@type": "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+					"use_remote_address": false,
+					"original_ip_detection_extensions": [
+						{
+							"name": "envoy.http.original_ip_detection.custom_header",
+							"typed_config": {
+								"@type": "type.googleapis.com/envoy.extensions.http.original_ip_detection.custom_header.v3.CustomHeaderConfig",
+								"header_name": "x-real-ip",
+								"allow_extension_to_set_address_as_trusted": false
+							}
+						},
+						{
+							"name": "envoy.http.original_ip_detection.xff",
+							"typed_config": {
+								"@type": "type.googleapis.com/envoy.extensions.http.original_ip_detection.xff.v3.XffConfig",
+								"xff_trusted_cidrs": {"cidrs": [
+									{"address_prefix": "10.0.0.0", "prefix_len": 8},
+									{"address_prefix": "192.168.1.1", "prefix_len": 32},
+									{"address_prefix": "fd00::", "prefix_len": 8}
+								]}
+							}
+						}
+					]
+				}
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.constructClientIp(tt.clientIp)
+			if tt.want == "" {
+				assert.Empty(t, got)
+				return
+			}
+			assert.JSONEq(t, tt.want, got)
+		})
+	}
+}
+
+func Test_generateClientIpEnvoyFilter(t *testing.T) {
+	g := NewGlobalOptionController("higress-system")
+	tests := []struct {
+		name     string
+		clientIp *ClientIp
+	}{
+		{
+			name:     "trusted cidrs",
+			clientIp: &ClientIp{TrustedCidrs: []string{"10.0.0.0/8"}},
+		},
+		{
+			name:     "original ip header",
+			clientIp: &ClientIp{OriginalIpHeader: "X-Real-IP"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patches := g.generateClientIpEnvoyFilter(g.constructClientIp(tt.clientIp), "")
+			assert.Len(t, patches, 1)
+			typedConfig := patches[0].Patch.Value.Fields["typed_config"].GetStructValue()
+			assert.NotNil(t, typedConfig)
+			// envoy rejects original_ip_detection_extensions mixed with use_remote_address
+			assert.NotEmpty(t, typedConfig.Fields["original_ip_detection_extensions"].GetListValue().GetValues())
+			useRemoteAddress, ok := typedConfig.Fields["use_remote_address"]
+			assert.True(t, ok)
+			assert.False(t, useRemoteAddress.GetBoolValue())
+			assert.NotContains(t, typedConfig.Fields, "xff_num_trusted_hops")
+		})
+	}
+}
+
+func Test_constructProxyProtocol(t *testing.T) {
+	g := NewGlobalOptionController("higress-system")
+	assert.Empty(t, g.constructProxyProtocol(nil))
+	assert.Empty(t, g.constructProxyProtocol(&ProxyProtocol{}))
+	assert.JSONEq(t, `{
+		"name": "envoy.filters.listener.proxy_protocol",
+		"typed_config": {
+			"@type": "type.googleapis.com/envoy.extensions.filters.listener.proxy_protocol.v3.ProxyProtocol",
+			"allow_requests_without_proxy_protocol": true,
+			"disallowed_versions": ["V1"]
+		}
+	}`, g.constructProxyProtocol(&ProxyProtocol{Enable: true, Versions: []string{"v2"}, AllowRequestsWithoutProxyProtocol: true}))
+	assert.JSONEq(t, `{
+		"name": "envoy.filters.listener.proxy_protocol",
+		"typed_config": {
+			"@type": "type.googleapis.com/envoy.extensions.filters.listener.proxy_protocol.v3.ProxyProtocol",
+			"allow_requests_without_proxy_protocol": false,
+			"disallowed_versions": []
+		}
+	}`, g.constructProxyProtocol(&ProxyProtocol{Enable: true}))
+}
+
+func Test_constructHttp3(t *testing.T) {
+	g := NewGlobalOptionController("higress-system")
+
+	assert.JSONEq(t, `{
+		"response_headers_to_add": [
+			{
+				"append_action": "OVERWRITE_IF_EXISTS_OR_ADD",
+				"header": {"key": "alt-svc", "value": "h3=\":443\"; ma=86400"}
+			}
+		]
+	}`, g.constructHttp3AltSvc(&Http3{Enable: true}))
+	assert.JSONEq(t, `{
+		"response_headers_to_add": [
+			{
+				"append_action": "OVERWRITE_IF_EXISTS_OR_ADD",
+				"header": {"key": "alt-svc", "value": "h3=\":8443\"; ma=3600"}
+			}
+		]
+	}`, g.constructHttp3AltSvc(&Http3{Enable: true, Port: 8443, AltSvcMaxAge: 3600}))
+
+	assert.Empty(t, g.constructHttp3(&Http3{Enable: true}))
+	assert.JSONEq(t, `{
+		"name": "envoy.filters.network.http_connection_manager",
+		"typed_config": {
+			"@type": "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+			"http3_protocol_options": {
+				"quic_protocol_options": {
+					"max_concurrent_streams": 100,
+					"initial_connection_window_size": 1048576
+				}
+			}
+		}
+	}`, g.constructHttp3(&Http3{Enable: true, MaxConcurrentStreams: 100, InitialConnectionWindowSize: 1048576}))
+
+	configs, err := g.ConstructEnvoyFilters()
+	assert.NoError(t, err)
+	assert.Len(t, configs, 1)
+	defaultPatches := len(configs[0].Spec.(*networking.EnvoyFilter).ConfigPatches)
+
+	global := NewDefaultGlobalOption()
+	global.Downstream.Http3 = &Http3{Enable: true, MaxConcurrentStreams: 100}
+	g.SetGlobal(global)
+
+	// HTTP/3 is not advertised without the QUIC listeners
+	g.quicListenersEnabled = false
+	configs, err = g.ConstructEnvoyFilters()
+	assert.NoError(t, err)
+	assert.Len(t, configs, 1)
+	assert.Len(t, configs[0].Spec.(*networking.EnvoyFilter).ConfigPatches, defaultPatches)
+
+	g.quicListenersEnabled = true
+	configs, err = g.ConstructEnvoyFilters()
+	assert.NoError(t, err)
+	assert.Len(t, configs, 1)
+	assert.Len(t, configs[0].Spec.(*networking.EnvoyFilter).ConfigPatches, defaultPatches+2)
+}
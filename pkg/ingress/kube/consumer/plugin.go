@@ -0,0 +1,239 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	networkingv1 "github.com/alibaba/higress/v2/api/networking/v1"
+	extv1alpha1 "github.com/alibaba/higress/v2/client/pkg/apis/extensions/v1alpha1"
+	v1 "github.com/alibaba/higress/v2/client/pkg/apis/networking/v1"
+)
+
+const (
+	// PluginNameLabel is set on the WasmPlugins created by hgctl and the console.
+	PluginNameLabel = "higress.io/wasm-plugin-name"
+	// ConsumerGroupsAnnotation limits the consumers injected into a WasmPlugin to the given groups, separated by comma.
+	ConsumerGroupsAnnotation = "higress.io/consumer-groups"
+	// ConsumerSelectorAnnotation limits the consumers injected into a WasmPlugin to the ones matching the label selector.
+	ConsumerSelectorAnnotation = "higress.io/consumer-selector"
+)
+
+const (
+	KeyAuth   = "key-auth"
+	BasicAuth = "basic-auth"
+	JwtAuth   = "jwt-auth"
+	HmacAuth  = "hmac-auth"
+)
+
+const consumersField = "consumers"
+
+// credentialTypes maps the auth plugins to the type of credentials they consume.
+var credentialTypes = map[string]string{
+	"key-auth":         KeyAuth,
+	"basic-auth":       BasicAuth,
+	"jwt-auth":         JwtAuth,
+	"hmac-auth-apisix": HmacAuth,
+}
+
+type SecretGetter func(namespace, name string) (*corev1.Secret, error)
+
+// GetPluginName returns the name of the plugin, taken from the label or else from the image url,
+// e.g. key-auth for oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/key-auth:1.0.0.
+func GetPluginName(wasmPlugin *extv1alpha1.WasmPlugin) string {
+	if name := wasmPlugin.Labels[PluginNameLabel]; name != "" {
+		return name
+	}
+	name := wasmPlugin.Spec.Url
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	if idx := strings.IndexAny(name, ":@"); idx >= 0 {
+		name = name[:idx]
+	}
+	return strings.TrimSuffix(name, ".wasm")
+}
+
+// GetCredentialType returns the type of credentials consumed by the plugin, or empty if it is not an auth plugin.
+func GetCredentialType(wasmPlugin *extv1alpha1.WasmPlugin) string {
+	return credentialTypes[GetPluginName(wasmPlugin)]
+}
+
+// GetConsumerName returns the name of the consumer used by the plugins, which defaults to the name of the resource.
+func GetConsumerName(consumer *v1.Consumer) string {
+	if consumer.Spec.Name != "" {
+		return consumer.Spec.Name
+	}
+	return consumer.Name
+}
+
+// SelectConsumers returns the consumers to be injected into the plugin sorted by name, filtered by the groups
+// and the label selector in the annotations of the plugin.
+func SelectConsumers(wasmPlugin *extv1alpha1.WasmPlugin, consumers []*v1.Consumer) ([]*v1.Consumer, error) {
+	groups := sets.New[string]()
+	if value := wasmPlugin.Annotations[ConsumerGroupsAnnotation]; value != "" {
+		for _, group := range strings.Split(value, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups.Insert(group)
+			}
+		}
+	}
+	selector := labels.Everything()
+	if value := wasmPlugin.Annotations[ConsumerSelectorAnnotation]; value != "" {
+		var err error
+		selector, err = labels.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation %s: %v", ConsumerSelectorAnnotation, err)
+		}
+	}
+	var result []*v1.Consumer
+	for _, consumer := range consumers {
+		if !selector.Matches(labels.Set(consumer.Labels)) {
+			continue
+		}
+		if groups.Len() > 0 && !inGroups(consumer, groups) {
+			continue
+		}
+		result = append(result, consumer)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return GetConsumerName(result[i]) < GetConsumerName(result[j])
+	})
+	return result, nil
+}
+
+func inGroups(consumer *v1.Consumer, groups sets.Set[string]) bool {
+	for _, group := range consumer.Spec.Groups {
+		if groups.Contains(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildConsumerConfigs converts the credentials of the given type into the consumer entries of the plugin config.
+// The consumers whose credentials can not be resolved are skipped and reported in the returned error.
+func BuildConsumerConfigs(credentialType string, consumers []*v1.Consumer, getSecret SecretGetter) ([]*structpb.Value, error) {
+	var entries []*structpb.Value
+	var errs []error
+	for _, consumer := range consumers {
+		for _, credential := range consumer.Spec.Credentials {
+			if credential.GetType() != credentialType {
+				continue
+			}
+			entry, err := buildConsumerConfig(GetConsumerName(consumer), consumer.Namespace, credential, getSecret)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("consumer %s/%s: %v", consumer.Namespace, consumer.Name, err))
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, errors.Join(errs...)
+}
+
+func buildConsumerConfig(name, namespace string, credential *networkingv1.ConsumerCredential, getSecret SecretGetter) (*structpb.Value, error) {
+	if credential.GetSecretName() == "" {
+		return nil, fmt.Errorf("secretName of %s credential is empty", credential.GetType())
+	}
+	secret, err := getSecret(namespace, credential.GetSecretName())
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]*structpb.Value{}
+	for key, value := range credential.GetConfig().GetFields() {
+		fields[key] = value
+	}
+	secretFields := map[string]string{}
+	switch credential.GetType() {
+	case KeyAuth:
+		secretFields["credential"] = "key"
+	case BasicAuth:
+		secretFields["username"] = "username"
+		secretFields["password"] = "password"
+	case JwtAuth:
+		secretFields["jwks"] = "jwks"
+	case HmacAuth:
+		secretFields["access_key"] = "access_key"
+		secretFields["secret_key"] = "secret_key"
+	}
+	values := map[string]string{}
+	for field, key := range secretFields {
+		value, exist := secret.Data[key]
+		if !exist || len(value) == 0 {
+			return nil, fmt.Errorf("key %s not found in secret %s/%s", key, namespace, secret.Name)
+		}
+		values[field] = string(value)
+	}
+	if credential.GetType() == BasicAuth {
+		values["credential"] = values["username"] + ":" + values["password"]
+		delete(values, "username")
+		delete(values, "password")
+	}
+	for field, value := range values {
+		fields[field] = structpb.NewStringValue(value)
+	}
+	fields["name"] = structpb.NewStringValue(name)
+	return structpb.NewStructValue(&structpb.Struct{Fields: fields}), nil
+}
+
+// InjectConsumers returns a copy of the plugin config with the consumer entries injected. The consumers configured
+// statically in the plugin config are kept, unless there is an injected entry with the same name.
+func InjectConsumers(pluginConfig *structpb.Struct, entries []*structpb.Value) *structpb.Struct {
+	result := &structpb.Struct{}
+	if pluginConfig != nil {
+		result = proto.Clone(pluginConfig).(*structpb.Struct)
+	}
+	if result.Fields == nil {
+		result.Fields = map[string]*structpb.Value{}
+	}
+	injected := sets.New[string]()
+	for _, entry := range entries {
+		injected.Insert(entryName(entry))
+	}
+	var values []*structpb.Value
+	for _, value := range result.Fields[consumersField].GetListValue().GetValues() {
+		if injected.Contains(entryName(value)) {
+			continue
+		}
+		values = append(values, value)
+	}
+	values = append(values, entries...)
+	result.Fields[consumersField] = structpb.NewListValue(&structpb.ListValue{Values: values})
+	return result
+}
+
+func entryName(entry *structpb.Value) string {
+	return entry.GetStructValue().GetFields()["name"].GetStringValue()
+}
+
+// ReferencesSecret returns whether any credential of the consumer is stored in the secret.
+func ReferencesSecret(consumer *v1.Consumer, name string) bool {
+	for _, credential := range consumer.Spec.Credentials {
+		if credential.GetSecretName() == name {
+			return true
+		}
+	}
+	return false
+}
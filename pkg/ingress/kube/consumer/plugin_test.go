@@ -0,0 +1,263 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkingv1 "github.com/alibaba/higress/v2/api/networking/v1"
+	extv1alpha1 "github.com/alibaba/higress/v2/client/pkg/apis/extensions/v1alpha1"
+	v1 "github.com/alibaba/higress/v2/client/pkg/apis/networking/v1"
+)
+
+func newConsumer(name string, labels map[string]string) *v1.Consumer {
+	return &v1.Consumer{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "higress-system", Labels: labels},
+	}
+}
+
+func newSecretGetter(secrets ...*corev1.Secret) SecretGetter {
+	return func(namespace, name string) (*corev1.Secret, error) {
+		for _, secret := range secrets {
+			if secret.Namespace == namespace && secret.Name == name {
+				return secret, nil
+			}
+		}
+		return nil, fmt.Errorf("secret %s/%s not found", namespace, name)
+	}
+}
+
+func newSecret(name string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "higress-system"},
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func TestGetCredentialType(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		url    string
+		expect string
+	}{
+		{
+			name:   "label",
+			labels: map[string]string{PluginNameLabel: "jwt-auth"},
+			url:    "oci://example.com/plugins/custom:1.0.0",
+			expect: JwtAuth,
+		},
+		{
+			name:   "oci url",
+			url:    "oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/key-auth:1.0.0",
+			expect: KeyAuth,
+		},
+		{
+			name:   "oci url with digest",
+			url:    "oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/hmac-auth-apisix@sha256:1234",
+			expect: HmacAuth,
+		},
+		{
+			name:   "file url",
+			url:    "file:///opt/plugins/basic-auth.wasm",
+			expect: BasicAuth,
+		},
+		{
+			name: "not auth plugin",
+			url:  "oci://higress-registry.cn-hangzhou.cr.aliyuncs.com/plugins/ai-proxy:1.0.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wasmPlugin := &extv1alpha1.WasmPlugin{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+			wasmPlugin.Spec.Url = tt.url
+			assert.Equal(t, tt.expect, GetCredentialType(wasmPlugin))
+		})
+	}
+}
+
+func TestSelectConsumers(t *testing.T) {
+	consumers := []*v1.Consumer{
+		newConsumer("c", map[string]string{"tier": "gold"}),
+		newConsumer("b", map[string]string{"tier": "silver"}),
+		newConsumer("x", nil),
+	}
+	consumers[0].Spec.Groups = []string{"internal"}
+	consumers[1].Spec.Groups = []string{"partner"}
+	consumers[2].Spec.Name = "a"
+	names := func(consumers []*v1.Consumer) []string {
+		var result []string
+		for _, consumer := range consumers {
+			result = append(result, GetConsumerName(consumer))
+		}
+		return result
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expect      []string
+		wantErr     bool
+	}{
+		{
+			name:   "all",
+			expect: []string{"a", "b", "c"},
+		},
+		{
+			name:        "groups",
+			annotations: map[string]string{ConsumerGroupsAnnotation: "internal, partner"},
+			expect:      []string{"b", "c"},
+		},
+		{
+			name:        "selector",
+			annotations: map[string]string{ConsumerSelectorAnnotation: "tier in (gold)"},
+			expect:      []string{"c"},
+		},
+		{
+			name: "groups and selector",
+			annotations: map[string]string{
+				ConsumerGroupsAnnotation:   "partner",
+				ConsumerSelectorAnnotation: "tier=gold",
+			},
+		},
+		{
+			name:        "invalid selector",
+			annotations: map[string]string{ConsumerSelectorAnnotation: "tier in gold"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wasmPlugin := &extv1alpha1.WasmPlugin{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			result, err := SelectConsumers(wasmPlugin, consumers)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, names(result))
+		})
+	}
+}
+
+func TestBuildConsumerConfigs(t *testing.T) {
+	getSecret := newSecretGetter(
+		newSecret("alice", map[string]string{
+			"key":        "alice-key",
+			"username":   "alice",
+			"password":   "123456",
+			"jwks":       `{"keys":[]}`,
+			"access_key": "alice-ak",
+			"secret_key": "alice-sk",
+		}),
+		newSecret("bob", map[string]string{"username": "bob"}),
+	)
+	issuer, _ := structpb.NewStruct(map[string]interface{}{"issuer": "higress"})
+	consumers := []*v1.Consumer{newConsumer("alice", nil), newConsumer("bob", nil)}
+	consumers[0].Spec.Credentials = []*networkingv1.ConsumerCredential{
+		{Type: KeyAuth, SecretName: "alice"},
+		{Type: BasicAuth, SecretName: "alice"},
+		{Type: JwtAuth, SecretName: "alice", Config: issuer},
+		{Type: HmacAuth, SecretName: "alice"},
+	}
+	consumers[1].Spec.Credentials = []*networkingv1.ConsumerCredential{
+		{Type: BasicAuth, SecretName: "bob"},
+		{Type: JwtAuth, SecretName: "missing"},
+	}
+
+	tests := []struct {
+		credentialType string
+		expect         []map[string]interface{}
+		wantErr        string
+	}{
+		{
+			credentialType: KeyAuth,
+			expect:         []map[string]interface{}{{"name": "alice", "credential": "alice-key"}},
+		},
+		{
+			credentialType: BasicAuth,
+			expect:         []map[string]interface{}{{"name": "alice", "credential": "alice:123456"}},
+			wantErr:        "consumer higress-system/bob: key password not found in secret higress-system/bob",
+		},
+		{
+			credentialType: JwtAuth,
+			expect:         []map[string]interface{}{{"name": "alice", "jwks": `{"keys":[]}`, "issuer": "higress"}},
+			wantErr:        "consumer higress-system/bob: secret higress-system/missing not found",
+		},
+		{
+			credentialType: HmacAuth,
+			expect:         []map[string]interface{}{{"name": "alice", "access_key": "alice-ak", "secret_key": "alice-sk"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.credentialType, func(t *testing.T) {
+			entries, err := BuildConsumerConfigs(tt.credentialType, consumers, getSecret)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+			var result []map[string]interface{}
+			for _, entry := range entries {
+				result = append(result, entry.GetStructValue().AsMap())
+			}
+			assert.Equal(t, tt.expect, result)
+		})
+	}
+}
+
+func TestInjectConsumers(t *testing.T) {
+	pluginConfig, err := structpb.NewStruct(map[string]interface{}{
+		"global_auth": true,
+		"keys":        []interface{}{"x-api-key"},
+		"consumers": []interface{}{
+			map[string]interface{}{"name": "alice", "credential": "old-key"},
+			map[string]interface{}{"name": "static", "credential": "static-key"},
+		},
+	})
+	require.NoError(t, err)
+	entries := []*structpb.Value{
+		structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+			"name":       structpb.NewStringValue("alice"),
+			"credential": structpb.NewStringValue("new-key"),
+		}}),
+	}
+
+	result := InjectConsumers(pluginConfig, entries)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "static", "credential": "static-key"},
+		map[string]interface{}{"name": "alice", "credential": "new-key"},
+	}, result.AsMap()["consumers"])
+	assert.Equal(t, true, result.AsMap()["global_auth"])
+	// the original config is shared with the WasmPlugin resource and must not be changed
+	assert.Len(t, pluginConfig.Fields["consumers"].GetListValue().GetValues(), 2)
+	assert.Equal(t, "old-key", pluginConfig.Fields["consumers"].GetListValue().GetValues()[0].GetStructValue().Fields["credential"].GetStringValue())
+
+	result = InjectConsumers(nil, entries)
+	assert.Equal(t, map[string]interface{}{
+		"consumers": []interface{}{map[string]interface{}{"name": "alice", "credential": "new-key"}},
+	}, result.AsMap())
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http2rpc
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	higressv1 "github.com/alibaba/higress/v2/api/networking/v1"
+)
+
+const (
+	grpcParamSourceQuery = "QUERY"
+	grpcParamSourcePath  = "PATH"
+	grpcParamSourceBody  = "BODY"
+)
+
+// DecodeGrpcDescriptor accepts a FileDescriptorSet either in binary form or
+// encoded in base64, and returns the binary form.
+func DecodeGrpcDescriptor(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty proto descriptor")
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err == nil {
+		data = decoded
+	}
+	descriptorSet := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, descriptorSet); err != nil {
+		return nil, fmt.Errorf("invalid proto descriptor: %v", err)
+	}
+	return data, nil
+}
+
+// BuildGrpcDescriptor rewrites the google.api.http rules of the service methods
+// according to the method mappings of the Http2Rpc, and returns the resulting
+// FileDescriptorSet encoded in base64, as expected by the grpc_json_transcoder.
+func BuildGrpcDescriptor(grpc *higressv1.GrpcService, descriptor []byte) (string, error) {
+	if grpc.GetService() == "" {
+		return "", errors.New("grpc service name is empty")
+	}
+	descriptorSet := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(descriptor, descriptorSet); err != nil {
+		return "", fmt.Errorf("invalid proto descriptor: %v", err)
+	}
+	service := findGrpcService(descriptorSet, grpc.GetService())
+	if service == nil {
+		return "", fmt.Errorf("grpc service %s not found in proto descriptor", grpc.GetService())
+	}
+	if len(grpc.GetMethods()) > 0 {
+		serviceMethods := make(map[string]*descriptorpb.MethodDescriptorProto, len(service.GetMethod()))
+		for _, method := range service.GetMethod() {
+			if method.Options == nil {
+				method.Options = &descriptorpb.MethodOptions{}
+			}
+			// Only the declared methods are exposed, just like the dubbo services.
+			proto.ClearExtension(method.Options, annotations.E_Http)
+			serviceMethods[method.GetName()] = method
+		}
+		for _, method := range grpc.GetMethods() {
+			serviceMethod, exist := serviceMethods[method.GetServiceMethod()]
+			if !exist {
+				return "", fmt.Errorf("method %s not found in grpc service %s", method.GetServiceMethod(), grpc.GetService())
+			}
+			rule, err := buildHttpRule(method)
+			if err != nil {
+				return "", fmt.Errorf("invalid method %s: %v", method.GetServiceMethod(), err)
+			}
+			proto.SetExtension(serviceMethod.Options, annotations.E_Http, rule)
+		}
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(descriptorSet)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func findGrpcService(descriptorSet *descriptorpb.FileDescriptorSet, name string) *descriptorpb.ServiceDescriptorProto {
+	for _, file := range descriptorSet.GetFile() {
+		for _, service := range file.GetService() {
+			fullName := service.GetName()
+			if file.GetPackage() != "" {
+				fullName = file.GetPackage() + "." + fullName
+			}
+			if fullName == name {
+				return service
+			}
+		}
+	}
+	return nil
+}
+
+func buildHttpRule(method *higressv1.GrpcMethod) (*annotations.HttpRule, error) {
+	httpPath := method.GetHttpPath()
+	if !strings.HasPrefix(httpPath, "/") {
+		return nil, fmt.Errorf("http path %q must start with /", httpPath)
+	}
+	if len(method.GetHttpMethods()) == 0 {
+		return nil, errors.New("http methods are empty")
+	}
+	var body string
+	if method.GetParamFromEntireBody() != nil {
+		body = "*"
+	}
+	for _, param := range method.GetParams() {
+		switch strings.ToUpper(param.GetParamSource()) {
+		case grpcParamSourceQuery:
+			// Fields not bound by the path or body are read from the query parameters.
+		case grpcParamSourcePath:
+			if !strings.Contains(httpPath, "{"+param.GetParamKey()+"}") &&
+				!strings.Contains(httpPath, "{"+param.GetParamKey()+"=") {
+				return nil, fmt.Errorf("path param %s is not declared in http path %s", param.GetParamKey(), httpPath)
+			}
+		case grpcParamSourceBody:
+			if body != "" {
+				return nil, errors.New("only one body param is supported")
+			}
+			body = param.GetParamKey()
+		default:
+			return nil, fmt.Errorf("unsupported param source %s", param.GetParamSource())
+		}
+	}
+	var rules []*annotations.HttpRule
+	for _, httpMethod := range method.GetHttpMethods() {
+		rule := &annotations.HttpRule{}
+		switch strings.ToUpper(httpMethod) {
+		case "GET":
+			rule.Pattern = &annotations.HttpRule_Get{Get: httpPath}
+		case "POST":
+			rule.Pattern = &annotations.HttpRule_Post{Post: httpPath}
+		case "PUT":
+			rule.Pattern = &annotations.HttpRule_Put{Put: httpPath}
+		case "DELETE":
+			rule.Pattern = &annotations.HttpRule_Delete{Delete: httpPath}
+		case "PATCH":
+			rule.Pattern = &annotations.HttpRule_Patch{Patch: httpPath}
+		default:
+			return nil, fmt.Errorf("unsupported http method %s", httpMethod)
+		}
+		if body != "" {
+			if _, isGet := rule.Pattern.(*annotations.HttpRule_Get); isGet {
+				return nil, errors.New("body params are not allowed for GET method")
+			}
+			rule.Body = body
+		}
+		rules = append(rules, rule)
+	}
+	rules[0].AdditionalBindings = rules[1:]
+	return rules[0], nil
+}
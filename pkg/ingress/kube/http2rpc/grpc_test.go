@@ -0,0 +1,231 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http2rpc
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	higressv1 "github.com/alibaba/higress/v2/api/networking/v1"
+)
+
+func buildTestDescriptor(t *testing.T) []byte {
+	sayByeOptions := &descriptorpb.MethodOptions{}
+	proto.SetExtension(sayByeOptions, annotations.E_Http, &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Get{Get: "/bye"},
+	})
+	descriptorSet := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("helloworld.proto"),
+				Package: proto.String("helloworld"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{Name: proto.String("HelloRequest")},
+					{Name: proto.String("HelloReply")},
+				},
+				Service: []*descriptorpb.ServiceDescriptorProto{
+					{
+						Name: proto.String("Greeter"),
+						Method: []*descriptorpb.MethodDescriptorProto{
+							{
+								Name:       proto.String("SayHello"),
+								InputType:  proto.String(".helloworld.HelloRequest"),
+								OutputType: proto.String(".helloworld.HelloReply"),
+							},
+							{
+								Name:       proto.String("SayBye"),
+								InputType:  proto.String(".helloworld.HelloRequest"),
+								OutputType: proto.String(".helloworld.HelloReply"),
+								Options:    sayByeOptions,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	data, err := proto.Marshal(descriptorSet)
+	require.NoError(t, err)
+	return data
+}
+
+func TestDecodeGrpcDescriptor(t *testing.T) {
+	raw := buildTestDescriptor(t)
+
+	decoded, err := DecodeGrpcDescriptor(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, decoded)
+
+	decoded, err = DecodeGrpcDescriptor([]byte(base64.StdEncoding.EncodeToString(raw)))
+	assert.NoError(t, err)
+	assert.Equal(t, raw, decoded)
+
+	_, err = DecodeGrpcDescriptor(nil)
+	assert.Error(t, err)
+}
+
+func TestBuildGrpcDescriptor(t *testing.T) {
+	raw := buildTestDescriptor(t)
+
+	testCases := []struct {
+		name   string
+		grpc   *higressv1.GrpcService
+		expect map[string]*annotations.HttpRule
+		err    bool
+	}{
+		{
+			name: "keep annotations without methods",
+			grpc: &higressv1.GrpcService{Service: "helloworld.Greeter"},
+			expect: map[string]*annotations.HttpRule{
+				"SayBye": {Pattern: &annotations.HttpRule_Get{Get: "/bye"}},
+			},
+		},
+		{
+			name: "declared methods only",
+			grpc: &higressv1.GrpcService{
+				Service: "helloworld.Greeter",
+				Methods: []*higressv1.GrpcMethod{
+					{
+						ServiceMethod: "SayHello",
+						HttpPath:      "/hello/{name}",
+						HttpMethods:   []string{"POST", "PUT"},
+						Params: []*higressv1.Param{
+							{ParamSource: "PATH", ParamKey: "name"},
+							{ParamSource: "BODY", ParamKey: "message"},
+						},
+					},
+				},
+			},
+			expect: map[string]*annotations.HttpRule{
+				"SayHello": {
+					Pattern: &annotations.HttpRule_Post{Post: "/hello/{name}"},
+					Body:    "message",
+					AdditionalBindings: []*annotations.HttpRule{
+						{Pattern: &annotations.HttpRule_Put{Put: "/hello/{name}"}, Body: "message"},
+					},
+				},
+			},
+		},
+		{
+			name: "entire body",
+			grpc: &higressv1.GrpcService{
+				Service: "helloworld.Greeter",
+				Methods: []*higressv1.GrpcMethod{
+					{
+						ServiceMethod:       "SayHello",
+						HttpPath:            "/hello",
+						HttpMethods:         []string{"POST"},
+						ParamFromEntireBody: &higressv1.ParamFromEntireBody{},
+					},
+				},
+			},
+			expect: map[string]*annotations.HttpRule{
+				"SayHello": {Pattern: &annotations.HttpRule_Post{Post: "/hello"}, Body: "*"},
+			},
+		},
+		{
+			name: "unknown service",
+			grpc: &higressv1.GrpcService{Service: "helloworld.Unknown"},
+			err:  true,
+		},
+		{
+			name: "unknown method",
+			grpc: &higressv1.GrpcService{
+				Service: "helloworld.Greeter",
+				Methods: []*higressv1.GrpcMethod{
+					{ServiceMethod: "SayNothing", HttpPath: "/nothing", HttpMethods: []string{"GET"}},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "undeclared path param",
+			grpc: &higressv1.GrpcService{
+				Service: "helloworld.Greeter",
+				Methods: []*higressv1.GrpcMethod{
+					{
+						ServiceMethod: "SayHello",
+						HttpPath:      "/hello",
+						HttpMethods:   []string{"GET"},
+						Params:        []*higressv1.Param{{ParamSource: "PATH", ParamKey: "name"}},
+					},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "body with get method",
+			grpc: &higressv1.GrpcService{
+				Service: "helloworld.Greeter",
+				Methods: []*higressv1.GrpcMethod{
+					{
+						ServiceMethod: "SayHello",
+						HttpPath:      "/hello",
+						HttpMethods:   []string{"GET"},
+						Params:        []*higressv1.Param{{ParamSource: "BODY", ParamKey: "name"}},
+					},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "header param",
+			grpc: &higressv1.GrpcService{
+				Service: "helloworld.Greeter",
+				Methods: []*higressv1.GrpcMethod{
+					{
+						ServiceMethod: "SayHello",
+						HttpPath:      "/hello",
+						HttpMethods:   []string{"POST"},
+						Params:        []*higressv1.Param{{ParamSource: "HEADER", ParamKey: "name"}},
+					},
+				},
+			},
+			err: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := BuildGrpcDescriptor(testCase.grpc, raw)
+			if testCase.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			data, err := base64.StdEncoding.DecodeString(result)
+			require.NoError(t, err)
+			descriptorSet := &descriptorpb.FileDescriptorSet{}
+			require.NoError(t, proto.Unmarshal(data, descriptorSet))
+			service := findGrpcService(descriptorSet, testCase.grpc.GetService())
+			require.NotNil(t, service)
+			for _, method := range service.GetMethod() {
+				expect := testCase.expect[method.GetName()]
+				if expect == nil {
+					assert.False(t, proto.HasExtension(method.GetOptions(), annotations.E_Http), method.GetName())
+					continue
+				}
+				rule := proto.GetExtension(method.GetOptions(), annotations.E_Http).(*annotations.HttpRule)
+				assert.True(t, proto.Equal(expect, rule), "method %s got %v", method.GetName(), rule)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@
 #pragma once
 
 #include <functional>
+#include <memory>
 #include <optional>
 #include <string>
 #include <unordered_map>
@@ -24,11 +25,13 @@
 #include <utility>
 #include <vector>
 
+#include "absl/strings/ascii.h"
 #include "absl/strings/match.h"
 #include "absl/strings/str_cat.h"
 #include "absl/strings/str_format.h"
 #include "absl/strings/str_split.h"
 #include "common/json_util.h"
+#include "common/regex.h"
 #include "http_util.h"
 
 #ifndef NULL_PLUGIN
@@ -61,14 +64,26 @@ using ::Wasm::Common::JsonValueAs;
 template <typename PluginConfig>
 class RouteRuleMatcher {
  public:
-  enum CATEGORY { Route, RoutePrefix, Host, Service, RouteAndService };
-  enum MATCH_TYPE { Prefix, Exact, Suffix };
+  enum CATEGORY { Route, RoutePrefix, Host, Service, RouteAndService, Any };
+  enum MATCH_TYPE { Prefix, Exact, Suffix, Regex };
+  struct StringMatch {
+    MATCH_TYPE type;
+    std::string value;
+    std::shared_ptr<Wasm::Common::Regex::CompiledGoogleReMatcher> regex;
+  };
+  struct HeaderMatch {
+    std::string name;
+    std::optional<StringMatch> value;
+  };
   struct RuleConfig {
     CATEGORY category;
     std::unordered_set<std::string> routes;
     std::vector<std::string> route_prefixs;
     std::vector<std::pair<MATCH_TYPE, std::string>> hosts;
     std::unordered_set<std::string> services;
+    std::vector<StringMatch> paths;
+    std::vector<HeaderMatch> headers;
+    std::unordered_set<std::string> methods;
     bool disable = false;
     PluginConfig config;
   };
@@ -173,7 +188,15 @@ class RouteRuleMatcher {
     bool disable_rule = false;
     for (int i = 0; i < rule_config_.size(); ++i) {
       auto& rule = rule_config_[i];
-      if (rule.category == CATEGORY::Host) {
+      if (!requestMatch(rule)) {
+        continue;
+      }
+      if (rule.category == CATEGORY::Any) {
+        rule_id = i + 1;
+        match_config = rule.config;
+        disable_rule = rule.disable;
+        break;
+      } else if (rule.category == CATEGORY::Host) {
         if (hostMatch(rule, request_host)) {
           rule_id = i + 1;
           match_config = rule.config;
@@ -256,7 +279,21 @@ class RouteRuleMatcher {
     bool is_matched = false;
     bool disable_rule = false;
     for (auto& auth_rule : auth_rule_config_) {
-      if (auth_rule.rule_config.category == CATEGORY::Host) {
+      if (!requestMatch(auth_rule.rule_config)) {
+        continue;
+      }
+      if (auth_rule.rule_config.category == CATEGORY::Any) {
+        LOG_DEBUG("request conditions are matched for this request");
+        is_matched = true;
+        if (auth_rule.rule_config.disable) {
+          disable_rule = true;
+        } else if (auth_rule.has_local_config) {
+          match_config = auth_rule.rule_config.config;
+        } else {
+          allow_set = auth_rule.allow_set;
+        }
+        break;
+      } else if (auth_rule.rule_config.category == CATEGORY::Host) {
         if (hostMatch(auth_rule.rule_config, request_host)) {
           LOG_DEBUG(absl::StrFormat("host %s is matched for this request",
                                     request_host));
@@ -397,14 +434,19 @@ class RouteRuleMatcher {
         LOG_WARN("failed to parse configuration for _match_service_");
         return false;
       }
+      if (!parseRequestMatchConfig(config, rule)) {
+        return false;
+      }
       auto has_route = !rule.routes.empty();
       auto has_route_prefix = !rule.route_prefixs.empty();
       auto has_service = !rule.services.empty();
       auto has_host = !rule.hosts.empty();
-      if (has_route + has_route_prefix + has_host + has_service == 0) {
+      if (has_route + has_route_prefix + has_host + has_service == 0 &&
+          !hasRequestMatch(rule)) {
         LOG_WARN(
             "there is at least one of  '_match_route_', '_match_domain_', "
-            "'_match_route_prefix_' and '_match_service_' can "
+            "'_match_route_prefix_', '_match_service_', '_match_path_', "
+            "'_match_header_' and '_match_method_' can "
             "present in configuration.");
         return false;
       }
@@ -417,8 +459,10 @@ class RouteRuleMatcher {
         rule.category = CATEGORY::RoutePrefix;
       } else if (has_service) {
         rule.category = CATEGORY::Service;
-      } else {
+      } else if (has_host) {
         rule.category = CATEGORY::Host;
+      } else {
+        rule.category = CATEGORY::Any;
       }
       auto has_disable = config.find("_disable_");
       if (has_disable != config.end()) {
@@ -470,6 +514,13 @@ class RouteRuleMatcher {
       auto config = item.value();
       // ignore the '_match_route_' or '_match_domain_' field
       auto local_config_size = config.size() - 1;
+      // ignore the request match fields
+      for (const auto* key :
+           {"_match_path_", "_match_header_", "_match_method_"}) {
+        if (config.find(key) != config.end()) {
+          local_config_size -= 1;
+        }
+      }
       auto has_allow = config.find("allow");
       if (has_allow != config.end()) {
         local_config_size -= 1;
@@ -526,14 +577,19 @@ class RouteRuleMatcher {
         LOG_WARN("failed to parse configuration for _match_domain_");
         return false;
       }
+      if (!parseRequestMatchConfig(config, auth_rule.rule_config)) {
+        return false;
+      }
       auto has_route = !auth_rule.rule_config.routes.empty();
       auto has_route_prefix = !auth_rule.rule_config.route_prefixs.empty();
       auto has_host = !auth_rule.rule_config.hosts.empty();
       auto has_service = !auth_rule.rule_config.services.empty();
-      if (has_route + has_route_prefix + has_host + has_service == 0) {
+      if (has_route + has_route_prefix + has_host + has_service == 0 &&
+          !hasRequestMatch(auth_rule.rule_config)) {
         LOG_WARN(
             "there is at least one of  '_match_route_', '_match_domain_', "
-            "'_match_route_prefix_' and '_match_service_' can "
+            "'_match_route_prefix_', '_match_service_', '_match_path_', "
+            "'_match_header_' and '_match_method_' can "
             "present in configuration.");
         return false;
       }
@@ -546,8 +602,10 @@ class RouteRuleMatcher {
         auth_rule.rule_config.category = CATEGORY::RoutePrefix;
       } else if (has_service) {
         auth_rule.rule_config.category = CATEGORY::Service;
-      } else {
+      } else if (has_host) {
         auth_rule.rule_config.category = CATEGORY::Host;
+      } else {
+        auth_rule.rule_config.category = CATEGORY::Any;
       }
       auth_rule_config_.push_back(std::move(auth_rule));
     }
@@ -617,6 +675,162 @@ class RouteRuleMatcher {
     return false;
   }
 
+  bool hasRequestMatch(const RuleConfig& rule) {
+    return !rule.paths.empty() || !rule.headers.empty() ||
+           !rule.methods.empty();
+  }
+
+  bool requestMatch(const RuleConfig& rule) {
+    if (!rule.methods.empty()) {
+      GET_HEADER_VIEW(":method", method);
+      if (rule.methods.find(std::string(method)) == rule.methods.end()) {
+        return false;
+      }
+    }
+    if (!rule.paths.empty()) {
+      GET_HEADER_VIEW(":path", path);
+      auto query_pos = path.find('?');
+      if (query_pos != std::string_view::npos) {
+        path = path.substr(0, query_pos);
+      }
+      bool path_matched = false;
+      for (const auto& path_match : rule.paths) {
+        if (stringMatch(path_match, path)) {
+          path_matched = true;
+          break;
+        }
+      }
+      if (!path_matched) {
+        return false;
+      }
+    }
+    for (const auto& header_match : rule.headers) {
+      // The header with an empty value is considered absent, since the host
+      // returns an empty value for the absent header.
+      GET_HEADER_VIEW(header_match.name, value);
+      if (value.empty()) {
+        return false;
+      }
+      if (header_match.value &&
+          !stringMatch(header_match.value.value(), value)) {
+        return false;
+      }
+    }
+    return true;
+  }
+
+  bool stringMatch(const StringMatch& match, std::string_view value) {
+    switch (match.type) {
+      case MATCH_TYPE::Exact:
+        return value == match.value;
+      case MATCH_TYPE::Prefix:
+        return absl::StartsWith(absl::string_view(value.data(), value.size()),
+                                match.value);
+      case MATCH_TYPE::Regex:
+        return match.regex->match(value);
+      default:
+        LOG_WARN(absl::StrCat("unexpected string match pattern"));
+        return false;
+    }
+  }
+
+  bool parseStringMatch(const json& config, StringMatch& match) {
+    for (const auto& [key, type] :
+         std::vector<std::pair<std::string, MATCH_TYPE>>{
+             {"exact", MATCH_TYPE::Exact},
+             {"prefix", MATCH_TYPE::Prefix},
+             {"regex", MATCH_TYPE::Regex}}) {
+      auto it = config.find(key);
+      if (it == config.end()) {
+        continue;
+      }
+      auto parse_result = JsonValueAs<std::string>(it.value());
+      if (parse_result.second != Wasm::Common::JsonParserResultDetail::OK ||
+          !parse_result.first) {
+        return false;
+      }
+      match.type = type;
+      match.value = parse_result.first.value();
+      if (type == MATCH_TYPE::Regex) {
+        match.regex =
+            std::make_shared<Wasm::Common::Regex::CompiledGoogleReMatcher>(
+                match.value, false);
+        if (!match.regex->error().empty()) {
+          LOG_WARN(absl::StrCat("invalid regex: ", match.regex->error()));
+          return false;
+        }
+      }
+      return true;
+    }
+    return false;
+  }
+
+  bool parseRequestMatchConfig(const json& config, RuleConfig& rule) {
+    if (!JsonArrayIterate(
+            config, "_match_path_", [&](const json& path) -> bool {
+              StringMatch path_match;
+              if (!path.is_object() || !parseStringMatch(path, path_match)) {
+                LOG_WARN(
+                    "failed to parse '_match_path_' field in filter "
+                    "configuration.");
+                return false;
+              }
+              rule.paths.push_back(std::move(path_match));
+              return true;
+            })) {
+      LOG_WARN("failed to parse configuration for _match_path_");
+      return false;
+    }
+    if (!JsonArrayIterate(
+            config, "_match_header_", [&](const json& header) -> bool {
+              HeaderMatch header_match;
+              auto name = JsonGetField<std::string>(header, "name");
+              if (!header.is_object() ||
+                  name.detail() != Wasm::Common::JsonParserResultDetail::OK ||
+                  name.value().empty()) {
+                LOG_WARN(
+                    "failed to parse '_match_header_' field in filter "
+                    "configuration.");
+                return false;
+              }
+              header_match.name = absl::AsciiStrToLower(name.value());
+              if (header.size() > 1) {
+                StringMatch value_match;
+                if (!parseStringMatch(header, value_match)) {
+                  LOG_WARN(
+                      "failed to parse '_match_header_' field in filter "
+                      "configuration.");
+                  return false;
+                }
+                header_match.value = std::move(value_match);
+              }
+              rule.headers.push_back(std::move(header_match));
+              return true;
+            })) {
+      LOG_WARN("failed to parse configuration for _match_header_");
+      return false;
+    }
+    if (!JsonArrayIterate(
+            config, "_match_method_", [&](const json& method) -> bool {
+              auto parse_result = JsonValueAs<std::string>(method);
+              if (parse_result.second !=
+                      Wasm::Common::JsonParserResultDetail::OK ||
+                  !parse_result.first) {
+                LOG_WARN(
+                    "failed to parse '_match_method_' field in filter "
+                    "configuration.");
+                return false;
+              }
+              rule.methods.insert(
+                  absl::AsciiStrToUpper(parse_result.first.value()));
+              return true;
+            })) {
+      LOG_WARN("failed to parse configuration for _match_method_");
+      return false;
+    }
+    return true;
+  }
+
   bool parseRouteMatchConfig(const json& config,
                              std::unordered_set<std::string>& routes) {
     return JsonArrayIterate(
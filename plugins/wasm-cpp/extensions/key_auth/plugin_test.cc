@@ -80,6 +80,9 @@ class KeyAuthTest : public ::testing::Test {
           if (header == ":path") {
             *result = path_;
           }
+          if (header == ":method") {
+            *result = method_;
+          }
           if (header == "x-api-key") {
             *result = key_header_;
           }
@@ -110,6 +113,7 @@ class KeyAuthTest : public ::testing::Test {
   std::unique_ptr<PluginContext> context_;
 
   std::string path_;
+  std::string method_;
   std::string authority_;
   std::string route_name_;
   std::string key_header_;
@@ -460,6 +464,50 @@ TEST_F(KeyAuthTest, NoGlobalKeySetting) {
             FilterHeadersStatus::Continue);
 }
 
+TEST_F(KeyAuthTest, RequestMatch) {
+  std::string configuration = R"(
+{
+  "consumers" : [ {"credential" : "abc", "name" : "consumer1"} ],
+  "keys" : [ "apiKey", "x-api-key" ],
+  "_rules_" : [
+    {
+      "_match_route_" : ["test"],
+      "_match_path_" : [ {"prefix" : "/admin/"}, {"exact" : "/login"} ],
+      "_match_method_" : ["POST"],
+      "allow" : ["consumer1"]
+    }
+  ]
+})";
+  BufferBase buffer;
+  buffer.set(configuration);
+  EXPECT_CALL(*mock_context_, getBuffer(WasmBufferType::PluginConfiguration))
+      .WillOnce([&buffer](WasmBufferType) { return &buffer; });
+  EXPECT_TRUE(root_context_->configure(configuration.size()));
+
+  route_name_ = "test";
+  method_ = "POST";
+  path_ = "/admin/users?hello=1";
+  EXPECT_EQ(context_->onRequestHeaders(0, false),
+            FilterHeadersStatus::StopIteration);
+
+  path_ = "/admin/users?apiKey=abc";
+  EXPECT_EQ(context_->onRequestHeaders(0, false),
+            FilterHeadersStatus::Continue);
+
+  path_ = "/login";
+  EXPECT_EQ(context_->onRequestHeaders(0, false),
+            FilterHeadersStatus::StopIteration);
+
+  path_ = "/public/users";
+  EXPECT_EQ(context_->onRequestHeaders(0, false),
+            FilterHeadersStatus::Continue);
+
+  method_ = "GET";
+  path_ = "/admin/users";
+  EXPECT_EQ(context_->onRequestHeaders(0, false),
+            FilterHeadersStatus::Continue);
+}
+
 }  // namespace key_auth
 }  // namespace null_plugin
 }  // namespace proxy_wasm
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/higress-group/wasm-go/pkg/log"
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+)
+
+// httpProvider stores the cache in a REST key/value service, which is useful when redis is not available.
+// Wasm plugins can only reach upstreams through HTTP and redis calls, so a Memcached-compatible store
+// can be used by exposing it with such an HTTP API. The service is expected to:
+//
+//	GET {path}{key}            returns 200 with the value as body, or 404 if the key is missing
+//	PUT {path}{key}?ttl={ttl}  stores the body as the value, ttl is omitted if cacheTTL is 0
+type httpProviderInitializer struct {
+}
+
+func (h *httpProviderInitializer) ValidateConfig(cf ProviderConfig) error {
+	if len(cf.serviceName) == 0 {
+		return errors.New("cache service name is required")
+	}
+	if !strings.HasPrefix(cf.path, "/") {
+		return errors.New("cache service path must start with /")
+	}
+	return nil
+}
+
+func (h *httpProviderInitializer) CreateProvider(cf ProviderConfig, log log.Log) (Provider, error) {
+	return &httpProvider{
+		config: cf,
+		client: wrapper.NewClusterClient(wrapper.FQDNCluster{
+			FQDN: cf.serviceName,
+			Host: cf.serviceHost,
+			Port: int64(cf.servicePort)}),
+		log: log,
+	}, nil
+}
+
+type httpProvider struct {
+	config ProviderConfig
+	client wrapper.HttpClient
+	log    log.Log
+}
+
+func (hp *httpProvider) GetProviderType() string {
+	return PROVIDER_TYPE_HTTP
+}
+
+func (hp *httpProvider) Get(key string, cb GetCallback) error {
+	return hp.client.Get(hp.buildPath(key, false), hp.buildHeaders(), func(statusCode int, responseHeaders http.Header, responseBody []byte) {
+		switch {
+		case statusCode == http.StatusOK:
+			cb(string(responseBody), true, nil)
+		case statusCode == http.StatusNotFound:
+			cb("", false, nil)
+		default:
+			cb("", false, fmt.Errorf("unexpected status code %d from cache service, body: %s", statusCode, string(responseBody)))
+		}
+	}, hp.config.timeout)
+}
+
+func (hp *httpProvider) Set(key string, value string, cb SetCallback) error {
+	return hp.client.Put(hp.buildPath(key, true), hp.buildHeaders(), []byte(value), func(statusCode int, responseHeaders http.Header, responseBody []byte) {
+		var err error
+		if statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices {
+			err = fmt.Errorf("unexpected status code %d from cache service, body: %s", statusCode, string(responseBody))
+			hp.log.Errorf("failed to set cache for key: %s, error: %v", key, err)
+		}
+		if cb != nil {
+			cb(err)
+		}
+	}, hp.config.timeout)
+}
+
+func (hp *httpProvider) GetCacheKeyPrefix() string {
+	return hp.config.cacheKeyPrefix
+}
+
+func (hp *httpProvider) buildPath(key string, withTTL bool) string {
+	path := strings.TrimSuffix(hp.config.path, "/") + "/" + url.PathEscape(key)
+	if withTTL && hp.config.cacheTTL > 0 {
+		path += "?ttl=" + strconv.Itoa(hp.config.cacheTTL)
+	}
+	return path
+}
+
+func (hp *httpProvider) buildHeaders() [][2]string {
+	headers := [][2]string{{"Content-Type", "text/plain; charset=utf-8"}}
+	if hp.config.username != "" {
+		credential := base64.StdEncoding.EncodeToString([]byte(hp.config.username + ":" + hp.config.password))
+		headers = append(headers, [2]string{"Authorization", "Basic " + credential})
+	} else if hp.config.password != "" {
+		headers = append(headers, [2]string{"Authorization", "Bearer " + hp.config.password})
+	}
+	return headers
+}
@@ -5,13 +5,14 @@ import (
 	"strings"
 
 	"github.com/higress-group/wasm-go/pkg/log"
-	"github.com/higress-group/wasm-go/pkg/wrapper"
 	"github.com/tidwall/gjson"
 )
 
 const (
 	PROVIDER_TYPE_REDIS  = "redis"
+	PROVIDER_TYPE_HTTP   = "http"
 	DEFAULT_CACHE_PREFIX = "higress-ai-cache:"
+	DEFAULT_HTTP_PATH    = "/"
 )
 
 type providerInitializer interface {
@@ -22,12 +23,13 @@ type providerInitializer interface {
 var (
 	providerInitializers = map[string]providerInitializer{
 		PROVIDER_TYPE_REDIS: &redisProviderInitializer{},
+		PROVIDER_TYPE_HTTP:  &httpProviderInitializer{},
 	}
 )
 
 type ProviderConfig struct {
-	// @Title zh-CN redis 缓存服务提供者类型
-	// @Description zh-CN 缓存服务提供者类型，例如 redis
+	// @Title zh-CN 缓存服务提供者类型
+	// @Description zh-CN 缓存服务提供者类型，可选值为 redis 和 http
 	typ string
 	// @Title zh-CN redis 缓存服务名称
 	// @Description zh-CN 缓存服务名称
@@ -56,6 +58,9 @@ type ProviderConfig struct {
 	// @Title redis database
 	// @Description 指定 redis 的 database，默认使用0
 	database int
+	// @Title zh-CN HTTP 缓存服务路径前缀
+	// @Description zh-CN 仅 http 类型使用，缓存 Key 会拼接在该路径之后，默认值为 "/"
+	path string
 }
 
 func (c *ProviderConfig) GetProviderType() string {
@@ -67,8 +72,8 @@ func (c *ProviderConfig) FromJson(json gjson.Result) {
 	c.serviceName = json.Get("serviceName").String()
 	c.servicePort = int(json.Get("servicePort").Int())
 	if !json.Get("servicePort").Exists() {
-		if strings.HasSuffix(c.serviceName, ".static") {
-			// use default logic port which is 80 for static service
+		if strings.HasSuffix(c.serviceName, ".static") || c.typ == PROVIDER_TYPE_HTTP {
+			// use default logic port which is 80 for static service and http service
 			c.servicePort = 80
 		} else {
 			c.servicePort = 6379
@@ -98,7 +103,10 @@ func (c *ProviderConfig) FromJson(json gjson.Result) {
 	} else {
 		c.cacheKeyPrefix = DEFAULT_CACHE_PREFIX
 	}
-
+	c.path = json.Get("path").String()
+	if c.path == "" {
+		c.path = DEFAULT_HTTP_PATH
+	}
 }
 
 func (c *ProviderConfig) ConvertLegacyJson(json gjson.Result) {
@@ -137,10 +145,17 @@ func CreateProvider(pc ProviderConfig, log log.Log) (Provider, error) {
 	return initializer.CreateProvider(pc, log)
 }
 
+// GetCallback is called with the cached value of the key, found is false when the key is missing.
+type GetCallback func(value string, found bool, err error)
+
+// SetCallback is called when the value is stored or failed to store.
+type SetCallback func(err error)
+
+// Provider is the exact-match cache layer, which maps the cache key to the LLM response.
 type Provider interface {
 	GetProviderType() string
-	Init(username string, password string, timeout uint32) error
-	Get(key string, cb wrapper.RedisResponseCallback) error
-	Set(key string, value string, cb wrapper.RedisResponseCallback) error
+	Get(key string, cb GetCallback) error
+	// Set stores the value with the configured cache TTL, the callback may be nil.
+	Set(key string, value string, cb SetCallback) error
 	GetCacheKeyPrefix() string
 }
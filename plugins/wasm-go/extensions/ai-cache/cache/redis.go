@@ -5,6 +5,7 @@ import (
 
 	"github.com/higress-group/wasm-go/pkg/log"
 	"github.com/higress-group/wasm-go/pkg/wrapper"
+	"github.com/tidwall/resp"
 )
 
 type redisProviderInitializer struct {
@@ -50,15 +51,31 @@ func (rp *redisProvider) Init(username string, password string, timeout uint32)
 	return err
 }
 
-func (rp *redisProvider) Get(key string, cb wrapper.RedisResponseCallback) error {
-	return rp.client.Get(key, cb)
+func (rp *redisProvider) Get(key string, cb GetCallback) error {
+	return rp.client.Get(key, func(response resp.Value) {
+		if err := response.Error(); err != nil {
+			cb("", false, err)
+			return
+		}
+		if response.IsNull() {
+			cb("", false, nil)
+			return
+		}
+		cb(response.String(), true, nil)
+	})
 }
 
-func (rp *redisProvider) Set(key string, value string, cb wrapper.RedisResponseCallback) error {
+func (rp *redisProvider) Set(key string, value string, cb SetCallback) error {
+	var redisCallback wrapper.RedisResponseCallback
+	if cb != nil {
+		redisCallback = func(response resp.Value) {
+			cb(response.Error())
+		}
+	}
 	if rp.config.cacheTTL == 0 {
-		return rp.client.Set(key, value, cb)
+		return rp.client.Set(key, value, redisCallback)
 	} else {
-		return rp.client.SetEx(key, value, rp.config.cacheTTL, cb)
+		return rp.client.SetEx(key, value, rp.config.cacheTTL, redisCallback)
 	}
 }
 
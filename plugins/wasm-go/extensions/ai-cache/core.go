@@ -11,7 +11,6 @@ import (
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
 	logs "github.com/higress-group/wasm-go/pkg/log"
 	"github.com/higress-group/wasm-go/pkg/wrapper"
-	"github.com/tidwall/resp"
 )
 
 // CheckCacheForKey checks if the key is in the cache, or triggers similarity search if not found.
@@ -25,8 +24,8 @@ func CheckCacheForKey(key string, ctx wrapper.HttpContext, c config.PluginConfig
 	queryKey := activeCacheProvider.GetCacheKeyPrefix() + key
 	log.Debugf("[%s] [CheckCacheForKey] querying cache with key: %s", PLUGIN_NAME, queryKey)
 
-	err := activeCacheProvider.Get(queryKey, func(response resp.Value) {
-		handleCacheResponse(key, response, ctx, log, stream, c, useSimilaritySearch)
+	err := activeCacheProvider.Get(queryKey, func(value string, found bool, err error) {
+		handleCacheResponse(key, value, found, err, ctx, log, stream, c, useSimilaritySearch)
 	})
 	if err != nil {
 		log.Errorf("[%s] [CheckCacheForKey] failed to retrieve key: %s from cache, error: %v", PLUGIN_NAME, key, err)
@@ -37,15 +36,15 @@ func CheckCacheForKey(key string, ctx wrapper.HttpContext, c config.PluginConfig
 }
 
 // handleCacheResponse processes cache response and handles cache hits and misses.
-func handleCacheResponse(key string, response resp.Value, ctx wrapper.HttpContext, log logs.Log, stream bool, c config.PluginConfig, useSimilaritySearch bool) {
-	if err := response.Error(); err == nil && !response.IsNull() {
+func handleCacheResponse(key string, value string, found bool, err error, ctx wrapper.HttpContext, log logs.Log, stream bool, c config.PluginConfig, useSimilaritySearch bool) {
+	if err == nil && found {
 		log.Infof("[%s] cache hit for key: %s", PLUGIN_NAME, key)
-		processCacheHit(key, response.String(), stream, ctx, c, log)
+		processCacheHit(key, value, stream, ctx, c, log)
 		return
 	}
 
 	log.Infof("[%s] [handleCacheResponse] cache miss for key: %s", PLUGIN_NAME, key)
-	if err := response.Error(); err != nil {
+	if err != nil {
 		log.Errorf("[%s] [handleCacheResponse] error retrieving key: %s from cache, error: %v", PLUGIN_NAME, key, err)
 	}
 
@@ -193,6 +193,21 @@ var redisAdvancedConfig = func() json.RawMessage {
 	return data
 }()
 
+// 测试配置：HTTP KV 缓存配置
+var httpCacheConfig = func() json.RawMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"cache": map[string]interface{}{
+			"type":        "http",
+			"serviceName": "kv-service.dns",
+			"path":        "/kv",
+			"password":    "test-token",
+			"cacheTTL":    3600,
+		},
+		"cacheKeyStrategy": "lastQuestion",
+	})
+	return data
+}()
+
 func TestParseConfig(t *testing.T) {
 	test.RunGoTest(t, func(t *testing.T) {
 		// 测试基础Redis缓存配置解析
@@ -222,6 +237,26 @@ func TestParseConfig(t *testing.T) {
 			require.False(t, config.EnableSemanticCache)
 		})
 
+		// 测试HTTP KV缓存配置解析
+		t.Run("http cache config", func(t *testing.T) {
+			host, status := test.NewTestHost(httpCacheConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			configRaw, err := host.GetMatchConfig()
+			require.NoError(t, err)
+			require.NotNil(t, configRaw)
+
+			config, ok := configRaw.(*config.PluginConfig)
+			require.True(t, ok, "config should be of type *PluginConfig")
+
+			// 验证缓存服务类型
+			require.NotNil(t, config.GetCacheProvider())
+			require.Equal(t, "http", config.GetCacheProvider().GetProviderType())
+			require.Equal(t, "higress-ai-cache:", config.GetCacheProvider().GetCacheKeyPrefix())
+			require.False(t, config.EnableSemanticCache)
+		})
+
 		// 测试完整配置解析
 		t.Run("complete config", func(t *testing.T) {
 			host, status := test.NewTestHost(completeConfig)
@@ -1124,6 +1159,101 @@ data: [DONE]`,
 			host.CompleteHttp()
 		})
 
+		// 测试HTTP KV缓存命中流程
+		t.Run("http cache hit flow", func(t *testing.T) {
+			host, status := test.NewTestHost(httpCacheConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			// 设置请求头
+			host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/api/chat"},
+				{":method", "POST"},
+				{"content-type", "application/json"},
+			})
+
+			// 设置请求体
+			requestBody := `{
+				"model": "qwen-turbo",
+				"messages": [
+					{
+						"role": "user",
+						"content": "今天天气怎么样？"
+					}
+				],
+				"stream": false
+			}`
+			action := host.CallOnHttpRequestBody([]byte(requestBody))
+			require.Equal(t, types.ActionPause, action)
+
+			// 模拟HTTP KV缓存命中响应
+			host.CallOnHttpCall([][2]string{
+				{":status", "200"},
+				{"content-type", "text/plain"},
+			}, []byte("今天北京天气晴朗，温度25度"))
+
+			// 缓存命中时直接返回缓存内容
+			localResponse := host.GetLocalResponse()
+			require.NotNil(t, localResponse)
+			require.Equal(t, uint32(200), localResponse.StatusCode)
+			require.Contains(t, string(localResponse.Data), "今天北京天气晴朗，温度25度")
+
+			host.CompleteHttp()
+		})
+
+		// 测试HTTP KV缓存未命中流程
+		t.Run("http cache miss flow", func(t *testing.T) {
+			host, status := test.NewTestHost(httpCacheConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			// 设置请求头
+			host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/api/chat"},
+				{":method", "POST"},
+				{"content-type", "application/json"},
+			})
+
+			// 设置请求体
+			requestBody := `{
+				"model": "qwen-turbo",
+				"messages": [
+					{
+						"role": "user",
+						"content": "今天天气怎么样？"
+					}
+				],
+				"stream": false
+			}`
+			host.CallOnHttpRequestBody([]byte(requestBody))
+
+			// 模拟HTTP KV缓存未命中
+			host.CallOnHttpCall([][2]string{
+				{":status", "404"},
+			}, nil)
+			require.Nil(t, host.GetLocalResponse())
+
+			// 设置响应头
+			host.CallOnHttpResponseHeaders([][2]string{
+				{":status", "200"},
+				{"content-type", "application/json"},
+			})
+
+			// 调用响应体处理，这会触发缓存存储
+			responseBody := `{"choices":[{"index":0,"message":{"role":"assistant","content":"今天北京天气晴朗，温度25度"},"finish_reason":"stop"}]}`
+			action := host.CallOnHttpResponseBody([]byte(responseBody))
+			require.Equal(t, types.ActionContinue, action)
+
+			// 模拟HTTP KV存储响应
+			host.CallOnHttpCall([][2]string{
+				{":status", "204"},
+			}, nil)
+
+			host.CompleteHttp()
+		})
+
 		// 测试缓存存储流程
 		t.Run("cache storage flow", func(t *testing.T) {
 			host, status := test.NewTestHost(basicRedisConfig)
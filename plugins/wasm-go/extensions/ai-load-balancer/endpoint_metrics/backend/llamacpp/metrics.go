@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package llamacpp provides llama.cpp server specific pod metrics implementation.
+package llamacpp
+
+import (
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	MetricPrefix = "llamacpp:"
+
+	RunningQueueSizeMetricName = "llamacpp:requests_processing"
+	WaitingQueueSizeMetricName = "llamacpp:requests_deferred"
+	// kv_cache_usage_ratio is only exposed by the older versions of llama.cpp server
+	KVCacheUsagePercentMetricName = "llamacpp:kv_cache_usage_ratio"
+)
+
+var mapping = backend.MetricMapping{
+	RunningQueueSize:    RunningQueueSizeMetricName,
+	WaitingQueueSize:    WaitingQueueSizeMetricName,
+	KVCacheUsagePercent: KVCacheUsagePercentMetricName,
+}
+
+// PromToPodMetrics updates internal pod metrics with the prometheus metrics scraped from llama.cpp server,
+// which exposes the metrics only if it is started with --metrics.
+func PromToPodMetrics(
+	metricFamilies map[string]*dto.MetricFamily,
+	existing *backend.PodMetrics,
+) (*backend.PodMetrics, error) {
+	return backend.PromToPodMetrics(metricFamilies, existing, mapping)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"fmt"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/multierr"
+)
+
+// Common metric names can be used as the target metric for the pools mixing different inference servers,
+// they are mapped to the corresponding metric of each server.
+const (
+	RunningQueueSizeMetricName    = "running_queue_size"
+	WaitingQueueSizeMetricName    = "waiting_queue_size"
+	KVCacheUsagePercentMetricName = "kv_cache_usage_percent"
+)
+
+// IsCommonMetric returns whether the metric name is one of the common metric names.
+func IsCommonMetric(name string) bool {
+	switch name {
+	case RunningQueueSizeMetricName, WaitingQueueSizeMetricName, KVCacheUsagePercentMetricName:
+		return true
+	}
+	return false
+}
+
+// CommonMetricValue returns the value of the common metric.
+func (m *Metrics) CommonMetricValue(name string) (float64, error) {
+	switch name {
+	case RunningQueueSizeMetricName:
+		return float64(m.RunningQueueSize), nil
+	case WaitingQueueSizeMetricName:
+		return float64(m.WaitingQueueSize), nil
+	case KVCacheUsagePercentMetricName:
+		if !m.KVCacheUsageAvailable {
+			return 0, fmt.Errorf("%q is not exposed by the server", name)
+		}
+		return m.KVCacheUsagePercent, nil
+	}
+	return 0, fmt.Errorf("%q is not a common metric", name)
+}
+
+// MetricMapping is the names of the metrics of an inference server for the fields of Metrics,
+// the metrics with empty names are not exposed by the server.
+type MetricMapping struct {
+	RunningQueueSize        string
+	WaitingQueueSize        string
+	KVCacheUsagePercent     string
+	KvCacheMaxTokenCapacity string
+}
+
+// PromToPodMetrics updates pod metrics with scraped prometheus metrics by the mapping.
+// The queue sizes are required while the KV cache metrics are optional since not all the versions
+// of the servers expose them, KVCacheUsageAvailable is set only if the KV cache usage is found.
+// A combined error is returned if the required metrics are missing.
+func PromToPodMetrics(
+	metricFamilies map[string]*dto.MetricFamily,
+	existing *PodMetrics,
+	mapping MetricMapping,
+) (*PodMetrics, error) {
+	var errs error
+	updated := existing.Clone()
+	// User selected metric
+	if updated.MetricName != "" {
+		metricValue, err := GetLatestMetric(metricFamilies, updated.MetricName)
+		errs = multierr.Append(errs, err)
+		if err == nil {
+			updated.MetricValue = MetricValue(metricValue)
+		}
+		return updated, errs
+	}
+	// Default metric
+	runningQueueSize, err := GetLatestMetric(metricFamilies, mapping.RunningQueueSize)
+	errs = multierr.Append(errs, err)
+	if err == nil {
+		updated.RunningQueueSize = int(MetricValue(runningQueueSize))
+	}
+	waitingQueueSize, err := GetLatestMetric(metricFamilies, mapping.WaitingQueueSize)
+	errs = multierr.Append(errs, err)
+	if err == nil {
+		updated.WaitingQueueSize = int(MetricValue(waitingQueueSize))
+	}
+	if mapping.KVCacheUsagePercent != "" {
+		if cachePercent, err := GetLatestMetric(metricFamilies, mapping.KVCacheUsagePercent); err == nil {
+			updated.KVCacheUsagePercent = MetricValue(cachePercent)
+			updated.KVCacheUsageAvailable = true
+		}
+	}
+	if mapping.KvCacheMaxTokenCapacity != "" {
+		if kvCap, err := GetLatestMetric(metricFamilies, mapping.KvCacheMaxTokenCapacity); err == nil {
+			updated.KvCacheMaxTokenCapacity = int(MetricValue(kvCap))
+		}
+	}
+	return updated, errs
+}
+
+// GetLatestMetric gets the latest metric of a family by the timestamp,
+// the first one is returned if the server doesn't set the timestamp.
+func GetLatestMetric(metricFamilies map[string]*dto.MetricFamily, metricName string) (*dto.Metric, error) {
+	mf, ok := metricFamilies[metricName]
+	if !ok {
+		return nil, fmt.Errorf("metric family %q not found", metricName)
+	}
+	if len(mf.GetMetric()) == 0 {
+		return nil, fmt.Errorf("no metrics available for %q", metricName)
+	}
+	var latestTs int64
+	var latest *dto.Metric
+	for _, m := range mf.GetMetric() {
+		if m.GetTimestampMs() >= latestTs {
+			latestTs = m.GetTimestampMs()
+			latest = m
+		}
+	}
+	return latest, nil
+}
+
+// MetricValue returns the value of a gauge, counter or untyped metric.
+func MetricValue(m *dto.Metric) float64 {
+	switch {
+	case m.GetGauge() != nil:
+		return m.GetGauge().GetValue()
+	case m.GetCounter() != nil:
+		return m.GetCounter().GetValue()
+	case m.GetUntyped() != nil:
+		return m.GetUntyped().GetValue()
+	}
+	return 0
+}
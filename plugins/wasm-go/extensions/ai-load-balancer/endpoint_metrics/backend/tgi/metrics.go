@@ -0,0 +1,45 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tgi provides text-generation-inference specific pod metrics implementation.
+package tgi
+
+import (
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	MetricPrefix = "tgi_"
+
+	RunningQueueSizeMetricName = "tgi_batch_current_size"
+	WaitingQueueSizeMetricName = "tgi_queue_size"
+)
+
+// tgi doesn't expose the KV cache usage, so the KV cache filters only consider the queue size of the tgi pods.
+var mapping = backend.MetricMapping{
+	RunningQueueSize: RunningQueueSizeMetricName,
+	WaitingQueueSize: WaitingQueueSizeMetricName,
+}
+
+// PromToPodMetrics updates internal pod metrics with the prometheus metrics scraped from tgi.
+func PromToPodMetrics(
+	metricFamilies map[string]*dto.MetricFamily,
+	existing *backend.PodMetrics,
+) (*backend.PodMetrics, error) {
+	return backend.PromToPodMetrics(metricFamilies, existing, mapping)
+}
@@ -38,6 +38,9 @@ type Metrics struct {
 	WaitingQueueSize        int
 	KVCacheUsagePercent     float64
 	KvCacheMaxTokenCapacity int
+	// KVCacheUsageAvailable is false if the server doesn't expose the KV cache usage, the KV cache
+	// filters fall back to the queue size for such pods instead of taking them as idle.
+	KVCacheUsageAvailable bool
 }
 
 type UserSelectedMetric struct {
@@ -68,6 +71,7 @@ func (pm *PodMetrics) Clone() *PodMetrics {
 			WaitingQueueSize:        pm.WaitingQueueSize,
 			KVCacheUsagePercent:     pm.KVCacheUsagePercent,
 			KvCacheMaxTokenCapacity: pm.KvCacheMaxTokenCapacity,
+			KVCacheUsageAvailable:   pm.KVCacheUsageAvailable,
 		},
 		UserSelectedMetric: UserSelectedMetric{
 			MetricName:  pm.MetricName,
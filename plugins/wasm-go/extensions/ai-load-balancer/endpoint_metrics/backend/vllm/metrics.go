@@ -30,6 +30,8 @@ import (
 )
 
 const (
+	MetricPrefix = "vllm:"
+
 	LoraRequestInfoMetricName                = "vllm:lora_requests_info"
 	LoraRequestInfoRunningAdaptersMetricName = "running_lora_adapters"
 	LoraRequestInfoMaxAdaptersMetricName     = "max_lora"
@@ -77,6 +79,7 @@ func PromToPodMetrics(
 	errs = multierr.Append(errs, err)
 	if err == nil {
 		updated.KVCacheUsagePercent = cachePercent.GetGauge().GetValue()
+		updated.KVCacheUsageAvailable = true
 	}
 
 	loraMetrics, _, err := getLatestLoraMetric(metricFamilies)
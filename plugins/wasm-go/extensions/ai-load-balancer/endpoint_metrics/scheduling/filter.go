@@ -145,13 +145,20 @@ func lowQueueingPodPredicate(_ *LLMRequest, pod *backend.PodMetrics) bool {
 // The intuition is that if there are multiple pods that share similar KV cache in the low range, we
 // should consider them all instead of the absolute minimum one. This worked better than picking the
 // least one as it gives more choices for the next filter, which on aggregate gave better results.
+// The pods not exposing the KV cache usage are kept, since they have been filtered by the queue size
+// already, and taking their KV cache usage as 0 would make them always preferred.
 // TODO: Compare this strategy with other strategies such as top K.
 func leastKVCacheFilterFunc(req *LLMRequest, pods []*backend.PodMetrics) ([]*backend.PodMetrics, error) {
 	min := math.MaxFloat64
 	var max float64 = 0
+	var withKVCache int
 	filtered := []*backend.PodMetrics{}
 
 	for _, pod := range pods {
+		if !pod.KVCacheUsageAvailable {
+			continue
+		}
+		withKVCache++
 		if pod.KVCacheUsagePercent <= min {
 			min = pod.KVCacheUsagePercent
 		}
@@ -161,7 +168,11 @@ func leastKVCacheFilterFunc(req *LLMRequest, pods []*backend.PodMetrics) ([]*bac
 	}
 
 	for _, pod := range pods {
-		if pod.KVCacheUsagePercent >= min && pod.KVCacheUsagePercent <= min+(max-min)/float64(len(pods)) {
+		if !pod.KVCacheUsageAvailable {
+			filtered = append(filtered, pod)
+			continue
+		}
+		if pod.KVCacheUsagePercent >= min && pod.KVCacheUsagePercent <= min+(max-min)/float64(withKVCache) {
 			filtered = append(filtered, pod)
 		}
 	}
@@ -196,8 +207,11 @@ func criticalRequestPredicate(req *LLMRequest, pod *backend.PodMetrics) bool {
 	return req.Critical
 }
 
+// noQueueAndLessThanKVCacheThresholdPredicate only checks the queue size of the pods not exposing
+// the KV cache usage.
 func noQueueAndLessThanKVCacheThresholdPredicate(queueThreshold int, kvCacheThreshold float64) podPredicate {
 	return func(req *LLMRequest, pod *backend.PodMetrics) bool {
-		return pod.WaitingQueueSize <= queueThreshold && pod.KVCacheUsagePercent <= kvCacheThreshold
+		return pod.WaitingQueueSize <= queueThreshold &&
+			(!pod.KVCacheUsageAvailable || pod.KVCacheUsagePercent <= kvCacheThreshold)
 	}
 }
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"strings"
+
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend/llamacpp"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend/sglang"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend/tgi"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend/vllm"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+type metricsMapper func(map[string]*dto.MetricFamily, *backend.PodMetrics) (*backend.PodMetrics, error)
+
+var metricsMappers = []struct {
+	prefix string
+	mapper metricsMapper
+}{
+	{vllm.MetricPrefix, vllm.PromToPodMetrics},
+	{sglang.MetricPrefix, sglang.PromToPodMetrics},
+	{tgi.MetricPrefix, tgi.PromToPodMetrics},
+	{llamacpp.MetricPrefix, llamacpp.PromToPodMetrics},
+}
+
+// detectMapper detects the inference server by the prefix of the metric names, so that a pool can mix
+// different servers. vllm is assumed if no known prefix is found.
+func detectMapper(metricFamilies map[string]*dto.MetricFamily) metricsMapper {
+	for name := range metricFamilies {
+		for _, m := range metricsMappers {
+			if strings.HasPrefix(name, m.prefix) {
+				return m.mapper
+			}
+		}
+	}
+	return vllm.PromToPodMetrics
+}
+
+// promToPodMetrics maps the scraped metrics of any supported inference server to the pod metrics.
+// A common target metric is resolved from the default metrics of the server.
+func promToPodMetrics(metricFamilies map[string]*dto.MetricFamily, pm *backend.PodMetrics) (*backend.PodMetrics, error) {
+	mapper := detectMapper(metricFamilies)
+	targetMetric := pm.MetricName
+	if !backend.IsCommonMetric(targetMetric) {
+		return mapper(metricFamilies, pm)
+	}
+	pm.MetricName = ""
+	updated, err := mapper(metricFamilies, pm)
+	if err != nil {
+		return nil, err
+	}
+	updated.MetricName = targetMetric
+	updated.MetricValue, err = updated.CommonMetricValue(targetMetric)
+	return updated, err
+}
@@ -0,0 +1,167 @@
+package scheduling
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/proxytest"
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
+)
+
+// testVMContext is a minimal VMContext for setting up the proxy-wasm mock host.
+type testVMContext struct {
+	types.DefaultVMContext
+}
+
+// TestMain sets up the proxy-wasm mock host since the filters log by proxywasm.
+func TestMain(m *testing.M) {
+	opt := proxytest.NewEmulatorOption().WithVMContext(&testVMContext{})
+	_, reset := proxytest.NewHostEmulator(opt)
+	defer reset()
+	os.Exit(m.Run())
+}
+
+const (
+	sglangMetrics = `# TYPE sglang:num_running_reqs gauge
+sglang:num_running_reqs{model_name="qwen"} 3
+# TYPE sglang:num_queue_reqs gauge
+sglang:num_queue_reqs{model_name="qwen"} 7
+# TYPE sglang:token_usage gauge
+sglang:token_usage{model_name="qwen"} 0.5
+# TYPE sglang:max_total_num_tokens gauge
+sglang:max_total_num_tokens{model_name="qwen"} 65536
+`
+	tgiMetrics = `# TYPE tgi_batch_current_size gauge
+tgi_batch_current_size 2
+# TYPE tgi_queue_size gauge
+tgi_queue_size 1
+`
+	llamacppMetrics = `# TYPE llamacpp:requests_processing gauge
+llamacpp:requests_processing 1
+# TYPE llamacpp:requests_deferred gauge
+llamacpp:requests_deferred 0
+`
+	vllmMetrics = `# TYPE vllm:num_requests_running gauge
+vllm:num_requests_running{model_name="qwen"} 4
+# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting{model_name="qwen"} 2
+# TYPE vllm:gpu_cache_usage_perc gauge
+vllm:gpu_cache_usage_perc{model_name="qwen"} 0.9
+# TYPE vllm:lora_requests_info gauge
+vllm:lora_requests_info{max_lora="1",running_lora_adapters="",waiting_lora_adapters=""} 1.7e+09
+`
+)
+
+func TestHeterogeneousPool(t *testing.T) {
+	hostMetrics := map[string]string{
+		"sglang":   sglangMetrics,
+		"tgi":      tgiMetrics,
+		"llamacpp": llamacppMetrics,
+		"vllm":     vllmMetrics,
+	}
+
+	s, err := GetScheduler(hostMetrics, MetricPolicyDefault, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][3]float64{
+		"sglang":   {3, 7, 0.5},
+		"tgi":      {2, 1, 0},
+		"llamacpp": {1, 0, 0},
+		"vllm":     {4, 2, 0.9},
+	}
+	for _, pm := range s.podMetrics {
+		got := [3]float64{float64(pm.RunningQueueSize), float64(pm.WaitingQueueSize), pm.KVCacheUsagePercent}
+		if got != expected[pm.Name] {
+			t.Errorf("metrics of %s: expected %v, got %v", pm.Name, expected[pm.Name], got)
+		}
+		if available := pm.Name == "sglang" || pm.Name == "vllm"; pm.KVCacheUsageAvailable != available {
+			t.Errorf("KV cache usage availability of %s: expected %v, got %v", pm.Name, available, pm.KVCacheUsageAvailable)
+		}
+	}
+
+	s, err = GetScheduler(hostMetrics, MetricPolicyLeast, "waiting_queue_size")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pm := range s.podMetrics {
+		if pm.MetricValue != expected[pm.Name][1] {
+			t.Errorf("waiting_queue_size of %s: expected %v, got %v", pm.Name, expected[pm.Name][1], pm.MetricValue)
+		}
+	}
+}
+
+func TestMixedPoolKVCache(t *testing.T) {
+	idleVllmMetrics := `# TYPE vllm:num_requests_running gauge
+vllm:num_requests_running{model_name="qwen"} 1
+# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting{model_name="qwen"} 0
+# TYPE vllm:gpu_cache_usage_perc gauge
+vllm:gpu_cache_usage_perc{model_name="qwen"} 0.5
+# TYPE vllm:lora_requests_info gauge
+vllm:lora_requests_info{max_lora="0",running_lora_adapters="",waiting_lora_adapters=""} 1.7e+09
+`
+	busyTgiMetrics := `# TYPE tgi_batch_current_size gauge
+tgi_batch_current_size 8
+# TYPE tgi_queue_size gauge
+tgi_queue_size 10
+`
+	tests := []struct {
+		name        string
+		hostMetrics map[string]string
+		critical    bool
+		expected    []string
+	}{
+		{
+			name:        "pods without KV cache usage are not taken as idle",
+			hostMetrics: map[string]string{"vllm": idleVllmMetrics, "tgi": "# TYPE tgi_batch_current_size gauge\ntgi_batch_current_size 1\n# TYPE tgi_queue_size gauge\ntgi_queue_size 0\n"},
+			critical:    true,
+			expected:    []string{"tgi", "vllm"},
+		},
+		{
+			name:        "pods without KV cache usage fall back to the queue size",
+			hostMetrics: map[string]string{"vllm": idleVllmMetrics, "tgi": busyTgiMetrics},
+			critical:    true,
+			expected:    []string{"vllm"},
+		},
+		{
+			name:        "sheddable request checks the queue size of pods without KV cache usage",
+			hostMetrics: map[string]string{"vllm": idleVllmMetrics, "tgi": busyTgiMetrics},
+			expected:    []string{"vllm"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := GetScheduler(tt.hostMetrics, MetricPolicyDefault, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			pods, err := s.filter.Filter(&LLMRequest{Model: "qwen", Critical: tt.critical}, s.podMetrics)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, pod := range pods {
+				got = append(got, pod.Name)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected pods %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	_, err := GetScheduler(map[string]string{"vllm": idleVllmMetrics, "tgi": busyTgiMetrics}, MetricPolicyLeast, "kv_cache_usage_percent")
+	if err == nil {
+		t.Error("expected error for kv_cache_usage_percent of tgi")
+	}
+}
+
+func TestMissingMetrics(t *testing.T) {
+	_, err := GetScheduler(map[string]string{"tgi": "# TYPE tgi_queue_size gauge\ntgi_queue_size 1\n"}, MetricPolicyDefault, "")
+	if err == nil {
+		t.Error("expected error for missing tgi_batch_current_size")
+	}
+}
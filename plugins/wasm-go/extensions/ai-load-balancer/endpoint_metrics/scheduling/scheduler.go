@@ -25,7 +25,6 @@ import (
 	"strings"
 
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend"
-	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-load-balancer/endpoint_metrics/backend/vllm"
 
 	"github.com/prometheus/common/expfmt"
 )
@@ -158,7 +157,7 @@ func GetScheduler(hostMetrics map[string]string, metricPolicy string, targetMetr
 				MetricName: targetMetric,
 			},
 		}
-		pm, err = vllm.PromToPodMetrics(metricFamilies, pm)
+		pm, err = promToPodMetrics(metricFamilies, pm)
 		if err != nil {
 			return nil, err
 		}
@@ -22,4 +22,7 @@ require (
 	google.golang.org/protobuf v1.36.6 // indirect
 )
 
-require github.com/tidwall/sjson v1.2.5 // indirect
+require (
+	github.com/tetratelabs/wazero v1.7.2 // indirect
+	github.com/tidwall/sjson v1.2.5 // indirect
+)
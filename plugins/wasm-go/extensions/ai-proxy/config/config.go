@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-proxy/provider"
 	"github.com/tidwall/gjson"
 )
@@ -71,6 +73,15 @@ func (c *PluginConfig) Validate() error {
 	if err := c.activeProviderConfig.Validate(); err != nil {
 		return err
 	}
+	for _, id := range c.activeProviderConfig.GetFallbackProviderIds() {
+		providerConfig := c.getProviderConfigById(id)
+		if providerConfig == nil {
+			return fmt.Errorf("fallback provider %s is not found in providers", id)
+		}
+		if err := providerConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid fallback provider %s: %v", id, err)
+		}
+	}
 	return nil
 }
 
@@ -88,9 +99,21 @@ func (c *PluginConfig) Complete() error {
 	}
 
 	providerConfig := c.GetProviderConfig()
+	if err := providerConfig.SetFallbackProviders(c.getProviderConfigById); err != nil {
+		return err
+	}
 	return providerConfig.SetApiTokensFailover(c.activeProvider)
 }
 
+func (c *PluginConfig) getProviderConfigById(id string) *provider.ProviderConfig {
+	for i := range c.providerConfigs {
+		if c.providerConfigs[i].GetId() == id {
+			return &c.providerConfigs[i]
+		}
+	}
+	return nil
+}
+
 func (c *PluginConfig) GetProvider() provider.Provider {
 	return c.activeProvider
 }
@@ -257,6 +257,8 @@ func onHttpRequestHeader(ctx wrapper.HttpContext, pluginConfig config.PluginConf
 		// save the original request host and path in case they are needed for apiToken health check and retry
 		ctx.SetContext(provider.CtxRequestHost, ctx.Host())
 		ctx.SetContext(provider.CtxRequestPath, ctx.Path())
+		// limit the time to wait for the first byte of the response if fallback is enabled
+		providerConfig.SetFirstByteTimeout()
 
 		err := handler.OnRequestHeaders(ctx, apiName)
 		if err != nil {
@@ -294,8 +296,8 @@ func onHttpRequestBody(ctx wrapper.HttpContext, pluginConfig config.PluginConfig
 	if handler, ok := activeProvider.(provider.RequestBodyHandler); ok {
 		apiName, _ := ctx.GetContext(provider.CtxKeyApiName).(provider.ApiName)
 		providerConfig := pluginConfig.GetProviderConfig()
-		// If retryOnFailure is enabled, save the transformed body to the context in case of retry
-		if providerConfig.IsRetryOnFailureEnabled() {
+		// If retryOnFailure or fallback is enabled, save the transformed body to the context in case of retry
+		if providerConfig.IsRetryOnFailureEnabled() || providerConfig.IsFallbackEnabled() {
 			ctx.SetContext(provider.CtxRequestBody, body)
 		}
 		newBody, settingErr := providerConfig.ReplaceByCustomSettings(body)
@@ -321,8 +323,11 @@ func onHttpRequestBody(ctx wrapper.HttpContext, pluginConfig config.PluginConfig
 
 func onHttpResponseHeaders(ctx wrapper.HttpContext, pluginConfig config.PluginConfig) types.Action {
 	if !wrapper.IsResponseFromUpstream() {
-		// Response is not coming from the upstream. Let it pass through.
+		// Response is not coming from the upstream. Fallback if the upstream is timed out, otherwise let it pass through.
 		ctx.DontReadResponseBody()
+		if providerConfig := pluginConfig.GetProviderConfig(); providerConfig != nil {
+			return providerConfig.OnLocalResponse(ctx)
+		}
 		return types.ActionContinue
 	}
 
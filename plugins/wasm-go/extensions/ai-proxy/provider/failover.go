@@ -166,7 +166,7 @@ func (c *ProviderConfig) SetApiTokensFailover(activeProvider Provider) error {
 						ctx := createHttpContext()
 						ctx.SetContext(c.failover.ctxApiTokenInUse, apiToken)
 
-						modifiedHeaders, modifiedBody, err := c.transformRequestHeadersAndBody(ctx, activeProvider, headers, body)
+						modifiedHeaders, modifiedBody, err := c.transformRequestHeadersAndBody(ctx, activeProvider, ApiNameChatCompletion, headers, body)
 						if err != nil {
 							log.Errorf("Failed to transform request headers and body: %v", err)
 						}
@@ -192,10 +192,10 @@ func generateUrl(header http.Header) string {
 	return fmt.Sprintf("https://%s%s", header.Get(":authority"), header.Get(":path"))
 }
 
-func (c *ProviderConfig) transformRequestHeadersAndBody(ctx wrapper.HttpContext, activeProvider Provider, headers [][2]string, body []byte) (http.Header, []byte, error) {
+func (c *ProviderConfig) transformRequestHeadersAndBody(ctx wrapper.HttpContext, activeProvider Provider, apiName ApiName, headers [][2]string, body []byte) (http.Header, []byte, error) {
 	modifiedHeaders := util.SliceToHeader(headers)
 	if handler, ok := activeProvider.(TransformRequestHeadersHandler); ok {
-		handler.TransformRequestHeaders(ctx, ApiNameChatCompletion, modifiedHeaders)
+		handler.TransformRequestHeaders(ctx, apiName, modifiedHeaders)
 	}
 
 	// Apply providerBasePath if configured
@@ -205,11 +205,11 @@ func (c *ProviderConfig) transformRequestHeadersAndBody(ctx wrapper.HttpContext,
 
 	var err error
 	if handler, ok := activeProvider.(TransformRequestBodyHandler); ok {
-		body, err = handler.TransformRequestBody(ctx, ApiNameChatCompletion, body)
+		body, err = handler.TransformRequestBody(ctx, apiName, body)
 	} else if handler, ok := activeProvider.(TransformRequestBodyHeadersHandler); ok {
-		body, err = handler.TransformRequestBodyHeaders(ctx, ApiNameChatCompletion, body, modifiedHeaders)
+		body, err = handler.TransformRequestBodyHeaders(ctx, apiName, body, modifiedHeaders)
 	} else {
-		body, err = c.defaultTransformRequestBody(ctx, ApiNameChatCompletion, body)
+		body, err = c.defaultTransformRequestBody(ctx, apiName, body)
 	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to transform request body: %v", err)
@@ -586,11 +586,18 @@ func (c *ProviderConfig) OnRequestFailed(activeProvider Provider, ctx wrapper.Ht
 	if c.IsRetryOnFailureEnabled() && util.MatchStatus(status, c.retryOnFailure.retryOnStatus) {
 		log.Warnf("need retry, notice that retry response will be bufferd, error status:%s", status)
 		err := c.retryFailedRequest(activeProvider, ctx, apiTokenInUse, apiTokens)
-		if err != nil {
-			log.Errorf("retryFailedRequest failed, err:%v", err)
-			return types.ActionContinue
+		if err == nil {
+			return types.HeaderStopAllIterationAndWatermark
+		}
+		log.Errorf("retryFailedRequest failed, err:%v", err)
+	}
+	if c.needFallback(ctx, status) {
+		log.Warnf("need fallback, notice that fallback response will be buffered, error status:%s", status)
+		err := c.fallbackFailedRequest(ctx)
+		if err == nil {
+			return types.HeaderStopAllIterationAndWatermark
 		}
-		return types.HeaderStopAllIterationAndWatermark
+		log.Errorf("fallbackFailedRequest failed, err:%v", err)
 	}
 	return types.ActionContinue
 }
@@ -0,0 +1,308 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-proxy/util"
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
+	"github.com/higress-group/wasm-go/pkg/log"
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	ctxFallbackIndex = "fallbackIndex"
+
+	headerUpstreamPerTryTimeout = "x-envoy-upstream-rq-per-try-timeout-ms"
+	headerFallbackProvider      = "x-higress-fallback-provider"
+
+	codeDetailsUpstreamResponseTimeout = "upstream_response_timeout"
+	codeDetailsUpstreamPerTryTimeout   = "upstream_per_try_timeout"
+)
+
+type fallback struct {
+	// @Title zh-CN 是否启用跨服务提供商的降级
+	enabled bool `required:"false" yaml:"enabled" json:"enabled"`
+	// @Title zh-CN 需要进行降级的原始请求的状态码，支持正则表达式匹配
+	fallbackOnStatus []string `required:"false" yaml:"fallbackOnStatus" json:"fallbackOnStatus"`
+	// @Title zh-CN 上游请求超时时是否进行降级
+	fallbackOnTimeout bool `required:"false" yaml:"fallbackOnTimeout" json:"fallbackOnTimeout"`
+	// @Title zh-CN 首字节超时时间，单位毫秒，超时未收到响应头时进行降级，为 0 时不限制
+	firstByteTimeout int64 `required:"false" yaml:"firstByteTimeout" json:"firstByteTimeout"`
+	// @Title zh-CN 降级请求的超时时间，单位毫秒
+	timeout int64 `required:"false" yaml:"timeout" json:"timeout"`
+	// @Title zh-CN 降级链，按顺序依次尝试
+	chain []*fallbackTarget `required:"true" yaml:"chain" json:"chain"`
+}
+
+type fallbackTarget struct {
+	// @Title zh-CN 降级使用的服务提供商 ID
+	providerId string `required:"true" yaml:"providerId" json:"providerId"`
+	// @Title zh-CN 降级使用的模型名称，为空时保持原请求中的模型
+	model string `required:"false" yaml:"model" json:"model"`
+	// @Title zh-CN 降级服务提供商在网关内对应的上游服务名称
+	serviceName string `required:"true" yaml:"serviceName" json:"serviceName"`
+	// @Title zh-CN 降级服务提供商在网关内对应的上游服务端口
+	servicePort int64 `required:"true" yaml:"servicePort" json:"servicePort"`
+
+	config   *ProviderConfig    `yaml:"-"`
+	provider Provider           `yaml:"-"`
+	client   wrapper.HttpClient `yaml:"-"`
+}
+
+func (f *fallback) FromJson(json gjson.Result) {
+	f.enabled = json.Get("enabled").Bool()
+	for _, status := range json.Get("fallbackOnStatus").Array() {
+		f.fallbackOnStatus = append(f.fallbackOnStatus, status.String())
+	}
+	// If fallbackOnStatus is empty, default to fallback on rate limiting and server errors
+	if len(f.fallbackOnStatus) == 0 {
+		f.fallbackOnStatus = []string{"429", "5.*"}
+	}
+	f.fallbackOnTimeout = true
+	if json.Get("fallbackOnTimeout").Exists() {
+		f.fallbackOnTimeout = json.Get("fallbackOnTimeout").Bool()
+	}
+	f.firstByteTimeout = json.Get("firstByteTimeout").Int()
+	f.timeout = json.Get("timeout").Int()
+	if f.timeout == 0 {
+		f.timeout = 60 * 1000
+	}
+	for _, targetJson := range json.Get("chain").Array() {
+		f.chain = append(f.chain, &fallbackTarget{
+			providerId:  targetJson.Get("providerId").String(),
+			model:       targetJson.Get("model").String(),
+			serviceName: targetJson.Get("serviceName").String(),
+			servicePort: targetJson.Get("servicePort").Int(),
+		})
+	}
+}
+
+func (f *fallback) Validate() error {
+	if len(f.chain) == 0 {
+		return errors.New("missing chain in fallback config")
+	}
+	for i, target := range f.chain {
+		if target.providerId == "" {
+			return fmt.Errorf("missing providerId in fallback chain[%d]", i)
+		}
+		if target.serviceName == "" {
+			return fmt.Errorf("missing serviceName in fallback chain[%d]", i)
+		}
+		if target.servicePort == 0 {
+			return fmt.Errorf("missing servicePort in fallback chain[%d]", i)
+		}
+	}
+	if f.firstByteTimeout < 0 {
+		return errors.New("firstByteTimeout in fallback config must not be negative")
+	}
+	return nil
+}
+
+func (c *ProviderConfig) IsFallbackEnabled() bool {
+	return c.fallback != nil && c.fallback.enabled
+}
+
+// GetFallbackProviderIds returns the IDs of the providers referenced by the fallback chain.
+func (c *ProviderConfig) GetFallbackProviderIds() []string {
+	if !c.IsFallbackEnabled() {
+		return nil
+	}
+	ids := make([]string, 0, len(c.fallback.chain))
+	for _, target := range c.fallback.chain {
+		ids = append(ids, target.providerId)
+	}
+	return ids
+}
+
+// SetFallbackProviders creates the providers of the fallback chain with the configs returned by getConfig.
+func (c *ProviderConfig) SetFallbackProviders(getConfig func(id string) *ProviderConfig) error {
+	if !c.IsFallbackEnabled() {
+		return nil
+	}
+	for i, target := range c.fallback.chain {
+		config := getConfig(target.providerId)
+		if config == nil {
+			return fmt.Errorf("unknown provider %s in fallback chain[%d]", target.providerId, i)
+		}
+		targetConfig := *config
+		targetConfig.initVariable()
+		targetProvider, err := CreateProvider(targetConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create provider %s in fallback chain[%d]: %v", target.providerId, i, err)
+		}
+		target.config = &targetConfig
+		target.provider = targetProvider
+		target.client = wrapper.NewClusterClient(wrapper.FQDNCluster{
+			FQDN: target.serviceName,
+			Port: target.servicePort,
+		})
+	}
+	return nil
+}
+
+// SetFirstByteTimeout limits the time to wait for the response headers of the original request,
+// Envoy ignores the per try timeout once the response has started.
+func (c *ProviderConfig) SetFirstByteTimeout() {
+	if !c.IsFallbackEnabled() || c.fallback.firstByteTimeout == 0 {
+		return
+	}
+	_ = proxywasm.ReplaceHttpRequestHeader(headerUpstreamPerTryTimeout, strconv.FormatInt(c.fallback.firstByteTimeout, 10))
+}
+
+// OnLocalResponse starts the fallback when the local response is caused by the upstream timeout.
+func (c *ProviderConfig) OnLocalResponse(ctx wrapper.HttpContext) types.Action {
+	if !c.IsFallbackEnabled() || !c.fallback.fallbackOnTimeout || ctx.GetContext(ctxFallbackIndex) != nil {
+		return types.ActionContinue
+	}
+	codeDetails, err := proxywasm.GetProperty([]string{"response", "code_details"})
+	if err != nil {
+		return types.ActionContinue
+	}
+	switch string(codeDetails) {
+	case codeDetailsUpstreamResponseTimeout, codeDetailsUpstreamPerTryTimeout:
+	default:
+		return types.ActionContinue
+	}
+	log.Warnf("need fallback, notice that fallback response will be buffered, upstream timeout: %s", codeDetails)
+	if err := c.fallbackFailedRequest(ctx); err != nil {
+		log.Errorf("fallbackFailedRequest failed, err:%v", err)
+		return types.ActionContinue
+	}
+	return types.HeaderStopAllIterationAndWatermark
+}
+
+func (c *ProviderConfig) needFallback(ctx wrapper.HttpContext, status string) bool {
+	return c.IsFallbackEnabled() && ctx.GetContext(ctxFallbackIndex) == nil && util.MatchStatus(status, c.fallback.fallbackOnStatus)
+}
+
+func (c *ProviderConfig) fallbackFailedRequest(ctx wrapper.HttpContext) error {
+	log.Infof("Fallback failed request: chain=%v", c.GetFallbackProviderIds())
+	return c.sendFallbackRequest(ctx, 0)
+}
+
+// sendFallbackRequest sends the request to the first target which is able to handle it, starting from index.
+func (c *ProviderConfig) sendFallbackRequest(ctx wrapper.HttpContext, index int) error {
+	apiName, _ := ctx.GetContext(CtxKeyApiName).(ApiName)
+	for ; index < len(c.fallback.chain); index++ {
+		ctx.SetContext(ctxFallbackIndex, index)
+		target := c.fallback.chain[index]
+		if target.provider == nil {
+			log.Errorf("provider %s in fallback chain is not created", target.providerId)
+			continue
+		}
+		headers, body, err := c.buildFallbackRequest(ctx, target, apiName)
+		if err != nil {
+			log.Errorf("failed to build fallback request for provider %s: %v", target.providerId, err)
+			continue
+		}
+		log.Infof("Send fallback request: %d/%d, provider=%s, model=%s", index+1, len(c.fallback.chain), target.providerId, target.model)
+		currentIndex := index
+		err = target.client.Post(generateUrl(headers), util.HeaderToSlice(headers), body,
+			func(statusCode int, responseHeaders http.Header, responseBody []byte) {
+				c.fallbackCall(ctx, apiName, currentIndex, statusCode, responseHeaders, responseBody)
+			}, uint32(c.fallback.timeout))
+		if err != nil {
+			log.Errorf("failed to send fallback request to provider %s: %v", target.providerId, err)
+			continue
+		}
+		return nil
+	}
+	return errors.New("no more providers to fallback")
+}
+
+func (c *ProviderConfig) buildFallbackRequest(ctx wrapper.HttpContext, target *fallbackTarget, apiName ApiName) (http.Header, []byte, error) {
+	body := ctx.GetByteSliceContext(CtxRequestBody, nil)
+	if len(body) == 0 {
+		return nil, nil, errors.New("original request body is not found")
+	}
+	var err error
+	if target.model != "" {
+		body, err = sjson.SetBytes(body, "model", target.model)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set model: %v", err)
+		}
+	}
+	body, err = target.config.ReplaceByCustomSettings(body)
+	if err != nil {
+		log.Errorf("failed to replace fallback request body by custom settings: %v", err)
+	}
+	// The api key selected for the original provider must not be reused by the fallback provider.
+	ctx.SetContext(ctxKeyApiKey, nil)
+	target.config.SetApiTokenInUse(ctx)
+	return target.config.transformRequestHeadersAndBody(ctx, target.provider, apiName, [][2]string{
+		{"content-type", "application/json"},
+		{":authority", ctx.GetStringContext(CtxRequestHost, "")},
+		{":path", ctx.GetStringContext(CtxRequestPath, "")},
+	}, body)
+}
+
+func (c *ProviderConfig) fallbackCall(ctx wrapper.HttpContext, apiName ApiName, index int, statusCode int, responseHeaders http.Header, responseBody []byte) {
+	target := c.fallback.chain[index]
+	if statusCode == http.StatusOK {
+		log.Infof("Fallback request succeeded, provider=%s", target.providerId)
+		headers, body := target.config.transformFallbackResponse(ctx, target.provider, apiName, responseHeaders, responseBody)
+		headers = append(headers, [2]string{headerFallbackProvider, target.providerId})
+		proxywasm.SendHttpResponse(http.StatusOK, headers, body, -1)
+		return
+	}
+
+	// The status header is missing when the http call is timed out or reset.
+	timedOut := responseHeaders.Get(":status") == ""
+	log.Infof("The fallback request failed, provider=%s, status: %d, timedOut: %t, responseBody: %s", target.providerId, statusCode, timedOut, string(responseBody))
+	if (timedOut && c.fallback.fallbackOnTimeout) || util.MatchStatus(strconv.Itoa(statusCode), c.fallback.fallbackOnStatus) {
+		if err := c.sendFallbackRequest(ctx, index+1); err == nil {
+			return
+		}
+	}
+	log.Infof("Reached the end of the fallback chain: %v", c.GetFallbackProviderIds())
+	proxywasm.ResumeHttpResponse()
+}
+
+func (c *ProviderConfig) transformFallbackResponse(ctx wrapper.HttpContext, activeProvider Provider, apiName ApiName, headers http.Header, body []byte) ([][2]string, []byte) {
+	if !strings.HasPrefix(headers.Get("content-type"), "text/event-stream") {
+		return c.transformResponseHeadersAndBody(ctx, activeProvider, apiName, headers, body)
+	}
+
+	// The streaming response is buffered as a whole, so it is transformed as the last chunk.
+	if handler, ok := activeProvider.(TransformResponseHeadersHandler); ok {
+		handler.TransformResponseHeaders(ctx, apiName, headers)
+	} else {
+		c.DefaultTransformResponseHeaders(ctx, headers)
+	}
+	if handler, ok := activeProvider.(StreamingResponseBodyHandler); ok {
+		if transformedBody, err := handler.OnStreamingResponseBody(ctx, apiName, body, true); err != nil {
+			log.Errorf("Failed to transform streaming response body: %v", err)
+		} else if transformedBody != nil {
+			body = transformedBody
+		}
+	} else if handler, ok := activeProvider.(StreamingEventHandler); ok {
+		var responseBuilder strings.Builder
+		for _, event := range ExtractStreamingEvents(ctx, body) {
+			if event.IsEndData() {
+				responseBuilder.WriteString(event.ToHttpString())
+				continue
+			}
+			outputEvents, err := handler.OnStreamingEvent(ctx, apiName, event)
+			if err != nil {
+				log.Errorf("Failed to transform streaming event: %v", err)
+				return util.HeaderToSlice(headers), body
+			}
+			if len(outputEvents) == 0 {
+				responseBuilder.WriteString(event.RawEvent)
+				continue
+			}
+			for _, outputEvent := range outputEvents {
+				responseBuilder.WriteString(outputEvent.ToHttpString())
+			}
+		}
+		body = []byte(responseBuilder.String())
+	}
+	return util.HeaderToSlice(headers), body
+}
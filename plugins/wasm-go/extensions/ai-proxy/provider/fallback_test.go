@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestFallback_Config(t *testing.T) {
+	t.Run("fallback_disabled_by_default", func(t *testing.T) {
+		config := ProviderConfig{}
+		config.FromJson(gjson.Result{})
+		assert.False(t, config.IsFallbackEnabled())
+		assert.Nil(t, config.GetFallbackProviderIds())
+	})
+
+	t.Run("fallback_defaults", func(t *testing.T) {
+		config := ProviderConfig{}
+		config.FromJson(gjson.Parse(`{
+			"fallback": {
+				"enabled": true,
+				"chain": [{"providerId": "qwen", "model": "qwen-max", "serviceName": "qwen.dns", "servicePort": 443}]
+			}
+		}`))
+		assert.True(t, config.IsFallbackEnabled())
+		assert.Equal(t, []string{"429", "5.*"}, config.fallback.fallbackOnStatus)
+		assert.True(t, config.fallback.fallbackOnTimeout)
+		assert.Equal(t, int64(0), config.fallback.firstByteTimeout)
+		assert.Equal(t, int64(60000), config.fallback.timeout)
+		require.Len(t, config.fallback.chain, 1)
+		assert.Equal(t, "qwen", config.fallback.chain[0].providerId)
+		assert.Equal(t, "qwen-max", config.fallback.chain[0].model)
+		assert.Equal(t, "qwen.dns", config.fallback.chain[0].serviceName)
+		assert.Equal(t, int64(443), config.fallback.chain[0].servicePort)
+	})
+
+	t.Run("fallback_parsed_from_json", func(t *testing.T) {
+		config := ProviderConfig{}
+		config.FromJson(gjson.Parse(`{
+			"fallback": {
+				"enabled": true,
+				"fallbackOnStatus": ["503"],
+				"fallbackOnTimeout": false,
+				"firstByteTimeout": 3000,
+				"timeout": 10000,
+				"chain": [
+					{"providerId": "qwen", "serviceName": "qwen.dns", "servicePort": 443},
+					{"providerId": "deepseek", "model": "deepseek-chat", "serviceName": "deepseek.dns", "servicePort": 443}
+				]
+			}
+		}`))
+		assert.Equal(t, []string{"503"}, config.fallback.fallbackOnStatus)
+		assert.False(t, config.fallback.fallbackOnTimeout)
+		assert.Equal(t, int64(3000), config.fallback.firstByteTimeout)
+		assert.Equal(t, int64(10000), config.fallback.timeout)
+		assert.Equal(t, []string{"qwen", "deepseek"}, config.GetFallbackProviderIds())
+	})
+}
+
+func TestFallback_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			json: `{"chain": [{"providerId": "qwen", "serviceName": "qwen.dns", "servicePort": 443}]}`,
+		},
+		{
+			name:    "missing_chain",
+			json:    `{}`,
+			wantErr: "missing chain in fallback config",
+		},
+		{
+			name:    "missing_provider_id",
+			json:    `{"chain": [{"serviceName": "qwen.dns", "servicePort": 443}]}`,
+			wantErr: "missing providerId in fallback chain[0]",
+		},
+		{
+			name:    "missing_service_name",
+			json:    `{"chain": [{"providerId": "qwen", "servicePort": 443}]}`,
+			wantErr: "missing serviceName in fallback chain[0]",
+		},
+		{
+			name:    "missing_service_port",
+			json:    `{"chain": [{"providerId": "qwen", "serviceName": "qwen.dns"}]}`,
+			wantErr: "missing servicePort in fallback chain[0]",
+		},
+		{
+			name:    "negative_first_byte_timeout",
+			json:    `{"firstByteTimeout": -1, "chain": [{"providerId": "qwen", "serviceName": "qwen.dns", "servicePort": 443}]}`,
+			wantErr: "firstByteTimeout in fallback config must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fallback{}
+			f.FromJson(gjson.Parse(tt.json))
+			err := f.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetFallbackProviders(t *testing.T) {
+	newConfig := func(json string) *ProviderConfig {
+		config := &ProviderConfig{}
+		config.FromJson(gjson.Parse(json))
+		return config
+	}
+	configs := map[string]*ProviderConfig{
+		"deepseek": newConfig(`{"id": "deepseek", "type": "deepseek", "apiTokens": ["sk-deepseek"]}`),
+	}
+	getConfig := func(id string) *ProviderConfig {
+		return configs[id]
+	}
+
+	t.Run("resolve_chain", func(t *testing.T) {
+		config := newConfig(`{
+			"id": "openai",
+			"type": "openai",
+			"apiTokens": ["sk-openai"],
+			"fallback": {
+				"enabled": true,
+				"chain": [{"providerId": "deepseek", "model": "deepseek-chat", "serviceName": "deepseek.dns", "servicePort": 443}]
+			}
+		}`)
+		require.NoError(t, config.SetFallbackProviders(getConfig))
+		target := config.fallback.chain[0]
+		require.NotNil(t, target.provider)
+		assert.Equal(t, providerTypeDeepSeek, target.provider.GetProviderType())
+		assert.Equal(t, "deepseek", target.config.GetId())
+		assert.NotNil(t, target.client)
+	})
+
+	t.Run("unknown_provider", func(t *testing.T) {
+		config := newConfig(`{
+			"id": "openai",
+			"type": "openai",
+			"apiTokens": ["sk-openai"],
+			"fallback": {
+				"enabled": true,
+				"chain": [{"providerId": "unknown", "serviceName": "unknown.dns", "servicePort": 443}]
+			}
+		}`)
+		assert.EqualError(t, config.SetFallbackProviders(getConfig), "unknown provider unknown in fallback chain[0]")
+	})
+}
@@ -321,6 +321,9 @@ type ProviderConfig struct {
 	// @Title zh-CN 失败请求重试
 	// @Description zh-CN 对失败的请求立即进行重试
 	retryOnFailure *retryOnFailure `required:"false" yaml:"retryOnFailure" json:"retryOnFailure"`
+	// @Title zh-CN 跨服务提供商降级
+	// @Description zh-CN 当请求失败或超时时，按照降级链依次使用其他服务提供商及模型重新发起请求
+	fallback *fallback `required:"false" yaml:"fallback" json:"fallback"`
 	// @Title zh-CN 推理内容处理方式
 	// @Description zh-CN 如何处理大模型服务返回的推理内容。目前支持以下取值：passthrough（正常输出推理内容）、ignore（不输出推理内容）、concat（将推理内容拼接在常规输出内容之前）。默认为 normal。仅支持通义千问服务。
 	reasoningContentMode string `required:"false" yaml:"reasoningContentMode" json:"reasoningContentMode"`
@@ -670,6 +673,14 @@ func (c *ProviderConfig) FromJson(json gjson.Result) {
 	if retryOnFailureJson.Exists() {
 		c.retryOnFailure.FromJson(retryOnFailureJson)
 	}
+
+	fallbackJson := json.Get("fallback")
+	c.fallback = &fallback{
+		enabled: false,
+	}
+	if fallbackJson.Exists() {
+		c.fallback.FromJson(fallbackJson)
+	}
 	c.difyApiUrl = json.Get("difyApiUrl").String()
 	c.botType = json.Get("botType").String()
 	c.inputVariable = json.Get("inputVariable").String()
@@ -746,6 +757,12 @@ func (c *ProviderConfig) Validate() error {
 		}
 	}
 
+	if c.fallback.enabled {
+		if err := c.fallback.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if c.typ == "" {
 		return errors.New("missing type in provider config")
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
+	"strconv"
 
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-proxy/util"
 	"github.com/higress-group/wasm-go/pkg/log"
@@ -99,16 +100,22 @@ func (c *ProviderConfig) retryCall(
 	if retryCount <= int(c.retryOnFailure.maxRetries) {
 		ctx.SetContext(ctxRetryCount, retryCount)
 		err := c.sendRetryRequest(ctx, apiName, activeProvider, retryClient, apiTokenInUse, apiTokens)
-		if err != nil {
-			log.Errorf("sendRetryRequest failed, err:%v", err)
-			proxywasm.ResumeHttpResponse()
+		if err == nil {
 			return
 		}
+		log.Errorf("sendRetryRequest failed, err:%v", err)
 	} else {
 		log.Infof("Reached the maximum retry count: %d", c.retryOnFailure.maxRetries)
-		proxywasm.ResumeHttpResponse()
-		return
 	}
+
+	if c.needFallback(ctx, strconv.Itoa(statusCode)) {
+		err := c.fallbackFailedRequest(ctx)
+		if err == nil {
+			return
+		}
+		log.Errorf("fallbackFailedRequest failed, err:%v", err)
+	}
+	proxywasm.ResumeHttpResponse()
 }
 
 func (c *ProviderConfig) sendRetryRequest(
@@ -127,7 +134,7 @@ func (c *ProviderConfig) sendRetryRequest(
 	ctx.SetContext(c.failover.ctxApiTokenInUse, apiTokenInUse)
 	requestBody := ctx.GetByteSliceContext(CtxRequestBody, []byte(""))
 	log.Debugf("get original requestBody:%s", requestBody)
-	modifiedHeaders, modifiedBody, err := c.transformRequestHeadersAndBody(ctx, activeProvider, [][2]string{
+	modifiedHeaders, modifiedBody, err := c.transformRequestHeadersAndBody(ctx, activeProvider, ApiNameChatCompletion, [][2]string{
 		{"content-type", "application/json"},
 		{":authority", ctx.GetStringContext(CtxRequestHost, "")},
 		{":path", ctx.GetStringContext(CtxRequestPath, "")},
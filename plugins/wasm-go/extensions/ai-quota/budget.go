@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+type QuotaMode string
+
+const (
+	// QuotaModeToken counts the quota in tokens
+	QuotaModeToken QuotaMode = "token"
+	// QuotaModeBudget counts the quota in currency, priced by the model price table
+	QuotaModeBudget QuotaMode = "budget"
+)
+
+type QuotaWindow string
+
+const (
+	QuotaWindowNone    QuotaWindow = "none"
+	QuotaWindowDaily   QuotaWindow = "daily"
+	QuotaWindowWeekly  QuotaWindow = "weekly"
+	QuotaWindowMonthly QuotaWindow = "monthly"
+)
+
+const (
+	// budgets are stored in redis as integers in micro units of the currency
+	budgetScale = 1000000
+
+	defaultSoftLimitHeader = "x-higress-quota-warning"
+
+	// fallbackModelPricing matches all the models, it prices the models not listed before it
+	fallbackModelPricing = "*"
+
+	// the used key of a window is kept for a while after the window ends, so that it can still be queried
+	usedKeyExpireDelay = 24 * 60 * 60
+)
+
+// increaseUsedScript increases the used quota of the current window, and sets the expiration for the new window
+const increaseUsedScript = `
+local used = redis.call('incrby', KEYS[1], ARGV[1])
+if redis.call('ttl', KEYS[1]) < 0 then
+	redis.call('expire', KEYS[1], ARGV[2])
+end
+return used
+`
+
+type Pricing struct {
+	// Number of tokens the prices apply to
+	Unit   float64
+	Models []ModelPricing
+}
+
+type ModelPricing struct {
+	// Model name, a trailing "*" matches by prefix, and "*" is the fallback of the models not listed
+	Model       string
+	Input       float64
+	Output      float64
+	CachedInput float64
+}
+
+func parsePricing(json gjson.Result) (*Pricing, error) {
+	pricing := &Pricing{
+		Unit: json.Get("unit").Float(),
+	}
+	if pricing.Unit == 0 {
+		pricing.Unit = 1000000
+	}
+	if pricing.Unit < 0 {
+		return nil, errors.New("unit of pricing must be positive")
+	}
+	hasFallback := false
+	for _, modelJson := range json.Get("models").Array() {
+		modelPricing := ModelPricing{
+			Model:       modelJson.Get("model").String(),
+			Input:       modelJson.Get("input").Float(),
+			Output:      modelJson.Get("output").Float(),
+			CachedInput: modelJson.Get("input").Float(),
+		}
+		if cachedInput := modelJson.Get("cached_input"); cachedInput.Exists() {
+			modelPricing.CachedInput = cachedInput.Float()
+		}
+		if modelPricing.Model == "" {
+			return nil, errors.New("model of pricing must not be empty")
+		}
+		if modelPricing.Input < 0 || modelPricing.Output < 0 || modelPricing.CachedInput < 0 {
+			return nil, fmt.Errorf("price of model %s must not be negative", modelPricing.Model)
+		}
+		hasFallback = hasFallback || modelPricing.Model == fallbackModelPricing
+		pricing.Models = append(pricing.Models, modelPricing)
+	}
+	if len(pricing.Models) == 0 {
+		return nil, errors.New("missing models in pricing")
+	}
+	// the models not priced would consume no budget, so a fallback price is required
+	if !hasFallback {
+		return nil, fmt.Errorf("missing the fallback pricing of model %q in pricing", fallbackModelPricing)
+	}
+	return pricing, nil
+}
+
+func (p *Pricing) getModelPricing(model string) *ModelPricing {
+	for i := range p.Models {
+		pattern := p.Models[i].Model
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(model, prefix) {
+				return &p.Models[i]
+			}
+		} else if pattern == model {
+			return &p.Models[i]
+		}
+	}
+	return nil
+}
+
+// calculateCost returns the cost in micro units. Cached tokens are counted in the input tokens of OpenAI compatible
+// APIs, while Anthropic cache reads are not.
+func (p *Pricing) calculateCost(modelPricing *ModelPricing, inputToken, outputToken int64, inputTokenDetails map[string]int64) int {
+	includedCachedToken := inputTokenDetails["cached_tokens"] + inputTokenDetails["cached_content_token_count"]
+	cachedToken := includedCachedToken + inputTokenDetails["cache_read_input_tokens"]
+	inputToken = max(inputToken-includedCachedToken, 0) + inputTokenDetails["cache_creation_input_tokens"]
+	cost := float64(inputToken)*modelPricing.Input + float64(cachedToken)*modelPricing.CachedInput +
+		float64(outputToken)*modelPricing.Output
+	return int(math.Round(cost / p.Unit * budgetScale))
+}
+
+func parseQuotaWindow(window string) (QuotaWindow, error) {
+	switch QuotaWindow(window) {
+	case "", QuotaWindowNone:
+		return QuotaWindowNone, nil
+	case QuotaWindowDaily, QuotaWindowWeekly, QuotaWindowMonthly:
+		return QuotaWindow(window), nil
+	default:
+		return "", fmt.Errorf("invalid quota_window: %s, must be one of [none, daily, weekly, monthly]", window)
+	}
+}
+
+// windowBounds returns the start and the end of the window which contains now.
+func windowBounds(window QuotaWindow, now time.Time, location *time.Location) (time.Time, time.Time) {
+	now = now.In(location)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	switch window {
+	case QuotaWindowDaily:
+		return start, start.AddDate(0, 0, 1)
+	case QuotaWindowWeekly:
+		// weeks start on Monday
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+		return start, start.AddDate(0, 0, 7)
+	case QuotaWindowMonthly:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+		return start, start.AddDate(0, 1, 0)
+	}
+	return time.Time{}, time.Time{}
+}
+
+func (config QuotaConfig) isWindowEnabled() bool {
+	return config.QuotaWindow != QuotaWindowNone
+}
+
+func (config QuotaConfig) getQuotaKey(consumer string) string {
+	return config.RedisKeyPrefix + consumer
+}
+
+// getUsedKey returns the key of the used quota in the window, which changes when a new window starts.
+func (config QuotaConfig) getUsedKey(consumer string, windowStart time.Time) string {
+	return fmt.Sprintf("%s%s:used:%s:%s", config.RedisKeyPrefix, consumer, config.QuotaWindow, windowStart.Format("20060102"))
+}
+
+// parseAmount parses the quota amount of the admin api, which is a decimal in budget mode.
+func (config QuotaConfig) parseAmount(value string) (int, error) {
+	if config.QuotaMode != QuotaModeBudget {
+		return strconv.Atoi(value)
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("invalid budget: %s", value)
+	}
+	return int(math.Round(amount * budgetScale)), nil
+}
+
+func (config QuotaConfig) amountType() string {
+	if config.QuotaMode == QuotaModeBudget {
+		return "number"
+	}
+	return "integer"
+}
+
+// formatAmount converts the quota amount stored in redis to the value returned by the admin api.
+func (config QuotaConfig) formatAmount(amount int) interface{} {
+	if config.QuotaMode != QuotaModeBudget {
+		return amount
+	}
+	return float64(amount) / budgetScale
+}
@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
@@ -22,6 +22,10 @@ import (
 
 const (
 	pluginName = "ai-quota"
+
+	ctxKeySoftLimitWarning = "softLimitWarning"
+	ctxKeyUsedKey          = "usedKey"
+	ctxKeyWindowEnd        = "windowEnd"
 )
 
 type ChatMode string
@@ -49,6 +53,7 @@ func init() {
 		wrapper.ParseConfig(parseConfig),
 		wrapper.ProcessRequestHeaders(onHttpRequestHeaders),
 		wrapper.ProcessRequestBody(onHttpRequestBody),
+		wrapper.ProcessResponseHeaders(onHttpResponseHeaders),
 		wrapper.ProcessStreamingResponseBody(onHttpStreamingResponseBody),
 	)
 }
@@ -58,7 +63,13 @@ type QuotaConfig struct {
 	RedisKeyPrefix  string            `yaml:"redis_key_prefix"`
 	AdminConsumer   string            `yaml:"admin_consumer"`
 	AdminPath       string            `yaml:"admin_path"`
+	QuotaMode       QuotaMode         `yaml:"quota_mode"`
+	QuotaWindow     QuotaWindow       `yaml:"quota_window"`
+	SoftLimitRatio  float64           `yaml:"soft_limit_ratio"`
+	SoftLimitHeader string            `yaml:"soft_limit_header"`
 	credential2Name map[string]string `yaml:"-"`
+	pricing         *Pricing          `yaml:"-"`
+	location        *time.Location    `yaml:"-"`
 	redisClient     wrapper.RedisClient
 }
 
@@ -87,6 +98,52 @@ func parseConfig(json gjson.Result, config *QuotaConfig) error {
 	if config.AdminConsumer == "" {
 		return errors.New("missing admin_consumer in config")
 	}
+	// quota mode
+	config.QuotaMode = QuotaMode(json.Get("quota_mode").String())
+	switch config.QuotaMode {
+	case "":
+		config.QuotaMode = QuotaModeToken
+	case QuotaModeToken:
+	case QuotaModeBudget:
+		pricingConfig := json.Get("pricing")
+		if !pricingConfig.Exists() {
+			return errors.New("missing pricing in config, which is required in budget mode")
+		}
+		pricing, err := parsePricing(pricingConfig)
+		if err != nil {
+			return err
+		}
+		config.pricing = pricing
+	default:
+		return fmt.Errorf("invalid quota_mode: %s, must be one of [token, budget]", config.QuotaMode)
+	}
+	// quota window
+	quotaWindow, err := parseQuotaWindow(json.Get("quota_window").String())
+	if err != nil {
+		return err
+	}
+	config.QuotaWindow = quotaWindow
+	config.location = time.UTC
+	if utcOffset := json.Get("window_utc_offset").String(); utcOffset != "" {
+		offsetTime, err := time.Parse("-07:00", utcOffset)
+		if err != nil {
+			return fmt.Errorf("invalid window_utc_offset: %s, must be like +08:00", utcOffset)
+		}
+		_, offset := offsetTime.Zone()
+		config.location = time.FixedZone(utcOffset, offset)
+	}
+	// soft limit
+	config.SoftLimitRatio = json.Get("soft_limit_ratio").Float()
+	if config.SoftLimitRatio < 0 || config.SoftLimitRatio >= 1 {
+		return errors.New("soft_limit_ratio must be in range [0, 1), 0 disables the soft limit")
+	}
+	if config.SoftLimitRatio > 0 && !config.isWindowEnabled() {
+		return errors.New("soft_limit_ratio requires quota_window to be configured")
+	}
+	config.SoftLimitHeader = json.Get("soft_limit_header").String()
+	if config.SoftLimitHeader == "" {
+		config.SoftLimitHeader = defaultSoftLimitHeader
+	}
 	// Redis
 	config.RedisKeyPrefix = json.Get("redis_key_prefix").String()
 	if config.RedisKeyPrefix == "" {
@@ -166,6 +223,9 @@ func onHttpRequestHeaders(context wrapper.HttpContext, config QuotaConfig) types
 
 	// there is no need to read request body when it is on chat completion mode
 	context.DontReadRequestBody()
+	if config.isWindowEnabled() {
+		return checkWindowQuota(context, config, consumer)
+	}
 	// check quota here
 	config.redisClient.Get(config.RedisKeyPrefix+consumer, func(response resp.Value) {
 		isDenied := false
@@ -188,6 +248,58 @@ func onHttpRequestHeaders(context wrapper.HttpContext, config QuotaConfig) types
 	return types.HeaderStopAllIterationAndWatermark
 }
 
+// checkWindowQuota checks the quota of the current window, the used quota is counted in a key per window,
+// so that the quota is reset automatically when a new window starts.
+func checkWindowQuota(context wrapper.HttpContext, config QuotaConfig, consumer string) types.Action {
+	windowStart, windowEnd := windowBounds(config.QuotaWindow, time.Now(), config.location)
+	usedKey := config.getUsedKey(consumer, windowStart)
+	context.SetContext(ctxKeyUsedKey, usedKey)
+	context.SetContext(ctxKeyWindowEnd, windowEnd)
+	// the quota key and the used key are read one by one, since they are not in the same slot of redis cluster
+	err := config.redisClient.Get(config.getQuotaKey(consumer), func(response resp.Value) {
+		if err := response.Error(); err != nil || response.IsNull() {
+			log.Debugf("get consumer:%s quota failed, response:%v", consumer, response)
+			util.SendResponse(http.StatusForbidden, "ai-quota.noquota", "text/plain", "Request denied by ai quota check, No quota left")
+			return
+		}
+		limit := response.Integer()
+		err := config.redisClient.Get(usedKey, func(response resp.Value) {
+			if err := response.Error(); err != nil {
+				log.Debugf("get consumer:%s used quota failed, response:%v", consumer, response)
+				util.SendResponse(http.StatusForbidden, "ai-quota.noquota", "text/plain", "Request denied by ai quota check, No quota left")
+				return
+			}
+			used := response.Integer()
+			log.Debugf("get consumer:%s quota:%d used:%d", consumer, limit, used)
+			if limit-used <= 0 {
+				util.SendResponse(http.StatusForbidden, "ai-quota.noquota", "text/plain", "Request denied by ai quota check, No quota left")
+				return
+			}
+			if config.SoftLimitRatio > 0 && float64(used) >= float64(limit)*config.SoftLimitRatio {
+				context.SetContext(ctxKeySoftLimitWarning, fmt.Sprintf("used=%.2f%%; reset=%s",
+					float64(used)*100/float64(limit), windowEnd.Format(time.RFC3339)))
+			}
+			proxywasm.ResumeHttpRequest()
+		})
+		if err != nil {
+			log.Errorf("failed to get used quota of consumer:%s, err:%v", consumer, err)
+			proxywasm.ResumeHttpRequest()
+		}
+	})
+	if err != nil {
+		log.Errorf("failed to get quota of consumer:%s, err:%v", consumer, err)
+		return types.ActionContinue
+	}
+	return types.HeaderStopAllIterationAndWatermark
+}
+
+func onHttpResponseHeaders(ctx wrapper.HttpContext, config QuotaConfig) types.Action {
+	if warning, ok := ctx.GetContext(ctxKeySoftLimitWarning).(string); ok {
+		_ = proxywasm.AddHttpResponseHeader(config.SoftLimitHeader, warning)
+	}
+	return types.ActionContinue
+}
+
 func onHttpRequestBody(ctx wrapper.HttpContext, config QuotaConfig, body []byte) types.Action {
 	log.Debugf("onHttpRequestBody()")
 	chatMode, ok := ctx.GetContext("chatMode").(ChatMode)
@@ -227,6 +339,8 @@ func onHttpStreamingResponseBody(ctx wrapper.HttpContext, config QuotaConfig, da
 	if usage := tokenusage.GetTokenUsage(ctx, data); usage.TotalToken > 0 {
 		ctx.SetContext(tokenusage.CtxKeyInputToken, usage.InputToken)
 		ctx.SetContext(tokenusage.CtxKeyOutputToken, usage.OutputToken)
+		ctx.SetContext(tokenusage.CtxKeyModel, usage.Model)
+		ctx.SetContext(tokenusage.CtxKeyInputTokenDetails, usage.InputTokenDetails)
 	}
 
 	// chat completion mode
@@ -242,11 +356,35 @@ func onHttpStreamingResponseBody(ctx wrapper.HttpContext, config QuotaConfig, da
 	outputToken := ctx.GetContext(tokenusage.CtxKeyOutputToken).(int64)
 	consumer := ctx.GetContext("consumer").(string)
 	totalToken := int(inputToken + outputToken)
-	log.Debugf("update consumer:%s, totalToken:%d", consumer, totalToken)
-	config.redisClient.DecrBy(config.RedisKeyPrefix+consumer, totalToken, nil)
+	usedQuota := totalToken
+	if config.QuotaMode == QuotaModeBudget {
+		model, _ := ctx.GetContext(tokenusage.CtxKeyModel).(string)
+		modelPricing := config.pricing.getModelPricing(model)
+		if modelPricing == nil {
+			// unreachable since the fallback pricing is required
+			log.Errorf("pricing of model %s not found, skip updating the quota of consumer:%s", model, consumer)
+			return data
+		}
+		inputTokenDetails, _ := ctx.GetContext(tokenusage.CtxKeyInputTokenDetails).(map[string]int64)
+		usedQuota = config.pricing.calculateCost(modelPricing, inputToken, outputToken, inputTokenDetails)
+	}
+	log.Debugf("update consumer:%s, totalToken:%d, usedQuota:%d", consumer, totalToken, usedQuota)
+	if config.isWindowEnabled() {
+		usedKey, _ := ctx.GetContext(ctxKeyUsedKey).(string)
+		windowEnd, _ := ctx.GetContext(ctxKeyWindowEnd).(time.Time)
+		increaseUsedQuota(config, usedKey, windowEnd, usedQuota, nil)
+		return data
+	}
+	config.redisClient.DecrBy(config.RedisKeyPrefix+consumer, usedQuota, nil)
 	return data
 }
 
+// increaseUsedQuota increases the used quota of a window, the key expires a while after the window ends.
+func increaseUsedQuota(config QuotaConfig, usedKey string, windowEnd time.Time, value int, callback wrapper.RedisResponseCallback) error {
+	ttl := int(time.Until(windowEnd).Seconds()) + usedKeyExpireDelay
+	return config.redisClient.Eval(increaseUsedScript, 1, []interface{}{usedKey}, []interface{}{value, ttl}, callback)
+}
+
 func deniedNoKeyAuthData() types.Action {
 	util.SendResponse(http.StatusUnauthorized, "ai-quota.no_key", "text/plain", "Request denied by ai quota check. No Key Authentication information found.")
 	return types.ActionContinue
@@ -287,9 +425,9 @@ func refreshQuota(ctx wrapper.HttpContext, config QuotaConfig, adminConsumer str
 		values[k] = v[0]
 	}
 	queryConsumer := values["consumer"]
-	quota, err := strconv.Atoi(values["quota"])
+	quota, err := config.parseAmount(values["quota"])
 	if queryConsumer == "" || err != nil {
-		util.SendResponse(http.StatusForbidden, "ai-quota.unauthorized", "text/plain", "Request denied by ai quota check. consumer can't be empty and quota must be integer.")
+		util.SendResponse(http.StatusForbidden, "ai-quota.unauthorized", "text/plain", "Request denied by ai quota check. consumer can't be empty and quota must be "+config.amountType()+".")
 		return types.ActionContinue
 	}
 	err2 := config.redisClient.Set(config.RedisKeyPrefix+queryConsumer, quota, func(response resp.Value) {
@@ -326,6 +464,9 @@ func queryQuota(ctx wrapper.HttpContext, config QuotaConfig, adminConsumer strin
 		return types.ActionContinue
 	}
 	queryConsumer := values["consumer"]
+	if config.isWindowEnabled() {
+		return queryWindowQuota(config, queryConsumer)
+	}
 	err := config.redisClient.Get(config.RedisKeyPrefix+queryConsumer, func(response resp.Value) {
 		quota := 0
 		if err := response.Error(); err != nil {
@@ -337,11 +478,11 @@ func queryQuota(ctx wrapper.HttpContext, config QuotaConfig, adminConsumer strin
 			quota = response.Integer()
 		}
 		result := struct {
-			Consumer string `json:"consumer"`
-			Quota    int    `json:"quota"`
+			Consumer string      `json:"consumer"`
+			Quota    interface{} `json:"quota"`
 		}{
 			Consumer: queryConsumer,
-			Quota:    quota,
+			Quota:    config.formatAmount(quota),
 		}
 		body, _ := json.Marshal(result)
 		util.SendResponse(http.StatusOK, "ai-quota.queryquota", "application/json", string(body))
@@ -353,6 +494,55 @@ func queryQuota(ctx wrapper.HttpContext, config QuotaConfig, adminConsumer strin
 	return types.ActionPause
 }
 
+// queryWindowQuota returns the remaining quota of the current window and when the window is reset.
+func queryWindowQuota(config QuotaConfig, queryConsumer string) types.Action {
+	windowStart, windowEnd := windowBounds(config.QuotaWindow, time.Now(), config.location)
+	// the quota key and the used key are read one by one, since they are not in the same slot of redis cluster
+	err := config.redisClient.Get(config.getQuotaKey(queryConsumer), func(response resp.Value) {
+		if err := response.Error(); err != nil {
+			util.SendResponse(http.StatusServiceUnavailable, "ai-quota.error", "text/plain", fmt.Sprintf("redis error:%v", err))
+			return
+		}
+		limit := response.Integer()
+		err := config.redisClient.Get(config.getUsedKey(queryConsumer, windowStart), func(response resp.Value) {
+			if err := response.Error(); err != nil {
+				util.SendResponse(http.StatusServiceUnavailable, "ai-quota.error", "text/plain", fmt.Sprintf("redis error:%v", err))
+				return
+			}
+			sendWindowQuota(config, queryConsumer, limit, response.Integer(), windowEnd)
+		})
+		if err != nil {
+			util.SendResponse(http.StatusServiceUnavailable, "ai-quota.error", "text/plain", fmt.Sprintf("redis error:%v", err))
+		}
+	})
+	if err != nil {
+		util.SendResponse(http.StatusServiceUnavailable, "ai-quota.error", "text/plain", fmt.Sprintf("redis error:%v", err))
+		return types.ActionContinue
+	}
+	return types.ActionPause
+}
+
+// sendWindowQuota responds the quota of the current window to the admin consumer.
+func sendWindowQuota(config QuotaConfig, queryConsumer string, limit, used int, windowEnd time.Time) {
+	result := struct {
+		Consumer  string      `json:"consumer"`
+		Quota     interface{} `json:"quota"`
+		Limit     interface{} `json:"limit"`
+		Used      interface{} `json:"used"`
+		Window    QuotaWindow `json:"window"`
+		ResetTime string      `json:"reset_time"`
+	}{
+		Consumer:  queryConsumer,
+		Quota:     config.formatAmount(limit - used),
+		Limit:     config.formatAmount(limit),
+		Used:      config.formatAmount(used),
+		Window:    config.QuotaWindow,
+		ResetTime: windowEnd.Format(time.RFC3339),
+	}
+	body, _ := json.Marshal(result)
+	util.SendResponse(http.StatusOK, "ai-quota.queryquota", "application/json", string(body))
+}
+
 func deltaQuota(ctx wrapper.HttpContext, config QuotaConfig, adminConsumer string, body string) types.Action {
 	// check consumer
 	if adminConsumer != config.AdminConsumer {
@@ -366,12 +556,31 @@ func deltaQuota(ctx wrapper.HttpContext, config QuotaConfig, adminConsumer strin
 		values[k] = v[0]
 	}
 	queryConsumer := values["consumer"]
-	value, err := strconv.Atoi(values["value"])
+	value, err := config.parseAmount(values["value"])
 	if queryConsumer == "" || err != nil {
-		util.SendResponse(http.StatusForbidden, "ai-quota.unauthorized", "text/plain", "Request denied by ai quota check. consumer can't be empty and value must be integer.")
+		util.SendResponse(http.StatusForbidden, "ai-quota.unauthorized", "text/plain", "Request denied by ai quota check. consumer can't be empty and value must be "+config.amountType()+".")
 		return types.ActionContinue
 	}
 
+	if config.isWindowEnabled() {
+		// delta is applied to the current window only, by changing the used quota of it
+		windowStart, windowEnd := windowBounds(config.QuotaWindow, time.Now(), config.location)
+		usedKey := config.getUsedKey(queryConsumer, windowStart)
+		err := increaseUsedQuota(config, usedKey, windowEnd, -value, func(response resp.Value) {
+			log.Debugf("Redis Incr key = %s value = %d", usedKey, -value)
+			if err := response.Error(); err != nil {
+				util.SendResponse(http.StatusServiceUnavailable, "ai-quota.error", "text/plain", fmt.Sprintf("redis error:%v", err))
+				return
+			}
+			util.SendResponse(http.StatusOK, "ai-quota.deltaquota", "text/plain", "delta quota successful")
+		})
+		if err != nil {
+			util.SendResponse(http.StatusServiceUnavailable, "ai-quota.error", "text/plain", fmt.Sprintf("redis error:%v", err))
+			return types.ActionContinue
+		}
+		return types.ActionPause
+	}
+
 	if value >= 0 {
 		err := config.redisClient.IncrBy(config.RedisKeyPrefix+queryConsumer, value, func(response resp.Value) {
 			log.Debugf("Redis Incr key = %s value = %d", config.RedisKeyPrefix+queryConsumer, value)
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
 	"github.com/higress-group/wasm-go/pkg/test"
@@ -51,6 +52,38 @@ var missingAdminConsumerConfig = func() json.RawMessage {
 	return data
 }()
 
+// 测试配置：按月重置的预算配置
+var budgetConfig = func() json.RawMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"admin_consumer":    "admin",
+		"redis_key_prefix":  "chat_quota:",
+		"admin_path":        "/quota",
+		"quota_mode":        "budget",
+		"quota_window":      "monthly",
+		"window_utc_offset": "+08:00",
+		"soft_limit_ratio":  0.8,
+		"pricing": map[string]interface{}{
+			"models": []map[string]interface{}{
+				{
+					"model":  "gpt-4o*",
+					"input":  2.5,
+					"output": 10,
+				},
+				{
+					"model":  "*",
+					"input":  5,
+					"output": 20,
+				},
+			},
+		},
+		"redis": map[string]interface{}{
+			"service_name": "redis.static",
+			"service_port": 6379,
+		},
+	})
+	return data
+}()
+
 func TestParseConfig(t *testing.T) {
 	test.RunGoTest(t, func(t *testing.T) {
 		// 测试基础配置解析
@@ -74,6 +107,69 @@ func TestParseConfig(t *testing.T) {
 			defer host.Reset()
 			require.Equal(t, types.OnPluginStartStatusFailed, status)
 		})
+
+		// 测试预算配置解析
+		t.Run("budget config", func(t *testing.T) {
+			host, status := test.NewTestHost(budgetConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+			config, err := host.GetMatchConfig()
+			require.NoError(t, err)
+
+			quotaConfig := config.(*QuotaConfig)
+			require.Equal(t, QuotaModeBudget, quotaConfig.QuotaMode)
+			require.Equal(t, QuotaWindowMonthly, quotaConfig.QuotaWindow)
+			require.Equal(t, 0.8, quotaConfig.SoftLimitRatio)
+			require.Equal(t, defaultSoftLimitHeader, quotaConfig.SoftLimitHeader)
+			require.Len(t, quotaConfig.pricing.Models, 2)
+		})
+
+		// 测试预算模式缺少兜底价格
+		t.Run("budget mode without fallback pricing", func(t *testing.T) {
+			data, _ := json.Marshal(map[string]interface{}{
+				"admin_consumer": "admin",
+				"quota_mode":     "budget",
+				"pricing": map[string]interface{}{
+					"models": []map[string]interface{}{
+						{"model": "gpt-4o*", "input": 2.5, "output": 10},
+					},
+				},
+				"redis": map[string]interface{}{
+					"service_name": "redis.static",
+				},
+			})
+			host, status := test.NewTestHost(data)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusFailed, status)
+		})
+
+		// 测试预算模式缺少价格表
+		t.Run("budget mode without pricing", func(t *testing.T) {
+			data, _ := json.Marshal(map[string]interface{}{
+				"admin_consumer": "admin",
+				"quota_mode":     "budget",
+				"redis": map[string]interface{}{
+					"service_name": "redis.static",
+				},
+			})
+			host, status := test.NewTestHost(data)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusFailed, status)
+		})
+
+		// 测试未配置重置周期时配置软限制
+		t.Run("soft limit without window", func(t *testing.T) {
+			data, _ := json.Marshal(map[string]interface{}{
+				"admin_consumer":   "admin",
+				"soft_limit_ratio": 0.8,
+				"redis": map[string]interface{}{
+					"service_name": "redis.static",
+				},
+			})
+			host, status := test.NewTestHost(data)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusFailed, status)
+		})
 	})
 }
 
@@ -326,3 +422,146 @@ func TestGetOperationMode(t *testing.T) {
 		})
 	}
 }
+
+func TestBudgetQuota(t *testing.T) {
+	test.RunTest(t, func(t *testing.T) {
+		// 测试超过软限制时添加告警响应头
+		t.Run("soft limit warning", func(t *testing.T) {
+			host, status := test.NewTestHost(budgetConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			action := host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/v1/chat/completions"},
+				{":method", "POST"},
+				{"x-mse-consumer", "consumer1"},
+			})
+			require.Equal(t, types.HeaderStopAllIterationAndWatermark, action)
+
+			// 预算为 10，已使用 9，配额和已用量分两次读取
+			host.CallOnRedisCall(0, test.CreateRedisResp(10000000))
+			host.CallOnRedisCall(0, test.CreateRedisResp(9000000))
+			require.Equal(t, types.ActionContinue, host.GetHttpStreamAction())
+
+			host.CallOnHttpResponseHeaders([][2]string{
+				{":status", "200"},
+				{"content-type", "application/json"},
+			})
+			warning := ""
+			for _, header := range host.GetResponseHeaders() {
+				if header[0] == defaultSoftLimitHeader {
+					warning = header[1]
+				}
+			}
+			require.Contains(t, warning, "used=90.00%")
+			host.CompleteHttp()
+		})
+
+		// 测试预算耗尽时拒绝请求
+		t.Run("budget exhausted", func(t *testing.T) {
+			host, status := test.NewTestHost(budgetConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/v1/chat/completions"},
+				{":method", "POST"},
+				{"x-mse-consumer", "consumer1"},
+			})
+			host.CallOnRedisCall(0, test.CreateRedisResp(10000000))
+			host.CallOnRedisCall(0, test.CreateRedisResp(10000000))
+
+			response := host.GetLocalResponse()
+			require.Equal(t, uint32(http.StatusForbidden), response.StatusCode)
+			host.CompleteHttp()
+		})
+
+		// 测试管理员查询剩余预算及重置时间
+		t.Run("admin query budget", func(t *testing.T) {
+			host, status := test.NewTestHost(budgetConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			action := host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/v1/chat/completions/quota?consumer=consumer1"},
+				{":method", "GET"},
+				{"x-mse-consumer", "admin"},
+			})
+			require.Equal(t, types.ActionPause, action)
+			host.CallOnRedisCall(0, test.CreateRedisResp(10000000))
+			host.CallOnRedisCall(0, test.CreateRedisResp(2500000))
+
+			response := host.GetLocalResponse()
+			require.Equal(t, uint32(http.StatusOK), response.StatusCode)
+			var result map[string]interface{}
+			require.NoError(t, json.Unmarshal(response.Data, &result))
+			require.Equal(t, 7.5, result["quota"])
+			require.Equal(t, float64(10), result["limit"])
+			require.Equal(t, 2.5, result["used"])
+			require.Equal(t, "monthly", result["window"])
+			require.NotEmpty(t, result["reset_time"])
+			host.CompleteHttp()
+		})
+	})
+}
+
+func TestWindowBounds(t *testing.T) {
+	location := time.FixedZone("+08:00", 8*60*60)
+	// 2026-10-17 is a Saturday
+	now := time.Date(2026, 10, 17, 1, 30, 0, 0, location)
+	tests := []struct {
+		window QuotaWindow
+		start  time.Time
+		end    time.Time
+	}{
+		{QuotaWindowDaily, time.Date(2026, 10, 17, 0, 0, 0, 0, location), time.Date(2026, 10, 18, 0, 0, 0, 0, location)},
+		{QuotaWindowWeekly, time.Date(2026, 10, 12, 0, 0, 0, 0, location), time.Date(2026, 10, 19, 0, 0, 0, 0, location)},
+		{QuotaWindowMonthly, time.Date(2026, 10, 1, 0, 0, 0, 0, location), time.Date(2026, 11, 1, 0, 0, 0, 0, location)},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.window), func(t *testing.T) {
+			start, end := windowBounds(tt.window, now.UTC(), location)
+			require.True(t, tt.start.Equal(start), "start: %v", start)
+			require.True(t, tt.end.Equal(end), "end: %v", end)
+		})
+	}
+
+	// the window is calculated in the configured time zone
+	start, _ := windowBounds(QuotaWindowDaily, now, time.UTC)
+	require.True(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC).Equal(start))
+}
+
+func TestPricing(t *testing.T) {
+	pricing := &Pricing{
+		Unit: 1000000,
+		Models: []ModelPricing{
+			{Model: "gpt-4o*", Input: 2.5, Output: 10, CachedInput: 1.25},
+			{Model: "claude-sonnet-4", Input: 3, Output: 15, CachedInput: 0.3},
+			{Model: "*", Input: 5, Output: 20, CachedInput: 5},
+		},
+	}
+	require.Equal(t, "gpt-4o*", pricing.getModelPricing("gpt-4o-mini").Model)
+	// the models not listed are priced by the fallback pricing
+	require.Equal(t, "*", pricing.getModelPricing("gpt-3.5-turbo").Model)
+	require.Equal(t, "*", pricing.getModelPricing("").Model)
+	// (100 * 5 + 50 * 20) / 1000000 = 0.0015
+	require.Equal(t, 1500, pricing.calculateCost(pricing.getModelPricing("gpt-3.5-turbo"), 100, 50, nil))
+
+	// (20 * 2.5 + 80 * 1.25 + 50 * 10) / 1000000 = 0.00065
+	require.Equal(t, 650, pricing.calculateCost(&pricing.Models[0], 100, 50, map[string]int64{"cached_tokens": 80}))
+	// (100 * 3 + 1000 * 0.3 + 50 * 15) / 1000000 = 0.00135
+	require.Equal(t, 1350, pricing.calculateCost(&pricing.Models[1], 100, 50, map[string]int64{"cache_read_input_tokens": 1000}))
+
+	budgetConfig := QuotaConfig{QuotaMode: QuotaModeBudget}
+	amount, err := budgetConfig.parseAmount("12.5")
+	require.NoError(t, err)
+	require.Equal(t, 12500000, amount)
+	require.Equal(t, 12.5, budgetConfig.formatAmount(amount))
+
+	tokenConfig := QuotaConfig{QuotaMode: QuotaModeToken}
+	_, err = tokenConfig.parseAmount("12.5")
+	require.Error(t, err)
+}
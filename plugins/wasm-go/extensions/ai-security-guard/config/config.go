@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/pii"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/provider"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
 	"github.com/higress-group/wasm-go/pkg/wrapper"
 	"github.com/tidwall/gjson"
@@ -201,6 +203,10 @@ type AISecurityConfig struct {
 	ApiType string
 	// openai, qwen, comfyui, etc.
 	ProviderType string
+	// The guard backend other than Alibaba Cloud content moderation, nil for aliyun
+	GuardProvider provider.Provider
+	// The pii detection running in the plugin, nil if not configured
+	PiiDetection *pii.Config
 	// "block" or "mask", default "block"
 	RiskAction string
 	// Dimension-level action fields (optional, empty string means not configured)
@@ -213,25 +219,49 @@ type AISecurityConfig struct {
 }
 
 func (config *AISecurityConfig) Parse(json gjson.Result) error {
+	if obj := json.Get("piiDetection"); obj.Exists() {
+		config.PiiDetection = &pii.Config{}
+		if err := config.PiiDetection.FromJson(obj); err != nil {
+			return err
+		}
+	}
 	serviceName := json.Get("serviceName").String()
 	servicePort := json.Get("servicePort").Int()
 	serviceHost := json.Get("serviceHost").String()
 	config.Host = serviceHost
-	if serviceName == "" || servicePort == 0 || serviceHost == "" {
+	// the guard service can be omitted if only the local pii detection is used
+	hasService := serviceName != "" || servicePort != 0 || serviceHost != ""
+	if (hasService || config.PiiDetection == nil) && (serviceName == "" || servicePort == 0 || serviceHost == "") {
 		return errors.New("invalid service config")
 	}
-	config.AK = json.Get("accessKey").String()
-	config.SK = json.Get("secretKey").String()
-	if config.AK == "" || config.SK == "" {
-		return errors.New("invalid AK/SK config")
+	var providerConfig provider.ProviderConfig
+	providerConfig.FromJson(json.Get("guardProvider"))
+	if err := providerConfig.Validate(); err != nil {
+		return err
+	}
+	guardProvider, err := provider.CreateProvider(providerConfig)
+	if err != nil {
+		return err
+	}
+	config.GuardProvider = guardProvider
+	if hasService && config.GuardProvider == nil {
+		config.AK = json.Get("accessKey").String()
+		config.SK = json.Get("secretKey").String()
+		if config.AK == "" || config.SK == "" {
+			return errors.New("invalid AK/SK config")
+		}
+		config.Token = json.Get("securityToken").String()
 	}
-	config.Token = json.Get("securityToken").String()
 	// set action
 	if obj := json.Get("action"); obj.Exists() {
 		config.Action = json.Get("action").String()
 	} else {
 		config.Action = TextModerationPlus
 	}
+	// other guard providers only check text content with the TextModerationPlus pipeline
+	if config.GuardProvider != nil && config.Action != TextModerationPlus {
+		return fmt.Errorf("action %s is not supported by guard provider %s", config.Action, config.GuardProvider.GetProviderType())
+	}
 	// set default values
 	config.SetDefaultValues()
 	// set riskAction
@@ -458,11 +488,13 @@ func (config *AISecurityConfig) Parse(json gjson.Result) error {
 	if obj := json.Get("providerType"); obj.Exists() {
 		config.ProviderType = obj.String()
 	}
-	config.Client = wrapper.NewClusterClient(wrapper.FQDNCluster{
-		FQDN: serviceName,
-		Port: servicePort,
-		Host: serviceHost,
-	})
+	if hasService {
+		config.Client = wrapper.NewClusterClient(wrapper.FQDNCluster{
+			FQDN: serviceName,
+			Port: servicePort,
+			Host: serviceHost,
+		})
+	}
 	config.Metrics = make(map[string]proxywasm.MetricCounter)
 	return nil
 }
@@ -533,6 +565,12 @@ func (config *AISecurityConfig) SetDefaultValues() {
 	config.RiskAction = "block"
 }
 
+// HasGuardService returns whether the content is checked by a guard service, it is false if only the local
+// pii detection is configured.
+func (config *AISecurityConfig) HasGuardService() bool {
+	return config.Client != nil
+}
+
 func (config *AISecurityConfig) IncrementCounter(metricName string, inc uint64) {
 	counter, ok := config.Metrics[metricName]
 	if !ok {
@@ -951,6 +989,19 @@ func BuildDenyResponseBody(response Response, config AISecurityConfig, consumer
 	return json.Marshal(body)
 }
 
+// BuildPiiDenyResponseBody builds the deny response body for the request blocked by the local pii detection.
+func BuildPiiDenyResponseBody(config AISecurityConfig) ([]byte, error) {
+	body := DenyResponseBody{
+		Code:        200,
+		DenyMessage: config.DenyMessage,
+		BlockedDetails: []BlockedDetail{{
+			Type:  SensitiveDataType,
+			Level: S4Sensitive,
+		}},
+	}
+	return json.Marshal(body)
+}
+
 func GetUnacceptableDetail(data Data, config AISecurityConfig, consumer string) []Detail {
 	result := []Detail{}
 	for _, detail := range data.Detail {
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cfg "github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/config"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/provider"
+	"github.com/tidwall/gjson"
+)
+
+// GenerateTextCheckRequest builds the text check request for the configured guard provider,
+// which defaults to Alibaba Cloud content moderation.
+func GenerateTextCheckRequest(config cfg.AISecurityConfig, checkAction, checkService, text, sessionID string, phase provider.Phase) (path string, headers [][2]string, reqBody []byte, err error) {
+	if config.GuardProvider == nil {
+		path, headers, reqBody = GenerateRequestForText(config, checkAction, checkService, text, sessionID)
+		return path, headers, reqBody, nil
+	}
+	return config.GuardProvider.BuildRequest(text, phase, sessionID)
+}
+
+// ParseTextCheckResponse parses the response of the text check request into the response of Alibaba Cloud
+// content moderation, so that the verdicts of all guard providers are evaluated in the same way.
+func ParseTextCheckResponse(config cfg.AISecurityConfig, statusCode int, responseBody []byte) (cfg.Response, error) {
+	var response cfg.Response
+	if config.GuardProvider == nil {
+		if statusCode != 200 || gjson.GetBytes(responseBody, "Code").Int() != 200 {
+			return response, fmt.Errorf("aliyun content security returned status %d: %s", statusCode, responseBody)
+		}
+		if err := json.Unmarshal(responseBody, &response); err != nil {
+			return response, fmt.Errorf("failed to unmarshal aliyun content security response: %v", err)
+		}
+		return response, nil
+	}
+	verdict, err := config.GuardProvider.ParseResponse(statusCode, responseBody)
+	if err != nil {
+		return response, err
+	}
+	response.Code = 200
+	response.Data.RiskLevel = verdict.RiskLevel
+	if verdict.Label != "" {
+		response.Data.Result = []cfg.Result{{Label: verdict.Label, Confidence: verdict.Confidence}}
+	}
+	return response, nil
+}
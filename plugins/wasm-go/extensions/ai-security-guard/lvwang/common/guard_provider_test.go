@@ -0,0 +1,56 @@
+package common
+
+import (
+	"testing"
+
+	cfg "github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/config"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/provider"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newGuardProvider(t *testing.T, configJSON string) provider.Provider {
+	var providerConfig provider.ProviderConfig
+	providerConfig.FromJson(gjson.Parse(configJSON))
+	require.NoError(t, providerConfig.Validate())
+	guardProvider, err := provider.CreateProvider(providerConfig)
+	require.NoError(t, err)
+	return guardProvider
+}
+
+func TestGenerateTextCheckRequest(t *testing.T) {
+	config := cfg.AISecurityConfig{Host: "green-cip.cn-shanghai.aliyuncs.com", AK: "ak", SK: "sk"}
+	path, _, _, err := GenerateTextCheckRequest(config, cfg.TextModerationPlus, "llm_query_moderation", "hello", "session", provider.PhaseRequest)
+	require.NoError(t, err)
+	require.Contains(t, path, "Service=llm_query_moderation")
+
+	config.GuardProvider = newGuardProvider(t, `{"type": "webhook", "path": "/check", "verdictJsonPath": "verdict"}`)
+	path, _, body, err := GenerateTextCheckRequest(config, cfg.TextModerationPlus, "llm_query_moderation", "hello", "session", provider.PhaseResponse)
+	require.NoError(t, err)
+	require.Equal(t, "/check", path)
+	require.JSONEq(t, `{"content": "hello", "phase": "response", "sessionId": "session"}`, string(body))
+}
+
+func TestParseTextCheckResponse(t *testing.T) {
+	config := cfg.AISecurityConfig{RiskLevelBar: cfg.HighRisk}
+
+	response, err := ParseTextCheckResponse(config, 200, []byte(`{"Code": 200, "Data": {"RiskLevel": "high"}}`))
+	require.NoError(t, err)
+	require.Equal(t, "high", response.Data.RiskLevel)
+	_, err = ParseTextCheckResponse(config, 200, []byte(`{"Code": 400, "Message": "invalid"}`))
+	require.Error(t, err)
+
+	config.GuardProvider = newGuardProvider(t, `{"type": "llama-guard", "model": "llama-guard"}`)
+	response, err = ParseTextCheckResponse(config, 200, []byte(`{"choices": [{"message": {"content": "unsafe\nS2"}}]}`))
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Code)
+	require.Equal(t, []cfg.Result{{Label: "S2"}}, response.Data.Result)
+	require.False(t, cfg.IsRiskLevelAcceptable(cfg.TextModerationPlus, response.Data, config, ""))
+
+	response, err = ParseTextCheckResponse(config, 200, []byte(`{"choices": [{"message": {"content": "safe"}}]}`))
+	require.NoError(t, err)
+	require.True(t, cfg.IsRiskLevelAcceptable(cfg.TextModerationPlus, response.Data, config, ""))
+
+	_, err = ParseTextCheckResponse(config, 500, []byte(`internal error`))
+	require.EqualError(t, err, "guard provider returned status 500: internal error")
+}
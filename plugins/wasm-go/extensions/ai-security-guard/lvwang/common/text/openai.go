@@ -10,6 +10,8 @@ import (
 
 	cfg "github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/config"
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/lvwang/common"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/pii"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/provider"
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/utils"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
@@ -55,17 +57,9 @@ func HandleTextGenerationStreamingResponseBody(ctx wrapper.HttpContext, config c
 	var singleCall func()
 	callback := func(statusCode int, responseHeaders http.Header, responseBody []byte) {
 		log.Info(string(responseBody))
-		if statusCode != 200 || gjson.GetBytes(responseBody, "Code").Int() != 200 {
-			if ctx.GetContext("end_of_stream_received").(bool) {
-				proxywasm.ResumeHttpResponse()
-			}
-			ctx.SetContext("during_call", false)
-			return
-		}
-		var response cfg.Response
-		err := json.Unmarshal(responseBody, &response)
+		response, err := common.ParseTextCheckResponse(config, statusCode, responseBody)
 		if err != nil {
-			log.Error("failed to unmarshal aliyun content security response at response phase")
+			log.Errorf("failed to parse the safe check response at response phase: %v", err)
 			if ctx.GetContext("end_of_stream_received").(bool) {
 				proxywasm.ResumeHttpResponse()
 			}
@@ -77,7 +71,7 @@ func HandleTextGenerationStreamingResponseBody(ctx wrapper.HttpContext, config c
 			if err != nil {
 				log.Errorf("failed to build deny response body: %v", err)
 				endStream := ctx.GetContext("end_of_stream_received").(bool) && ctx.BufferQueueSize() == 0
-				proxywasm.InjectEncodedDataToFilterChain(bytes.Join(bufferQueue, []byte("")), endStream)
+				proxywasm.InjectEncodedDataToFilterChain(pii.RestoreStreamingResponse(ctx, bytes.Join(bufferQueue, []byte("")), config.ResponseStreamContentJsonPath, endStream), endStream)
 				bufferQueue = [][]byte{}
 				if !endStream {
 					ctx.SetContext("during_call", false)
@@ -92,7 +86,7 @@ func HandleTextGenerationStreamingResponseBody(ctx wrapper.HttpContext, config c
 			return
 		}
 		endStream := ctx.GetContext("end_of_stream_received").(bool) && ctx.BufferQueueSize() == 0
-		proxywasm.InjectEncodedDataToFilterChain(bytes.Join(bufferQueue, []byte("")), endStream)
+		proxywasm.InjectEncodedDataToFilterChain(pii.RestoreStreamingResponse(ctx, bytes.Join(bufferQueue, []byte("")), config.ResponseStreamContentJsonPath, endStream), endStream)
 		bufferQueue = [][]byte{}
 		if !endStream {
 			ctx.SetContext("during_call", false)
@@ -126,8 +120,10 @@ func HandleTextGenerationStreamingResponseBody(ctx wrapper.HttpContext, config c
 			ctx.SetContext("during_call", true)
 			log.Debugf("current content piece: %s", buffer)
 			checkService := config.GetResponseCheckService(consumer)
-			path, headers, body := common.GenerateRequestForText(config, config.Action, checkService, buffer, sessionID)
-			err := config.Client.Post(path, headers, body, callback, config.Timeout)
+			path, headers, body, err := common.GenerateTextCheckRequest(config, config.Action, checkService, buffer, sessionID, provider.PhaseResponse)
+			if err == nil {
+				err = config.Client.Post(path, headers, body, callback, config.Timeout)
+			}
 			if err != nil {
 				log.Errorf("failed call the safe check service: %v", err)
 				if ctx.GetContext("end_of_stream_received").(bool) {
@@ -201,14 +197,9 @@ func HandleTextGenerationResponseBody(ctx wrapper.HttpContext, config cfg.AISecu
 	var singleCall func()
 	callback := func(statusCode int, responseHeaders http.Header, responseBody []byte) {
 		log.Info(string(responseBody))
-		if statusCode != 200 || gjson.GetBytes(responseBody, "Code").Int() != 200 {
-			proxywasm.ResumeHttpResponse()
-			return
-		}
-		var response cfg.Response
-		err := json.Unmarshal(responseBody, &response)
+		response, err := common.ParseTextCheckResponse(config, statusCode, responseBody)
 		if err != nil {
-			log.Error("failed to unmarshal aliyun content security response at response phase")
+			log.Errorf("failed to parse the safe check response at response phase: %v", err)
 			proxywasm.ResumeHttpResponse()
 			return
 		}
@@ -264,8 +255,10 @@ func HandleTextGenerationResponseBody(ctx wrapper.HttpContext, config cfg.AISecu
 		contentIndex = nextContentIndex
 		log.Debugf("current content piece: %s", contentPiece)
 		checkService := config.GetResponseCheckService(consumer)
-		path, headers, body := common.GenerateRequestForText(config, config.Action, checkService, contentPiece, sessionID)
-		err := config.Client.Post(path, headers, body, callback, config.Timeout)
+		path, headers, body, err := common.GenerateTextCheckRequest(config, config.Action, checkService, contentPiece, sessionID, provider.PhaseResponse)
+		if err == nil {
+			err = config.Client.Post(path, headers, body, callback, config.Timeout)
+		}
 		if err != nil {
 			log.Errorf("failed call the safe check service: %v", err)
 			proxywasm.ResumeHttpResponse()
@@ -9,6 +9,7 @@ import (
 
 	cfg "github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/config"
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/lvwang/common"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/pii"
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/utils"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
@@ -122,28 +123,28 @@ func HandleMcpStreamingResponseBody(ctx wrapper.HttpContext, config cfg.AISecuri
 		}()
 		log.Info(string(responseBody))
 		if statusCode != 200 || gjson.GetBytes(responseBody, "Code").Int() != 200 {
-			proxywasm.InjectEncodedDataToFilterChain(frontBuffer, false)
+			proxywasm.InjectEncodedDataToFilterChain(pii.RestoreEvents(ctx, frontBuffer), false)
 			return
 		}
 		var response cfg.Response
 		err := json.Unmarshal(responseBody, &response)
 		if err != nil {
 			log.Error("failed to unmarshal aliyun content security response at response phase")
-			proxywasm.InjectEncodedDataToFilterChain(frontBuffer, false)
+			proxywasm.InjectEncodedDataToFilterChain(pii.RestoreEvents(ctx, frontBuffer), false)
 			return
 		}
 		if !cfg.IsRiskLevelAcceptable(config.Action, response.Data, config, consumer) {
 			denyBody, err := cfg.BuildDenyResponseBody(response, config, consumer)
 			if err != nil {
 				log.Errorf("failed to build deny response body: %v", err)
-				proxywasm.InjectEncodedDataToFilterChain(frontBuffer, false)
+				proxywasm.InjectEncodedDataToFilterChain(pii.RestoreEvents(ctx, frontBuffer), false)
 				return
 			}
 			marshalledDenyMessage := wrapper.MarshalStr(string(denyBody))
 			denySSEResponse := fmt.Sprintf(DenySSEResponse, marshalledDenyMessage)
 			proxywasm.InjectEncodedDataToFilterChain([]byte(denySSEResponse), true)
 		} else {
-			proxywasm.InjectEncodedDataToFilterChain(frontBuffer, false)
+			proxywasm.InjectEncodedDataToFilterChain(pii.RestoreEvents(ctx, frontBuffer), false)
 		}
 	}
 	singleCall = func() {
@@ -162,7 +163,7 @@ func HandleMcpStreamingResponseBody(ctx wrapper.HttpContext, config cfg.AISecuri
 			err := config.Client.Post(path, headers, body, callback, config.Timeout)
 			if err != nil {
 				log.Errorf("failed call the safe check service: %v", err)
-				proxywasm.InjectEncodedDataToFilterChain(frontBuffer, false)
+				proxywasm.InjectEncodedDataToFilterChain(pii.RestoreEvents(ctx, frontBuffer), false)
 				ctx.SetContext("during_call", false)
 			}
 		}
@@ -178,7 +179,7 @@ func HandleMcpStreamingResponseBody(ctx wrapper.HttpContext, config cfg.AISecuri
 			return []byte{}
 		}
 	}
-	proxywasm.InjectEncodedDataToFilterChain(data, false)
+	proxywasm.InjectEncodedDataToFilterChain(pii.RestoreEvents(ctx, data), false)
 	return []byte{}
 }
 
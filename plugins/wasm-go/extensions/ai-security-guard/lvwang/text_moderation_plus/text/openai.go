@@ -1,13 +1,13 @@
 package text
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	cfg "github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/config"
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/lvwang/common"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/provider"
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/utils"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
@@ -30,14 +30,9 @@ func HandleTextGenerationRequestBody(ctx wrapper.HttpContext, config cfg.AISecur
 	var singleCall func()
 	callback := func(statusCode int, responseHeaders http.Header, responseBody []byte) {
 		log.Info(string(responseBody))
-		if statusCode != 200 || gjson.GetBytes(responseBody, "Code").Int() != 200 {
-			proxywasm.ResumeHttpRequest()
-			return
-		}
-		var response cfg.Response
-		err := json.Unmarshal(responseBody, &response)
+		response, err := common.ParseTextCheckResponse(config, statusCode, responseBody)
 		if err != nil {
-			log.Error("failed to unmarshal aliyun content security response at request phase")
+			log.Errorf("failed to parse the safe check response at request phase: %v", err)
 			proxywasm.ResumeHttpRequest()
 			return
 		}
@@ -93,8 +88,10 @@ func HandleTextGenerationRequestBody(ctx wrapper.HttpContext, config cfg.AISecur
 		contentPiece := content[contentIndex:nextContentIndex]
 		contentIndex = nextContentIndex
 		checkService := config.GetRequestCheckService(consumer)
-		path, headers, body := common.GenerateRequestForText(config, cfg.TextModerationPlus, checkService, contentPiece, sessionID)
-		err := config.Client.Post(path, headers, body, callback, config.Timeout)
+		path, headers, body, err := common.GenerateTextCheckRequest(config, cfg.TextModerationPlus, checkService, contentPiece, sessionID, provider.PhaseRequest)
+		if err == nil {
+			err = config.Client.Post(path, headers, body, callback, config.Timeout)
+		}
 		if err != nil {
 			log.Errorf("failed call the safe check service: %v", err)
 			proxywasm.ResumeHttpRequest()
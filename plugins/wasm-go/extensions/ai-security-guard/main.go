@@ -4,6 +4,7 @@ import (
 	cfg "github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/config"
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/lvwang/multi_modal_guard"
 	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/lvwang/text_moderation_plus"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/pii"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
 	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
 	"github.com/higress-group/wasm-go/pkg/log"
@@ -34,7 +35,7 @@ func onHttpRequestHeaders(ctx wrapper.HttpContext, config cfg.AISecurityConfig)
 	consumer, _ := proxywasm.GetHttpRequestHeader("x-mse-consumer")
 	ctx.SetContext("consumer", consumer)
 	ctx.DisableReroute()
-	if !config.CheckRequest {
+	if !config.CheckRequest && config.PiiDetection == nil {
 		log.Debugf("request checking is disabled")
 		ctx.DontReadRequestBody()
 	}
@@ -42,6 +43,15 @@ func onHttpRequestHeaders(ctx wrapper.HttpContext, config cfg.AISecurityConfig)
 }
 
 func onHttpRequestBody(ctx wrapper.HttpContext, config cfg.AISecurityConfig, body []byte) types.Action {
+	if config.PiiDetection != nil {
+		var action types.Action
+		if body, action = handlePiiRequestBody(ctx, config, body); action != types.ActionContinue {
+			return action
+		}
+	}
+	if !config.CheckRequest || !config.HasGuardService() {
+		return types.ActionContinue
+	}
 	log.Debugf("checking request body...")
 	switch config.Action {
 	case cfg.MultiModalGuard:
@@ -55,7 +65,8 @@ func onHttpRequestBody(ctx wrapper.HttpContext, config cfg.AISecurityConfig, bod
 }
 
 func onHttpResponseHeaders(ctx wrapper.HttpContext, config cfg.AISecurityConfig) types.Action {
-	if !config.CheckResponse {
+	restorePii := pii.GetTokens(ctx) != nil
+	if !isResponseChecked(config) && !restorePii {
 		log.Debugf("response checking is disabled")
 		ctx.DontReadResponseBody()
 		return types.ActionContinue
@@ -66,6 +77,12 @@ func onHttpResponseHeaders(ctx wrapper.HttpContext, config cfg.AISecurityConfig)
 		ctx.DontReadResponseBody()
 		return types.ActionContinue
 	}
+	if restorePii {
+		proxywasm.RemoveHttpResponseHeader("content-length")
+		if !isResponseChecked(config) {
+			return handlePiiResponseHeaders(ctx)
+		}
+	}
 	switch config.Action {
 	case cfg.MultiModalGuard:
 		return multi_modal_guard.OnHttpResponseHeaders(ctx, config)
@@ -78,6 +95,9 @@ func onHttpResponseHeaders(ctx wrapper.HttpContext, config cfg.AISecurityConfig)
 }
 
 func onHttpStreamingResponseBody(ctx wrapper.HttpContext, config cfg.AISecurityConfig, data []byte, endOfStream bool) []byte {
+	if !isResponseChecked(config) {
+		return pii.RestoreStreamingResponse(ctx, data, config.ResponseStreamContentJsonPath, endOfStream)
+	}
 	log.Debugf("checking streaming response body...")
 	switch config.Action {
 	case cfg.MultiModalGuard:
@@ -91,6 +111,10 @@ func onHttpStreamingResponseBody(ctx wrapper.HttpContext, config cfg.AISecurityC
 }
 
 func onHttpResponseBody(ctx wrapper.HttpContext, config cfg.AISecurityConfig, body []byte) types.Action {
+	restorePiiResponseBody(ctx, config, body)
+	if !isResponseChecked(config) {
+		return types.ActionContinue
+	}
 	log.Debugf("checking response body...")
 	switch config.Action {
 	case cfg.MultiModalGuard:
@@ -102,3 +126,8 @@ func onHttpResponseBody(ctx wrapper.HttpContext, config cfg.AISecurityConfig, bo
 		return types.ActionContinue
 	}
 }
+
+// isResponseChecked returns whether the response is checked by the guard service.
+func isResponseChecked(config cfg.AISecurityConfig) bool {
+	return config.CheckResponse && config.HasGuardService()
+}
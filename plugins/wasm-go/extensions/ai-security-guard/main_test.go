@@ -974,6 +974,102 @@ func TestMCP(t *testing.T) {
 	})
 }
 
+func TestPiiRestoreWithMultiModalGuard(t *testing.T) {
+	withPii := func(config json.RawMessage) json.RawMessage {
+		var cfgMap map[string]interface{}
+		_ = json.Unmarshal(config, &cfgMap)
+		cfgMap["checkRequest"] = false
+		cfgMap["piiDetection"] = map[string]interface{}{"action": "tokenize", "types": []string{"email"}}
+		data, _ := json.Marshal(cfgMap)
+		return data
+	}
+	securityResponse := `{"Code": 200, "Message": "Success", "RequestId": "req-pii", "Data": {"RiskLevel": "low"}}`
+	request := func(t *testing.T, host test.TestHost, body string) {
+		host.CallOnHttpRequestHeaders([][2]string{
+			{":authority", "example.com"},
+			{":path", "/v1/chat/completions"},
+			{":method", "POST"},
+		})
+		action := host.CallOnHttpRequestBody([]byte(body))
+		require.Equal(t, types.ActionContinue, action)
+		require.Contains(t, string(host.GetRequestBody()), "[PII_EMAIL_1]")
+	}
+
+	test.RunTest(t, func(t *testing.T) {
+		// 测试 MultiModalGuard 流式响应中的占位符被还原
+		t.Run("text generation streaming response restores pii", func(t *testing.T) {
+			host, status := test.NewTestHost(withPii(multiModalGuardTextConfig))
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			request(t, host, `{"messages": [{"role": "user", "content": "write a mail to john@example.com"}], "stream": true}`)
+			host.CallOnHttpResponseHeaders([][2]string{
+				{":status", "200"},
+				{"content-type", "text/event-stream"},
+			})
+			chunk := "data: {\"choices\":[{\"delta\":{\"content\":\"Dear [PII_EMAIL_1]\"}}]}\n\ndata: [DONE]\n\n"
+			host.CallOnHttpStreamingResponseBody([]byte(chunk), true)
+			host.CallOnHttpCall([][2]string{
+				{":status", "200"},
+				{"content-type", "application/json"},
+			}, []byte(securityResponse))
+
+			body := string(host.GetResponseBody())
+			require.Contains(t, body, "Dear john@example.com")
+			require.NotContains(t, body, "[PII_EMAIL_1]")
+			host.CompleteHttp()
+		})
+
+		// 测试 MultiModalGuard 非流式响应中的占位符被还原
+		t.Run("text generation response restores pii", func(t *testing.T) {
+			host, status := test.NewTestHost(withPii(multiModalGuardTextConfig))
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			request(t, host, `{"messages": [{"role": "user", "content": "write a mail to john@example.com"}]}`)
+			host.CallOnHttpResponseHeaders([][2]string{
+				{":status", "200"},
+				{"content-type", "application/json"},
+			})
+			action := host.CallOnHttpResponseBody([]byte(`{"choices": [{"message": {"role": "assistant", "content": "Dear [PII_EMAIL_1]"}}]}`))
+			require.Equal(t, types.ActionPause, action)
+			host.CallOnHttpCall([][2]string{
+				{":status", "200"},
+				{"content-type", "application/json"},
+			}, []byte(securityResponse))
+
+			body := string(host.GetResponseBody())
+			require.Contains(t, body, "Dear john@example.com")
+			require.NotContains(t, body, "[PII_EMAIL_1]")
+			host.CompleteHttp()
+		})
+
+		// 测试 MultiModalGuard MCP 响应中的占位符被还原
+		t.Run("mcp response restores pii", func(t *testing.T) {
+			host, status := test.NewTestHost(withPii(mcpConfig))
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			request(t, host, `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "send_mail", "arguments": {"to": "john@example.com"}}}`)
+			host.CallOnHttpResponseHeaders([][2]string{
+				{":status", "200"},
+				{"content-type", "application/json"},
+			})
+			action := host.CallOnHttpResponseBody([]byte(`{"content": "mail sent to [PII_EMAIL_1]"}`))
+			require.Equal(t, types.ActionPause, action)
+			host.CallOnHttpCall([][2]string{
+				{":status", "200"},
+				{"content-type", "application/json"},
+			}, []byte(securityResponse))
+
+			body := string(host.GetResponseBody())
+			require.Contains(t, body, "mail sent to john@example.com")
+			require.NotContains(t, body, "[PII_EMAIL_1]")
+			host.CompleteHttp()
+		})
+	})
+}
+
 func TestGetRiskAction(t *testing.T) {
 	// 测试全局默认值
 	t.Run("default is block", func(t *testing.T) {
@@ -1157,6 +1253,103 @@ func TestParseConsumerRiskActionValidation(t *testing.T) {
 	})
 }
 
+func TestParseGuardProvider(t *testing.T) {
+	// 测试非阿里云安全防护服务无需 AK/SK
+	t.Run("openai moderation without AK/SK", func(t *testing.T) {
+		config := cfg.AISecurityConfig{}
+		configJSON := `{
+			"serviceName": "moderation.dns",
+			"servicePort": 443,
+			"serviceHost": "api.openai.com",
+			"checkRequest": true,
+			"guardProvider": {
+				"type": "openai-moderation",
+				"apiKey": "sk-xxx"
+			}
+		}`
+		err := config.Parse(gjson.Parse(configJSON))
+		require.NoError(t, err)
+		require.NotNil(t, config.GuardProvider)
+		require.Equal(t, "openai-moderation", config.GuardProvider.GetProviderType())
+		require.Equal(t, cfg.TextModerationPlus, config.Action)
+	})
+
+	// 测试默认使用阿里云内容安全
+	t.Run("default aliyun requires AK/SK", func(t *testing.T) {
+		config := cfg.AISecurityConfig{}
+		configJSON := `{
+			"serviceName": "security-service",
+			"servicePort": 8080,
+			"serviceHost": "security.example.com"
+		}`
+		err := config.Parse(gjson.Parse(configJSON))
+		require.EqualError(t, err, "invalid AK/SK config")
+	})
+
+	// 测试非阿里云安全防护服务不支持 MultiModalGuard
+	t.Run("multi modal guard is not supported", func(t *testing.T) {
+		config := cfg.AISecurityConfig{}
+		configJSON := `{
+			"serviceName": "guard.dns",
+			"servicePort": 80,
+			"serviceHost": "guard.example.com",
+			"action": "MultiModalGuard",
+			"guardProvider": {
+				"type": "webhook",
+				"path": "/check",
+				"verdictJsonPath": "verdict"
+			}
+		}`
+		err := config.Parse(gjson.Parse(configJSON))
+		require.EqualError(t, err, "action MultiModalGuard is not supported by guard provider webhook")
+	})
+
+	// 测试无效的安全防护服务类型
+	t.Run("unknown guard provider", func(t *testing.T) {
+		config := cfg.AISecurityConfig{}
+		configJSON := `{
+			"serviceName": "guard.dns",
+			"servicePort": 80,
+			"serviceHost": "guard.example.com",
+			"guardProvider": {"type": "unknown"}
+		}`
+		err := config.Parse(gjson.Parse(configJSON))
+		require.EqualError(t, err, "unknown guard provider type: unknown")
+	})
+}
+
+func TestParsePiiDetection(t *testing.T) {
+	// 测试仅开启本地敏感信息检测时无需配置安全防护服务
+	t.Run("local pii detection only", func(t *testing.T) {
+		config := cfg.AISecurityConfig{}
+		configJSON := `{
+			"piiDetection": {
+				"action": "tokenize",
+				"types": ["email", "creditCard"]
+			}
+		}`
+		err := config.Parse(gjson.Parse(configJSON))
+		require.NoError(t, err)
+		require.NotNil(t, config.PiiDetection)
+		require.Equal(t, "tokenize", config.PiiDetection.Action)
+		require.False(t, config.HasGuardService())
+	})
+
+	// 测试未配置本地敏感信息检测时必须配置安全防护服务
+	t.Run("service is required without pii detection", func(t *testing.T) {
+		config := cfg.AISecurityConfig{}
+		err := config.Parse(gjson.Parse(`{}`))
+		require.EqualError(t, err, "invalid service config")
+	})
+
+	// 测试无效的处置动作
+	t.Run("invalid pii action", func(t *testing.T) {
+		config := cfg.AISecurityConfig{}
+		err := config.Parse(gjson.Parse(`{"piiDetection": {"action": "drop"}}`))
+		require.EqualError(t, err, "invalid piiDetection.action, value must be one of [block, mask, tokenize]")
+	})
+}
+
 func TestRiskLevelFunctions(t *testing.T) {
 	// 测试风险等级转换函数
 	t.Run("risk level conversion", func(t *testing.T) {
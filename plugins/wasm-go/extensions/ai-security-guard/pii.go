@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	cfg "github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/config"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/pii"
+	"github.com/alibaba/higress/plugins/wasm-go/extensions/ai-security-guard/utils"
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
+	"github.com/higress-group/wasm-go/pkg/log"
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+	"github.com/tidwall/gjson"
+)
+
+// handlePiiRequestBody detects the pii in the request body locally, and blocks the request or replaces the request
+// body with the masked or tokenized one. The tokens are kept in the context to be restored in the response.
+func handlePiiRequestBody(ctx wrapper.HttpContext, config cfg.AISecurityConfig, body []byte) ([]byte, types.Action) {
+	tokens := pii.NewTokens()
+	newBody, matches, err := config.PiiDetection.RedactJson(body, tokens)
+	if err != nil {
+		log.Errorf("failed to redact pii in request body: %v", err)
+		return body, types.ActionContinue
+	}
+	if len(matches) == 0 {
+		return body, types.ActionContinue
+	}
+	piiTypes := pii.TypesOf(matches)
+	log.Debugf("pii detected in request body: %s", strings.Join(piiTypes, ","))
+	config.IncrementCounter("ai_sec_request_pii", 1)
+	ctx.SetUserAttribute("safecheck_pii_types", strings.Join(piiTypes, ","))
+	if config.PiiDetection.Action != pii.ActionBlock {
+		if err := proxywasm.ReplaceHttpRequestBody(newBody); err != nil {
+			log.Errorf("failed to replace request body: %v", err)
+			return body, types.ActionContinue
+		}
+		if config.PiiDetection.Action == pii.ActionTokenize {
+			pii.SetTokens(ctx, tokens)
+		}
+		ctx.WriteUserAttributeToLogWithKey(wrapper.AILogKey)
+		return newBody, types.ActionContinue
+	}
+	denyBody, err := cfg.BuildPiiDenyResponseBody(config)
+	if err != nil {
+		log.Errorf("failed to build deny response body: %v", err)
+		return body, types.ActionContinue
+	}
+	if config.ProtocolOriginal {
+		proxywasm.SendHttpResponse(uint32(config.DenyCode), [][2]string{{"content-type", "application/json"}}, denyBody, -1)
+	} else if gjson.GetBytes(body, "stream").Bool() {
+		randomID := utils.GenerateRandomChatID()
+		marshalledDenyMessage := wrapper.MarshalStr(string(denyBody))
+		jsonData := []byte(fmt.Sprintf(cfg.OpenAIStreamResponseFormat, randomID, marshalledDenyMessage, randomID))
+		proxywasm.SendHttpResponse(uint32(config.DenyCode), [][2]string{{"content-type", "text/event-stream;charset=UTF-8"}}, jsonData, -1)
+	} else {
+		randomID := utils.GenerateRandomChatID()
+		marshalledDenyMessage := wrapper.MarshalStr(string(denyBody))
+		jsonData := []byte(fmt.Sprintf(cfg.OpenAIResponseFormat, randomID, marshalledDenyMessage))
+		proxywasm.SendHttpResponse(uint32(config.DenyCode), [][2]string{{"content-type", "application/json"}}, jsonData, -1)
+	}
+	ctx.DontReadResponseBody()
+	config.IncrementCounter("ai_sec_request_deny", 1)
+	ctx.SetUserAttribute("safecheck_status", "reqeust deny")
+	ctx.WriteUserAttributeToLogWithKey(wrapper.AILogKey)
+	return body, types.ActionPause
+}
+
+// handlePiiResponseHeaders prepares the response for restoring the tokens when the response is not checked by the
+// guard service.
+func handlePiiResponseHeaders(ctx wrapper.HttpContext) types.Action {
+	contentType, _ := proxywasm.GetHttpResponseHeader("content-type")
+	if strings.Contains(contentType, "text/event-stream") {
+		return types.ActionContinue
+	}
+	ctx.BufferResponseBody()
+	return types.HeaderStopIteration
+}
+
+// restorePiiResponseBody replaces the response body with the one in which the tokens are restored.
+func restorePiiResponseBody(ctx wrapper.HttpContext, config cfg.AISecurityConfig, body []byte) {
+	tokens := pii.GetTokens(ctx)
+	if tokens == nil {
+		return
+	}
+	var restored []byte
+	contentType, _ := proxywasm.GetHttpResponseHeader("content-type")
+	if strings.Contains(contentType, "event-stream") {
+		restored = pii.RestoreStreamingResponse(ctx, body, config.ResponseStreamContentJsonPath, true)
+	} else {
+		var err error
+		if restored, err = tokens.RestoreJson(body); err != nil {
+			log.Errorf("failed to restore pii in response body: %v", err)
+			return
+		}
+	}
+	if err := proxywasm.ReplaceHttpResponseBody(restored); err != nil {
+		log.Errorf("failed to replace response body: %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package pii
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// pii types
+const (
+	TypeEmail      = "email"
+	TypePhone      = "phone"
+	TypeCreditCard = "creditCard"
+	TypeIDCard     = "idCard"
+	TypeIBAN       = "iban"
+	TypeAPIKey     = "apiKey"
+)
+
+type detector struct {
+	typ     string
+	pattern *regexp.Regexp
+	// validate filters out the matches that look like the pii but fail the checksum
+	validate func(value string) bool
+	mask     func(value string) string
+}
+
+// detectors are ordered by priority, the earlier one wins when two matches start at the same position
+var detectors = []detector{
+	{
+		typ:     TypeAPIKey,
+		pattern: regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_\-]{20,}|AKIA[0-9A-Z]{16}|LTAI[0-9A-Za-z]{12,20}|gh[pousr]_[A-Za-z0-9]{36}|xox[abprs]-[A-Za-z0-9\-]{10,}|AIza[0-9A-Za-z_\-]{35})`),
+		mask: func(value string) string {
+			return maskMiddle(value, 4, 0)
+		},
+	},
+	{
+		typ:     TypeEmail,
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+		mask: func(value string) string {
+			at := strings.LastIndex(value, "@")
+			return maskMiddle(value[:at], 1, 0) + value[at:]
+		},
+	},
+	{
+		typ:      TypeIBAN,
+		pattern:  regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		validate: validIBAN,
+		mask: func(value string) string {
+			return maskMiddle(value, 4, 4)
+		},
+	},
+	{
+		typ:      TypeIDCard,
+		pattern:  regexp.MustCompile(`\b\d{17}[\dXx]\b`),
+		validate: validIDCard,
+		mask: func(value string) string {
+			return maskMiddle(value, 3, 4)
+		},
+	},
+	{
+		typ:      TypeCreditCard,
+		pattern:  regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+		validate: validCreditCard,
+		mask: func(value string) string {
+			return maskMiddle(value, 0, 4)
+		},
+	},
+	{
+		typ:     TypePhone,
+		pattern: regexp.MustCompile(`(?:\+86[ \-]?)?\b1[3-9]\d{9}\b|\+[1-9]\d{0,2}[ \-]?\d{4,14}\b`),
+		mask: func(value string) string {
+			return maskMiddle(value, 3, 4)
+		},
+	},
+}
+
+var allTypes = func() []string {
+	var types []string
+	for _, d := range detectors {
+		types = append(types, d.typ)
+	}
+	return types
+}()
+
+// Match is a piece of pii found in the text
+type Match struct {
+	Type  string
+	Value string
+	Start int
+	End   int
+}
+
+// Detect returns the non-overlapping pii of the given types found in the text, ordered by position.
+func Detect(text string, types []string) []Match {
+	var matches []Match
+	priority := map[string]int{}
+	for i, d := range detectors {
+		priority[d.typ] = i
+		if !contains(types, d.typ) {
+			continue
+		}
+		for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+			value := text[loc[0]:loc[1]]
+			if d.validate != nil && !d.validate(value) {
+				continue
+			}
+			matches = append(matches, Match{Type: d.typ, Value: value, Start: loc[0], End: loc[1]})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return priority[matches[i].Type] < priority[matches[j].Type]
+	})
+	var result []Match
+	end := 0
+	for _, match := range matches {
+		if match.Start < end {
+			continue
+		}
+		result = append(result, match)
+		end = match.End
+	}
+	return result
+}
+
+func getDetector(typ string) detector {
+	for _, d := range detectors {
+		if d.typ == typ {
+			return d
+		}
+	}
+	return detector{}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// maskMiddle replaces the letters and digits with '*', except the first keepStart and the last keepEnd ones.
+func maskMiddle(value string, keepStart, keepEnd int) string {
+	total := 0
+	for _, r := range value {
+		if isAlnum(r) {
+			total++
+		}
+	}
+	if keepStart+keepEnd >= total {
+		keepStart, keepEnd = 0, 0
+	}
+	var builder strings.Builder
+	index := 0
+	for _, r := range value {
+		if !isAlnum(r) {
+			builder.WriteRune(r)
+			continue
+		}
+		if index < keepStart || index >= total-keepEnd {
+			builder.WriteRune(r)
+		} else {
+			builder.WriteRune('*')
+		}
+		index++
+	}
+	return builder.String()
+}
+
+func isAlnum(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func digitsOf(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// validCreditCard checks the length and the Luhn checksum of the card number.
+func validCreditCard(value string) bool {
+	digits := digitsOf(value)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+var idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+const idCardCheckCodes = "10X98765432"
+
+// validIDCard checks the birth date and the check code of the 18-digit resident identity card number.
+func validIDCard(value string) bool {
+	value = strings.ToUpper(value)
+	month := (value[10]-'0')*10 + value[11] - '0'
+	day := (value[12]-'0')*10 + value[13] - '0'
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return false
+	}
+	sum := 0
+	for i, weight := range idCardWeights {
+		sum += int(value[i]-'0') * weight
+	}
+	return idCardCheckCodes[sum%11] == value[17]
+}
+
+// validIBAN checks the length and the mod-97 checksum of the IBAN.
+func validIBAN(value string) bool {
+	value = strings.ReplaceAll(value, " ", "")
+	if len(value) < 15 || len(value) > 34 {
+		return false
+	}
+	rearranged := value[4:] + value[:4]
+	remainder := 0
+	for _, r := range rearranged {
+		var n int
+		switch {
+		case r >= '0' && r <= '9':
+			n = int(r - '0')
+			remainder = (remainder*10 + n) % 97
+		case r >= 'A' && r <= 'Z':
+			n = int(r-'A') + 10
+			remainder = (remainder*100 + n) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
@@ -0,0 +1,198 @@
+package pii
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// actions on the detected pii
+const (
+	ActionBlock    = "block"
+	ActionMask     = "mask"
+	ActionTokenize = "tokenize"
+)
+
+// Config is the config of the local pii detection, which runs in the plugin without calling any guard service.
+type Config struct {
+	// block, mask or tokenize, default mask
+	Action string
+	// the pii types to detect, default all
+	Types []string
+}
+
+func (c *Config) FromJson(json gjson.Result) error {
+	c.Action = ActionMask
+	if obj := json.Get("action"); obj.Exists() {
+		c.Action = obj.String()
+	}
+	switch c.Action {
+	case ActionBlock, ActionMask, ActionTokenize:
+	default:
+		return fmt.Errorf("invalid piiDetection.action, value must be one of [block, mask, tokenize]")
+	}
+	c.Types = allTypes
+	if obj := json.Get("types"); obj.Exists() {
+		c.Types = nil
+		for _, item := range obj.Array() {
+			if !contains(allTypes, item.String()) {
+				return fmt.Errorf("invalid piiDetection.types: %s, value must be one of [%s]", item.String(), strings.Join(allTypes, ", "))
+			}
+			c.Types = append(c.Types, item.String())
+		}
+	}
+	return nil
+}
+
+// Tokens maps the pii to the tokens replacing them in the request, so that they can be restored in the response.
+type Tokens struct {
+	values   map[string]string
+	tokens   map[string]string
+	counters map[string]int
+}
+
+func NewTokens() *Tokens {
+	return &Tokens{
+		values:   map[string]string{},
+		tokens:   map[string]string{},
+		counters: map[string]int{},
+	}
+}
+
+func (t *Tokens) Len() int {
+	return len(t.values)
+}
+
+// tokenOf returns the token of the pii value, the same value always gets the same token in a request.
+func (t *Tokens) tokenOf(typ, value string) string {
+	if token, ok := t.tokens[value]; ok {
+		return token
+	}
+	t.counters[typ]++
+	token := fmt.Sprintf("[PII_%s_%d]", strings.ToUpper(typ), t.counters[typ])
+	t.tokens[value] = token
+	t.values[token] = value
+	return token
+}
+
+// Restore replaces the tokens in the text with the original values.
+func (t *Tokens) Restore(text string) string {
+	if len(t.values) == 0 || !strings.Contains(text, tokenPrefix) {
+		return text
+	}
+	pairs := make([]string, 0, len(t.values)*2)
+	for token, value := range t.values {
+		pairs = append(pairs, token, value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+const tokenPrefix = "[PII_"
+
+// pendingLength returns the length of the suffix of the text which may be the beginning of a token.
+func (t *Tokens) pendingLength(text string) int {
+	idx := strings.LastIndex(text, "[")
+	if idx < 0 {
+		return 0
+	}
+	suffix := text[idx:]
+	for token := range t.values {
+		if len(suffix) < len(token) && strings.HasPrefix(token, suffix) {
+			return len(suffix)
+		}
+	}
+	return 0
+}
+
+// Redact masks or tokenizes the pii found in the text. It returns the text unchanged with the matches if the action
+// is block.
+func (c *Config) Redact(text string, tokens *Tokens) (string, []Match) {
+	matches := Detect(text, c.Types)
+	if len(matches) == 0 || c.Action == ActionBlock {
+		return text, matches
+	}
+	var builder strings.Builder
+	last := 0
+	for _, match := range matches {
+		builder.WriteString(text[last:match.Start])
+		if c.Action == ActionTokenize {
+			builder.WriteString(tokens.tokenOf(match.Type, match.Value))
+		} else {
+			builder.WriteString(getDetector(match.Type).mask(match.Value))
+		}
+		last = match.End
+	}
+	builder.WriteString(text[last:])
+	return builder.String(), matches
+}
+
+// RedactJson redacts the pii in all the string values of the json body.
+func (c *Config) RedactJson(body []byte, tokens *Tokens) ([]byte, []Match, error) {
+	var matches []Match
+	result, err := transformJsonStrings(body, func(value string) string {
+		redacted, found := c.Redact(value, tokens)
+		matches = append(matches, found...)
+		return redacted
+	})
+	return result, matches, err
+}
+
+// RestoreJson restores the tokens in all the string values of the json body.
+func (t *Tokens) RestoreJson(body []byte) ([]byte, error) {
+	return transformJsonStrings(body, t.Restore)
+}
+
+// transformJsonStrings applies the transform to every string value of the json body, the keys are kept as is.
+func transformJsonStrings(body []byte, transform func(string) string) ([]byte, error) {
+	type change struct {
+		path  string
+		value string
+	}
+	var changes []change
+	var walk func(value gjson.Result, path string)
+	walk = func(value gjson.Result, path string) {
+		switch {
+		case value.IsObject():
+			value.ForEach(func(key, item gjson.Result) bool {
+				walk(item, joinPath(path, gjson.Escape(key.String())))
+				return true
+			})
+		case value.IsArray():
+			for i, item := range value.Array() {
+				walk(item, joinPath(path, fmt.Sprint(i)))
+			}
+		case value.Type == gjson.String:
+			if transformed := transform(value.String()); transformed != value.String() {
+				changes = append(changes, change{path: path, value: transformed})
+			}
+		}
+	}
+	walk(gjson.ParseBytes(body), "")
+	var err error
+	for _, c := range changes {
+		if body, err = sjson.SetBytes(body, c.path, c.value); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// TypesOf returns the distinct types of the matches.
+func TypesOf(matches []Match) []string {
+	var types []string
+	for _, match := range matches {
+		if !contains(types, match.Type) {
+			types = append(types, match.Type)
+		}
+	}
+	return types
+}
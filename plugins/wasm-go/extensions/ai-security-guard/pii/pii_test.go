@@ -0,0 +1,183 @@
+package pii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type mockContext map[string]interface{}
+
+func (m mockContext) GetContext(key string) interface{} {
+	return m[key]
+}
+
+func (m mockContext) SetContext(key string, value interface{}) {
+	m[key] = value
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		expect []Match
+	}{
+		{
+			name:   "email",
+			text:   "mail me at john.doe@example.com please",
+			expect: []Match{{Type: TypeEmail, Value: "john.doe@example.com", Start: 11, End: 31}},
+		},
+		{
+			name:   "phone",
+			text:   "call 13800138000 or +1 4155552671",
+			expect: []Match{{Type: TypePhone, Value: "13800138000", Start: 5, End: 16}, {Type: TypePhone, Value: "+1 4155552671", Start: 20, End: 33}},
+		},
+		{
+			name:   "credit card with luhn",
+			text:   "card 4111 1111 1111 1111, not 4111 1111 1111 1112",
+			expect: []Match{{Type: TypeCreditCard, Value: "4111 1111 1111 1111", Start: 5, End: 24}},
+		},
+		{
+			name:   "id card with check code",
+			text:   "id 11010519491231002X, not 110105194912310021",
+			expect: []Match{{Type: TypeIDCard, Value: "11010519491231002X", Start: 3, End: 21}},
+		},
+		{
+			name:   "iban with checksum",
+			text:   "iban GB82 WEST 1234 5698 7654 32, not GB82 WEST 1234 5698 7654 33",
+			expect: []Match{{Type: TypeIBAN, Value: "GB82 WEST 1234 5698 7654 32", Start: 5, End: 32}},
+		},
+		{
+			name:   "api key",
+			text:   "key=sk-abcdefghijklmnopqrstuvwx",
+			expect: []Match{{Type: TypeAPIKey, Value: "sk-abcdefghijklmnopqrstuvwx", Start: 4, End: 31}},
+		},
+		{
+			name: "no pii",
+			text: "order 12345 shipped on 2024-01-01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, Detect(tt.text, allTypes))
+		})
+	}
+
+	// only the configured types are detected
+	require.Empty(t, Detect("john.doe@example.com", []string{TypePhone}))
+}
+
+func TestValidators(t *testing.T) {
+	require.True(t, validCreditCard("5500-0000-0000-0004"))
+	require.False(t, validCreditCard("5500-0000-0000-0005"))
+	require.True(t, validIDCard("11010519491231002x"))
+	require.False(t, validIDCard("110105194913310026"))
+	require.True(t, validIBAN("DE89370400440532013000"))
+	require.False(t, validIBAN("DE89370400440532013001"))
+}
+
+func TestParseConfig(t *testing.T) {
+	var config Config
+	require.NoError(t, config.FromJson(gjson.Parse(`{}`)))
+	require.Equal(t, ActionMask, config.Action)
+	require.Equal(t, allTypes, config.Types)
+
+	require.NoError(t, config.FromJson(gjson.Parse(`{"action": "tokenize", "types": ["email", "apiKey"]}`)))
+	require.Equal(t, ActionTokenize, config.Action)
+	require.Equal(t, []string{TypeEmail, TypeAPIKey}, config.Types)
+
+	require.EqualError(t, config.FromJson(gjson.Parse(`{"action": "drop"}`)), "invalid piiDetection.action, value must be one of [block, mask, tokenize]")
+	require.EqualError(t, config.FromJson(gjson.Parse(`{"types": ["ssn"]}`)), "invalid piiDetection.types: ssn, value must be one of [apiKey, email, iban, idCard, creditCard, phone]")
+}
+
+func TestRedact(t *testing.T) {
+	text := "I am john.doe@example.com, my card is 4111 1111 1111 1111 and my phone is 13800138000"
+
+	config := Config{Action: ActionMask, Types: allTypes}
+	masked, matches := config.Redact(text, NewTokens())
+	require.Len(t, matches, 3)
+	require.Equal(t, "I am j***.***@example.com, my card is **** **** **** 1111 and my phone is 138****8000", masked)
+
+	config.Action = ActionBlock
+	blocked, matches := config.Redact(text, NewTokens())
+	require.Equal(t, text, blocked)
+	require.Equal(t, []string{TypeEmail, TypeCreditCard, TypePhone}, TypesOf(matches))
+
+	config.Action = ActionTokenize
+	tokens := NewTokens()
+	tokenized, _ := config.Redact(text+", again john.doe@example.com", tokens)
+	require.Equal(t, "I am [PII_EMAIL_1], my card is [PII_CREDITCARD_1] and my phone is [PII_PHONE_1], again [PII_EMAIL_1]", tokenized)
+	require.Equal(t, text+", again john.doe@example.com", tokens.Restore(tokenized))
+}
+
+func TestRedactJson(t *testing.T) {
+	config := Config{Action: ActionTokenize, Types: allTypes}
+	tokens := NewTokens()
+	body := []byte(`{"model":"qwen","messages":[{"role":"system","content":"key sk-abcdefghijklmnopqrstuvwx"},{"role":"user","content":[{"type":"text","text":"mail john@example.com"}]}],"user.name":"john@example.com"}`)
+	redacted, matches, err := config.RedactJson(body, tokens)
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+	require.JSONEq(t, `{"model":"qwen","messages":[{"role":"system","content":"key [PII_APIKEY_1]"},{"role":"user","content":[{"type":"text","text":"mail [PII_EMAIL_1]"}]}],"user.name":"[PII_EMAIL_1]"}`, string(redacted))
+
+	response := []byte(`{"choices":[{"message":{"role":"assistant","content":"I will mail [PII_EMAIL_1] with \"[PII_APIKEY_1]\""}}]}`)
+	restored, err := tokens.RestoreJson(response)
+	require.NoError(t, err)
+	require.Equal(t, `I will mail john@example.com with "sk-abcdefghijklmnopqrstuvwx"`, gjson.GetBytes(restored, "choices.0.message.content").String())
+}
+
+func TestRestoreStreamingResponse(t *testing.T) {
+	const jsonPath = "choices.0.delta.content"
+	config := Config{Action: ActionTokenize, Types: allTypes}
+	tokens := NewTokens()
+	config.Redact("john@example.com", tokens)
+	ctx := mockContext{}
+
+	// no tokens, the data is returned as is
+	data := []byte("data: {\"choices\":[{\"delta\":{\"content\":\"[PII_EMAIL_1]\"}}]}\n\n")
+	require.Equal(t, data, RestoreStreamingResponse(ctx, data, jsonPath, false))
+
+	SetTokens(ctx, tokens)
+	chunk := func(content string) string {
+		return `data: {"choices":[{"delta":{"content":"` + content + `"}}]}` + "\n\n"
+	}
+	var output strings.Builder
+	// the token is split into several events, and the second event is split into two chunks
+	inputs := []string{
+		chunk("Hi [PII_"),
+		chunk("EMA")[:20],
+		chunk("EMA")[20:] + chunk("IL_1], bye ["),
+		`data: {"choices":[{"delta":{},"finish_reason":"stop"}]}` + "\n\n",
+		"data: [DONE]\n\n",
+	}
+	for i, input := range inputs {
+		output.Write(RestoreStreamingResponse(ctx, []byte(input), jsonPath, i == len(inputs)-1))
+	}
+	var contents []string
+	for _, event := range strings.Split(strings.TrimSpace(output.String()), "\n\n") {
+		payload := strings.TrimPrefix(event, "data: ")
+		if payload == "[DONE]" {
+			continue
+		}
+		contents = append(contents, gjson.Get(payload, jsonPath).String())
+	}
+	require.Equal(t, []string{"Hi ", "", "john@example.com, bye ", "["}, contents)
+	require.True(t, strings.HasSuffix(output.String(), "data: [DONE]\n\n"))
+}
+
+func TestRestoreEvents(t *testing.T) {
+	config := Config{Action: ActionTokenize, Types: allTypes}
+	tokens := NewTokens()
+	config.Redact("john@example.com", tokens)
+	ctx := mockContext{}
+
+	data := []byte("event: message\ndata: {\"result\":{\"content\":[{\"text\":\"mail [PII_EMAIL_1]\"}]}}\n\n")
+	require.Equal(t, data, RestoreEvents(ctx, data))
+
+	SetTokens(ctx, tokens)
+	require.Equal(t, "event: message\ndata: {\"result\":{\"content\":[{\"text\":\"mail john@example.com\"}]}}\n\n", string(RestoreEvents(ctx, data)))
+	// the events without tokens are kept as is
+	data = []byte("event: endpoint\ndata: /messages?session_id=1\n\n")
+	require.Equal(t, data, RestoreEvents(ctx, data))
+}
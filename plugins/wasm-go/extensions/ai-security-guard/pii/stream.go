@@ -0,0 +1,156 @@
+package pii
+
+import (
+	"bytes"
+
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	ctxKeyTokens  = "pii_tokens"
+	ctxKeyPending = "pii_pending"
+	ctxKeyPartial = "pii_partial_sse_event"
+	ctxKeyLastSSE = "pii_last_sse_event"
+)
+
+type httpContext interface {
+	GetContext(key string) interface{}
+	SetContext(key string, value interface{})
+}
+
+// SetTokens saves the tokens of the request, which are restored in the response.
+func SetTokens(ctx httpContext, tokens *Tokens) {
+	ctx.SetContext(ctxKeyTokens, tokens)
+}
+
+// GetTokens returns the tokens of the request, or nil if no pii is tokenized.
+func GetTokens(ctx httpContext) *Tokens {
+	tokens, _ := ctx.GetContext(ctxKeyTokens).(*Tokens)
+	if tokens == nil || tokens.Len() == 0 {
+		return nil
+	}
+	return tokens
+}
+
+// RestoreStreamingResponse restores the tokens in the content of the SSE events. A token may be split into several
+// events, so the content that may be the beginning of a token is held back until the following events arrive.
+func RestoreStreamingResponse(ctx httpContext, data []byte, jsonPath string, endOfStream bool) []byte {
+	tokens := GetTokens(ctx)
+	if tokens == nil {
+		return data
+	}
+	pending, _ := ctx.GetContext(ctxKeyPending).(string)
+	partial, _ := ctx.GetContext(ctxKeyPartial).([]byte)
+	lastEvent, _ := ctx.GetContext(ctxKeyLastSSE).([]byte)
+	// restore sets the content with the pending content prepended, and holds back the possible beginning of a token
+	restore := func(payload []byte, hold bool) []byte {
+		content := tokens.Restore(pending + gjson.GetBytes(payload, jsonPath).String())
+		pending = ""
+		if hold {
+			if n := tokens.pendingLength(content); n > 0 {
+				pending = content[len(content)-n:]
+				content = content[:len(content)-n]
+			}
+		}
+		newPayload, err := sjson.SetBytes(payload, jsonPath, content)
+		if err != nil {
+			newPayload = payload
+		}
+		return append(append([]byte("data: "), newPayload...), '\n', '\n')
+	}
+	buffer := append(partial, wrapper.UnifySSEChunk(data)...)
+	events := bytes.Split(buffer, []byte("\n\n"))
+	partial = nil
+	if !endOfStream {
+		// the last event is incomplete unless the buffer ends with the separator
+		partial = append([]byte(nil), events[len(events)-1]...)
+		events = events[:len(events)-1]
+	}
+	var result [][]byte
+	for _, event := range events {
+		if len(bytes.TrimSpace(event)) == 0 {
+			continue
+		}
+		payload := ssePayload(event)
+		if payload == nil || !gjson.ValidBytes(payload) {
+			// flush the held back content before the events without content, e.g. data: [DONE]
+			if pending != "" && lastEvent != nil {
+				result = append(result, restore(lastEvent, false))
+			}
+			result = append(result, append(event, '\n', '\n'))
+			continue
+		}
+		hasContent := gjson.GetBytes(payload, jsonPath).Exists()
+		if !hasContent && pending == "" {
+			result = append(result, append(event, '\n', '\n'))
+			continue
+		}
+		if hasContent {
+			lastEvent = emptyContentEvent(payload, jsonPath)
+		}
+		result = append(result, restore(payload, hasContent && !endOfStream))
+	}
+	if endOfStream && pending != "" && lastEvent != nil {
+		result = append(result, restore(lastEvent, false))
+	}
+	ctx.SetContext(ctxKeyPending, pending)
+	ctx.SetContext(ctxKeyPartial, partial)
+	ctx.SetContext(ctxKeyLastSSE, lastEvent)
+	return bytes.Join(result, nil)
+}
+
+// RestoreEvents restores the tokens in all the string values of the SSE events, which carry complete messages that
+// are never split into several events, e.g. the JSON-RPC messages of MCP.
+func RestoreEvents(ctx httpContext, data []byte) []byte {
+	tokens := GetTokens(ctx)
+	if tokens == nil {
+		return data
+	}
+	events := bytes.Split(wrapper.UnifySSEChunk(data), []byte("\n\n"))
+	for i, event := range events {
+		payload := ssePayload(event)
+		if payload == nil || !gjson.ValidBytes(payload) {
+			continue
+		}
+		restored, err := tokens.RestoreJson(payload)
+		if err != nil || bytes.Equal(restored, payload) {
+			continue
+		}
+		// keep the other fields of the event, e.g. event and id
+		var lines [][]byte
+		for _, line := range bytes.Split(event, []byte("\n")) {
+			if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("data:")) {
+				lines = append(lines, line)
+			}
+		}
+		events[i] = bytes.Join(append(lines, append([]byte("data: "), restored...)), []byte("\n"))
+	}
+	return bytes.Join(events, []byte("\n\n"))
+}
+
+// ssePayload returns the data of the SSE event, multi-line data fields are joined with '\n'.
+func ssePayload(event []byte) []byte {
+	var lines [][]byte
+	for _, line := range bytes.Split(event, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+		lines = append(lines, bytes.TrimSpace(bytes.TrimPrefix(line, []byte("data:"))))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// emptyContentEvent is used as the template to carry the held back content when the stream ends.
+func emptyContentEvent(payload []byte, jsonPath string) []byte {
+	event, err := sjson.SetBytes(append([]byte(nil), payload...), jsonPath, "")
+	if err != nil {
+		return nil
+	}
+	return event
+}
@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// llamaGuardProvider classifies the content with a Llama Guard style model served by an OpenAI compatible
+// chat completions API. The model replies "safe", or "unsafe" followed by the violated categories on the next line.
+
+const defaultLlamaGuardPath = "/v1/chat/completions"
+
+type llamaGuardProviderInitializer struct{}
+
+func (m *llamaGuardProviderInitializer) ValidateConfig(config *ProviderConfig) error {
+	if config.model == "" {
+		return errors.New("model is required for llama-guard guard provider")
+	}
+	return nil
+}
+
+func (m *llamaGuardProviderInitializer) CreateProvider(config ProviderConfig) (Provider, error) {
+	return &llamaGuardProvider{config: config}, nil
+}
+
+type llamaGuardProvider struct {
+	config ProviderConfig
+}
+
+func (m *llamaGuardProvider) GetProviderType() string {
+	return ProviderLlamaGuard
+}
+
+func (m *llamaGuardProvider) BuildRequest(content string, phase Phase, sessionID string) (string, [][2]string, []byte, error) {
+	// Llama Guard classifies the last turn of the conversation, so the model response is sent as the assistant turn
+	messages := []map[string]string{{"role": "user", "content": content}}
+	if phase == PhaseResponse {
+		messages = []map[string]string{
+			{"role": "user", "content": ""},
+			{"role": "assistant", "content": content},
+		}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"model":       m.config.model,
+		"messages":    messages,
+		"temperature": 0,
+		"stream":      false,
+	})
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return m.config.pathOrDefault(defaultLlamaGuardPath), m.config.buildHeaders(), body, nil
+}
+
+func (m *llamaGuardProvider) ParseResponse(statusCode int, body []byte) (Verdict, error) {
+	if err := checkStatusCode(statusCode, body); err != nil {
+		return Verdict{}, err
+	}
+	content := gjson.GetBytes(body, "choices.0.message.content")
+	if !content.Exists() {
+		return Verdict{}, errors.New("no choices in the classifier response")
+	}
+	lines := strings.Split(strings.TrimSpace(content.String()), "\n")
+	switch strings.ToLower(strings.TrimSpace(lines[0])) {
+	case "safe":
+		return m.config.flagged(false, "", 0), nil
+	case "unsafe":
+		label := ""
+		if len(lines) > 1 {
+			label = strings.TrimSpace(lines[1])
+		}
+		return m.config.flagged(true, label, 0), nil
+	default:
+		return Verdict{}, errors.New("unexpected classifier verdict: " + content.String())
+	}
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// openaiModerationProvider checks the content with an OpenAI compatible moderation API.
+
+const defaultOpenAIModerationPath = "/v1/moderations"
+
+type openaiModerationProviderInitializer struct{}
+
+func (m *openaiModerationProviderInitializer) ValidateConfig(config *ProviderConfig) error {
+	return nil
+}
+
+func (m *openaiModerationProviderInitializer) CreateProvider(config ProviderConfig) (Provider, error) {
+	return &openaiModerationProvider{config: config}, nil
+}
+
+type openaiModerationProvider struct {
+	config ProviderConfig
+}
+
+func (m *openaiModerationProvider) GetProviderType() string {
+	return ProviderOpenAIModeration
+}
+
+func (m *openaiModerationProvider) BuildRequest(content string, phase Phase, sessionID string) (string, [][2]string, []byte, error) {
+	request := map[string]interface{}{
+		"input": content,
+	}
+	if m.config.model != "" {
+		request["model"] = m.config.model
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return m.config.pathOrDefault(defaultOpenAIModerationPath), m.config.buildHeaders(), body, nil
+}
+
+func (m *openaiModerationProvider) ParseResponse(statusCode int, body []byte) (Verdict, error) {
+	if err := checkStatusCode(statusCode, body); err != nil {
+		return Verdict{}, err
+	}
+	results := gjson.GetBytes(body, "results")
+	if !results.IsArray() || len(results.Array()) == 0 {
+		return Verdict{}, errors.New("no results in the moderation response")
+	}
+	// The content is flagged if any of the results is flagged
+	flagged := false
+	categorySet := map[string]bool{}
+	confidence := 0.0
+	for _, result := range results.Array() {
+		if !result.Get("flagged").Bool() {
+			continue
+		}
+		flagged = true
+		for category, value := range result.Get("categories").Map() {
+			if !value.Bool() {
+				continue
+			}
+			categorySet[category] = true
+			if score := result.Get("category_scores").Get(gjson.Escape(category)).Float(); score > confidence {
+				confidence = score
+			}
+		}
+	}
+	categories := make([]string, 0, len(categorySet))
+	for category := range categorySet {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return m.config.flagged(flagged, strings.Join(categories, ","), confidence), nil
+}
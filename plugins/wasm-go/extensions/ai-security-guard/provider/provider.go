@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// guard provider types
+const (
+	// ProviderAliyun is the Alibaba Cloud content moderation service, which is handled by the lvwang package
+	ProviderAliyun           = "aliyun"
+	ProviderOpenAIModeration = "openai-moderation"
+	ProviderLlamaGuard       = "llama-guard"
+	ProviderWebhook          = "webhook"
+
+	// The risk level assigned to the content flagged by the guard provider, it is compared with riskLevelBar
+	defaultRiskLevel = "high"
+	noRiskLevel      = "none"
+)
+
+// Phase is the phase in which the content is checked
+type Phase string
+
+const (
+	PhaseRequest  Phase = "request"
+	PhaseResponse Phase = "response"
+)
+
+// Verdict is the result of a content check, translated into the risk levels used by riskLevelBar
+type Verdict struct {
+	RiskLevel  string
+	Label      string
+	Confidence float64
+}
+
+// Provider is a guard backend other than Alibaba Cloud content moderation.
+type Provider interface {
+	GetProviderType() string
+	// BuildRequest returns the path, headers and body of the check request for the content
+	BuildRequest(content string, phase Phase, sessionID string) (string, [][2]string, []byte, error)
+	// ParseResponse parses the verdict from the response of the check request
+	ParseResponse(statusCode int, body []byte) (Verdict, error)
+}
+
+type providerInitializer interface {
+	ValidateConfig(*ProviderConfig) error
+	CreateProvider(ProviderConfig) (Provider, error)
+}
+
+var providerInitializers = map[string]providerInitializer{
+	ProviderOpenAIModeration: &openaiModerationProviderInitializer{},
+	ProviderLlamaGuard:       &llamaGuardProviderInitializer{},
+	ProviderWebhook:          &webhookProviderInitializer{},
+}
+
+var riskLevels = map[string]bool{
+	"max":    true,
+	"high":   true,
+	"medium": true,
+	"low":    true,
+}
+
+type ProviderConfig struct {
+	// aliyun, openai-moderation, llama-guard or webhook, default aliyun
+	typ string
+	// path of the check request, required by webhook
+	path string
+	// sent as the bearer token of the check request
+	apiKey string
+	// extra headers of the check request
+	headers [][2]string
+	// model used by openai-moderation and llama-guard
+	model string
+	// risk level of the flagged content, default high
+	riskLevel string
+	// JSONPath of the verdict in the webhook response
+	verdictJsonPath string
+	// JSONPath of the risk label in the webhook response
+	labelJsonPath string
+	// the content is flagged when the webhook verdict is one of these values
+	unsafeValues []string
+}
+
+func (c *ProviderConfig) FromJson(json gjson.Result) {
+	c.typ = json.Get("type").String()
+	if c.typ == "" {
+		c.typ = ProviderAliyun
+	}
+	c.path = json.Get("path").String()
+	c.apiKey = json.Get("apiKey").String()
+	c.headers = nil
+	for key, value := range json.Get("headers").Map() {
+		c.headers = append(c.headers, [2]string{key, value.String()})
+	}
+	c.model = json.Get("model").String()
+	c.riskLevel = strings.ToLower(json.Get("riskLevel").String())
+	if c.riskLevel == "" {
+		c.riskLevel = defaultRiskLevel
+	}
+	c.verdictJsonPath = json.Get("verdictJsonPath").String()
+	c.labelJsonPath = json.Get("labelJsonPath").String()
+	c.unsafeValues = nil
+	for _, value := range json.Get("unsafeValues").Array() {
+		c.unsafeValues = append(c.unsafeValues, strings.ToLower(value.String()))
+	}
+	if len(c.unsafeValues) == 0 {
+		c.unsafeValues = []string{"true", "unsafe", "block", "deny", "reject", "flagged"}
+	}
+}
+
+func (c *ProviderConfig) GetType() string {
+	return c.typ
+}
+
+func (c *ProviderConfig) Validate() error {
+	if c.typ == ProviderAliyun {
+		return nil
+	}
+	initializer, has := providerInitializers[c.typ]
+	if !has {
+		return errors.New("unknown guard provider type: " + c.typ)
+	}
+	if !riskLevels[c.riskLevel] {
+		return fmt.Errorf("invalid riskLevel of guard provider: %s, value must be one of [max, high, medium, low]", c.riskLevel)
+	}
+	return initializer.ValidateConfig(c)
+}
+
+// CreateProvider creates the guard provider, it returns nil for ProviderAliyun.
+func CreateProvider(pc ProviderConfig) (Provider, error) {
+	if pc.typ == ProviderAliyun {
+		return nil, nil
+	}
+	initializer, has := providerInitializers[pc.typ]
+	if !has {
+		return nil, errors.New("unknown guard provider type: " + pc.typ)
+	}
+	return initializer.CreateProvider(pc)
+}
+
+func (c *ProviderConfig) buildHeaders() [][2]string {
+	headers := [][2]string{{"Content-Type", "application/json"}}
+	if c.apiKey != "" {
+		headers = append(headers, [2]string{"Authorization", "Bearer " + c.apiKey})
+	}
+	return append(headers, c.headers...)
+}
+
+func (c *ProviderConfig) pathOrDefault(defaultPath string) string {
+	if c.path != "" {
+		return c.path
+	}
+	return defaultPath
+}
+
+func (c *ProviderConfig) flagged(flagged bool, label string, confidence float64) Verdict {
+	if !flagged {
+		return Verdict{RiskLevel: noRiskLevel}
+	}
+	return Verdict{RiskLevel: c.riskLevel, Label: label, Confidence: confidence}
+}
+
+func checkStatusCode(statusCode int, body []byte) error {
+	if statusCode != 200 {
+		return fmt.Errorf("guard provider returned status %d: %s", statusCode, body)
+	}
+	return nil
+}
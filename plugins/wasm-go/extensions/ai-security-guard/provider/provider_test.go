@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func createProvider(t *testing.T, configJSON string) Provider {
+	var config ProviderConfig
+	config.FromJson(gjson.Parse(configJSON))
+	require.NoError(t, config.Validate())
+	provider, err := CreateProvider(config)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		configJSON string
+		wantErr    string
+	}{
+		{name: "default aliyun", configJSON: `{}`},
+		{name: "openai moderation", configJSON: `{"type": "openai-moderation"}`},
+		{name: "unknown type", configJSON: `{"type": "foo"}`, wantErr: "unknown guard provider type: foo"},
+		{
+			name:       "invalid risk level",
+			configJSON: `{"type": "openai-moderation", "riskLevel": "none"}`,
+			wantErr:    "invalid riskLevel of guard provider: none, value must be one of [max, high, medium, low]",
+		},
+		{name: "llama guard without model", configJSON: `{"type": "llama-guard"}`, wantErr: "model is required for llama-guard guard provider"},
+		{name: "webhook without path", configJSON: `{"type": "webhook", "verdictJsonPath": "verdict"}`, wantErr: "path is required for webhook guard provider"},
+		{name: "webhook without verdict", configJSON: `{"type": "webhook", "path": "/check"}`, wantErr: "verdictJsonPath is required for webhook guard provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config ProviderConfig
+			config.FromJson(gjson.Parse(tt.configJSON))
+			err := config.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	provider, err := CreateProvider(ProviderConfig{typ: ProviderAliyun})
+	require.NoError(t, err)
+	require.Nil(t, provider)
+}
+
+func TestOpenAIModerationProvider(t *testing.T) {
+	provider := createProvider(t, `{"type": "openai-moderation", "apiKey": "sk-test", "model": "omni-moderation-latest"}`)
+
+	path, headers, body, err := provider.BuildRequest("hello", PhaseRequest, "session")
+	require.NoError(t, err)
+	require.Equal(t, "/v1/moderations", path)
+	require.Contains(t, headers, [2]string{"Authorization", "Bearer sk-test"})
+	require.JSONEq(t, `{"model": "omni-moderation-latest", "input": "hello"}`, string(body))
+
+	verdict, err := provider.ParseResponse(200, []byte(`{"results": [{"flagged": true,
+		"categories": {"violence": true, "harassment/threatening": true, "sexual": false},
+		"category_scores": {"violence": 0.91, "harassment/threatening": 0.52, "sexual": 0.01}}]}`))
+	require.NoError(t, err)
+	require.Equal(t, Verdict{RiskLevel: "high", Label: "harassment/threatening,violence", Confidence: 0.91}, verdict)
+
+	verdict, err = provider.ParseResponse(200, []byte(`{"results": [{"flagged": false, "categories": {"violence": false}}]}`))
+	require.NoError(t, err)
+	require.Equal(t, Verdict{RiskLevel: "none"}, verdict)
+
+	_, err = provider.ParseResponse(429, []byte(`rate limited`))
+	require.EqualError(t, err, "guard provider returned status 429: rate limited")
+}
+
+func TestLlamaGuardProvider(t *testing.T) {
+	provider := createProvider(t, `{"type": "llama-guard", "model": "meta-llama/Llama-Guard-3-8B", "riskLevel": "max"}`)
+
+	path, _, body, err := provider.BuildRequest("the answer", PhaseResponse, "session")
+	require.NoError(t, err)
+	require.Equal(t, "/v1/chat/completions", path)
+	var request struct {
+		Model    string              `json:"model"`
+		Messages []map[string]string `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal(body, &request))
+	require.Equal(t, "meta-llama/Llama-Guard-3-8B", request.Model)
+	require.Equal(t, []map[string]string{
+		{"role": "user", "content": ""},
+		{"role": "assistant", "content": "the answer"},
+	}, request.Messages)
+
+	verdict, err := provider.ParseResponse(200, []byte(`{"choices": [{"message": {"content": "\n\nunsafe\nS1,S10"}}]}`))
+	require.NoError(t, err)
+	require.Equal(t, Verdict{RiskLevel: "max", Label: "S1,S10"}, verdict)
+
+	verdict, err = provider.ParseResponse(200, []byte(`{"choices": [{"message": {"content": "safe"}}]}`))
+	require.NoError(t, err)
+	require.Equal(t, Verdict{RiskLevel: "none"}, verdict)
+
+	_, err = provider.ParseResponse(200, []byte(`{"choices": [{"message": {"content": "I can't help with that"}}]}`))
+	require.EqualError(t, err, "unexpected classifier verdict: I can't help with that")
+}
+
+func TestWebhookProvider(t *testing.T) {
+	provider := createProvider(t, `{"type": "webhook", "path": "/guard/check", "headers": {"x-token": "abc"},
+		"verdictJsonPath": "data.verdict", "labelJsonPath": "data.label"}`)
+
+	path, headers, body, err := provider.BuildRequest("hello", PhaseRequest, "session")
+	require.NoError(t, err)
+	require.Equal(t, "/guard/check", path)
+	require.Contains(t, headers, [2]string{"x-token", "abc"})
+	require.JSONEq(t, `{"content": "hello", "phase": "request", "sessionId": "session"}`, string(body))
+
+	tests := []struct {
+		name     string
+		response string
+		expect   Verdict
+	}{
+		{name: "boolean", response: `{"data": {"verdict": true, "label": "pii"}}`, expect: Verdict{RiskLevel: "high", Label: "pii"}},
+		{name: "unsafe value", response: `{"data": {"verdict": "BLOCK", "label": "pii"}}`, expect: Verdict{RiskLevel: "high", Label: "pii"}},
+		{name: "risk level", response: `{"data": {"verdict": "medium", "label": "pii"}}`, expect: Verdict{RiskLevel: "medium", Label: "pii"}},
+		{name: "no risk", response: `{"data": {"verdict": "none", "label": "pii"}}`, expect: Verdict{RiskLevel: "none"}},
+		{name: "safe", response: `{"data": {"verdict": "pass"}}`, expect: Verdict{RiskLevel: "none"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := provider.ParseResponse(200, []byte(tt.response))
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, verdict)
+		})
+	}
+
+	_, err = provider.ParseResponse(200, []byte(`{"data": {}}`))
+	require.EqualError(t, err, "verdict not found at data.verdict in the webhook response")
+}
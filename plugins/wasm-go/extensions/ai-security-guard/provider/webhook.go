@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// webhookProvider posts the content to a custom HTTP service and reads the verdict with a JSONPath.
+// The request body is {"content": "...", "phase": "request|response", "sessionId": "..."}.
+// The verdict is either a risk level (max, high, medium, low, none), which is compared with riskLevelBar directly,
+// or a value that flags the content when it is one of unsafeValues, e.g. true or "block".
+
+type webhookProviderInitializer struct{}
+
+func (m *webhookProviderInitializer) ValidateConfig(config *ProviderConfig) error {
+	if config.path == "" {
+		return errors.New("path is required for webhook guard provider")
+	}
+	if config.verdictJsonPath == "" {
+		return errors.New("verdictJsonPath is required for webhook guard provider")
+	}
+	return nil
+}
+
+func (m *webhookProviderInitializer) CreateProvider(config ProviderConfig) (Provider, error) {
+	return &webhookProvider{config: config}, nil
+}
+
+type webhookProvider struct {
+	config ProviderConfig
+}
+
+func (m *webhookProvider) GetProviderType() string {
+	return ProviderWebhook
+}
+
+func (m *webhookProvider) BuildRequest(content string, phase Phase, sessionID string) (string, [][2]string, []byte, error) {
+	body, err := json.Marshal(map[string]string{
+		"content":   content,
+		"phase":     string(phase),
+		"sessionId": sessionID,
+	})
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return m.config.path, m.config.buildHeaders(), body, nil
+}
+
+func (m *webhookProvider) ParseResponse(statusCode int, body []byte) (Verdict, error) {
+	if err := checkStatusCode(statusCode, body); err != nil {
+		return Verdict{}, err
+	}
+	verdict := gjson.GetBytes(body, m.config.verdictJsonPath)
+	if !verdict.Exists() {
+		return Verdict{}, fmt.Errorf("verdict not found at %s in the webhook response", m.config.verdictJsonPath)
+	}
+	label := ""
+	if m.config.labelJsonPath != "" {
+		label = gjson.GetBytes(body, m.config.labelJsonPath).String()
+	}
+	value := strings.ToLower(verdict.String())
+	if value == noRiskLevel || riskLevels[value] {
+		result := Verdict{RiskLevel: value}
+		if value != noRiskLevel {
+			result.Label = label
+		}
+		return result, nil
+	}
+	return m.config.flagged(slices.Contains(m.config.unsafeValues, value), label, 0), nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"strings"
 	"time"
@@ -49,6 +50,7 @@ const (
 	// Context consts
 	StatisticsRequestStartTime = "ai-statistics-request-start-time"
 	StatisticsFirstTokenTime   = "ai-statistics-first-token-time"
+	StatisticsCostMicros       = "ai-statistics-cost-micros"
 	CtxGeneralAtrribute        = "attributes"
 	CtxLogAtrribute            = "logAttributes"
 	CtxStreamingBodyBuffer     = "streamingBodyBuffer"
@@ -86,6 +88,7 @@ const (
 	ResponseType           = "response_type"
 	ChatID                 = "chat_id"
 	ChatRound              = "chat_round"
+	LLMCost                = "cost"
 
 	// Inner span attributes
 	ArmsSpanKind     = "gen_ai.span.kind"
@@ -460,6 +463,26 @@ type AIStatisticsConfig struct {
 	enableContentTypes []string
 	// Session ID header name (if configured, takes priority over default headers)
 	sessionIdHeader string
+	// Pricing table used to calculate the cost of each request
+	pricing *Pricing
+}
+
+// Pricing is the pricing table of models, prices are per `unit` tokens.
+type Pricing struct {
+	Unit   float64        `json:"unit,omitempty"`
+	Models []ModelPricing `json:"models"`
+}
+
+// ModelPricing is the price of a model, optionally limited to a provider.
+type ModelPricing struct {
+	// Model name, a trailing "*" matches by prefix
+	Model string `json:"model"`
+	// Provider is matched against the upstream cluster name or the service name in it
+	Provider    string   `json:"provider,omitempty"`
+	Input       float64  `json:"input"`
+	Output      float64  `json:"output"`
+	CachedInput *float64 `json:"cached_input,omitempty"`
+	Reasoning   *float64 `json:"reasoning,omitempty"`
 }
 
 func generateMetricName(route, cluster, model, consumer, metricName string) string {
@@ -660,9 +683,131 @@ func parseConfig(configJson gjson.Result, config *AIStatisticsConfig) error {
 		config.sessionIdHeader = sessionIdHeader.String()
 	}
 
+	// Parse pricing configuration
+	if pricingConfig := configJson.Get("pricing"); pricingConfig.Exists() {
+		pricing := &Pricing{}
+		if err := json.Unmarshal([]byte(pricingConfig.Raw), pricing); err != nil {
+			log.Errorf("parse pricing config failed, %v", err)
+			return err
+		}
+		if pricing.Unit == 0 {
+			pricing.Unit = 1000000
+		}
+		if pricing.Unit < 0 {
+			return errors.New("unit of pricing must be positive")
+		}
+		for _, modelPricing := range pricing.Models {
+			if modelPricing.Model == "" {
+				return errors.New("model of pricing must not be empty")
+			}
+			if modelPricing.Input < 0 || modelPricing.Output < 0 ||
+				(modelPricing.CachedInput != nil && *modelPricing.CachedInput < 0) ||
+				(modelPricing.Reasoning != nil && *modelPricing.Reasoning < 0) {
+				return fmt.Errorf("price of model %s must not be negative", modelPricing.Model)
+			}
+		}
+		config.pricing = pricing
+	}
+
 	return nil
 }
 
+// getModelPricing returns the pricing of the model, the ones limited to the provider take precedence.
+func (p *Pricing) getModelPricing(model, cluster string) *ModelPricing {
+	var matched *ModelPricing
+	for i := range p.Models {
+		modelPricing := &p.Models[i]
+		if !matchModel(modelPricing.Model, model) {
+			continue
+		}
+		if modelPricing.Provider == "" {
+			if matched == nil {
+				matched = modelPricing
+			}
+			continue
+		}
+		if matchProvider(modelPricing.Provider, cluster) {
+			return modelPricing
+		}
+	}
+	return matched
+}
+
+func matchModel(pattern, model string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(model, prefix)
+	}
+	return pattern == model
+}
+
+// matchProvider matches the provider against the cluster name, like outbound|443||llm-openai.internal.dns
+func matchProvider(provider, cluster string) bool {
+	if provider == cluster {
+		return true
+	}
+	if index := strings.LastIndex(cluster, "|"); index != -1 {
+		return provider == cluster[index+1:]
+	}
+	return false
+}
+
+// calculateCost calculates the cost of a request. Cached input tokens are counted in the input tokens and reasoning
+// tokens in the output tokens for OpenAI compatible APIs, while Anthropic cache reads and Gemini thoughts are not.
+func (p *Pricing) calculateCost(modelPricing *ModelPricing, inputToken, outputToken int64, inputTokenDetails, outputTokenDetails map[string]int64) float64 {
+	includedCachedToken := inputTokenDetails["cached_tokens"] + inputTokenDetails["cached_content_token_count"]
+	cachedToken := includedCachedToken + inputTokenDetails["cache_read_input_tokens"]
+	includedReasoningToken := outputTokenDetails["reasoning_tokens"]
+	reasoningToken := includedReasoningToken + outputTokenDetails["thoughts_token_count"]
+	// Anthropic cache writes are not counted in the input tokens either, bill them as input
+	inputToken = max(inputToken-includedCachedToken, 0) + inputTokenDetails["cache_creation_input_tokens"]
+	outputToken = max(outputToken-includedReasoningToken, 0)
+
+	cachedInputPrice := modelPricing.Input
+	if modelPricing.CachedInput != nil {
+		cachedInputPrice = *modelPricing.CachedInput
+	}
+	reasoningPrice := modelPricing.Output
+	if modelPricing.Reasoning != nil {
+		reasoningPrice = *modelPricing.Reasoning
+	}
+	cost := float64(inputToken)*modelPricing.Input + float64(cachedToken)*cachedInputPrice +
+		float64(outputToken)*modelPricing.Output + float64(reasoningToken)*reasoningPrice
+	return cost / p.Unit
+}
+
+// setCostAttribute calculates the cost of the request by the pricing table and records it in log & context.
+func setCostAttribute(ctx wrapper.HttpContext, config AIStatisticsConfig) {
+	if config.pricing == nil || config.disableOpenaiUsage {
+		return
+	}
+	// The token attributes may be overridden by user defined attributes, which are float64
+	inputToken, inputOk := convertToUInt(ctx.GetUserAttribute(tokenusage.CtxKeyInputToken))
+	outputToken, outputOk := convertToUInt(ctx.GetUserAttribute(tokenusage.CtxKeyOutputToken))
+	if !inputOk || !outputOk {
+		log.Debugf("token usage not found, skip cost calculation")
+		return
+	}
+	cluster := ctx.GetStringContext(ClusterName, "")
+	model, _ := ctx.GetUserAttribute(tokenusage.CtxKeyModel).(string)
+	modelPricing := config.pricing.getModelPricing(model, cluster)
+	if modelPricing == nil {
+		// The model in response may be a snapshot version, try the model in request
+		requestModel := ctx.GetStringContext(tokenusage.CtxKeyRequestModel, "")
+		modelPricing = config.pricing.getModelPricing(requestModel, cluster)
+	}
+	if modelPricing == nil {
+		log.Debugf("pricing of model %s not found, skip cost calculation", model)
+		return
+	}
+	inputTokenDetails, _ := ctx.GetContext(tokenusage.CtxKeyInputTokenDetails).(map[string]int64)
+	outputTokenDetails, _ := ctx.GetContext(tokenusage.CtxKeyOutputTokenDetails).(map[string]int64)
+	cost := config.pricing.calculateCost(modelPricing, int64(inputToken), int64(outputToken), inputTokenDetails, outputTokenDetails)
+	// Metrics only support integers, so the cost is recorded in micro units
+	costMicros := uint64(math.Round(cost * 1e6))
+	ctx.SetUserAttribute(LLMCost, float64(costMicros)/1e6)
+	ctx.SetContext(StatisticsCostMicros, costMicros)
+}
+
 func onHttpRequestHeaders(ctx wrapper.HttpContext, config AIStatisticsConfig) types.Action {
 	// Check if request path matches enabled suffixes
 	requestPath, _ := proxywasm.GetHttpRequestHeader(":path")
@@ -869,6 +1014,7 @@ func onHttpStreamingBody(ctx wrapper.HttpContext, config AIStatisticsConfig, dat
 			streamingBodyBuffer, _ = ctx.GetContext(CtxStreamingBodyBuffer).([]byte)
 		}
 		setAttributeBySource(ctx, config, ResponseStreamingBody, streamingBodyBuffer)
+		setCostAttribute(ctx, config)
 
 		// Write log
 		debugLogAiLog(ctx)
@@ -923,6 +1069,7 @@ func onHttpResponseBody(ctx wrapper.HttpContext, config AIStatisticsConfig, body
 
 	// Set user defined log & span attributes.
 	setAttributeBySource(ctx, config, ResponseBody, body)
+	setCostAttribute(ctx, config)
 
 	// Write log
 	debugLogAiLog(ctx)
@@ -1303,6 +1450,9 @@ func debugLogAiLog(ctx wrapper.HttpContext) {
 	if outputTokenDetails := ctx.GetUserAttribute("output_token_details"); outputTokenDetails != nil {
 		userAttrs["output_token_details"] = outputTokenDetails
 	}
+	if cost := ctx.GetUserAttribute("cost"); cost != nil {
+		userAttrs["cost"] = cost
+	}
 
 	// Log the attributes as JSON
 	logJson, _ := json.Marshal(userAttrs)
@@ -1365,6 +1515,9 @@ func writeMetric(ctx wrapper.HttpContext, config AIStatisticsConfig) {
 	} else {
 		log.Info("TotalToken type assert failed, skip metric record")
 	}
+	if costMicros, ok := ctx.GetContext(StatisticsCostMicros).(uint64); ok {
+		config.incrementCounter(generateMetricName(route, cluster, model, consumer, LLMCost), costMicros)
+	}
 
 	// Generate duration metrics
 	var llmFirstTokenDuration, llmServiceDuration uint64
@@ -2014,3 +2014,118 @@ func TestConfigWithDefaultAttributes(t *testing.T) {
 		})
 	})
 }
+
+// 测试配置：成本计算配置
+var pricingConfig = func() json.RawMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"use_default_response_attributes": true,
+		"enable_path_suffixes":            []string{"*"},
+		"pricing": map[string]interface{}{
+			"models": []map[string]interface{}{
+				{
+					"model":        "gpt-4o*",
+					"input":        2,
+					"output":       8,
+					"cached_input": 0.5,
+					"reasoning":    10,
+				},
+				{
+					"model":    "gpt-4o",
+					"provider": "llm-azure.internal.dns",
+					"input":    3,
+					"output":   12,
+				},
+			},
+		},
+	})
+	return data
+}()
+
+func TestCost(t *testing.T) {
+	test.RunTest(t, func(t *testing.T) {
+		t.Run("invalid pricing config", func(t *testing.T) {
+			host, status := test.NewTestHost([]byte(`{
+				"pricing": {"models": [{"model": "gpt-4o", "input": -1, "output": 8}]}
+			}`))
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusFailed, status)
+		})
+
+		t.Run("cost metrics", func(t *testing.T) {
+			host, status := test.NewTestHost(pricingConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			host.SetRouteName("api-v1")
+			host.SetClusterName("outbound|443||llm-openai.internal.dns")
+
+			host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/v1/chat/completions"},
+				{":method", "POST"},
+				{"x-mse-consumer", "user1"},
+			})
+			host.CallOnHttpRequestBody([]byte(`{"model": "gpt-4o", "messages": [{"role": "user", "content": "Hello"}]}`))
+			host.CallOnHttpResponseHeaders([][2]string{
+				{":status", "200"},
+				{"content-type", "application/json"},
+			})
+			host.CallOnHttpResponseBody([]byte(`{
+				"model": "gpt-4o-2024-08-06",
+				"usage": {
+					"prompt_tokens": 100,
+					"completion_tokens": 50,
+					"total_tokens": 150,
+					"prompt_tokens_details": {"cached_tokens": 80},
+					"completion_tokens_details": {"reasoning_tokens": 25}
+				},
+				"choices": [{"message": {"role": "assistant", "content": "Hi"}}]
+			}`))
+			host.CompleteHttp()
+
+			// (20 * 2 + 80 * 0.5 + 25 * 8 + 25 * 10) / 1000000 = 0.00053
+			costMetric := "route.api-v1.upstream.outbound|443||llm-openai.internal.dns.model.gpt-4o-2024-08-06.consumer.user1.metric.cost"
+			costValue, err := host.GetCounterMetric(costMetric)
+			require.NoError(t, err)
+			require.Equal(t, uint64(530), costValue)
+		})
+	})
+}
+
+func TestPricing(t *testing.T) {
+	cachedInput := 0.5
+	pricing := &Pricing{
+		Unit: 1000000,
+		Models: []ModelPricing{
+			{Model: "gpt-4o*", Input: 2, Output: 8, CachedInput: &cachedInput},
+			{Model: "gpt-4o", Provider: "llm-azure.internal.dns", Input: 3, Output: 12},
+			{Model: "claude-sonnet-4", Input: 3, Output: 15, CachedInput: &cachedInput},
+			{Model: "gemini-2.5-pro", Input: 1, Output: 10},
+		},
+	}
+
+	t.Run("getModelPricing", func(t *testing.T) {
+		require.Equal(t, float64(2), pricing.getModelPricing("gpt-4o", "outbound|443||llm-openai.internal.dns").Input)
+		require.Equal(t, float64(2), pricing.getModelPricing("gpt-4o-mini", "outbound|443||llm-azure.internal.dns").Input)
+		require.Equal(t, float64(3), pricing.getModelPricing("gpt-4o", "outbound|443||llm-azure.internal.dns").Input)
+		require.Equal(t, float64(3), pricing.getModelPricing("gpt-4o", "llm-azure.internal.dns").Input)
+		require.Nil(t, pricing.getModelPricing("gpt-3.5-turbo", "outbound|443||llm-openai.internal.dns"))
+	})
+
+	t.Run("calculateCost", func(t *testing.T) {
+		// OpenAI: cached tokens are counted in the input tokens, reasoning tokens in the output tokens
+		cost := pricing.calculateCost(&pricing.Models[0], 100, 50,
+			map[string]int64{"cached_tokens": 80}, map[string]int64{"reasoning_tokens": 25})
+		require.InDelta(t, 0.00048, cost, 1e-12)
+
+		// Anthropic: cache reads and writes are not counted in the input tokens
+		cost = pricing.calculateCost(&pricing.Models[2], 100, 50,
+			map[string]int64{"cache_read_input_tokens": 1000, "cache_creation_input_tokens": 200}, nil)
+		require.InDelta(t, 0.00215, cost, 1e-12)
+
+		// Gemini: thoughts are not counted in the output tokens
+		cost = pricing.calculateCost(&pricing.Models[3], 100, 50,
+			map[string]int64{"cached_content_token_count": 40}, map[string]int64{"thoughts_token_count": 30})
+		require.InDelta(t, 0.0009, cost, 1e-12)
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"ai-token-ratelimit/config"
+)
+
+// 滑动窗口和令牌桶算法都使用 Redis 的 TIME 命令获取当前时间，避免多个网关实例之间的时钟偏差。
+// 请求阶段只检查是否超过阈值，返回值与固定窗口保持一致：{阈值, 当前计数, 重置时间(秒)}，当前计数超过阈值时触发限流；
+// 响应阶段根据实际消耗的 token 数进行累加。
+const (
+	// RequestPhaseSlidingWindowScript 滑动窗口计数，使用一个 hash 保存当前窗口和上一个窗口的计数，
+	// 按上一个窗口在滑动窗口中的剩余占比加权估算 token 数，避免固定窗口在边界处出现两倍的突发流量
+	RequestPhaseSlidingWindowScript = `
+		local key = KEYS[1]
+		local threshold = tonumber(ARGV[1])
+		local window = tonumber(ARGV[2]) * 1000
+
+		local time = redis.call('time')
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+		local currentStart = now - now % window
+		local elapsed = now - currentStart
+
+		local counts = redis.call('hmget', key, currentStart, currentStart - window)
+		local current = tonumber(counts[1] or "0")
+		local previous = tonumber(counts[2] or "0")
+		local estimated = math.floor(previous * (window - elapsed) / window) + current
+
+		return {threshold, estimated, math.ceil((window - elapsed) / 1000)}
+	`
+	ResponsePhaseSlidingWindowScript = `
+		redis.replicate_commands()
+		local key = KEYS[1]
+		local window = tonumber(ARGV[2]) * 1000
+		local added = tonumber(ARGV[3])  -- 需要累加的token数量
+
+		local time = redis.call('time')
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+		local currentStart = now - now % window
+
+		redis.call('hincrby', key, currentStart, added)
+		-- 清理已经滑出的窗口
+		redis.call('hdel', key, currentStart - window * 2)
+		redis.call('pexpire', key, window * 2)
+	`
+	// RequestPhaseTokenBucketScript 令牌桶，容量为 burst，每个时间窗口匀速补充 threshold 个令牌。
+	// 请求结束后才能得知消耗的 token 数，因此令牌数可以为负，令牌数小于 0 时触发限流
+	RequestPhaseTokenBucketScript = `
+		local key = KEYS[1]
+		local threshold = tonumber(ARGV[1])
+		local window = tonumber(ARGV[2]) * 1000
+		local capacity = tonumber(ARGV[3])
+		local rate = threshold / window
+
+		local time = redis.call('time')
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+		local bucket = redis.call('hmget', key, 'tokens', 'timestamp')
+		local tokens = tonumber(bucket[1])
+		local timestamp = tonumber(bucket[2])
+		if not tokens or not timestamp then
+			return {capacity, 0, 0}
+		end
+		tokens = math.min(capacity, tokens + math.max(0, now - timestamp) * rate)
+
+		local used = math.ceil(capacity - tokens)
+		if used > capacity then
+			-- 触发限流时返回令牌数恢复为 0 所需的时间
+			return {capacity, used, math.ceil(-tokens / rate / 1000)}
+		end
+		return {capacity, used, math.ceil((capacity - tokens) / rate / 1000)}
+	`
+	ResponsePhaseTokenBucketScript = `
+		redis.replicate_commands()
+		local key = KEYS[1]
+		local threshold = tonumber(ARGV[1])
+		local window = tonumber(ARGV[2]) * 1000
+		local added = tonumber(ARGV[3])  -- 需要扣减的token数量
+		local capacity = tonumber(ARGV[4])
+		local rate = threshold / window
+
+		local time = redis.call('time')
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+		local bucket = redis.call('hmget', key, 'tokens', 'timestamp')
+		local tokens = tonumber(bucket[1])
+		local timestamp = tonumber(bucket[2])
+		if not tokens or not timestamp then
+			tokens = capacity
+			timestamp = now
+		end
+		tokens = math.min(capacity, tokens + math.max(0, now - timestamp) * rate) - added
+
+		redis.call('hset', key, 'tokens', tokens, 'timestamp', now)
+		-- 令牌桶补满后即可删除
+		redis.call('pexpire', key, math.ceil((capacity - tokens) / rate) + 1000)
+	`
+)
+
+// getLimitKey 不同算法在 Redis 中存储的数据结构不同，使用不同的 key 避免切换算法后出现类型错误
+func getLimitKey(algorithm config.Algorithm, limitKey string) string {
+	if algorithm == config.FixedWindowAlgorithm {
+		return limitKey
+	}
+	return limitKey + ":" + string(algorithm)
+}
+
+// getRequestPhaseScript 返回请求阶段检查是否超过阈值的 Redis 脚本和参数
+func getRequestPhaseScript(algorithm config.Algorithm, limitRedisContext LimitRedisContext) (string, []interface{}) {
+	switch algorithm {
+	case config.SlidingWindowAlgorithm:
+		return RequestPhaseSlidingWindowScript, []interface{}{limitRedisContext.count, limitRedisContext.window}
+	case config.TokenBucketAlgorithm:
+		return RequestPhaseTokenBucketScript, []interface{}{limitRedisContext.count, limitRedisContext.window, limitRedisContext.burst}
+	default:
+		return RequestPhaseFixedWindowScript, []interface{}{limitRedisContext.count, limitRedisContext.window}
+	}
+}
+
+// getResponsePhaseScript 返回响应阶段累加 token 数的 Redis 脚本和参数
+func getResponsePhaseScript(algorithm config.Algorithm, limitRedisContext LimitRedisContext, tokens int64) (string, []interface{}) {
+	switch algorithm {
+	case config.SlidingWindowAlgorithm:
+		return ResponsePhaseSlidingWindowScript, []interface{}{limitRedisContext.count, limitRedisContext.window, tokens}
+	case config.TokenBucketAlgorithm:
+		return ResponsePhaseTokenBucketScript, []interface{}{limitRedisContext.count, limitRedisContext.window, tokens, limitRedisContext.burst}
+	default:
+		return ResponsePhaseFixedWindowScript, []interface{}{limitRedisContext.count, limitRedisContext.window, tokens}
+	}
+}
@@ -20,6 +20,9 @@ type LimitRuleItemType string
 // LimitConfigItemType 限流配置项key类型
 type LimitConfigItemType string
 
+// Algorithm 限流算法
+type Algorithm string
+
 const (
 	LimitByHeaderType      LimitRuleItemType = "limit_by_header"
 	LimitByParamType       LimitRuleItemType = "limit_by_param"
@@ -39,6 +42,10 @@ const (
 	RemoteAddrSourceType = "remote-addr"
 	HeaderSourceType     = "header"
 
+	FixedWindowAlgorithm   Algorithm = "fixed_window"   // 固定窗口
+	SlidingWindowAlgorithm Algorithm = "sliding_window" // 滑动窗口,按上一窗口计数加权估算当前窗口的token数
+	TokenBucketAlgorithm   Algorithm = "token_bucket"   // 令牌桶,按时间窗口内的token数匀速补充令牌,允许突发请求
+
 	DefaultRejectedCode uint32 = 429
 	DefaultRejectedMsg  string = "Too many requests"
 
@@ -56,18 +63,21 @@ var timeWindows = map[string]int64{
 }
 
 type AiTokenRateLimitConfig struct {
-	RuleName        string           // 限流规则名称
-	GlobalThreshold *GlobalThreshold // 全局限流配置
-	RuleItems       []LimitRuleItem  // 限流规则项
-	RejectedCode    uint32           // 当请求超过阈值被拒绝时,返回的HTTP状态码
-	RejectedMsg     string           // 当请求超过阈值被拒绝时,返回的响应体
-	RedisClient     wrapper.RedisClient
-	CounterMetrics  map[string]proxywasm.MetricCounter // Metrics
+	RuleName             string           // 限流规则名称
+	Algorithm            Algorithm        // 限流算法
+	GlobalThreshold      *GlobalThreshold // 全局限流配置
+	RuleItems            []LimitRuleItem  // 限流规则项
+	ShowRateLimitHeaders bool             // 响应头中是否显示标准的RateLimit-Limit、RateLimit-Remaining和RateLimit-Reset
+	RejectedCode         uint32           // 当请求超过阈值被拒绝时,返回的HTTP状态码
+	RejectedMsg          string           // 当请求超过阈值被拒绝时,返回的响应体
+	RedisClient          wrapper.RedisClient
+	CounterMetrics       map[string]proxywasm.MetricCounter // Metrics
 }
 
 type GlobalThreshold struct {
 	Count      int64 // 时间窗口内的token数
 	TimeWindow int64 // 时间窗口大小(秒)
+	Burst      int64 // 令牌桶容量,仅用于token_bucket算法,未配置时与Count相同
 }
 
 type LimitRuleItem struct {
@@ -89,6 +99,7 @@ type LimitConfigItem struct {
 	Regexp     *re.Regexp          // 正则表达式,仅用于itemType为regexpType
 	Count      int64               // 指定时间窗口内的token数
 	TimeWindow int64               // 时间窗口大小
+	Burst      int64               // 令牌桶容量,仅用于token_bucket算法,未配置时与Count相同
 }
 
 func (cfg *AiTokenRateLimitConfig) IncrementCounter(metricName string, inc uint64) {
@@ -152,11 +163,18 @@ func ParseAiTokenRateLimitConfig(json gjson.Result, config *AiTokenRateLimitConf
 	}
 	config.RuleName = ruleName.String()
 
+	algorithm, err := parseAlgorithm(json.Get("algorithm").String())
+	if err != nil {
+		return err
+	}
+	config.Algorithm = algorithm
+
 	// 初始化限流规则
-	err := initLimitRule(json, config)
+	err = initLimitRule(json, config)
 	if err != nil {
 		return err
 	}
+	config.ShowRateLimitHeaders = json.Get("show_ratelimit_headers").Bool()
 
 	rejectedCode := json.Get("rejected_code")
 	if rejectedCode.Exists() {
@@ -173,6 +191,29 @@ func ParseAiTokenRateLimitConfig(json gjson.Result, config *AiTokenRateLimitConf
 	return nil
 }
 
+func parseAlgorithm(algorithm string) (Algorithm, error) {
+	switch Algorithm(algorithm) {
+	case "", FixedWindowAlgorithm:
+		return FixedWindowAlgorithm, nil
+	case SlidingWindowAlgorithm, TokenBucketAlgorithm:
+		return Algorithm(algorithm), nil
+	default:
+		return "", fmt.Errorf("invalid algorithm: %s, must be one of 'fixed_window', 'sliding_window' or 'token_bucket'", algorithm)
+	}
+}
+
+// parseBurst 解析令牌桶容量,未配置时返回0
+func parseBurst(item gjson.Result) (int64, error) {
+	burst := item.Get("burst")
+	if !burst.Exists() {
+		return 0, nil
+	}
+	if burst.Int() <= 0 {
+		return 0, fmt.Errorf("'burst' must be a positive integer, got %d", burst.Int())
+	}
+	return burst.Int(), nil
+}
+
 func initLimitRule(json gjson.Result, config *AiTokenRateLimitConfig) error {
 	globalThresholdResult := json.Get("global_threshold")
 	ruleItemsResult := json.Get("rule_items")
@@ -235,9 +276,14 @@ func parseGlobalThreshold(item gjson.Result) (*GlobalThreshold, error) {
 			if count <= 0 {
 				return nil, fmt.Errorf("'%s' must be a positive integer, got %d", timeWindowKey, count)
 			}
+			burst, err := parseBurst(item)
+			if err != nil {
+				return nil, err
+			}
 			return &GlobalThreshold{
 				Count:      count,
 				TimeWindow: duration,
+				Burst:      burst,
 			}, nil
 		}
 	}
@@ -378,6 +424,10 @@ func createConfigItemFromRate(item gjson.Result, itemType LimitConfigItemType, k
 			if count <= 0 {
 				return nil, fmt.Errorf("'%s' must be a positive integer for key '%s', got %d", timeWindowKey, key, count)
 			}
+			burst, err := parseBurst(item)
+			if err != nil {
+				return nil, fmt.Errorf("%w for key '%s'", err, key)
+			}
 			return &LimitConfigItem{
 				ConfigType: itemType,
 				Key:        key,
@@ -385,6 +435,7 @@ func createConfigItemFromRate(item gjson.Result, itemType LimitConfigItemType, k
 				Regexp:     regexp,
 				Count:      count,
 				TimeWindow: duration,
+				Burst:      burst,
 			}, nil
 		}
 	}
@@ -39,7 +39,8 @@ func TestParseAiTokenRateLimitConfig(t *testing.T) {
 				}
 			}`,
 			expected: AiTokenRateLimitConfig{
-				RuleName: "global-route-limit",
+				RuleName:  "global-route-limit",
+				Algorithm: FixedWindowAlgorithm,
 				GlobalThreshold: &GlobalThreshold{
 					Count:      100,
 					TimeWindow: Second,
@@ -57,7 +58,8 @@ func TestParseAiTokenRateLimitConfig(t *testing.T) {
 				}
 			}`,
 			expected: AiTokenRateLimitConfig{
-				RuleName: "global-route-limit",
+				RuleName:  "global-route-limit",
+				Algorithm: FixedWindowAlgorithm,
 				GlobalThreshold: &GlobalThreshold{
 					Count:      1000,
 					TimeWindow: SecondsPerMinute,
@@ -95,7 +97,8 @@ func TestParseAiTokenRateLimitConfig(t *testing.T) {
 				]
 			}`,
 			expected: AiTokenRateLimitConfig{
-				RuleName: "rule-based-limit",
+				RuleName:  "rule-based-limit",
+				Algorithm: FixedWindowAlgorithm,
 				RuleItems: []LimitRuleItem{
 					{
 						LimitType: LimitByHeaderType,
@@ -134,7 +137,8 @@ func TestParseAiTokenRateLimitConfig(t *testing.T) {
 				]
 			}`,
 			expected: AiTokenRateLimitConfig{
-				RuleName: "multi-rule-limit",
+				RuleName:  "multi-rule-limit",
+				Algorithm: FixedWindowAlgorithm,
 				RuleItems: []LimitRuleItem{
 					{
 						LimitType: LimitByParamType,
@@ -190,7 +194,8 @@ func TestParseAiTokenRateLimitConfig(t *testing.T) {
 				"global_threshold": {"token_per_second": 100}
 			}`,
 			expected: AiTokenRateLimitConfig{
-				RuleName: "custom-reject",
+				RuleName:  "custom-reject",
+				Algorithm: FixedWindowAlgorithm,
 				GlobalThreshold: &GlobalThreshold{
 					Count:      100,
 					TimeWindow: Second,
@@ -199,6 +204,80 @@ func TestParseAiTokenRateLimitConfig(t *testing.T) {
 				RejectedMsg:  "Forbidden",
 			},
 		},
+		{
+			name: "Algorithm_Invalid",
+			json: `{
+				"rule_name": "invalid-algorithm",
+				"algorithm": "leaky_bucket",
+				"global_threshold": {"token_per_second": 100}
+			}`,
+			expectedErr: errors.New("invalid algorithm: leaky_bucket, must be one of 'fixed_window', 'sliding_window' or 'token_bucket'"),
+		},
+		{
+			name: "Algorithm_SlidingWindow",
+			json: `{
+				"rule_name": "sliding-window",
+				"algorithm": "sliding_window",
+				"global_threshold": {"token_per_minute": 100}
+			}`,
+			expected: AiTokenRateLimitConfig{
+				RuleName:  "sliding-window",
+				Algorithm: SlidingWindowAlgorithm,
+				GlobalThreshold: &GlobalThreshold{
+					Count:      100,
+					TimeWindow: SecondsPerMinute,
+				},
+				RejectedCode: DefaultRejectedCode,
+				RejectedMsg:  DefaultRejectedMsg,
+			},
+		},
+		{
+			name: "Algorithm_TokenBucketWithBurst",
+			json: `{
+				"rule_name": "token-bucket",
+				"algorithm": "token_bucket",
+				"show_ratelimit_headers": true,
+				"rule_items": [
+					{
+						"limit_by_header": "x-api-key",
+						"limit_keys": [
+							{"key": "key1", "token_per_second": 10, "burst": 50}
+						]
+					}
+				]
+			}`,
+			expected: AiTokenRateLimitConfig{
+				RuleName:  "token-bucket",
+				Algorithm: TokenBucketAlgorithm,
+				RuleItems: []LimitRuleItem{
+					{
+						LimitType: LimitByHeaderType,
+						Key:       "x-api-key",
+						ConfigItems: []LimitConfigItem{
+							{
+								ConfigType: ExactType,
+								Key:        "key1",
+								Count:      10,
+								TimeWindow: Second,
+								Burst:      50,
+							},
+						},
+					},
+				},
+				ShowRateLimitHeaders: true,
+				RejectedCode:         DefaultRejectedCode,
+				RejectedMsg:          DefaultRejectedMsg,
+			},
+		},
+		{
+			name: "Algorithm_InvalidBurst",
+			json: `{
+				"rule_name": "invalid-burst",
+				"algorithm": "token_bucket",
+				"global_threshold": {"token_per_second": 100, "burst": -1}
+			}`,
+			expectedErr: errors.New("failed to parse global_threshold: 'burst' must be a positive integer, got -1"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -39,6 +39,7 @@ func init() {
 		"ai-token-ratelimit",
 		wrapper.ParseConfig(parseConfig),
 		wrapper.ProcessRequestHeaders(onHttpRequestHeaders),
+		wrapper.ProcessResponseHeaders(onHttpResponseHeaders),
 		wrapper.ProcessStreamingResponseBody(onHttpStreamingBody),
 	)
 }
@@ -96,11 +97,17 @@ const (
     `
 
 	LimitRedisContextKey = "LimitRedisContext"
+	LimitContextKey      = "LimitContext" // 限流上下文信息
 
 	CookieHeader = "cookie"
 
 	RateLimitResetHeader = "X-TokenRateLimit-Reset" // 限流重置时间（触发限流时返回）
 
+	// 标准限流响应头，参考 https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+	StandardRateLimitLimitHeader     = "RateLimit-Limit"
+	StandardRateLimitRemainingHeader = "RateLimit-Remaining"
+	StandardRateLimitResetHeader     = "RateLimit-Reset"
+
 	TokenRateLimitCount = "token_ratelimit_count" // metric name
 )
 
@@ -114,6 +121,7 @@ type LimitRedisContext struct {
 	key    string
 	count  int64
 	window int64
+	burst  int64
 }
 
 func parseConfig(json gjson.Result, cfg *config.AiTokenRateLimitConfig) error {
@@ -132,13 +140,14 @@ func parseConfig(json gjson.Result, cfg *config.AiTokenRateLimitConfig) error {
 
 func onHttpRequestHeaders(ctx wrapper.HttpContext, cfg config.AiTokenRateLimitConfig) types.Action {
 	ctx.DisableReroute()
-	limitKey, count, timeWindow := "", int64(0), int64(0)
+	limitKey, count, timeWindow, burst := "", int64(0), int64(0), int64(0)
 
 	if cfg.GlobalThreshold != nil {
 		// 全局限流模式
 		limitKey = fmt.Sprintf(AiTokenGlobalRateLimitFormat, cfg.RuleName, cfg.GlobalThreshold.TimeWindow)
 		count = cfg.GlobalThreshold.Count
 		timeWindow = cfg.GlobalThreshold.TimeWindow
+		burst = cfg.GlobalThreshold.Burst
 	} else {
 		// 规则限流模式
 		val, ruleItem, configItem := checkRequestAgainstLimitRule(ctx, cfg.RuleItems)
@@ -150,18 +159,24 @@ func onHttpRequestHeaders(ctx wrapper.HttpContext, cfg config.AiTokenRateLimitCo
 		limitKey = fmt.Sprintf(AiTokenRateLimitFormat, cfg.RuleName, ruleItem.LimitType, configItem.TimeWindow, ruleItem.Key, val)
 		count = configItem.Count
 		timeWindow = configItem.TimeWindow
+		burst = configItem.Burst
+	}
+	if burst == 0 {
+		burst = count
 	}
 
-	ctx.SetContext(LimitRedisContextKey, LimitRedisContext{
-		key:    limitKey,
+	limitRedisContext := LimitRedisContext{
+		key:    getLimitKey(cfg.Algorithm, limitKey),
 		count:  count,
 		window: timeWindow,
-	})
+		burst:  burst,
+	}
+	ctx.SetContext(LimitRedisContextKey, limitRedisContext)
 
 	// 执行限流逻辑
-	keys := []interface{}{limitKey}
-	args := []interface{}{count, timeWindow}
-	err := cfg.RedisClient.Eval(RequestPhaseFixedWindowScript, 1, keys, args, func(response resp.Value) {
+	keys := []interface{}{limitRedisContext.key}
+	script, args := getRequestPhaseScript(cfg.Algorithm, limitRedisContext)
+	err := cfg.RedisClient.Eval(script, 1, keys, args, func(response resp.Value) {
 		resultArray := response.Array()
 		if len(resultArray) != 3 {
 			log.Errorf("redis response parse error, response: %v", response)
@@ -173,7 +188,7 @@ func onHttpRequestHeaders(ctx wrapper.HttpContext, cfg config.AiTokenRateLimitCo
 		threshold, current, ttl := resultArray[0].Integer(), resultArray[1].Integer(), resultArray[2].Integer()
 		context := LimitContext{
 			count:     threshold,
-			remaining: threshold - current,
+			remaining: max(threshold-current, 0),
 			reset:     ttl,
 		}
 		if current > threshold {
@@ -182,6 +197,7 @@ func onHttpRequestHeaders(ctx wrapper.HttpContext, cfg config.AiTokenRateLimitCo
 			ctx.WriteUserAttributeToLogWithKey(wrapper.AILogKey)
 			rejected(cfg, context)
 		} else {
+			ctx.SetContext(LimitContextKey, context)
 			proxywasm.ResumeHttpRequest()
 		}
 	})
@@ -192,6 +208,18 @@ func onHttpRequestHeaders(ctx wrapper.HttpContext, cfg config.AiTokenRateLimitCo
 	return types.HeaderStopAllIterationAndWatermark
 }
 
+func onHttpResponseHeaders(ctx wrapper.HttpContext, cfg config.AiTokenRateLimitConfig) types.Action {
+	limitContext, ok := ctx.GetContext(LimitContextKey).(LimitContext)
+	if !ok || !cfg.ShowRateLimitHeaders {
+		return types.ActionContinue
+	}
+	// 请求阶段的剩余token数，不包含本次请求消耗的token
+	_ = proxywasm.ReplaceHttpResponseHeader(StandardRateLimitLimitHeader, strconv.Itoa(limitContext.count))
+	_ = proxywasm.ReplaceHttpResponseHeader(StandardRateLimitRemainingHeader, strconv.Itoa(limitContext.remaining))
+	_ = proxywasm.ReplaceHttpResponseHeader(StandardRateLimitResetHeader, strconv.Itoa(limitContext.reset))
+	return types.ActionContinue
+}
+
 func onHttpStreamingBody(ctx wrapper.HttpContext, cfg config.AiTokenRateLimitConfig, data []byte, endOfStream bool) []byte {
 	if usage := tokenusage.GetTokenUsage(ctx, data); usage.TotalToken > 0 {
 		ctx.SetContext(tokenusage.CtxKeyInputToken, usage.InputToken)
@@ -208,8 +236,8 @@ func onHttpStreamingBody(ctx wrapper.HttpContext, cfg config.AiTokenRateLimitCon
 			return data
 		}
 		keys := []interface{}{limitRedisContext.key}
-		args := []interface{}{limitRedisContext.count, limitRedisContext.window, inputToken + outputToken}
-		err := cfg.RedisClient.Eval(ResponsePhaseFixedWindowScript, 1, keys, args, nil)
+		script, args := getResponsePhaseScript(cfg.Algorithm, limitRedisContext, inputToken+outputToken)
+		err := cfg.RedisClient.Eval(script, 1, keys, args, nil)
 		if err != nil {
 			log.Errorf("redis call failed: %v", err)
 		}
@@ -345,6 +373,11 @@ func generateMetricName(route, cluster, model, consumer, metricName string) stri
 func rejected(cfg config.AiTokenRateLimitConfig, context LimitContext) {
 	headers := make(map[string][]string)
 	headers[RateLimitResetHeader] = []string{strconv.Itoa(context.reset)}
+	if cfg.ShowRateLimitHeaders {
+		headers[StandardRateLimitLimitHeader] = []string{strconv.Itoa(context.count)}
+		headers[StandardRateLimitRemainingHeader] = []string{strconv.Itoa(0)}
+		headers[StandardRateLimitResetHeader] = []string{strconv.Itoa(context.reset)}
+	}
 	_ = proxywasm.SendHttpResponseWithDetail(
 		cfg.RejectedCode, "ai-token-ratelimit.rejected", util.ReconvertHeaders(headers), []byte(cfg.RejectedMsg), -1)
 
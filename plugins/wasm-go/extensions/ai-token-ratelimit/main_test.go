@@ -555,3 +555,82 @@ func TestCompleteFlow(t *testing.T) {
 		})
 	})
 }
+
+// 测试配置：滑动窗口限流配置
+var slidingWindowConfig = func() json.RawMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"rule_name": "ai-token-sliding-window-limit",
+		"algorithm": "sliding_window",
+		"global_threshold": map[string]interface{}{
+			"token_per_minute": 1000,
+		},
+		"redis": map[string]interface{}{
+			"service_name": "redis.static",
+			"service_port": 6379,
+		},
+		"show_ratelimit_headers": true,
+	})
+	return data
+}()
+
+func TestAlgorithm(t *testing.T) {
+	test.RunTest(t, func(t *testing.T) {
+		// 测试滑动窗口允许请求时返回标准限流响应头
+		t.Run("sliding window standard headers", func(t *testing.T) {
+			host, status := test.NewTestHost(slidingWindowConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			action := host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/api/test"},
+				{":method", "POST"},
+			})
+			require.Equal(t, types.HeaderStopAllIterationAndWatermark, action)
+
+			// 滑动窗口内估算已消耗400个token，当前窗口还剩25秒
+			resp := test.CreateRedisRespArray([]interface{}{1000, 400, 25})
+			host.CallOnRedisCall(0, resp)
+
+			action = host.CallOnHttpResponseHeaders([][2]string{
+				{":status", "200"},
+			})
+			require.Equal(t, types.ActionContinue, action)
+
+			responseHeaders := host.GetResponseHeaders()
+			require.True(t, test.HasHeaderWithValue(responseHeaders, "ratelimit-limit", "1000"))
+			require.True(t, test.HasHeaderWithValue(responseHeaders, "ratelimit-remaining", "600"))
+			require.True(t, test.HasHeaderWithValue(responseHeaders, "ratelimit-reset", "25"))
+
+			// 响应结束后按实际消耗的token数累加
+			responseBody := []byte(`{"choices":[{"message":{"content":"AI response"}}],"usage":{"prompt_tokens":5,"completion_tokens":8,"total_tokens":13}}`)
+			host.CallOnHttpStreamingResponseBody(responseBody, true)
+
+			host.CompleteHttp()
+		})
+
+		// 测试滑动窗口触发限流
+		t.Run("sliding window exceeded", func(t *testing.T) {
+			host, status := test.NewTestHost(slidingWindowConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/api/test"},
+				{":method", "POST"},
+			})
+
+			resp := test.CreateRedisRespArray([]interface{}{1000, 1200, 25})
+			host.CallOnRedisCall(0, resp)
+
+			localResponse := host.GetLocalResponse()
+			require.NotNil(t, localResponse)
+			require.Equal(t, uint32(429), localResponse.StatusCode)
+			require.True(t, test.HasHeaderWithValue(localResponse.Headers, "x-tokenratelimit-reset", "25"))
+			require.True(t, test.HasHeaderWithValue(localResponse.Headers, "ratelimit-remaining", "0"))
+
+			host.CompleteHttp()
+		})
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cluster-key-rate-limit/config"
+)
+
+// 滑动窗口和令牌桶算法都使用 Redis 的 TIME 命令获取当前时间，避免多个网关实例之间的时钟偏差，
+// 脚本的返回值与固定窗口保持一致：{阈值, 当前计数, 重置时间(秒)}，当前计数超过阈值时触发限流。
+const (
+	// SlidingWindowScript 滑动窗口计数，使用一个 hash 保存当前窗口和上一个窗口的计数，
+	// 按上一个窗口在滑动窗口中的剩余占比加权估算请求数，避免固定窗口在边界处出现两倍的突发流量
+	SlidingWindowScript = `
+		redis.replicate_commands()
+		local key = KEYS[1]
+		local threshold = tonumber(ARGV[1])
+		local window = tonumber(ARGV[2]) * 1000
+
+		local time = redis.call('time')
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+		local currentStart = now - now % window
+		local previousStart = currentStart - window
+
+		local counts = redis.call('hmget', key, currentStart, previousStart)
+		local current = tonumber(counts[1] or "0")
+		local previous = tonumber(counts[2] or "0")
+		local elapsed = now - currentStart
+		local reset = math.ceil((window - elapsed) / 1000)
+
+		-- 估算包含本次请求在内的请求数
+		local estimated = math.floor(previous * (window - elapsed) / window) + current + 1
+		if estimated > threshold then
+			return {threshold, estimated, reset}
+		end
+
+		redis.call('hincrby', key, currentStart, 1)
+		-- 清理已经滑出的窗口
+		redis.call('hdel', key, previousStart - window)
+		redis.call('pexpire', key, window * 2)
+		return {threshold, estimated, reset}
+	`
+	// TokenBucketScript 令牌桶，容量为 burst，每个时间窗口匀速补充 threshold 个令牌，每个请求消耗一个令牌
+	TokenBucketScript = `
+		redis.replicate_commands()
+		local key = KEYS[1]
+		local threshold = tonumber(ARGV[1])
+		local window = tonumber(ARGV[2]) * 1000
+		local capacity = tonumber(ARGV[3])
+		local rate = threshold / window
+
+		local time = redis.call('time')
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+		local bucket = redis.call('hmget', key, 'tokens', 'timestamp')
+		local tokens = tonumber(bucket[1])
+		local timestamp = tonumber(bucket[2])
+		if not tokens or not timestamp then
+			tokens = capacity
+			timestamp = now
+		end
+		tokens = math.min(capacity, tokens + math.max(0, now - timestamp) * rate)
+
+		-- 令牌不足一个时触发限流，此时的计数大于容量
+		local used = math.ceil(capacity - tokens + 1)
+		if used > capacity then
+			return {capacity, used, math.ceil((1 - tokens) / rate / 1000)}
+		end
+
+		tokens = tokens - 1
+		redis.call('hset', key, 'tokens', tokens, 'timestamp', now)
+		-- 令牌桶补满后即可删除
+		redis.call('pexpire', key, math.ceil((capacity - tokens) / rate) + 1000)
+		return {capacity, used, math.ceil((capacity - tokens) / rate / 1000)}
+	`
+)
+
+// getLimitScript 根据限流算法返回对应的 Redis 脚本、key 和参数
+func getLimitScript(algorithm config.Algorithm, limitKey string, count, timeWindow, burst int64) (string, []interface{}, []interface{}) {
+	switch algorithm {
+	case config.SlidingWindowAlgorithm:
+		// 不同算法在 Redis 中存储的数据结构不同，使用不同的 key 避免切换算法后出现类型错误
+		return SlidingWindowScript, []interface{}{limitKey + ":" + string(algorithm)}, []interface{}{count, timeWindow}
+	case config.TokenBucketAlgorithm:
+		if burst == 0 {
+			burst = count
+		}
+		return TokenBucketScript, []interface{}{limitKey + ":" + string(algorithm)}, []interface{}{count, timeWindow, burst}
+	default:
+		return FixedWindowScript, []interface{}{limitKey}, []interface{}{count, timeWindow}
+	}
+}
@@ -19,6 +19,9 @@ type LimitRuleItemType string
 // LimitConfigItemType 限流配置项key类型
 type LimitConfigItemType string
 
+// Algorithm 限流算法
+type Algorithm string
+
 const (
 	LimitByHeaderType      LimitRuleItemType = "limit_by_header"
 	LimitByParamType       LimitRuleItemType = "limit_by_param"
@@ -40,6 +43,10 @@ const (
 	RemoteAddrSourceType = "remote-addr"
 	HeaderSourceType     = "header"
 
+	FixedWindowAlgorithm   Algorithm = "fixed_window"   // 固定窗口
+	SlidingWindowAlgorithm Algorithm = "sliding_window" // 滑动窗口,按上一窗口计数加权估算当前窗口的请求数
+	TokenBucketAlgorithm   Algorithm = "token_bucket"   // 令牌桶,按时间窗口内的请求数匀速补充令牌,允许突发请求
+
 	DefaultRejectedCode uint32 = 429
 	DefaultRejectedMsg  string = "Too many requests"
 
@@ -58,9 +65,11 @@ var timeWindows = map[string]int64{
 
 type ClusterKeyRateLimitConfig struct {
 	RuleName             string           // 限流规则名称
+	Algorithm            Algorithm        // 限流算法
 	GlobalThreshold      *GlobalThreshold // 全局限流配置
 	RuleItems            []LimitRuleItem  // 限流规则项
 	ShowLimitQuotaHeader bool             // 响应头中是否显示X-RateLimit-Limit和X-RateLimit-Remaining
+	ShowRateLimitHeaders bool             // 响应头中是否显示标准的RateLimit-Limit、RateLimit-Remaining和RateLimit-Reset
 	RejectedCode         uint32           // 当请求超过阈值被拒绝时,返回的HTTP状态码
 	RejectedMsg          string           // 当请求超过阈值被拒绝时,返回的响应体
 	RedisClient          wrapper.RedisClient
@@ -69,6 +78,7 @@ type ClusterKeyRateLimitConfig struct {
 type GlobalThreshold struct {
 	Count      int64 // 时间窗口内请求数
 	TimeWindow int64 // 时间窗口大小(秒)
+	Burst      int64 // 令牌桶容量,仅用于token_bucket算法,未配置时与Count相同
 }
 
 type LimitRuleItem struct {
@@ -90,6 +100,7 @@ type LimitConfigItem struct {
 	Regexp     *re.Regexp          // 正则表达式,仅用于itemType为regexpType
 	Count      int64               // 指定时间窗口内的总请求数量阈值
 	TimeWindow int64               // 时间窗口大小
+	Burst      int64               // 令牌桶容量,仅用于token_bucket算法,未配置时与Count相同
 }
 
 func InitRedisClusterClient(json gjson.Result, config *ClusterKeyRateLimitConfig) error {
@@ -135,6 +146,12 @@ func ParseClusterKeyRateLimitConfig(json gjson.Result, config *ClusterKeyRateLim
 	}
 	config.RuleName = ruleName.String()
 
+	algorithm, err := parseAlgorithm(json.Get("algorithm").String())
+	if err != nil {
+		return err
+	}
+	config.Algorithm = algorithm
+
 	// 初始化限流规则
 	if err := initLimitRule(json, config); err != nil {
 		return err
@@ -144,6 +161,7 @@ func ParseClusterKeyRateLimitConfig(json gjson.Result, config *ClusterKeyRateLim
 	if showLimitQuotaHeader.Exists() {
 		config.ShowLimitQuotaHeader = showLimitQuotaHeader.Bool()
 	}
+	config.ShowRateLimitHeaders = json.Get("show_ratelimit_headers").Bool()
 
 	rejectedCode := json.Get("rejected_code")
 	if rejectedCode.Exists() {
@@ -161,6 +179,29 @@ func ParseClusterKeyRateLimitConfig(json gjson.Result, config *ClusterKeyRateLim
 	return nil
 }
 
+func parseAlgorithm(algorithm string) (Algorithm, error) {
+	switch Algorithm(algorithm) {
+	case "", FixedWindowAlgorithm:
+		return FixedWindowAlgorithm, nil
+	case SlidingWindowAlgorithm, TokenBucketAlgorithm:
+		return Algorithm(algorithm), nil
+	default:
+		return "", fmt.Errorf("invalid algorithm: %s, must be one of 'fixed_window', 'sliding_window' or 'token_bucket'", algorithm)
+	}
+}
+
+// parseBurst 解析令牌桶容量,未配置时返回0
+func parseBurst(item gjson.Result) (int64, error) {
+	burst := item.Get("burst")
+	if !burst.Exists() {
+		return 0, nil
+	}
+	if burst.Int() <= 0 {
+		return 0, fmt.Errorf("'burst' must be a positive integer, got %d", burst.Int())
+	}
+	return burst.Int(), nil
+}
+
 func initLimitRule(json gjson.Result, config *ClusterKeyRateLimitConfig) error {
 	globalThresholdResult := json.Get("global_threshold")
 	ruleItemsResult := json.Get("rule_items")
@@ -223,9 +264,14 @@ func parseGlobalThreshold(item gjson.Result) (*GlobalThreshold, error) {
 			if count <= 0 {
 				return nil, fmt.Errorf("'%s' must be a positive integer, got %d", timeWindowKey, count)
 			}
+			burst, err := parseBurst(item)
+			if err != nil {
+				return nil, err
+			}
 			return &GlobalThreshold{
 				Count:      count,
 				TimeWindow: duration,
+				Burst:      burst,
 			}, nil
 		}
 	}
@@ -366,6 +412,10 @@ func createConfigItemFromRate(item gjson.Result, itemType LimitConfigItemType, k
 			if count <= 0 {
 				return nil, fmt.Errorf("'%s' must be a positive integer for key '%s', got %d", timeWindowKey, key, count)
 			}
+			burst, err := parseBurst(item)
+			if err != nil {
+				return nil, fmt.Errorf("%w for key '%s'", err, key)
+			}
 			return &LimitConfigItem{
 				ConfigType: itemType,
 				Key:        key,
@@ -373,6 +423,7 @@ func createConfigItemFromRate(item gjson.Result, itemType LimitConfigItemType, k
 				Regexp:     regexp,
 				Count:      count,
 				TimeWindow: duration,
+				Burst:      burst,
 			}, nil
 		}
 	}
@@ -79,6 +79,11 @@ const (
 	RateLimitLimitHeader     = "X-RateLimit-Limit"     // 限制的总请求数
 	RateLimitRemainingHeader = "X-RateLimit-Remaining" // 剩余还可以发送的请求数
 	RateLimitResetHeader     = "X-RateLimit-Reset"     // 限流重置时间（触发限流时返回）
+
+	// 标准限流响应头，参考 https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+	StandardRateLimitLimitHeader     = "RateLimit-Limit"
+	StandardRateLimitRemainingHeader = "RateLimit-Remaining"
+	StandardRateLimitResetHeader     = "RateLimit-Reset"
 )
 
 type LimitContext struct {
@@ -101,13 +106,14 @@ func parseConfig(json gjson.Result, cfg *config.ClusterKeyRateLimitConfig) error
 
 func onHttpRequestHeaders(ctx wrapper.HttpContext, cfg config.ClusterKeyRateLimitConfig) types.Action {
 	ctx.DisableReroute()
-	limitKey, count, timeWindow := "", int64(0), int64(0)
+	limitKey, count, timeWindow, burst := "", int64(0), int64(0), int64(0)
 
 	if cfg.GlobalThreshold != nil {
 		// 全局限流模式
 		limitKey = fmt.Sprintf(ClusterGlobalRateLimitFormat, cfg.RuleName, cfg.GlobalThreshold.TimeWindow)
 		count = cfg.GlobalThreshold.Count
 		timeWindow = cfg.GlobalThreshold.TimeWindow
+		burst = cfg.GlobalThreshold.Burst
 	} else {
 		// 规则限流模式
 		val, ruleItem, configItem := checkRequestAgainstLimitRule(ctx, cfg.RuleItems)
@@ -119,12 +125,12 @@ func onHttpRequestHeaders(ctx wrapper.HttpContext, cfg config.ClusterKeyRateLimi
 		limitKey = fmt.Sprintf(ClusterRateLimitFormat, cfg.RuleName, ruleItem.LimitType, configItem.TimeWindow, ruleItem.Key, val)
 		count = configItem.Count
 		timeWindow = configItem.TimeWindow
+		burst = configItem.Burst
 	}
 
 	// 执行限流逻辑
-	keys := []interface{}{limitKey}
-	args := []interface{}{count, timeWindow}
-	err := cfg.RedisClient.Eval(FixedWindowScript, 1, keys, args, func(response resp.Value) {
+	script, keys, args := getLimitScript(cfg.Algorithm, limitKey, count, timeWindow, burst)
+	err := cfg.RedisClient.Eval(script, 1, keys, args, func(response resp.Value) {
 		resultArray := response.Array()
 		if len(resultArray) != 3 {
 			log.Errorf("redis response parse error, response: %v", response)
@@ -136,7 +142,7 @@ func onHttpRequestHeaders(ctx wrapper.HttpContext, cfg config.ClusterKeyRateLimi
 		threshold, current, ttl := resultArray[0].Integer(), resultArray[1].Integer(), resultArray[2].Integer()
 		context := LimitContext{
 			count:     threshold,
-			remaining: threshold - current,
+			remaining: max(threshold-current, 0),
 			reset:     ttl,
 		}
 		if current > threshold {
@@ -164,6 +170,11 @@ func onHttpResponseHeaders(ctx wrapper.HttpContext, config config.ClusterKeyRate
 		_ = proxywasm.ReplaceHttpResponseHeader(RateLimitLimitHeader, strconv.Itoa(limitContext.count))
 		_ = proxywasm.ReplaceHttpResponseHeader(RateLimitRemainingHeader, strconv.Itoa(limitContext.remaining))
 	}
+	if config.ShowRateLimitHeaders {
+		_ = proxywasm.ReplaceHttpResponseHeader(StandardRateLimitLimitHeader, strconv.Itoa(limitContext.count))
+		_ = proxywasm.ReplaceHttpResponseHeader(StandardRateLimitRemainingHeader, strconv.Itoa(limitContext.remaining))
+		_ = proxywasm.ReplaceHttpResponseHeader(StandardRateLimitResetHeader, strconv.Itoa(limitContext.reset))
+	}
 	return types.ActionContinue
 }
 
@@ -295,6 +306,11 @@ func rejected(config config.ClusterKeyRateLimitConfig, context LimitContext) {
 		headers[RateLimitLimitHeader] = []string{strconv.Itoa(context.count)}
 		headers[RateLimitRemainingHeader] = []string{strconv.Itoa(0)}
 	}
+	if config.ShowRateLimitHeaders {
+		headers[StandardRateLimitLimitHeader] = []string{strconv.Itoa(context.count)}
+		headers[StandardRateLimitRemainingHeader] = []string{strconv.Itoa(0)}
+		headers[StandardRateLimitResetHeader] = []string{strconv.Itoa(context.reset)}
+	}
 	_ = proxywasm.SendHttpResponseWithDetail(
 		config.RejectedCode, "cluster-key-rate-limit.rejected", util.ReconvertHeaders(headers), []byte(config.RejectedMsg), -1)
 }
@@ -364,6 +364,20 @@ func TestParseConfig(t *testing.T) {
 			require.Equal(t, config.RegexpType, parsedConfig.RuleItems[0].ConfigItems[0].ConfigType)
 			require.True(t, parsedConfig.ShowLimitQuotaHeader)
 		})
+
+		// 测试令牌桶限流配置解析
+		t.Run("token bucket config", func(t *testing.T) {
+			host, status := test.NewTestHost(tokenBucketConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			cfg, err := host.GetMatchConfig()
+			require.NoError(t, err)
+			parsedConfig := cfg.(*config.ClusterKeyRateLimitConfig)
+			require.Equal(t, config.TokenBucketAlgorithm, parsedConfig.Algorithm)
+			require.Equal(t, int64(50), parsedConfig.GlobalThreshold.Burst)
+			require.True(t, parsedConfig.ShowRateLimitHeaders)
+		})
 	})
 }
 
@@ -672,3 +686,81 @@ func TestCompleteFlow(t *testing.T) {
 		})
 	})
 }
+
+// 测试配置：令牌桶限流配置
+var tokenBucketConfig = func() json.RawMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"rule_name": "routeA-token-bucket-limit-rule",
+		"algorithm": "token_bucket",
+		"global_threshold": map[string]interface{}{
+			"query_per_second": 10,
+			"burst":            50,
+		},
+		"redis": map[string]interface{}{
+			"service_name": "redis.static",
+			"service_port": 6379,
+		},
+		"show_ratelimit_headers": true,
+	})
+	return data
+}()
+
+func TestAlgorithm(t *testing.T) {
+	test.RunTest(t, func(t *testing.T) {
+		// 测试令牌桶允许请求时返回标准限流响应头
+		t.Run("token bucket standard headers", func(t *testing.T) {
+			host, status := test.NewTestHost(tokenBucketConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			action := host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/api/test"},
+				{":method", "GET"},
+			})
+			require.Equal(t, types.HeaderStopAllIterationAndWatermark, action)
+
+			// 令牌桶容量为50，已消耗20个令牌，3秒后补满
+			resp := test.CreateRedisRespArray([]interface{}{50, 20, 3})
+			host.CallOnRedisCall(0, resp)
+
+			action = host.CallOnHttpResponseHeaders([][2]string{
+				{":status", "200"},
+			})
+			require.Equal(t, types.ActionContinue, action)
+
+			responseHeaders := host.GetResponseHeaders()
+			require.True(t, test.HasHeaderWithValue(responseHeaders, "ratelimit-limit", "50"))
+			require.True(t, test.HasHeaderWithValue(responseHeaders, "ratelimit-remaining", "30"))
+			require.True(t, test.HasHeaderWithValue(responseHeaders, "ratelimit-reset", "3"))
+			require.False(t, test.HasHeader(responseHeaders, "x-ratelimit-limit"))
+
+			host.CompleteHttp()
+		})
+
+		// 测试令牌不足时触发限流
+		t.Run("token bucket exhausted", func(t *testing.T) {
+			host, status := test.NewTestHost(tokenBucketConfig)
+			defer host.Reset()
+			require.Equal(t, types.OnPluginStartStatusOK, status)
+
+			host.CallOnHttpRequestHeaders([][2]string{
+				{":authority", "example.com"},
+				{":path", "/api/test"},
+				{":method", "GET"},
+			})
+
+			resp := test.CreateRedisRespArray([]interface{}{50, 51, 1})
+			host.CallOnRedisCall(0, resp)
+
+			localResponse := host.GetLocalResponse()
+			require.NotNil(t, localResponse)
+			require.Equal(t, uint32(429), localResponse.StatusCode)
+			require.True(t, test.HasHeaderWithValue(localResponse.Headers, "ratelimit-limit", "50"))
+			require.True(t, test.HasHeaderWithValue(localResponse.Headers, "ratelimit-remaining", "0"))
+			require.True(t, test.HasHeaderWithValue(localResponse.Headers, "ratelimit-reset", "1"))
+
+			host.CompleteHttp()
+		})
+	})
+}
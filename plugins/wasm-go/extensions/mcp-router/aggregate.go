@@ -0,0 +1,395 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/alibaba/higress/plugins/wasm-go/pkg/mcp/consts"
+	"github.com/alibaba/higress/plugins/wasm-go/pkg/mcp/server"
+	"github.com/alibaba/higress/plugins/wasm-go/pkg/mcp/utils"
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm/types"
+	"github.com/higress-group/wasm-go/pkg/log"
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	// maxToolListPages limits the pages fetched from a single server, in case the server keeps returning cursors
+	maxToolListPages = 20
+	// maxToolListCacheEntries limits the memory used by the cache, as the entries are kept per credential
+	maxToolListCacheEntries = 1024
+)
+
+// ProcessJsonRpcRequest answers initialize and tools/list on the unified endpoint when aggregation is enabled,
+// other methods are passed through.
+func ProcessJsonRpcRequest(context wrapper.HttpContext, config any, id utils.JsonRpcID, method string, params gjson.Result, rawBody []byte) types.Action {
+	routerConfig, ok := config.(McpRouterConfig)
+	if !ok || !routerConfig.enable || !routerConfig.aggregate {
+		return types.ActionContinue
+	}
+	switch method {
+	case "initialize":
+		onInitialize(context, params)
+		return types.ActionPause
+	case "notifications/initialized":
+		proxywasm.SendHttpResponseWithDetail(202, "mcp-router:notifications/initialized", nil, nil, -1)
+		return types.ActionPause
+	case "ping":
+		utils.OnMCPResponseSuccess(context, map[string]any{}, "mcp-router:ping")
+		return types.ActionPause
+	case "tools/list":
+		return onToolsList(context, routerConfig.global, params.Get("cursor").String())
+	}
+	return types.ActionContinue
+}
+
+func onInitialize(ctx wrapper.HttpContext, params gjson.Result) {
+	requestedVersion := params.Get("protocolVersion").String()
+	if requestedVersion == "" {
+		utils.OnMCPResponseError(ctx, errors.New("protocolVersion is required"), utils.ErrInvalidParams, "mcp-router:initialize:error")
+		return
+	}
+	negotiatedVersion := requestedVersion
+	if !slices.Contains(server.SupportedMCPVersions, requestedVersion) {
+		negotiatedVersion = server.SupportedMCPVersions[len(server.SupportedMCPVersions)-1]
+	}
+	utils.OnMCPResponseSuccess(ctx, map[string]any{
+		"protocolVersion": negotiatedVersion,
+		"capabilities": map[string]any{
+			"tools": map[string]any{},
+		},
+		"serverInfo": map[string]any{
+			"name":    "higress-mcp-router",
+			"version": "1.0.0",
+		},
+	}, "mcp-router:initialize")
+}
+
+func onToolsList(ctx wrapper.HttpContext, global *McpRouterGlobalConfig, cursor string) types.Action {
+	offset, err := decodeCursor(cursor)
+	if err != nil {
+		utils.OnMCPResponseError(ctx, err, utils.ErrInvalidParams, "mcp-router:tools/list:invalid_cursor")
+		return types.ActionPause
+	}
+	authorization, _ := proxywasm.GetHttpRequestHeader("authorization")
+	cacheKey := toolListCacheKey(global, authorization)
+	if tools, ok := getCachedToolList(cacheKey, time.Now()); ok {
+		log.Debugf("tools/list served from cache, %d tools", len(tools))
+		sendToolList(ctx, tools, offset, global.ToolListPageSize)
+		return types.ActionPause
+	}
+	if len(global.Servers) == 0 {
+		sendToolList(ctx, nil, offset, global.ToolListPageSize)
+		return types.ActionPause
+	}
+
+	headers := [][2]string{
+		{"Content-Type", "application/json"},
+		{"Accept", "application/json, text/event-stream"},
+	}
+	if authorization != "" {
+		headers = append(headers, [2]string{"Authorization", authorization})
+	}
+	results := make([][]json.RawMessage, len(global.Servers))
+	pending := len(global.Servers)
+	failed := 0
+	for i, serverConfig := range global.Servers {
+		listServerTools(serverConfig, headers, global.Timeout, func(tools []json.RawMessage, err error) {
+			if err != nil {
+				log.Warnf("failed to list tools of server %s: %v", serverConfig.Name, err)
+				failed++
+			} else {
+				results[i] = tools
+			}
+			pending--
+			if pending > 0 {
+				return
+			}
+			if failed == len(global.Servers) {
+				utils.OnMCPResponseError(ctx, errors.New("failed to list tools of all the servers"), utils.ErrInternalError, "mcp-router:tools/list:error")
+				return
+			}
+			merged := mergeToolLists(global.Servers, results)
+			// Only the complete result is cached, so that a temporarily unavailable server is retried
+			if failed == 0 && global.ToolListCacheTTL > 0 {
+				putCachedToolList(cacheKey, merged, time.Now().Add(time.Duration(global.ToolListCacheTTL)*time.Second))
+			}
+			sendToolList(ctx, merged, offset, global.ToolListPageSize)
+		})
+	}
+	return types.ActionPause
+}
+
+// listServerTools initializes a session with the server before listing its tools, since streamable HTTP servers
+// may reject the requests without the Mcp-Session-Id returned by initialize. The session is terminated once
+// all the tools are fetched.
+func listServerTools(serverConfig ServerConfig, headers [][2]string, timeout uint32, done func([]json.RawMessage, error)) {
+	client := newServerClient(serverConfig)
+	body := []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":%q,"capabilities":{},"clientInfo":{"name":"higress-mcp-router","version":"1.0.0"}}}`,
+		server.SupportedMCPVersions[len(server.SupportedMCPVersions)-1]))
+	err := client.Post(serverConfig.Path, headers, body, func(statusCode int, responseHeaders http.Header, responseBody []byte) {
+		if statusCode != http.StatusOK {
+			done(nil, fmt.Errorf("initialize failed with status code %d, body: %s", statusCode, responseBody))
+			return
+		}
+		protocolVersion, err := parseInitializeResponse(responseBody)
+		if err != nil {
+			done(nil, err)
+			return
+		}
+		sessionHeaders := append([][2]string{{"MCP-Protocol-Version", protocolVersion}}, headers...)
+		sessionID := responseHeaders.Get("Mcp-Session-Id")
+		if sessionID != "" {
+			sessionHeaders = append(sessionHeaders, [2]string{"Mcp-Session-Id", sessionID})
+		}
+		notification := []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		err = client.Post(serverConfig.Path, sessionHeaders, notification, func(statusCode int, responseHeaders http.Header, responseBody []byte) {
+			// The server may still answer tools/list even if it fails to handle the notification
+			if statusCode >= 300 {
+				log.Warnf("initialized notification of server %s failed with status %d: %s", serverConfig.Name, statusCode, responseBody)
+			}
+			fetchServerTools(client, serverConfig, sessionHeaders, timeout, "", nil, 1, func(tools []json.RawMessage, err error) {
+				if sessionID != "" {
+					terminateSession(client, serverConfig, sessionHeaders, timeout)
+				}
+				done(tools, err)
+			})
+		}, timeout)
+		if err != nil {
+			done(nil, err)
+		}
+	}, timeout)
+	if err != nil {
+		done(nil, err)
+	}
+}
+
+// terminateSession deletes the session of the server, the result is ignored since the server may not support it
+func terminateSession(client wrapper.HttpClient, serverConfig ServerConfig, sessionHeaders [][2]string, timeout uint32) {
+	err := client.Delete(serverConfig.Path, sessionHeaders, nil, func(statusCode int, responseHeaders http.Header, responseBody []byte) {
+		log.Debugf("terminated session of server %s with status %d", serverConfig.Name, statusCode)
+	}, timeout)
+	if err != nil {
+		log.Debugf("failed to terminate session of server %s: %v", serverConfig.Name, err)
+	}
+}
+
+// fetchServerTools lists the tools of the server, following the cursors until all the pages are fetched
+func fetchServerTools(client wrapper.HttpClient, serverConfig ServerConfig, headers [][2]string, timeout uint32, cursor string, collected []json.RawMessage, page int, done func([]json.RawMessage, error)) {
+	body := []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/list","params":{}}`, page))
+	if cursor != "" {
+		body, _ = sjson.SetBytes(body, "params.cursor", cursor)
+	}
+	err := client.Post(serverConfig.Path, headers, body, func(statusCode int, responseHeaders http.Header, responseBody []byte) {
+		if statusCode != http.StatusOK {
+			done(nil, fmt.Errorf("unexpected status code %d, body: %s", statusCode, responseBody))
+			return
+		}
+		tools, nextCursor, err := parseToolListResponse(responseBody)
+		if err != nil {
+			done(nil, err)
+			return
+		}
+		for _, tool := range tools {
+			prefixed, err := sjson.SetBytes([]byte(tool.Raw), "name", serverConfig.Name+consts.ToolSetNameSplitter+tool.Get("name").String())
+			if err != nil {
+				done(nil, err)
+				return
+			}
+			collected = append(collected, prefixed)
+		}
+		if nextCursor == "" {
+			done(collected, nil)
+			return
+		}
+		if page >= maxToolListPages {
+			log.Warnf("tools of server %s have more than %d pages, the rest are ignored", serverConfig.Name, maxToolListPages)
+			done(collected, nil)
+			return
+		}
+		fetchServerTools(client, serverConfig, headers, timeout, nextCursor, collected, page+1, done)
+	}, timeout)
+	if err != nil {
+		done(nil, err)
+	}
+}
+
+// newServerClient creates the client of the server, serviceName is required when aggregate is enabled,
+// since the servers are usually served by different clusters than the current route
+func newServerClient(serverConfig ServerConfig) wrapper.HttpClient {
+	port := serverConfig.ServicePort
+	if port == 0 {
+		port = 80
+	}
+	return wrapper.NewClusterClient(wrapper.FQDNCluster{
+		FQDN: serverConfig.ServiceName,
+		Port: port,
+		Host: serverConfig.Domain,
+	})
+}
+
+// parseJsonRpcMessage extracts the JSON-RPC message from the response, which is either the message itself
+// or an SSE stream carrying it
+func parseJsonRpcMessage(body []byte) ([]byte, error) {
+	if gjson.ValidBytes(body) {
+		return body, nil
+	}
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+		data := bytes.TrimSpace(bytes.TrimPrefix(line, []byte("data:")))
+		if gjson.GetBytes(data, "jsonrpc").Exists() {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid JSON-RPC response: %s", body)
+}
+
+// parseInitializeResponse returns the protocol version negotiated by the server
+func parseInitializeResponse(body []byte) (string, error) {
+	message, err := parseJsonRpcMessage(body)
+	if err != nil {
+		return "", err
+	}
+	if errorMessage := gjson.GetBytes(message, "error"); errorMessage.Exists() {
+		return "", fmt.Errorf("initialize error: %s", errorMessage.Raw)
+	}
+	protocolVersion := gjson.GetBytes(message, "result.protocolVersion").String()
+	if protocolVersion == "" {
+		return "", fmt.Errorf("no protocolVersion in initialize response: %s", message)
+	}
+	return protocolVersion, nil
+}
+
+// parseToolListResponse extracts the tools and the next cursor from the tools/list response,
+// which is either a JSON-RPC response or an SSE stream carrying it
+func parseToolListResponse(body []byte) ([]gjson.Result, string, error) {
+	message, err := parseJsonRpcMessage(body)
+	if err != nil {
+		return nil, "", err
+	}
+	if errorMessage := gjson.GetBytes(message, "error"); errorMessage.Exists() {
+		return nil, "", fmt.Errorf("tools/list error: %s", errorMessage.Raw)
+	}
+	tools := gjson.GetBytes(message, "result.tools")
+	if !tools.IsArray() {
+		return nil, "", fmt.Errorf("no tools in tools/list response: %s", message)
+	}
+	return tools.Array(), gjson.GetBytes(message, "result.nextCursor").String(), nil
+}
+
+// mergeToolLists merges the tools of the servers in the order of configuration
+func mergeToolLists(servers []ServerConfig, results [][]json.RawMessage) []json.RawMessage {
+	merged := []json.RawMessage{}
+	for i := range servers {
+		merged = append(merged, results[i]...)
+	}
+	return merged
+}
+
+// sendToolList sends a page of the merged tools, the cursor of the next page is the offset of its first tool
+func sendToolList(ctx wrapper.HttpContext, tools []json.RawMessage, offset int, pageSize int) {
+	if offset > len(tools) {
+		offset = len(tools)
+	}
+	end := len(tools)
+	if pageSize > 0 && offset+pageSize < end {
+		end = offset + pageSize
+	}
+	result := map[string]any{
+		"tools": append([]json.RawMessage{}, tools[offset:end]...),
+	}
+	if end < len(tools) {
+		result["nextCursor"] = encodeCursor(end)
+	}
+	utils.OnMCPResponseSuccess(ctx, result, "mcp-router:tools/list")
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	offset, err := strconv.Atoi(string(decoded))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	return offset, nil
+}
+
+type toolListCacheEntry struct {
+	tools    []json.RawMessage
+	expireAt time.Time
+}
+
+// toolListCache is kept in the plugin VM, the tools may differ between credentials so they are part of the key
+var toolListCache = map[string]toolListCacheEntry{}
+
+func toolListCacheKey(global *McpRouterGlobalConfig, authorization string) string {
+	h := fnv.New64a()
+	for _, serverConfig := range global.Servers {
+		fmt.Fprintf(h, "%s|%s|%s|%s|%d\n", serverConfig.Name, serverConfig.Domain, serverConfig.Path, serverConfig.ServiceName, serverConfig.ServicePort)
+	}
+	h.Write([]byte(authorization))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+func getCachedToolList(key string, now time.Time) ([]json.RawMessage, bool) {
+	entry, ok := toolListCache[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(entry.expireAt) {
+		delete(toolListCache, key)
+		return nil, false
+	}
+	return entry.tools, true
+}
+
+func putCachedToolList(key string, tools []json.RawMessage, expireAt time.Time) {
+	if len(toolListCache) >= maxToolListCacheEntries {
+		now := time.Now()
+		for k, entry := range toolListCache {
+			if !now.Before(entry.expireAt) {
+				delete(toolListCache, k)
+			}
+		}
+		if len(toolListCache) >= maxToolListCacheEntries {
+			toolListCache = map[string]toolListCacheEntry{}
+		}
+	}
+	toolListCache[key] = toolListCacheEntry{tools: tools, expireAt: expireAt}
+}
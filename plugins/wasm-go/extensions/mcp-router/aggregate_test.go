@@ -0,0 +1,114 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolListResponse(t *testing.T) {
+	tools, nextCursor, err := parseToolListResponse([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"a"},{"name":"b"}],"nextCursor":"page2"}}`))
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	require.Equal(t, "b", tools[1].Get("name").String())
+	require.Equal(t, "page2", nextCursor)
+
+	// Streamable HTTP servers may answer with SSE
+	tools, nextCursor, err = parseToolListResponse([]byte("event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"tools\":[{\"name\":\"a\"}]}}\n\n"))
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	require.Empty(t, nextCursor)
+
+	_, _, err = parseToolListResponse([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+	require.Error(t, err)
+
+	_, _, err = parseToolListResponse([]byte(`not found`))
+	require.Error(t, err)
+}
+
+func TestParseInitializeResponse(t *testing.T) {
+	protocolVersion, err := parseInitializeResponse([]byte(`{"jsonrpc":"2.0","id":0,"result":{"protocolVersion":"2025-03-26","capabilities":{"tools":{}}}}`))
+	require.NoError(t, err)
+	require.Equal(t, "2025-03-26", protocolVersion)
+
+	protocolVersion, err = parseInitializeResponse([]byte("event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":0,\"result\":{\"protocolVersion\":\"2025-06-18\"}}\n\n"))
+	require.NoError(t, err)
+	require.Equal(t, "2025-06-18", protocolVersion)
+
+	_, err = parseInitializeResponse([]byte(`{"jsonrpc":"2.0","id":0,"error":{"code":-32602,"message":"unsupported protocol version"}}`))
+	require.Error(t, err)
+}
+
+func TestParseOverrideConfig(t *testing.T) {
+	var global any = McpRouterGlobalConfig{Servers: []ServerConfig{
+		{Name: "s1", Path: "/mcp-servers/s1", ServiceName: "s1.dns"},
+		{Name: "s2", Path: "/mcp-servers/s2"},
+	}}
+	var config any
+	require.NoError(t, ParseOverrideConfig([]byte(`{"enable":true}`), global, &config))
+	require.EqualError(t, ParseOverrideConfig([]byte(`{"enable":true,"aggregate":true}`), global, &config),
+		"servers[].serviceName of server s2 is required when aggregate is enabled")
+}
+
+func TestToolListCursor(t *testing.T) {
+	offset, err := decodeCursor("")
+	require.NoError(t, err)
+	require.Equal(t, 0, offset)
+
+	offset, err = decodeCursor(encodeCursor(42))
+	require.NoError(t, err)
+	require.Equal(t, 42, offset)
+
+	_, err = decodeCursor("not-a-cursor")
+	require.Error(t, err)
+}
+
+func TestMergeToolLists(t *testing.T) {
+	servers := []ServerConfig{{Name: "s1"}, {Name: "s2"}, {Name: "s3"}}
+	results := [][]json.RawMessage{
+		{json.RawMessage(`{"name":"s1___a"}`)},
+		nil, // s2 failed
+		{json.RawMessage(`{"name":"s3___b"}`), json.RawMessage(`{"name":"s3___c"}`)},
+	}
+	merged := mergeToolLists(servers, results)
+	require.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"name":"s1___a"}`),
+		json.RawMessage(`{"name":"s3___b"}`),
+		json.RawMessage(`{"name":"s3___c"}`),
+	}, merged)
+}
+
+func TestToolListCache(t *testing.T) {
+	global := &McpRouterGlobalConfig{Servers: []ServerConfig{{Name: "s1", Path: "/mcp/s1"}}}
+	key := toolListCacheKey(global, "Bearer user1")
+	require.NotEqual(t, key, toolListCacheKey(global, "Bearer user2"), "cache should be kept per credential")
+
+	now := time.Now()
+	tools := []json.RawMessage{json.RawMessage(`{"name":"s1___a"}`)}
+	putCachedToolList(key, tools, now.Add(time.Minute))
+
+	cached, ok := getCachedToolList(key, now)
+	require.True(t, ok)
+	require.Equal(t, tools, cached)
+
+	_, ok = getCachedToolList(key, now.Add(time.Minute))
+	require.False(t, ok, "expired entry should not be served")
+	_, ok = toolListCache[key]
+	require.False(t, ok, "expired entry should be removed")
+}
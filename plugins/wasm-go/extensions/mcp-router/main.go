@@ -16,6 +16,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -35,26 +36,45 @@ func init() {
 	mcp.LoadMCPFilter(
 		mcp.FilterName("mcp-router"),
 		mcp.SetConfigOverrideParser(ParseGlobalConfig, ParseOverrideConfig),
+		mcp.SetJsonRpcRequestFilter(ProcessJsonRpcRequest),
 		mcp.SetToolCallRequestFilter(ProcessRequest),
 	)
 	mcp.InitMCPFilter()
 }
 
+const (
+	defaultToolListCacheTTL = 60
+	defaultTimeout          = 5000
+)
+
 // ServerConfig represents the routing configuration for a single MCP server
 type ServerConfig struct {
 	Name   string `json:"name"`
 	Domain string `json:"domain,omitempty"`
 	Path   string `json:"path"`
+	// ServiceName and ServicePort specify the service to send the aggregated tools/list requests to,
+	// ServiceName is required when aggregate is enabled.
+	ServiceName string `json:"serviceName,omitempty"`
+	ServicePort int64  `json:"servicePort,omitempty"`
 }
 
 // McpRouterGlobalConfig represents the global configuration for the mcp-router filter
 type McpRouterGlobalConfig struct {
 	Servers []ServerConfig `json:"servers"`
+	// ToolListCacheTTL is the seconds to cache the aggregated tools/list result, 0 disables the cache
+	ToolListCacheTTL int64 `json:"toolListCacheTTL"`
+	// ToolListPageSize is the page size of the aggregated tools/list result, 0 returns all tools in one page
+	ToolListPageSize int `json:"toolListPageSize,omitempty"`
+	// Timeout is the timeout in milliseconds of the tools/list requests to each server
+	Timeout uint32 `json:"timeout,omitempty"`
 }
 
 type McpRouterConfig struct {
 	global *McpRouterGlobalConfig
 	enable bool
+	// aggregate makes the route a unified MCP endpoint, answering initialize and tools/list with
+	// the tools of all the servers
+	aggregate bool
 }
 
 func ParseGlobalConfig(configBytes []byte, globalConfig *any) error {
@@ -62,6 +82,23 @@ func ParseGlobalConfig(configBytes []byte, globalConfig *any) error {
 	if err := json.Unmarshal(configBytes, &config); err != nil {
 		return fmt.Errorf("failed to parse mcp-router config: %v", err)
 	}
+	if !gjson.GetBytes(configBytes, "toolListCacheTTL").Exists() {
+		config.ToolListCacheTTL = defaultToolListCacheTTL
+	}
+	if config.ToolListCacheTTL < 0 {
+		return fmt.Errorf("invalid toolListCacheTTL: %d", config.ToolListCacheTTL)
+	}
+	if config.ToolListPageSize < 0 {
+		return fmt.Errorf("invalid toolListPageSize: %d", config.ToolListPageSize)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+	for _, server := range config.Servers {
+		if server.Name == "" {
+			return errors.New("servers[].name cannot be empty")
+		}
+	}
 
 	log.Infof("Parsed mcp-router config with %d servers", len(config.Servers))
 	for _, server := range config.Servers {
@@ -87,6 +124,14 @@ func ParseOverrideConfig(configBytes []byte, globalConfig any, ruleConfig *any)
 	}
 	config.global = &parent
 	config.enable = gjson.GetBytes(configBytes, "enable").Bool()
+	config.aggregate = gjson.GetBytes(configBytes, "aggregate").Bool()
+	if config.enable && config.aggregate {
+		for _, server := range parent.Servers {
+			if server.ServiceName == "" {
+				return fmt.Errorf("servers[].serviceName of server %s is required when aggregate is enabled", server.Name)
+			}
+		}
+	}
 	*ruleConfig = config
 	return nil
 }
@@ -47,11 +47,28 @@ func setOrReplaceHeader(headers *[][2]string, key, value string) {
 // SecurityScheme defines a security scheme for the REST API
 type SecurityScheme struct {
 	ID                string `json:"id"`
-	Type              string `json:"type"`             // http, apiKey
+	Type              string `json:"type"`             // http, apiKey, oauth2
 	Scheme            string `json:"scheme,omitempty"` // basic, bearer (for type: http)
 	In                string `json:"in,omitempty"`     // header, query (for type: apiKey)
 	Name              string `json:"name,omitempty"`   // Header or query parameter name (for type: apiKey)
 	DefaultCredential string `json:"defaultCredential,omitempty"`
+
+	// The following fields are for type: oauth2, the access token is fetched from the token endpoint
+	TokenURL         string   `json:"tokenUrl,omitempty"`
+	ClientID         string   `json:"clientId,omitempty"`
+	ClientSecret     string   `json:"clientSecret,omitempty"`
+	ClientAuthMethod string   `json:"clientAuthMethod,omitempty"` // client_secret_basic (default), client_secret_post
+	Scopes           []string `json:"scopes,omitempty"`
+	Audience         string   `json:"audience,omitempty"`
+	// If true, the passthrough credential of the caller is exchanged for an access token (RFC 8693),
+	// otherwise the client credentials grant is used
+	TokenExchange    bool   `json:"tokenExchange,omitempty"`
+	SubjectTokenType string `json:"subjectTokenType,omitempty"` // Defaults to urn:ietf:params:oauth:token-type:access_token
+	// Service of the token endpoint, required unless tokenUrl has the same host as the request url of the tools,
+	// in which case the token endpoint is served by the cluster of the current route
+	TokenServiceName string `json:"tokenServiceName,omitempty"`
+	TokenServicePort int64  `json:"tokenServicePort,omitempty"`
+	TokenTimeout     uint32 `json:"tokenTimeout,omitempty"` // Timeout of the token request in milliseconds
 }
 
 // SecurityRequirement specifies a security scheme requirement for a tool
@@ -83,6 +100,11 @@ func ExtractAndRemoveIncomingCredential(scheme SecurityScheme) (string, error) {
 	credentialValue := ""
 	var err error
 
+	if scheme.Type == "oauth2" {
+		// Clients present OAuth2 access tokens as bearer tokens
+		scheme = SecurityScheme{ID: scheme.ID, Type: "http", Scheme: "bearer"}
+	}
+
 	switch scheme.Type {
 	case "http":
 		authHeader, _ := proxywasm.GetHttpRequestHeader("Authorization") // Error ignored, check content
@@ -209,6 +231,13 @@ func ApplySecurity(securityConfig SecurityRequirement, provider SecuritySchemePr
 			return fmt.Errorf("unsupported http scheme type for upstream: %s", upstreamScheme.Scheme)
 		}
 		setOrReplaceHeader(&reqCtx.Headers, "Authorization", authValue)
+	case "oauth2":
+		// The credential is the access token fetched from the token endpoint
+		authValue := credentialToUse
+		if !strings.HasPrefix(authValue, "Bearer ") {
+			authValue = "Bearer " + credentialToUse
+		}
+		setOrReplaceHeader(&reqCtx.Headers, "Authorization", authValue)
 	case "apiKey":
 		if upstreamScheme.In == "header" {
 			if upstreamScheme.Name == "" {
@@ -0,0 +1,248 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/higress-group/wasm-go/pkg/log"
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	oauth2GrantTypeClientCredentials = "client_credentials"
+	oauth2GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+	oauth2TokenTypeAccessToken       = "urn:ietf:params:oauth:token-type:access_token"
+
+	oauth2ClientSecretBasic = "client_secret_basic"
+	oauth2ClientSecretPost  = "client_secret_post"
+
+	oauth2DefaultTokenTimeout = 5000
+	// Used when the token endpoint does not return expires_in
+	oauth2DefaultExpiresIn = 5 * time.Minute
+	// Tokens are refreshed a bit before they expire, so that the upstream never sees an expired token
+	oauth2ExpirySkew = 30 * time.Second
+	// Exchanged tokens are cached per caller, expired tokens are purged when the cache grows beyond this size
+	oauth2MaxCachedTokens = 1024
+)
+
+// ctxOAuth2TokenPending marks the request paused while fetching the access token
+const ctxOAuth2TokenPending = "oauth2TokenPending"
+
+type oauth2Token struct {
+	accessToken string
+	expireAt    time.Time
+}
+
+// oauth2TokenCache caches the access tokens by scheme and subject token. Each wasm VM keeps its own cache.
+var oauth2TokenCache = map[string]oauth2Token{}
+
+// validateOAuth2Scheme checks the fields required by an oauth2 security scheme.
+func validateOAuth2Scheme(scheme SecurityScheme) error {
+	if scheme.TokenURL == "" {
+		return fmt.Errorf("oauth2 security scheme '%s' requires tokenUrl", scheme.ID)
+	}
+	tokenURL, err := url.Parse(scheme.TokenURL)
+	if err != nil || tokenURL.Host == "" {
+		return fmt.Errorf("invalid tokenUrl of oauth2 security scheme '%s': %s", scheme.ID, scheme.TokenURL)
+	}
+	if scheme.ClientID == "" && !scheme.TokenExchange {
+		return fmt.Errorf("oauth2 security scheme '%s' requires clientId for the client credentials grant", scheme.ID)
+	}
+	switch scheme.ClientAuthMethod {
+	case "", oauth2ClientSecretBasic, oauth2ClientSecretPost:
+	default:
+		return fmt.Errorf("unsupported clientAuthMethod of oauth2 security scheme '%s': %s", scheme.ID, scheme.ClientAuthMethod)
+	}
+	if scheme.TokenServiceName != "" && scheme.TokenServicePort <= 0 {
+		return fmt.Errorf("oauth2 security scheme '%s' requires tokenServicePort when tokenServiceName is set", scheme.ID)
+	}
+	return nil
+}
+
+// validateOAuth2TokenService checks that the token request of a tool, which carries the client credentials, is
+// not sent to a backend other than the token endpoint. Without tokenServiceName the token request goes to the
+// cluster of the current route, which is only the token endpoint when the tool is served by the same host.
+func validateOAuth2TokenService(scheme SecurityScheme, toolURL string) error {
+	if scheme.TokenServiceName != "" {
+		return nil
+	}
+	tokenURL, err := url.Parse(scheme.TokenURL)
+	if err != nil {
+		return fmt.Errorf("invalid tokenUrl of oauth2 security scheme '%s': %s", scheme.ID, scheme.TokenURL)
+	}
+	if parsedToolURL, err := url.Parse(toolURL); err == nil && parsedToolURL.Host != "" &&
+		strings.EqualFold(parsedToolURL.Host, tokenURL.Host) {
+		return nil
+	}
+	return fmt.Errorf("oauth2 security scheme '%s' requires tokenServiceName and tokenServicePort, "+
+		"since the host of tokenUrl %s differs from the backend of tool url %s", scheme.ID, scheme.TokenURL, toolURL)
+}
+
+func oauth2CacheKey(scheme SecurityScheme, subjectToken string) string {
+	if subjectToken == "" {
+		return scheme.ID
+	}
+	hash := sha256.Sum256([]byte(subjectToken))
+	return scheme.ID + ":" + hex.EncodeToString(hash[:])
+}
+
+// getCachedOAuth2Token returns the cached access token if it has not expired.
+func getCachedOAuth2Token(scheme SecurityScheme, subjectToken string, now time.Time) (string, bool) {
+	token, ok := oauth2TokenCache[oauth2CacheKey(scheme, subjectToken)]
+	if !ok || !now.Before(token.expireAt) {
+		return "", false
+	}
+	return token.accessToken, true
+}
+
+func cacheOAuth2Token(scheme SecurityScheme, subjectToken string, token oauth2Token, now time.Time) {
+	if len(oauth2TokenCache) >= oauth2MaxCachedTokens {
+		for key, cached := range oauth2TokenCache {
+			if !now.Before(cached.expireAt) {
+				delete(oauth2TokenCache, key)
+			}
+		}
+		if len(oauth2TokenCache) >= oauth2MaxCachedTokens {
+			oauth2TokenCache = map[string]oauth2Token{}
+		}
+	}
+	oauth2TokenCache[oauth2CacheKey(scheme, subjectToken)] = token
+}
+
+// buildOAuth2TokenRequest builds the headers and the form body of the token request. The client credentials grant
+// is used when subjectToken is empty, otherwise the subject token is exchanged for an access token.
+func buildOAuth2TokenRequest(scheme SecurityScheme, subjectToken string) ([][2]string, []byte) {
+	form := url.Values{}
+	if subjectToken == "" {
+		form.Set("grant_type", oauth2GrantTypeClientCredentials)
+	} else {
+		subjectTokenType := scheme.SubjectTokenType
+		if subjectTokenType == "" {
+			subjectTokenType = oauth2TokenTypeAccessToken
+		}
+		form.Set("grant_type", oauth2GrantTypeTokenExchange)
+		form.Set("subject_token", subjectToken)
+		form.Set("subject_token_type", subjectTokenType)
+		form.Set("requested_token_type", oauth2TokenTypeAccessToken)
+	}
+	if len(scheme.Scopes) > 0 {
+		form.Set("scope", strings.Join(scheme.Scopes, " "))
+	}
+	if scheme.Audience != "" {
+		form.Set("audience", scheme.Audience)
+	}
+	headers := [][2]string{
+		{"Content-Type", "application/x-www-form-urlencoded"},
+		{"Accept", "application/json"},
+	}
+	if scheme.ClientID != "" {
+		if scheme.ClientAuthMethod == oauth2ClientSecretPost {
+			form.Set("client_id", scheme.ClientID)
+			form.Set("client_secret", scheme.ClientSecret)
+		} else {
+			// The client id and secret are form-urlencoded before base64 encoding, see RFC 6749 section 2.3.1
+			credential := url.QueryEscape(scheme.ClientID) + ":" + url.QueryEscape(scheme.ClientSecret)
+			headers = append(headers, [2]string{"Authorization", "Basic " + base64.StdEncoding.EncodeToString([]byte(credential))})
+		}
+	}
+	return headers, []byte(form.Encode())
+}
+
+// parseOAuth2TokenResponse parses the access token and its expiration from the response of the token endpoint.
+func parseOAuth2TokenResponse(statusCode int, body []byte, now time.Time) (oauth2Token, error) {
+	if statusCode != http.StatusOK {
+		return oauth2Token{}, fmt.Errorf("token endpoint returned status %d: %s", statusCode, body)
+	}
+	accessToken := gjson.GetBytes(body, "access_token").String()
+	if accessToken == "" {
+		return oauth2Token{}, errors.New("no access_token in the response of token endpoint")
+	}
+	expiresIn := oauth2DefaultExpiresIn
+	if seconds := gjson.GetBytes(body, "expires_in").Int(); seconds > 0 {
+		expiresIn = time.Duration(seconds) * time.Second
+	}
+	expiresIn -= min(oauth2ExpirySkew, expiresIn/2)
+	return oauth2Token{accessToken: accessToken, expireAt: now.Add(expiresIn)}, nil
+}
+
+// newOAuth2TokenClient creates the client of the token endpoint, the cluster of the current route is only used
+// when the token endpoint is served by the backend of the tool, see validateOAuth2TokenService.
+func newOAuth2TokenClient(scheme SecurityScheme, tokenURL *url.URL) wrapper.HttpClient {
+	if scheme.TokenServiceName != "" {
+		return wrapper.NewClusterClient(wrapper.FQDNCluster{
+			FQDN: scheme.TokenServiceName,
+			Host: tokenURL.Host,
+			Port: scheme.TokenServicePort,
+		})
+	}
+	return wrapper.NewClusterClient(wrapper.RouteCluster{Host: tokenURL.Host})
+}
+
+// requestOAuth2Token fetches an access token from the token endpoint and caches it. The callback is invoked with
+// the access token or the error once the token endpoint responds.
+func requestOAuth2Token(scheme SecurityScheme, subjectToken string, callback func(accessToken string, err error)) error {
+	tokenURL, err := url.Parse(scheme.TokenURL)
+	if err != nil {
+		return fmt.Errorf("invalid tokenUrl of oauth2 security scheme '%s': %v", scheme.ID, err)
+	}
+	timeout := scheme.TokenTimeout
+	if timeout == 0 {
+		timeout = oauth2DefaultTokenTimeout
+	}
+	headers, body := buildOAuth2TokenRequest(scheme, subjectToken)
+	client := newOAuth2TokenClient(scheme, tokenURL)
+	return client.Post(scheme.TokenURL, headers, body, func(statusCode int, responseHeaders http.Header, responseBody []byte) {
+		now := time.Now()
+		token, err := parseOAuth2TokenResponse(statusCode, responseBody, now)
+		if err != nil {
+			callback("", fmt.Errorf("failed to fetch access token for oauth2 security scheme '%s': %v", scheme.ID, err))
+			return
+		}
+		log.Debugf("Fetched access token for oauth2 security scheme %s, expires at %s", scheme.ID, token.expireAt)
+		cacheOAuth2Token(scheme, subjectToken, token, now)
+		callback(token.accessToken, nil)
+	}, timeout)
+}
+
+// resolveOAuth2Token gets the access token of an oauth2 security scheme from the cache, or else from the token
+// endpoint asynchronously. It returns true if the callback will be invoked asynchronously, in which case the
+// request must be paused until then.
+func resolveOAuth2Token(scheme SecurityScheme, passthroughCredential string, callback func(accessToken string, err error)) (bool, error) {
+	subjectToken := ""
+	if scheme.TokenExchange {
+		if passthroughCredential == "" {
+			return false, fmt.Errorf("oauth2 security scheme '%s' requires a passthrough credential for token exchange", scheme.ID)
+		}
+		subjectToken = passthroughCredential
+	}
+	if accessToken, ok := getCachedOAuth2Token(scheme, subjectToken, time.Now()); ok {
+		callback(accessToken, nil)
+		return false, nil
+	}
+	if err := requestOAuth2Token(scheme, subjectToken, callback); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOAuth2Scheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		scheme  SecurityScheme
+		wantErr string
+	}{
+		{
+			name:   "client credentials",
+			scheme: SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "https://auth.example.com/oauth/token", ClientID: "id", ClientSecret: "secret"},
+		},
+		{
+			name:   "token exchange without client",
+			scheme: SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "https://auth.example.com/oauth/token", TokenExchange: true},
+		},
+		{
+			name:    "missing token url",
+			scheme:  SecurityScheme{ID: "oauth", Type: "oauth2", ClientID: "id"},
+			wantErr: "oauth2 security scheme 'oauth' requires tokenUrl",
+		},
+		{
+			name:    "relative token url",
+			scheme:  SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "/oauth/token", ClientID: "id"},
+			wantErr: "invalid tokenUrl of oauth2 security scheme 'oauth': /oauth/token",
+		},
+		{
+			name:    "missing client id",
+			scheme:  SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "https://auth.example.com/oauth/token"},
+			wantErr: "oauth2 security scheme 'oauth' requires clientId for the client credentials grant",
+		},
+		{
+			name:    "invalid client auth method",
+			scheme:  SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "https://auth.example.com/oauth/token", ClientID: "id", ClientAuthMethod: "private_key_jwt"},
+			wantErr: "unsupported clientAuthMethod of oauth2 security scheme 'oauth': private_key_jwt",
+		},
+		{
+			name:    "missing token service port",
+			scheme:  SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "https://auth.example.com/oauth/token", ClientID: "id", TokenServiceName: "auth.dns"},
+			wantErr: "oauth2 security scheme 'oauth' requires tokenServicePort when tokenServiceName is set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOAuth2Scheme(tt.scheme)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOAuth2TokenService(t *testing.T) {
+	scheme := SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "https://api.example.com/oauth/token", ClientID: "id"}
+	assert.NoError(t, validateOAuth2TokenService(scheme, "https://api.example.com/users/{{.args.id}}"))
+	assert.EqualError(t, validateOAuth2TokenService(scheme, "https://backend.example.com/users"),
+		"oauth2 security scheme 'oauth' requires tokenServiceName and tokenServicePort, "+
+			"since the host of tokenUrl https://api.example.com/oauth/token differs from the backend of tool url https://backend.example.com/users")
+
+	scheme.TokenServiceName = "auth.dns"
+	scheme.TokenServicePort = 443
+	assert.NoError(t, validateOAuth2TokenService(scheme, "https://backend.example.com/users"))
+}
+
+func TestAddRestToolWithOAuth2(t *testing.T) {
+	server := NewRestMCPServer("test")
+	server.AddSecurityScheme(SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "https://auth.example.com/oauth/token", ClientID: "id"})
+	server.SetDefaultUpstreamSecurity(SecurityRequirement{ID: "oauth"})
+	err := server.AddRestTool(RestTool{
+		Name:            "get_user",
+		RequestTemplate: RestToolRequestTemplate{URL: "https://api.example.com/users", Method: "GET"},
+	})
+	assert.ErrorContains(t, err, "invalid tool get_user: oauth2 security scheme 'oauth' requires tokenServiceName and tokenServicePort")
+}
+
+func TestBuildOAuth2TokenRequest(t *testing.T) {
+	scheme := SecurityScheme{
+		ID:           "oauth",
+		Type:         "oauth2",
+		TokenURL:     "https://auth.example.com/oauth/token",
+		ClientID:     "client id",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "write"},
+		Audience:     "https://api.example.com",
+	}
+
+	t.Run("client credentials with basic auth", func(t *testing.T) {
+		headers, body := buildOAuth2TokenRequest(scheme, "")
+		form, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		assert.Equal(t, "client_credentials", form.Get("grant_type"))
+		assert.Equal(t, "read write", form.Get("scope"))
+		assert.Equal(t, "https://api.example.com", form.Get("audience"))
+		assert.Empty(t, form.Get("client_id"))
+		assert.Contains(t, headers, [2]string{"Content-Type", "application/x-www-form-urlencoded"})
+		assert.Contains(t, headers, [2]string{"Authorization", "Basic " + base64.StdEncoding.EncodeToString([]byte("client+id:secret"))})
+	})
+
+	t.Run("token exchange with client secret post", func(t *testing.T) {
+		scheme := scheme
+		scheme.ClientAuthMethod = oauth2ClientSecretPost
+		headers, body := buildOAuth2TokenRequest(scheme, "caller-token")
+		form, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		assert.Equal(t, oauth2GrantTypeTokenExchange, form.Get("grant_type"))
+		assert.Equal(t, "caller-token", form.Get("subject_token"))
+		assert.Equal(t, oauth2TokenTypeAccessToken, form.Get("subject_token_type"))
+		assert.Equal(t, "client id", form.Get("client_id"))
+		assert.Equal(t, "secret", form.Get("client_secret"))
+		for _, header := range headers {
+			assert.NotEqual(t, "Authorization", header[0])
+		}
+	})
+}
+
+func TestParseOAuth2TokenResponse(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	token, err := parseOAuth2TokenResponse(200, []byte(`{"access_token":"abc","token_type":"Bearer","expires_in":3600}`), now)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", token.accessToken)
+	assert.Equal(t, now.Add(3600*time.Second-oauth2ExpirySkew), token.expireAt)
+
+	// short lived tokens are refreshed halfway through their lifetime
+	token, err = parseOAuth2TokenResponse(200, []byte(`{"access_token":"abc","expires_in":20}`), now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*time.Second), token.expireAt)
+
+	token, err = parseOAuth2TokenResponse(200, []byte(`{"access_token":"abc"}`), now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(oauth2DefaultExpiresIn-oauth2ExpirySkew), token.expireAt)
+
+	_, err = parseOAuth2TokenResponse(401, []byte(`{"error":"invalid_client"}`), now)
+	assert.EqualError(t, err, `token endpoint returned status 401: {"error":"invalid_client"}`)
+
+	_, err = parseOAuth2TokenResponse(200, []byte(`{"token_type":"Bearer"}`), now)
+	assert.EqualError(t, err, "no access_token in the response of token endpoint")
+}
+
+func TestOAuth2TokenCache(t *testing.T) {
+	oauth2TokenCache = map[string]oauth2Token{}
+	defer func() { oauth2TokenCache = map[string]oauth2Token{} }()
+
+	now := time.Now()
+	scheme := SecurityScheme{ID: "oauth", Type: "oauth2"}
+	cacheOAuth2Token(scheme, "", oauth2Token{accessToken: "shared", expireAt: now.Add(time.Minute)}, now)
+	cacheOAuth2Token(scheme, "caller-token", oauth2Token{accessToken: "exchanged", expireAt: now.Add(time.Minute)}, now)
+
+	token, ok := getCachedOAuth2Token(scheme, "", now)
+	assert.True(t, ok)
+	assert.Equal(t, "shared", token)
+	token, ok = getCachedOAuth2Token(scheme, "caller-token", now)
+	assert.True(t, ok)
+	assert.Equal(t, "exchanged", token)
+	_, ok = getCachedOAuth2Token(scheme, "other-token", now)
+	assert.False(t, ok)
+	_, ok = getCachedOAuth2Token(scheme, "", now.Add(time.Minute))
+	assert.False(t, ok)
+
+	// a cached token is used without calling the token endpoint
+	var resolved string
+	async, err := resolveOAuth2Token(SecurityScheme{ID: "oauth", Type: "oauth2", TokenExchange: true}, "caller-token", func(accessToken string, err error) {
+		require.NoError(t, err)
+		resolved = accessToken
+	})
+	require.NoError(t, err)
+	assert.False(t, async)
+	assert.Equal(t, "exchanged", resolved)
+
+	_, err = resolveOAuth2Token(SecurityScheme{ID: "oauth", Type: "oauth2", TokenExchange: true}, "", nil)
+	assert.EqualError(t, err, "oauth2 security scheme 'oauth' requires a passthrough credential for token exchange")
+}
+
+func TestApplyOAuth2Security(t *testing.T) {
+	server := NewRestMCPServer("oauth-test")
+	server.AddSecurityScheme(SecurityScheme{ID: "oauth", Type: "oauth2", TokenURL: "https://auth.example.com/oauth/token", ClientID: "id"})
+
+	parsedURL, _ := url.Parse("https://api.example.com/v1/items")
+	reqCtx := &AuthRequestContext{Method: "GET", ParsedURL: parsedURL}
+	err := ApplySecurity(SecurityRequirement{ID: "oauth", Credential: "access-token"}, server, reqCtx)
+	require.NoError(t, err)
+	assert.Equal(t, [][2]string{{"Authorization", "Bearer access-token"}}, reqCtx.Headers)
+}
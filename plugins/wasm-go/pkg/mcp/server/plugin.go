@@ -266,6 +266,7 @@ type McpServerConfig struct {
 	methodHandlers utils.MethodHandlers
 	toolSet        *ToolSetConfig // Parsed toolset configuration
 	isComposed     bool
+	toolAuthorizer *ToolAuthorizer // Per-tool authorization policies, nil if not configured
 }
 
 // GetServerName returns the server name for external access
@@ -374,6 +375,9 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 		}
 
 		toolsJson := configJson.Get("tools") // These are REST tools for this server instance or MCP proxy tools
+		resourcesJson := configJson.Get("resources")
+		promptsJson := configJson.Get("prompts")
+		hasRestDefinitions := len(toolsJson.Array()) > 0 || len(resourcesJson.Array()) > 0 || len(promptsJson.Array()) > 0
 
 		if serverType == "mcp-proxy" {
 			// Create MCP proxy server
@@ -399,8 +403,8 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 			}
 			// Set the proxy server regardless of whether tools are configured
 			config.server = proxyServer
-		} else if toolsJson.Exists() && len(toolsJson.Array()) > 0 {
-			// Handle REST-to-MCP server (requires tools configuration)
+		} else if hasRestDefinitions {
+			// Handle REST-to-MCP server (requires tools, resources or prompts configuration)
 			// Create REST-to-MCP server (default behavior)
 			restServer := NewRestMCPServer(config.serverName)         // Pass the server name
 			restServer.SetConfig([]byte(serverConfigJsonForInstance)) // Pass the server's specific config
@@ -412,6 +416,11 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 					if err := json.Unmarshal([]byte(schemeJson.Raw), &scheme); err != nil {
 						return fmt.Errorf("failed to parse security scheme config: %v", err)
 					}
+					if scheme.Type == "oauth2" {
+						if err := validateOAuth2Scheme(scheme); err != nil {
+							return err
+						}
+					}
 					restServer.AddSecurityScheme(scheme)
 				}
 			}
@@ -452,6 +461,26 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 				// Register tool to registry
 				opts.ToolRegistry.RegisterTool(config.serverName, restTool.Name, restServer.GetMCPTools()[restTool.Name])
 			}
+
+			for _, resourceJson := range resourcesJson.Array() {
+				var restResource RestResource
+				if err := json.Unmarshal([]byte(resourceJson.Raw), &restResource); err != nil {
+					return fmt.Errorf("failed to parse resource config: %v", err)
+				}
+				if err := restServer.AddRestResource(restResource); err != nil {
+					return fmt.Errorf("failed to add resource %s: %v", restResource.URI, err)
+				}
+			}
+
+			for _, promptJson := range promptsJson.Array() {
+				var restPrompt RestPrompt
+				if err := json.Unmarshal([]byte(promptJson.Raw), &restPrompt); err != nil {
+					return fmt.Errorf("failed to parse prompt config: %v", err)
+				}
+				if err := restServer.AddRestPrompt(restPrompt); err != nil {
+					return fmt.Errorf("failed to add prompt %s: %v", restPrompt.Name, err)
+				}
+			}
 			config.server = restServer
 		} else {
 			// Logic for pre-registered Go-based servers (non-REST)
@@ -492,6 +521,25 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 	}
 	// If allowTools is nil, it means not configured (allow all)
 
+	// Parse per-tool authorization policies, only single servers are supported
+	if !config.isComposed && serverJson.Get("toolPolicies").Exists() {
+		var toolPolicies map[string]ToolPolicy
+		if err := json.Unmarshal([]byte(serverJson.Get("toolPolicies").Raw), &toolPolicies); err != nil {
+			return fmt.Errorf("failed to parse toolPolicies config: %v", err)
+		}
+		var consumerGroups map[string][]string
+		if consumerGroupsJson := serverJson.Get("consumerGroups"); consumerGroupsJson.Exists() {
+			if err := json.Unmarshal([]byte(consumerGroupsJson.Raw), &consumerGroups); err != nil {
+				return fmt.Errorf("failed to parse consumerGroups config: %v", err)
+			}
+		}
+		authorizer, err := NewToolAuthorizer(toolPolicies, consumerGroups, serverJson.Get("claimsHeader").String())
+		if err != nil {
+			return err
+		}
+		config.toolAuthorizer = authorizer
+	}
+
 	config.methodHandlers = make(utils.MethodHandlers)
 	// Use config.serverName which is now reliably set
 	currentServerNameForHandlers := config.serverName
@@ -508,6 +556,24 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 		proxywasm.SendHttpResponseWithDetail(202, fmt.Sprintf("mcp:%s:notifications/cancelled", currentServerNameForHandlers), nil, nil, -1)
 		return nil
 	}
+	capabilities := map[string]any{
+		"tools": map[string]any{},
+	}
+	// Resources and prompts are only supported by REST-to-MCP servers
+	if restServer, ok := config.server.(*RestMCPServer); ok {
+		if len(restServer.GetResources()) > 0 {
+			capabilities["resources"] = map[string]any{}
+			for method, handler := range CreateRestResourceMethodHandlers(restServer) {
+				config.methodHandlers[method] = handler
+			}
+		}
+		if len(restServer.GetPrompts()) > 0 {
+			capabilities["prompts"] = map[string]any{}
+			for method, handler := range CreateRestPromptMethodHandlers(restServer) {
+				config.methodHandlers[method] = handler
+			}
+		}
+	}
 	config.methodHandlers["initialize"] = func(ctx wrapper.HttpContext, id utils.JsonRpcID, params gjson.Result) error {
 		requestedVersion := params.Get("protocolVersion").String()
 		if requestedVersion == "" {
@@ -529,9 +595,7 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 
 		utils.OnMCPResponseSuccess(ctx, map[string]any{
 			"protocolVersion": negotiatedVersion,
-			"capabilities": map[string]any{
-				"tools": map[string]any{},
-			},
+			"capabilities":    capabilities,
 			"serverInfo": map[string]any{
 				"name":    currentServerNameForHandlers, // Use the actual server name (single or composed)
 				"version": "1.0.0",
@@ -569,6 +633,9 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 						continue
 					}
 				}
+				if !toolAllowedByPolicy(ctx, toolFullName) {
+					continue
+				}
 				toolDef := map[string]any{
 					"name":        toolFullName,
 					"description": tool.Description(),
@@ -637,9 +704,37 @@ func parseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *Con
 		}
 	}
 
+	if config.toolAuthorizer != nil {
+		wrapToolPolicyHandlers(config.methodHandlers, config.toolAuthorizer, currentServerNameForHandlers)
+	}
+
 	return nil
 }
 
+// wrapToolPolicyHandlers enforces the tool policies on tools/list and tools/call
+func wrapToolPolicyHandlers(handlers utils.MethodHandlers, authorizer *ToolAuthorizer, serverName string) {
+	listHandler := handlers["tools/list"]
+	handlers["tools/list"] = func(ctx wrapper.HttpContext, id utils.JsonRpcID, params gjson.Result) error {
+		caller := authorizer.getToolCaller()
+		// The filter is also used by the MCP proxy servers when the tools/list response of the backend arrives
+		ctx.SetContext(CtxToolPolicyFilter, func(toolName string) bool {
+			return authorizer.Allowed(toolName, caller)
+		})
+		return listHandler(ctx, id, params)
+	}
+	callHandler := handlers["tools/call"]
+	handlers["tools/call"] = func(ctx wrapper.HttpContext, id utils.JsonRpcID, params gjson.Result) error {
+		toolName := params.Get("name").String()
+		caller := authorizer.getToolCaller()
+		if !authorizer.Allowed(toolName, caller) {
+			log.Infof("Tool call [%s] on server [%s] denied by policy, consumer: %s", toolName, serverName, caller.Consumer)
+			utils.OnMCPResponseError(ctx, fmt.Errorf("Tool not allowed: %s", toolName), utils.ErrInvalidParams, fmt.Sprintf("mcp:%s:tools/call:tool_denied_by_policy", serverName))
+			return nil
+		}
+		return callHandler(ctx, id, params)
+	}
+}
+
 // ParseConfigCore exports the core parsing logic for external use (e.g., validation)
 func ParseConfigCore(configJson gjson.Result, config *McpServerConfig, opts *ConfigOptions) error {
 	return parseConfigCore(configJson, config, opts)
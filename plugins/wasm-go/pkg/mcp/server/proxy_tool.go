@@ -825,6 +825,11 @@ func CreateMcpProxyMethodHandlers(server *McpProxyServer, allowTools *map[string
 
 // applyAllowToolsFilter applies allowTools filtering to the tools/list response
 func (h *McpProtocolHandler) applyAllowToolsFilter(ctx wrapper.HttpContext, resultMap map[string]interface{}) map[string]interface{} {
+	return filterListedTools(ctx, resultMap)
+}
+
+// filterListedTools applies allowTools and tool policy filtering to the tools/list result of the backend
+func filterListedTools(ctx wrapper.HttpContext, resultMap map[string]interface{}) map[string]interface{} {
 	// Get pre-computed effective allowTools from context
 	var effectiveAllowTools *map[string]struct{}
 	if allowToolsCtx := ctx.GetContext("mcp_proxy_effective_allow_tools"); allowToolsCtx != nil {
@@ -834,7 +839,7 @@ func (h *McpProtocolHandler) applyAllowToolsFilter(ctx wrapper.HttpContext, resu
 	}
 
 	// If no restrictions, return original result
-	if effectiveAllowTools == nil {
+	if effectiveAllowTools == nil && ctx.GetContext(CtxToolPolicyFilter) == nil {
 		return resultMap
 	}
 
@@ -848,7 +853,12 @@ func (h *McpProtocolHandler) applyAllowToolsFilter(ctx wrapper.HttpContext, resu
 					if name, hasName := toolMap["name"]; hasName {
 						if toolName, ok := name.(string); ok {
 							// Check if tool is allowed
-							if _, allow := (*effectiveAllowTools)[toolName]; !allow {
+							if effectiveAllowTools != nil {
+								if _, allow := (*effectiveAllowTools)[toolName]; !allow {
+									continue
+								}
+							}
+							if !toolAllowedByPolicy(ctx, toolName) {
 								continue
 							}
 							// Tool is allowed, add to filtered list
@@ -0,0 +1,191 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	template "github.com/higress-group/gjson_template"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/alibaba/higress/plugins/wasm-go/pkg/mcp/utils"
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+)
+
+// RestPromptArg represents an argument of a prompt template
+type RestPromptArg struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// RestPromptMessage represents a message of a prompt template
+type RestPromptMessage struct {
+	Role    string `json:"role"`    // user or assistant
+	Content string `json:"content"` // Template of the text content, e.g. "Summarize {{.args.topic}}"
+}
+
+// RestPrompt represents a prompt template exposed through prompts/list and prompts/get
+type RestPrompt struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []RestPromptArg     `json:"arguments,omitempty"`
+	Messages    []RestPromptMessage `json:"messages"`
+
+	// Parsed templates of the messages (not from JSON)
+	parsedMessageTemplates []*template.Template
+}
+
+// parseTemplates validates the prompt and parses the templates of the messages
+func (p *RestPrompt) parseTemplates() error {
+	if p.Name == "" {
+		return errors.New("prompt name cannot be empty")
+	}
+	if len(p.Messages) == 0 {
+		return fmt.Errorf("prompt %s must have at least one message", p.Name)
+	}
+	for _, arg := range p.Arguments {
+		if arg.Name == "" {
+			return fmt.Errorf("argument name cannot be empty in prompt %s", p.Name)
+		}
+	}
+	p.parsedMessageTemplates = make([]*template.Template, 0, len(p.Messages))
+	for i, message := range p.Messages {
+		if message.Role != "user" && message.Role != "assistant" {
+			return fmt.Errorf("invalid role '%s' of message %d in prompt %s, must be 'user' or 'assistant'", message.Role, i, p.Name)
+		}
+		tmpl, err := template.New(fmt.Sprintf("prompt_message_%d", i)).Funcs(templateFuncs()).Parse(message.Content)
+		if err != nil {
+			return fmt.Errorf("error parsing message template %d of prompt %s: %v", i, p.Name, err)
+		}
+		p.parsedMessageTemplates = append(p.parsedMessageTemplates, tmpl)
+	}
+	return nil
+}
+
+// Render renders the messages of the prompt with the arguments of prompts/get and the server config
+func (p *RestPrompt) Render(arguments gjson.Result, serverConfig map[string]interface{}) ([]map[string]any, error) {
+	args := make(map[string]interface{})
+	for _, arg := range p.Arguments {
+		value := arguments.Get(gjson.Escape(arg.Name))
+		if !value.Exists() {
+			if arg.Required {
+				return nil, fmt.Errorf("missing required argument: %s", arg.Name)
+			}
+			continue
+		}
+		args[arg.Name] = value.String()
+	}
+
+	var templateDataBytes []byte
+	templateDataBytes, _ = sjson.SetBytes(templateDataBytes, "config", serverConfig)
+	templateDataBytes, _ = sjson.SetBytes(templateDataBytes, "args", args)
+
+	messages := make([]map[string]any, 0, len(p.Messages))
+	for i, message := range p.Messages {
+		text, err := executeTemplate(p.parsedMessageTemplates[i], templateDataBytes)
+		if err != nil {
+			return nil, fmt.Errorf("error executing message template %d of prompt %s: %v", i, p.Name, err)
+		}
+		messages = append(messages, map[string]any{
+			"role": message.Role,
+			"content": map[string]any{
+				"type": "text",
+				"text": text,
+			},
+		})
+	}
+	return messages, nil
+}
+
+// AddRestPrompt adds a prompt template configuration
+func (s *RestMCPServer) AddRestPrompt(prompt RestPrompt) error {
+	if err := prompt.parseTemplates(); err != nil {
+		return err
+	}
+	if _, exist := s.GetPrompt(prompt.Name); exist {
+		return fmt.Errorf("duplicate prompt name: %s", prompt.Name)
+	}
+	s.prompts = append(s.prompts, prompt)
+	return nil
+}
+
+// GetPrompts returns the prompt templates in the order of configuration
+func (s *RestMCPServer) GetPrompts() []RestPrompt {
+	return s.prompts
+}
+
+// GetPrompt returns the prompt template with the given name
+func (s *RestMCPServer) GetPrompt(name string) (RestPrompt, bool) {
+	for _, prompt := range s.prompts {
+		if prompt.Name == name {
+			return prompt, true
+		}
+	}
+	return RestPrompt{}, false
+}
+
+// CreateRestPromptMethodHandlers creates the handlers of prompts/list and prompts/get for the prompt templates of the server
+func CreateRestPromptMethodHandlers(restServer *RestMCPServer) utils.MethodHandlers {
+	serverName := restServer.name
+	handlers := make(utils.MethodHandlers)
+
+	handlers["prompts/list"] = func(ctx wrapper.HttpContext, id utils.JsonRpcID, params gjson.Result) error {
+		listedPrompts := []map[string]any{}
+		for _, prompt := range restServer.GetPrompts() {
+			promptDef := map[string]any{
+				"name": prompt.Name,
+			}
+			if prompt.Description != "" {
+				promptDef["description"] = prompt.Description
+			}
+			if len(prompt.Arguments) > 0 {
+				promptDef["arguments"] = prompt.Arguments
+			}
+			listedPrompts = append(listedPrompts, promptDef)
+		}
+		utils.OnMCPResponseSuccess(ctx, map[string]any{
+			"prompts": listedPrompts,
+		}, fmt.Sprintf("mcp:%s:prompts/list", serverName))
+		return nil
+	}
+
+	handlers["prompts/get"] = func(ctx wrapper.HttpContext, id utils.JsonRpcID, params gjson.Result) error {
+		name := params.Get("name").String()
+		prompt, ok := restServer.GetPrompt(name)
+		if !ok {
+			utils.OnMCPResponseError(ctx, fmt.Errorf("unknown prompt: %s", name), utils.ErrInvalidParams, fmt.Sprintf("mcp:%s:prompts/get:invalid_prompt_name", serverName))
+			return nil
+		}
+		var serverConfig map[string]interface{}
+		restServer.GetConfig(&serverConfig)
+		messages, err := prompt.Render(params.Get("arguments"), serverConfig)
+		if err != nil {
+			utils.OnMCPResponseError(ctx, err, utils.ErrInvalidParams, fmt.Sprintf("mcp:%s:prompts/get:error", serverName))
+			return nil
+		}
+		result := map[string]any{
+			"messages": messages,
+		}
+		if prompt.Description != "" {
+			result["description"] = prompt.Description
+		}
+		utils.OnMCPResponseSuccess(ctx, result, fmt.Sprintf("mcp:%s:prompts/get", serverName))
+		return nil
+	}
+
+	return handlers
+}
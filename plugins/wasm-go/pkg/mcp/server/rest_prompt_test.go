@@ -0,0 +1,111 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestRestPromptParseTemplates(t *testing.T) {
+	tests := []struct {
+		name        string
+		prompt      RestPrompt
+		expectError bool
+	}{
+		{
+			name: "valid prompt",
+			prompt: RestPrompt{
+				Name:      "summarize",
+				Arguments: []RestPromptArg{{Name: "topic", Required: true}},
+				Messages:  []RestPromptMessage{{Role: "user", Content: "Summarize {{.args.topic}}"}},
+			},
+		},
+		{
+			name:        "missing name",
+			prompt:      RestPrompt{Messages: []RestPromptMessage{{Role: "user", Content: "hi"}}},
+			expectError: true,
+		},
+		{
+			name:        "no messages",
+			prompt:      RestPrompt{Name: "empty"},
+			expectError: true,
+		},
+		{
+			name: "invalid role",
+			prompt: RestPrompt{
+				Name:     "system-prompt",
+				Messages: []RestPromptMessage{{Role: "system", Content: "You are a helpful assistant"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "invalid template",
+			prompt: RestPrompt{
+				Name:     "broken",
+				Messages: []RestPromptMessage{{Role: "user", Content: "{{.args.topic"}},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.prompt.parseTemplates()
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRestPromptRender(t *testing.T) {
+	server := NewRestMCPServer("assistant")
+	prompt := RestPrompt{
+		Name:        "translate",
+		Description: "Translate the text",
+		Arguments: []RestPromptArg{
+			{Name: "text", Required: true},
+			{Name: "language"},
+		},
+		Messages: []RestPromptMessage{
+			{Role: "user", Content: "Translate into {{if .args.language}}{{.args.language}}{{else}}{{.config.defaultLanguage}}{{end}}: {{.args.text}}"},
+			{Role: "assistant", Content: "Sure."},
+		},
+	}
+	assert.NoError(t, server.AddRestPrompt(prompt))
+	assert.Error(t, server.AddRestPrompt(prompt), "duplicate prompt name should be rejected")
+
+	stored, ok := server.GetPrompt("translate")
+	assert.True(t, ok)
+	serverConfig := map[string]interface{}{"defaultLanguage": "English"}
+
+	messages, err := stored.Render(gjson.Parse(`{"text": "你好"}`), serverConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"role": "user", "content": map[string]any{"type": "text", "text": "Translate into English: 你好"}},
+		{"role": "assistant", "content": map[string]any{"type": "text", "text": "Sure."}},
+	}, messages)
+
+	messages, err = stored.Render(gjson.Parse(`{"text": "你好", "language": "French"}`), serverConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "Translate into French: 你好", messages[0]["content"].(map[string]any)["text"])
+
+	_, err = stored.Render(gjson.Parse(`{"language": "French"}`), serverConfig)
+	assert.EqualError(t, err, "missing required argument: text")
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
+	"github.com/tidwall/gjson"
+
+	"github.com/alibaba/higress/plugins/wasm-go/pkg/mcp/utils"
+	"github.com/higress-group/wasm-go/pkg/log"
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+)
+
+// RestResource represents a REST API that can be read as an MCP resource.
+// The URI is either a fixed URI listed by resources/list, or a URI template like "weather://cities/{city}"
+// listed by resources/templates/list. The variables of the URI template are available as args in the templates.
+type RestResource struct {
+	URI                   string                   `json:"uri"`
+	Name                  string                   `json:"name"`
+	Description           string                   `json:"description,omitempty"`
+	MimeType              string                   `json:"mimeType,omitempty"`
+	RequestTemplate       RestToolRequestTemplate  `json:"requestTemplate,omitempty"`
+	ResponseTemplate      RestToolResponseTemplate `json:"responseTemplate"`
+	ErrorResponseTemplate string                   `json:"errorResponseTemplate"`
+
+	// Parsed configuration (not from JSON)
+	toolConfig RestTool       // Used to render the request and the response in the same way as a REST tool
+	uriVars    []string       // Variables of the URI template, empty for a fixed URI
+	uriPattern *regexp.Regexp // Matches the URIs of the URI template and captures the variables
+}
+
+var uriTemplateVarRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+
+var uriTemplateVarNameRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// parse validates the resource and parses the URI template and the request/response templates
+func (r *RestResource) parse() error {
+	if r.URI == "" {
+		return errors.New("resource uri cannot be empty")
+	}
+	if r.Name == "" {
+		return fmt.Errorf("resource name cannot be empty for %s", r.URI)
+	}
+
+	// Build the pattern of the URI template, only simple string expansion like {var} is supported
+	r.uriVars = nil
+	r.uriPattern = nil
+	locs := uriTemplateVarRegex.FindAllStringSubmatchIndex(r.URI, -1)
+	if len(locs) > 0 {
+		var pattern strings.Builder
+		pattern.WriteString("^")
+		last := 0
+		for _, loc := range locs {
+			name := r.URI[loc[2]:loc[3]]
+			if !uriTemplateVarNameRegex.MatchString(name) {
+				return fmt.Errorf("unsupported variable {%s} in resource uri template %s", name, r.URI)
+			}
+			pattern.WriteString(regexp.QuoteMeta(r.URI[last:loc[0]]))
+			pattern.WriteString("([^/?#]+)")
+			r.uriVars = append(r.uriVars, name)
+			last = loc[1]
+		}
+		pattern.WriteString(regexp.QuoteMeta(r.URI[last:]))
+		pattern.WriteString("$")
+		r.uriPattern = regexp.MustCompile(pattern.String())
+	}
+
+	r.toolConfig = RestTool{
+		Name:                  r.Name,
+		Description:           r.Description,
+		RequestTemplate:       r.RequestTemplate,
+		ResponseTemplate:      r.ResponseTemplate,
+		ErrorResponseTemplate: r.ErrorResponseTemplate,
+	}
+	for _, name := range r.uriVars {
+		r.toolConfig.Args = append(r.toolConfig.Args, RestToolArg{Name: name, Type: "string"})
+	}
+	if err := r.toolConfig.parseTemplates(); err != nil {
+		return fmt.Errorf("invalid resource %s: %v", r.URI, err)
+	}
+	return nil
+}
+
+// IsTemplate returns whether the resource is declared with a URI template
+func (r *RestResource) IsTemplate() bool {
+	return r.uriPattern != nil
+}
+
+// match checks whether the URI is served by the resource, and returns the values of the URI template variables
+func (r *RestResource) match(uri string) (map[string]interface{}, bool) {
+	if !r.IsTemplate() {
+		return map[string]interface{}{}, uri == r.URI
+	}
+	values := r.uriPattern.FindStringSubmatch(uri)
+	if values == nil {
+		return nil, false
+	}
+	args := make(map[string]interface{}, len(r.uriVars))
+	for i, name := range r.uriVars {
+		args[name] = values[i+1]
+	}
+	return args, true
+}
+
+// AddRestResource adds a REST resource configuration
+func (s *RestMCPServer) AddRestResource(resource RestResource) error {
+	if err := resource.parse(); err != nil {
+		return err
+	}
+	for _, existing := range s.resources {
+		if existing.URI == resource.URI {
+			return fmt.Errorf("duplicate resource uri: %s", resource.URI)
+		}
+	}
+	s.resources = append(s.resources, resource)
+	return nil
+}
+
+// GetResources returns the REST resources in the order of configuration
+func (s *RestMCPServer) GetResources() []RestResource {
+	return s.resources
+}
+
+// MatchResource finds the resource serving the URI. Fixed URIs take precedence over URI templates,
+// and URI templates are matched in the order of configuration.
+func (s *RestMCPServer) MatchResource(uri string) (RestResource, map[string]interface{}, bool) {
+	for _, resource := range s.resources {
+		if !resource.IsTemplate() && resource.URI == uri {
+			return resource, map[string]interface{}{}, true
+		}
+	}
+	for _, resource := range s.resources {
+		if !resource.IsTemplate() {
+			continue
+		}
+		if args, ok := resource.match(uri); ok {
+			return resource, args, true
+		}
+	}
+	return RestResource{}, nil, false
+}
+
+// resourceResponder sends the result of a REST call as the result of resources/read
+type resourceResponder struct {
+	serverName string
+	uri        string
+	mimeType   string
+}
+
+func (r resourceResponder) sendError(ctx wrapper.HttpContext, err error) {
+	utils.OnMCPResponseError(ctx, err, utils.ErrInternalError, fmt.Sprintf("mcp:%s:resources/read:error", r.serverName))
+}
+
+func (r resourceResponder) sendText(ctx wrapper.HttpContext, text string, structuredContent json.RawMessage) {
+	content := map[string]any{
+		"uri":  r.uri,
+		"text": text,
+	}
+	if r.mimeType != "" {
+		content["mimeType"] = r.mimeType
+	}
+	utils.OnMCPResponseSuccess(ctx, map[string]any{
+		"contents": []map[string]any{content},
+	}, fmt.Sprintf("mcp:%s:resources/read", r.serverName))
+}
+
+func (r resourceResponder) sendImage(ctx wrapper.HttpContext, image []byte, contentType string) {
+	utils.OnMCPResponseSuccess(ctx, map[string]any{
+		"contents": []map[string]any{
+			{
+				"uri":      r.uri,
+				"mimeType": contentType,
+				"blob":     base64.StdEncoding.EncodeToString(image),
+			},
+		},
+	}, fmt.Sprintf("mcp:%s:resources/read", r.serverName))
+}
+
+// CreateRestResourceMethodHandlers creates the handlers of resources/list, resources/templates/list and
+// resources/read for the REST resources of the server
+func CreateRestResourceMethodHandlers(restServer *RestMCPServer) utils.MethodHandlers {
+	serverName := restServer.name
+	handlers := make(utils.MethodHandlers)
+
+	handlers["resources/list"] = func(ctx wrapper.HttpContext, id utils.JsonRpcID, params gjson.Result) error {
+		listedResources := []map[string]any{}
+		for _, resource := range restServer.GetResources() {
+			if resource.IsTemplate() {
+				continue
+			}
+			resourceDef := map[string]any{
+				"uri":  resource.URI,
+				"name": resource.Name,
+			}
+			if resource.Description != "" {
+				resourceDef["description"] = resource.Description
+			}
+			if resource.MimeType != "" {
+				resourceDef["mimeType"] = resource.MimeType
+			}
+			listedResources = append(listedResources, resourceDef)
+		}
+		utils.OnMCPResponseSuccess(ctx, map[string]any{
+			"resources": listedResources,
+		}, fmt.Sprintf("mcp:%s:resources/list", serverName))
+		return nil
+	}
+
+	handlers["resources/templates/list"] = func(ctx wrapper.HttpContext, id utils.JsonRpcID, params gjson.Result) error {
+		listedTemplates := []map[string]any{}
+		for _, resource := range restServer.GetResources() {
+			if !resource.IsTemplate() {
+				continue
+			}
+			templateDef := map[string]any{
+				"uriTemplate": resource.URI,
+				"name":        resource.Name,
+			}
+			if resource.Description != "" {
+				templateDef["description"] = resource.Description
+			}
+			if resource.MimeType != "" {
+				templateDef["mimeType"] = resource.MimeType
+			}
+			listedTemplates = append(listedTemplates, templateDef)
+		}
+		utils.OnMCPResponseSuccess(ctx, map[string]any{
+			"resourceTemplates": listedTemplates,
+		}, fmt.Sprintf("mcp:%s:resources/templates/list", serverName))
+		return nil
+	}
+
+	handlers["resources/read"] = func(ctx wrapper.HttpContext, id utils.JsonRpcID, params gjson.Result) error {
+		uri := params.Get("uri").String()
+		if uri == "" {
+			utils.OnMCPResponseError(ctx, errors.New("uri is required"), utils.ErrInvalidParams, fmt.Sprintf("mcp:%s:resources/read:invalid_uri", serverName))
+			return nil
+		}
+		resource, args, ok := restServer.MatchResource(uri)
+		if !ok {
+			utils.OnMCPResponseError(ctx, fmt.Errorf("resource not found: %s", uri), utils.ErrResourceNotFound, fmt.Sprintf("mcp:%s:resources/read:not_found", serverName))
+			return nil
+		}
+
+		proxywasm.SetProperty([]string{"mcp_server_name"}, []byte(serverName))
+		proxywasm.SetProperty([]string{"mcp_resource_uri"}, []byte(uri))
+
+		log.Debugf("Resource read [%s] on server [%s] with uri template args %v", uri, serverName, args)
+		reader := &RestMCPTool{
+			serverName: serverName,
+			name:       resource.Name,
+			toolConfig: resource.toolConfig,
+			arguments:  args,
+			responder: resourceResponder{
+				serverName: serverName,
+				uri:        uri,
+				mimeType:   resource.MimeType,
+			},
+		}
+		if err := reader.Call(ctx, restServer); err != nil {
+			reader.responder.sendError(ctx, err)
+		}
+		return nil
+	}
+
+	return handlers
+}
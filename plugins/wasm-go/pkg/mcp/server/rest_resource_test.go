@@ -0,0 +1,186 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestRestResourceParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		resource    RestResource
+		expectError bool
+		isTemplate  bool
+		uriVars     []string
+	}{
+		{
+			name: "fixed uri",
+			resource: RestResource{
+				URI:  "weather://cities",
+				Name: "cities",
+				RequestTemplate: RestToolRequestTemplate{
+					URL:    "https://api.example.com/cities",
+					Method: "GET",
+				},
+			},
+		},
+		{
+			name: "uri template",
+			resource: RestResource{
+				URI:  "weather://cities/{city}/days/{day}",
+				Name: "forecast",
+				RequestTemplate: RestToolRequestTemplate{
+					URL:    "https://api.example.com/forecast?city={{.args.city}}&day={{.args.day}}",
+					Method: "GET",
+				},
+			},
+			isTemplate: true,
+			uriVars:    []string{"city", "day"},
+		},
+		{
+			name: "direct response",
+			resource: RestResource{
+				URI:              "docs://readme",
+				Name:             "readme",
+				ResponseTemplate: RestToolResponseTemplate{Body: "# Readme"},
+			},
+		},
+		{
+			name:        "missing uri",
+			resource:    RestResource{Name: "no-uri"},
+			expectError: true,
+		},
+		{
+			name:        "missing name",
+			resource:    RestResource{URI: "docs://readme", ResponseTemplate: RestToolResponseTemplate{Body: "# Readme"}},
+			expectError: true,
+		},
+		{
+			name: "unsupported uri template expression",
+			resource: RestResource{
+				URI:              "docs://{+path}",
+				Name:             "docs",
+				ResponseTemplate: RestToolResponseTemplate{Body: "doc"},
+			},
+			expectError: true,
+		},
+		{
+			name: "neither request template nor response body",
+			resource: RestResource{
+				URI:  "docs://readme",
+				Name: "readme",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.resource.parse()
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.isTemplate, tt.resource.IsTemplate())
+			assert.Equal(t, tt.uriVars, tt.resource.uriVars)
+		})
+	}
+}
+
+func TestRestServerMatchResource(t *testing.T) {
+	server := NewRestMCPServer("weather")
+	resources := []RestResource{
+		{
+			URI:              "weather://cities/{city}",
+			Name:             "city",
+			ResponseTemplate: RestToolResponseTemplate{Body: "{{.args.city}}"},
+		},
+		{
+			URI:              "weather://cities/default",
+			Name:             "default-city",
+			ResponseTemplate: RestToolResponseTemplate{Body: "hangzhou"},
+		},
+	}
+	for _, resource := range resources {
+		assert.NoError(t, server.AddRestResource(resource))
+	}
+	assert.Error(t, server.AddRestResource(resources[1]), "duplicate uri should be rejected")
+
+	// Fixed URIs take precedence over URI templates
+	resource, args, ok := server.MatchResource("weather://cities/default")
+	assert.True(t, ok)
+	assert.Equal(t, "default-city", resource.Name)
+	assert.Empty(t, args)
+
+	resource, args, ok = server.MatchResource("weather://cities/beijing")
+	assert.True(t, ok)
+	assert.Equal(t, "city", resource.Name)
+	assert.Equal(t, map[string]interface{}{"city": "beijing"}, args)
+
+	_, _, ok = server.MatchResource("weather://cities/beijing/today")
+	assert.False(t, ok)
+	_, _, ok = server.MatchResource("weather://countries")
+	assert.False(t, ok)
+
+	// Resources are kept by the cloned server
+	cloned := server.Clone().(*RestMCPServer)
+	assert.Len(t, cloned.GetResources(), 2)
+}
+
+func TestParseConfigWithResourcesAndPrompts(t *testing.T) {
+	configJson := gjson.Parse(`{
+		"server": {"name": "weather"},
+		"resources": [
+			{
+				"uri": "weather://cities/{city}",
+				"name": "city-weather",
+				"mimeType": "application/json",
+				"requestTemplate": {"url": "https://api.example.com/weather?city={{.args.city}}", "method": "GET"}
+			}
+		],
+		"prompts": [
+			{
+				"name": "plan-trip",
+				"arguments": [{"name": "city", "required": true}],
+				"messages": [{"role": "user", "content": "Plan a trip to {{.args.city}}"}]
+			}
+		]
+	}`)
+	config := &McpServerConfig{}
+	registry := &GlobalToolRegistry{}
+	registry.Initialize()
+	err := ParseConfigCore(configJson, config, &ConfigOptions{ToolRegistry: registry})
+	assert.NoError(t, err)
+
+	restServer, ok := config.server.(*RestMCPServer)
+	assert.True(t, ok, "server without tools should still be a REST server")
+	assert.Len(t, restServer.GetResources(), 1)
+	assert.Len(t, restServer.GetPrompts(), 1)
+	for _, method := range []string{"resources/list", "resources/templates/list", "resources/read", "prompts/list", "prompts/get"} {
+		assert.NotNil(t, config.methodHandlers[method], "handler of %s should be registered", method)
+	}
+
+	// An invalid prompt fails the config parsing
+	configJson = gjson.Parse(`{
+		"server": {"name": "weather"},
+		"prompts": [{"name": "empty"}]
+	}`)
+	err = ParseConfigCore(configJson, &McpServerConfig{}, &ConfigOptions{ToolRegistry: registry})
+	assert.Error(t, err)
+}
@@ -259,6 +259,8 @@ type RestMCPServer struct {
 	defaultDownstreamSecurity SecurityRequirement // Default client-to-gateway authentication
 	defaultUpstreamSecurity   SecurityRequirement // Default gateway-to-backend authentication
 	passthroughAuthHeader     bool                // If true, pass through Authorization header even without downstream security
+	resources                 []RestResource      // Resources in the order of configuration, fixed URIs and URI templates
+	prompts                   []RestPrompt        // Prompt templates in the order of configuration
 }
 
 // NewRestMCPServer creates a new REST-to-MCP server
@@ -327,6 +329,17 @@ func (s *RestMCPServer) AddRestTool(toolConfig RestTool) error {
 	if err := toolConfig.parseTemplates(); err != nil {
 		return err
 	}
+	if !toolConfig.isDirectResponseTool {
+		upstreamSecurity := toolConfig.RequestTemplate.Security
+		if upstreamSecurity.ID == "" {
+			upstreamSecurity = s.defaultUpstreamSecurity
+		}
+		if scheme, ok := s.GetSecurityScheme(upstreamSecurity.ID); ok && scheme.Type == "oauth2" {
+			if err := validateOAuth2TokenService(scheme, toolConfig.RequestTemplate.URL); err != nil {
+				return fmt.Errorf("invalid tool %s: %v", toolConfig.Name, err)
+			}
+		}
+	}
 
 	s.toolsConfig[toolConfig.Name] = toolConfig
 	s.base.AddMCPTool(toolConfig.Name, &RestMCPTool{
@@ -364,6 +377,8 @@ func (s *RestMCPServer) Clone() Server {
 	for k, v := range s.toolsConfig {
 		newServer.toolsConfig[k] = v
 	}
+	newServer.resources = append(newServer.resources, s.resources...)
+	newServer.prompts = append(newServer.prompts, s.prompts...)
 	// Deep copy securitySchemes
 	if s.securitySchemes != nil {
 		for k, v := range s.securitySchemes {
@@ -385,6 +400,47 @@ type RestMCPTool struct {
 	name       string
 	toolConfig RestTool
 	arguments  map[string]interface{}
+	// responder sends the result of the call, defaults to the tools/call result.
+	// REST resources are read through RestMCPTool with a different responder.
+	responder restResponder
+}
+
+// restResponder sends the result of a REST call back to the MCP client in the format of the method being served
+type restResponder interface {
+	sendError(ctx wrapper.HttpContext, err error)
+	sendText(ctx wrapper.HttpContext, text string, structuredContent json.RawMessage)
+	sendImage(ctx wrapper.HttpContext, image []byte, contentType string)
+}
+
+// toolResponder sends the result of a REST call as the result of tools/call
+type toolResponder struct {
+	serverName string
+	name       string
+}
+
+func (r toolResponder) sendError(ctx wrapper.HttpContext, err error) {
+	utils.OnMCPToolCallError(ctx, err)
+}
+
+func (r toolResponder) sendText(ctx wrapper.HttpContext, text string, structuredContent json.RawMessage) {
+	debugInfo := fmt.Sprintf("mcp:tools/call:%s/%s:result", r.serverName, r.name)
+	if structuredContent != nil {
+		utils.SendMCPToolTextResultWithStructuredContent(ctx, text, structuredContent, debugInfo)
+	} else {
+		utils.SendMCPToolTextResult(ctx, text, debugInfo)
+	}
+}
+
+func (r toolResponder) sendImage(ctx wrapper.HttpContext, image []byte, contentType string) {
+	utils.SendMCPToolImageResult(ctx, image, contentType, fmt.Sprintf("mcp:tools/call:%s/%s:result", r.serverName, r.name))
+}
+
+// getResponder returns the responder of the call
+func (t *RestMCPTool) getResponder() restResponder {
+	if t.responder != nil {
+		return t.responder
+	}
+	return toolResponder{serverName: t.serverName, name: t.name}
 }
 
 // Create implements Tool interface
@@ -394,6 +450,7 @@ func (t *RestMCPTool) Create(params []byte) Tool {
 		name:       t.name,
 		toolConfig: t.toolConfig,
 		arguments:  make(map[string]interface{}),
+		responder:  t.responder,
 	}
 
 	// Parse raw arguments
@@ -501,7 +558,12 @@ func (t *RestMCPTool) applySecurity(serverObj Server, reqCtx *AuthRequestContext
 		return errors.New("server is not a RestMCPServer")
 	}
 
-	// Determine which upstream security to use: tool-level or server's default
+	// Apply security using the determined configuration
+	return ApplySecurity(t.getUpstreamSecurity(restServer), restServer, reqCtx)
+}
+
+// getUpstreamSecurity determines which upstream security to use: tool-level or server's default
+func (t *RestMCPTool) getUpstreamSecurity(restServer *RestMCPServer) SecurityRequirement {
 	var upstreamSecurity SecurityRequirement
 	if t.toolConfig.RequestTemplate.Security.ID != "" {
 		// Use tool-level upstream security if configured
@@ -514,9 +576,7 @@ func (t *RestMCPTool) applySecurity(serverObj Server, reqCtx *AuthRequestContext
 			log.Debugf("Using default upstream security for tool %s: %s", t.name, upstreamSecurity.ID)
 		}
 	}
-
-	// Apply security using the determined configuration
-	return ApplySecurity(upstreamSecurity, restServer, reqCtx)
+	return upstreamSecurity
 }
 
 // Call implements Tool interface
@@ -595,11 +655,7 @@ func (t *RestMCPTool) Call(httpCtx HttpContext, server Server) error {
 		}
 
 		// Send the result using structured content if available
-		if structuredContent != nil {
-			utils.SendMCPToolTextResultWithStructuredContent(ctx, result, structuredContent, fmt.Sprintf("mcp:tools/call:%s/%s:result", t.serverName, t.name))
-		} else {
-			utils.SendMCPToolTextResult(ctx, result, fmt.Sprintf("mcp:tools/call:%s/%s:result", t.serverName, t.name))
-		}
+		t.getResponder().sendText(ctx, result, structuredContent)
 		return nil
 	}
 
@@ -836,13 +892,42 @@ func (t *RestMCPTool) Call(httpCtx HttpContext, server Server) error {
 		RequestBody:           requestBody,
 		PassthroughCredential: passthroughCredential,
 	}
-	if err := t.applySecurity(server, &authReqCtx); err != nil {
+	upstreamSecurity := t.getUpstreamSecurity(restServer)
+	if upstreamScheme, ok := restServer.GetSecurityScheme(upstreamSecurity.ID); ok && upstreamScheme.Type == "oauth2" {
+		// The access token may have to be fetched from the token endpoint before making the call
+		async, err := resolveOAuth2Token(upstreamScheme, passthroughCredential, func(accessToken string, err error) {
+			if err != nil {
+				log.Errorf("Failed to get access token for tool %s: %v", t.name, err)
+				t.getResponder().sendError(ctx, err)
+				return
+			}
+			authReqCtx.PassthroughCredential = ""
+			upstreamSecurity.Credential = accessToken
+			t.sendRequest(ctx, restServer, upstreamSecurity, &authReqCtx)
+		})
+		if err != nil {
+			return err
+		}
+		if async {
+			ctx.SetContext(utils.CtxNeedPause, true)
+			ctx.SetContext(ctxOAuth2TokenPending, true)
+		}
+		return nil
+	}
+	t.sendRequest(ctx, restServer, upstreamSecurity, &authReqCtx)
+	return nil
+}
+
+// sendRequest applies the upstream security and calls the REST API with the request built by Call
+func (t *RestMCPTool) sendRequest(ctx wrapper.HttpContext, restServer *RestMCPServer, upstreamSecurity SecurityRequirement, authReqCtx *AuthRequestContext) {
+	if err := ApplySecurity(upstreamSecurity, restServer, authReqCtx); err != nil {
 		// Log the error and continue, rather than failing the entire call.
 		// The request will proceed without the intended security modifications if applySecurity failed.
 		log.Errorf("Failed to apply security scheme for tool %s: %v. Request will proceed with potentially incomplete authentication.", t.name, err)
 	}
 	// After applySecurity, authReqCtx.Headers and authReqCtx.ParsedURL (RawQuery) might have been modified.
-	// Update urlStr from the potentially modified ParsedURL.
+	// Build urlStr from the potentially modified ParsedURL.
+	var urlStr string
 	u := authReqCtx.ParsedURL
 	encodedPath := u.EscapedPath()
 	if u.Scheme != "" && u.Host != "" {
@@ -856,8 +941,9 @@ func (t *RestMCPTool) Call(httpCtx HttpContext, server Server) error {
 	if u.Fragment != "" {
 		urlStr += "#" + u.Fragment
 	}
+	responder := t.getResponder()
 	// Make HTTP request using potentially modified headers from authReqCtx
-	err = ctx.RouteCall(authReqCtx.Method, urlStr, authReqCtx.Headers, authReqCtx.RequestBody,
+	err := ctx.RouteCall(authReqCtx.Method, urlStr, authReqCtx.Headers, authReqCtx.RequestBody,
 		func(statusCode int, responseHeaders [][2]string, responseBody []byte) {
 
 			if statusCode >= 300 || statusCode < 200 {
@@ -871,15 +957,15 @@ func (t *RestMCPTool) Call(httpCtx HttpContext, server Server) error {
 					errorResponseTemplateDataBytes, _ := sjson.SetBytes(responseBody, "_headers", convertHeaders(responseHeaders))
 					errorTemplateResult, err := executeTemplate(t.toolConfig.parsedErrorResponseTemplate, errorResponseTemplateDataBytes)
 					if err != nil {
-						utils.OnMCPToolCallError(ctx, fmt.Errorf("error executing error response template: %v", err))
+						responder.sendError(ctx, fmt.Errorf("error executing error response template: %v", err))
 						return
 					}
 					if errorTemplateResult != "" {
-						utils.OnMCPToolCallError(ctx, fmt.Errorf("%s", errorTemplateResult))
+						responder.sendError(ctx, fmt.Errorf("%s", errorTemplateResult))
 						return
 					}
 				}
-				utils.OnMCPToolCallError(ctx, fmt.Errorf("call failed, status: %d, response: %s", statusCode, responseBody))
+				responder.sendError(ctx, fmt.Errorf("call failed, status: %d, response: %s", statusCode, responseBody))
 				return
 			}
 
@@ -891,7 +977,7 @@ func (t *RestMCPTool) Call(httpCtx HttpContext, server Server) error {
 			// Check if the response is an image
 			if strings.HasPrefix(contentType, "image/") {
 				// Handle image response by sending it as an MCP tool result
-				utils.SendMCPToolImageResult(ctx, responseBody, contentType, fmt.Sprintf("mcp:tools/call:%s/%s:result", t.serverName, t.name))
+				responder.sendImage(ctx, responseBody, contentType)
 				return
 			}
 
@@ -899,7 +985,7 @@ func (t *RestMCPTool) Call(httpCtx HttpContext, server Server) error {
 			if t.toolConfig.parsedResponseTemplate != nil {
 				templateResult, err := executeTemplate(t.toolConfig.parsedResponseTemplate, responseBody)
 				if err != nil {
-					utils.OnMCPToolCallError(ctx, fmt.Errorf("error executing response template: %v", err))
+					responder.sendError(ctx, fmt.Errorf("error executing response template: %v", err))
 					return
 				}
 				result = templateResult
@@ -932,17 +1018,18 @@ func (t *RestMCPTool) Call(httpCtx HttpContext, server Server) error {
 			}
 
 			// Send the result using structured content if available
-			if structuredContent != nil {
-				utils.SendMCPToolTextResultWithStructuredContent(ctx, result, structuredContent, fmt.Sprintf("mcp:tools/call:%s/%s:result", t.serverName, t.name))
-			} else {
-				utils.SendMCPToolTextResult(ctx, result, fmt.Sprintf("mcp:tools/call:%s/%s:result", t.serverName, t.name))
-			}
+			responder.sendText(ctx, result, structuredContent)
 		})
 	if err != nil {
-		utils.OnMCPToolCallError(ctx, errors.New("route failed"))
+		responder.sendError(ctx, errors.New("route failed"))
 		log.Errorf("call api failed, err:%v", err)
+		return
+	}
+	// The request is paused while fetching the access token, resume it to make the call
+	if pending, _ := ctx.GetContext(ctxOAuth2TokenPending).(bool); pending {
+		ctx.SetContext(ctxOAuth2TokenPending, false)
+		proxywasm.ResumeHttpRequest()
 	}
-	return nil
 }
 
 // Description implements Tool interface
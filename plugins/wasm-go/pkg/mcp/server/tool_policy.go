@@ -0,0 +1,201 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/higress-group/proxy-wasm-go-sdk/proxywasm"
+	"github.com/higress-group/wasm-go/pkg/wrapper"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// DefaultToolPolicyKey is the key of the policy applied to the tools without their own policy
+	DefaultToolPolicyKey = "*"
+
+	// ConsumerHeader is set by the auth plugins (key-auth, jwt-auth, etc.) with the name of the authenticated consumer
+	ConsumerHeader = "x-mse-consumer"
+
+	// CtxToolPolicyFilter is the context key of the tools/list filter built from the tool policies
+	CtxToolPolicyFilter = "mcp_tool_policy_filter"
+)
+
+// ToolPolicyRule matches the callers of a tool. A caller matches the rule if it matches any of the
+// consumers, groups or claims.
+type ToolPolicyRule struct {
+	Consumers []string `json:"consumers,omitempty"` // Names of the consumers
+	Groups    []string `json:"groups,omitempty"`    // Groups of the consumers, see consumerGroups of the server
+	// Claims verified by the auth plugin and forwarded in the claims header of the server, keyed by the claim path
+	// (e.g. "realm_access.roles"), the rule matches if the claim (or any element of an array claim) equals one of the values
+	Claims map[string][]string `json:"claims,omitempty"`
+}
+
+// ToolPolicy defines who can list and call a tool. Callers matching deny are rejected; if allow is set,
+// only callers matching it are accepted.
+type ToolPolicy struct {
+	Allow *ToolPolicyRule `json:"allow,omitempty"`
+	Deny  *ToolPolicyRule `json:"deny,omitempty"`
+}
+
+// ToolCaller is the identity of the caller used to evaluate the tool policies
+type ToolCaller struct {
+	Consumer string
+	Groups   []string
+	Claims   gjson.Result
+}
+
+// ToolAuthorizer evaluates the tool policies of a server
+type ToolAuthorizer struct {
+	policies       map[string]ToolPolicy
+	consumerGroups map[string][]string // consumer name -> groups
+	// claimsHeader is the header carrying the JWT payload verified by the auth plugin in front of the server,
+	// e.g. the outputPayloadToHeader of the RequestAuthentication. The MCP server does not verify the JWT itself,
+	// so the claims of the Authorization header are never trusted.
+	claimsHeader string
+}
+
+// NewToolAuthorizer validates the policies and creates the authorizer, consumerGroups maps consumer names to their groups,
+// and claimsHeader is the header carrying the verified claims, which is required by the claim rules
+func NewToolAuthorizer(policies map[string]ToolPolicy, consumerGroups map[string][]string, claimsHeader string) (*ToolAuthorizer, error) {
+	for toolName, policy := range policies {
+		if toolName == "" {
+			return nil, fmt.Errorf("tool name of tool policy cannot be empty")
+		}
+		for _, rule := range []*ToolPolicyRule{policy.Allow, policy.Deny} {
+			if rule == nil {
+				continue
+			}
+			for claim := range rule.Claims {
+				if claim == "" {
+					return nil, fmt.Errorf("claim name cannot be empty in the policy of tool %s", toolName)
+				}
+			}
+			if len(rule.Claims) > 0 && claimsHeader == "" {
+				return nil, fmt.Errorf("claimsHeader is required by the claim rules in the policy of tool %s", toolName)
+			}
+		}
+	}
+	return &ToolAuthorizer{
+		policies:       policies,
+		consumerGroups: consumerGroups,
+		claimsHeader:   strings.ToLower(claimsHeader),
+	}, nil
+}
+
+// NewCaller builds the caller identity from the consumer name and the value of the claims header
+func (a *ToolAuthorizer) NewCaller(consumer string, claims string) ToolCaller {
+	return ToolCaller{
+		Consumer: consumer,
+		Groups:   a.consumerGroups[consumer],
+		Claims:   parseClaims(claims),
+	}
+}
+
+// Allowed checks whether the caller can list and call the tool
+func (a *ToolAuthorizer) Allowed(toolName string, caller ToolCaller) bool {
+	policy, ok := a.policies[toolName]
+	if !ok {
+		if policy, ok = a.policies[DefaultToolPolicyKey]; !ok {
+			return true
+		}
+	}
+	return policy.allows(caller)
+}
+
+func (p ToolPolicy) allows(caller ToolCaller) bool {
+	if p.Deny != nil && p.Deny.matches(caller) {
+		return false
+	}
+	return p.Allow == nil || p.Allow.matches(caller)
+}
+
+func (r *ToolPolicyRule) matches(caller ToolCaller) bool {
+	if caller.Consumer != "" {
+		for _, consumer := range r.Consumers {
+			if consumer == caller.Consumer {
+				return true
+			}
+		}
+	}
+	for _, group := range r.Groups {
+		for _, callerGroup := range caller.Groups {
+			if group == callerGroup {
+				return true
+			}
+		}
+	}
+	if !caller.Claims.Exists() {
+		return false
+	}
+	for claim, values := range r.Claims {
+		claimValue := caller.Claims.Get(claim)
+		claimValues := []gjson.Result{claimValue}
+		if claimValue.IsArray() {
+			claimValues = claimValue.Array()
+		}
+		for _, v := range claimValues {
+			if !v.Exists() {
+				continue
+			}
+			for _, value := range values {
+				if v.String() == value {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// parseClaims parses the verified JWT payload forwarded by the auth plugin, which is either a JSON object
+// or a base64url encoded one
+func parseClaims(value string) gjson.Result {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return gjson.Result{}
+	}
+	payload := []byte(value)
+	if !gjson.ValidBytes(payload) {
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+		if err != nil || !gjson.ValidBytes(decoded) {
+			return gjson.Result{}
+		}
+		payload = decoded
+	}
+	claims := gjson.ParseBytes(payload)
+	if !claims.IsObject() {
+		return gjson.Result{}
+	}
+	return claims
+}
+
+// getToolCaller builds the caller identity from the current request
+func (a *ToolAuthorizer) getToolCaller() ToolCaller {
+	consumer, _ := proxywasm.GetHttpRequestHeader(ConsumerHeader)
+	var claims string
+	if a.claimsHeader != "" {
+		claims, _ = proxywasm.GetHttpRequestHeader(a.claimsHeader)
+	}
+	return a.NewCaller(consumer, claims)
+}
+
+// toolAllowedByPolicy checks the tool against the filter stored in the context by the tools/list handler
+func toolAllowedByPolicy(ctx wrapper.HttpContext, toolName string) bool {
+	filter, ok := ctx.GetContext(CtxToolPolicyFilter).(func(string) bool)
+	return !ok || filter(toolName)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func encodedClaims(payload string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(payload))
+}
+
+func TestParseClaims(t *testing.T) {
+	payload := `{"sub":"alice","realm_access":{"roles":["admin","user"]}}`
+	for _, value := range []string{payload, encodedClaims(payload), base64.URLEncoding.EncodeToString([]byte(payload))} {
+		claims := parseClaims(value)
+		assert.Equal(t, "alice", claims.Get("sub").String())
+		assert.Equal(t, 2, len(claims.Get("realm_access.roles").Array()))
+	}
+
+	assert.False(t, parseClaims("").Exists())
+	assert.False(t, parseClaims(`["admin"]`).Exists())
+	assert.False(t, parseClaims("!!!").Exists())
+	// A raw JWT is not accepted as claims, the claims header must carry the verified payload only
+	assert.False(t, parseClaims("eyJhbGciOiJIUzI1NiJ9."+encodedClaims(payload)+".c2lnbmF0dXJl").Exists())
+}
+
+func TestToolAuthorizer(t *testing.T) {
+	authorizer, err := NewToolAuthorizer(map[string]ToolPolicy{
+		"delete_user": {
+			Allow: &ToolPolicyRule{Groups: []string{"admin"}, Claims: map[string][]string{"realm_access.roles": {"admin"}}},
+		},
+		"get_user": {
+			Deny: &ToolPolicyRule{Consumers: []string{"guest"}},
+		},
+		DefaultToolPolicyKey: {
+			Allow: &ToolPolicyRule{Consumers: []string{"alice", "bob", "guest"}},
+		},
+	}, map[string][]string{
+		"alice": {"admin"},
+		"bob":   {"dev"},
+	}, "x-jwt-payload")
+	assert.NoError(t, err)
+
+	alice := authorizer.NewCaller("alice", "")
+	bob := authorizer.NewCaller("bob", "")
+	guest := authorizer.NewCaller("guest", "")
+	anonymous := authorizer.NewCaller("", "")
+	jwtAdmin := authorizer.NewCaller("", encodedClaims(`{"sub":"carol","realm_access":{"roles":["admin"]}}`))
+	jwtUser := authorizer.NewCaller("", encodedClaims(`{"sub":"dave","realm_access":{"roles":["user"]}}`))
+
+	tests := []struct {
+		name     string
+		tool     string
+		caller   ToolCaller
+		expected bool
+	}{
+		{"allowed by group", "delete_user", alice, true},
+		{"not in allowed group", "delete_user", bob, false},
+		{"allowed by claim", "delete_user", jwtAdmin, true},
+		{"claim not matched", "delete_user", jwtUser, false},
+		{"not denied", "get_user", bob, true},
+		{"not denied without identity", "get_user", anonymous, true},
+		{"denied by consumer", "get_user", guest, false},
+		{"default policy allows", "list_users", guest, true},
+		{"default policy rejects", "list_users", anonymous, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, authorizer.Allowed(tt.tool, tt.caller))
+		})
+	}
+
+	// Deny takes precedence over allow
+	policy := ToolPolicy{
+		Allow: &ToolPolicyRule{Groups: []string{"admin"}},
+		Deny:  &ToolPolicyRule{Consumers: []string{"alice"}},
+	}
+	assert.False(t, policy.allows(alice))
+	assert.False(t, policy.allows(ToolCaller{Claims: gjson.Parse(`{"sub":"alice"}`)}))
+}
+
+func TestNewToolAuthorizerValidation(t *testing.T) {
+	_, err := NewToolAuthorizer(map[string]ToolPolicy{"": {}}, nil, "")
+	assert.Error(t, err)
+
+	_, err = NewToolAuthorizer(map[string]ToolPolicy{
+		"get_user": {Deny: &ToolPolicyRule{Claims: map[string][]string{"": {"x"}}}},
+	}, nil, "x-jwt-payload")
+	assert.Error(t, err)
+
+	// Claims can only be read from the header set by the auth plugin which verifies the token
+	_, err = NewToolAuthorizer(map[string]ToolPolicy{
+		"get_user": {Allow: &ToolPolicyRule{Claims: map[string][]string{"roles": {"admin"}}}},
+	}, nil, "")
+	assert.ErrorContains(t, err, "claimsHeader is required")
+}
+
+func TestParseConfigWithToolPolicies(t *testing.T) {
+	configJson := gjson.Parse(`{
+		"server": {
+			"name": "users",
+			"toolPolicies": {
+				"delete_user": {"allow": {"groups": ["admin"]}}
+			},
+			"consumerGroups": {"alice": ["admin"]}
+		},
+		"tools": [
+			{
+				"name": "delete_user",
+				"description": "Delete a user",
+				"requestTemplate": {"url": "https://api.example.com/users/{{.args.id}}", "method": "DELETE"}
+			}
+		]
+	}`)
+	config := &McpServerConfig{}
+	registry := &GlobalToolRegistry{}
+	registry.Initialize()
+	err := ParseConfigCore(configJson, config, &ConfigOptions{ToolRegistry: registry})
+	assert.NoError(t, err)
+	assert.NotNil(t, config.toolAuthorizer)
+	assert.True(t, config.toolAuthorizer.Allowed("delete_user", config.toolAuthorizer.NewCaller("alice", "")))
+	assert.False(t, config.toolAuthorizer.Allowed("delete_user", config.toolAuthorizer.NewCaller("bob", "")))
+
+	configJson = gjson.Parse(`{
+		"server": {"name": "users", "toolPolicies": {"delete_user": {"allow": {"groups": "admin"}}}},
+		"tools": [{"name": "delete_user", "requestTemplate": {"url": "https://api.example.com/users", "method": "DELETE"}}]
+	}`)
+	err = ParseConfigCore(configJson, &McpServerConfig{}, &ConfigOptions{ToolRegistry: registry})
+	assert.Error(t, err)
+
+	configJson = gjson.Parse(`{
+		"server": {
+			"name": "users",
+			"toolPolicies": {"delete_user": {"allow": {"claims": {"roles": ["admin"]}}}},
+			"claimsHeader": "X-Jwt-Payload"
+		},
+		"tools": [{"name": "delete_user", "requestTemplate": {"url": "https://api.example.com/users", "method": "DELETE"}}]
+	}`)
+	config = &McpServerConfig{}
+	err = ParseConfigCore(configJson, config, &ConfigOptions{ToolRegistry: registry})
+	assert.NoError(t, err)
+	assert.Equal(t, "x-jwt-payload", config.toolAuthorizer.claimsHeader)
+	assert.True(t, config.toolAuthorizer.Allowed("delete_user", config.toolAuthorizer.NewCaller("", `{"roles":["admin"]}`)))
+}
@@ -43,6 +43,9 @@ const (
 	ErrMethodNotFound = -32601
 	ErrInvalidParams  = -32602
 	ErrInternalError  = -32603
+
+	// ErrResourceNotFound is the MCP specific error code for resources/read with an unknown URI
+	ErrResourceNotFound = -32002
 )
 
 // JsonRpcID represents a JSON-RPC ID which can be either a string or a number
@@ -16,6 +16,7 @@ use crate::error::WasmRustError;
 use crate::internal::{get_http_request_header, get_property};
 use crate::log::Log;
 use proxy_wasm::traits::RootContext;
+use regex::Regex;
 use serde::de::DeserializeOwned;
 use serde_json::{from_slice, Map, Value};
 use std::borrow::Borrow;
@@ -29,6 +30,7 @@ enum Category {
     Host,
     RoutePrefix,
     Service,
+    Any,
 }
 
 #[derive(PartialEq)]
@@ -43,6 +45,9 @@ const MATCH_ROUTE_KEY: &str = "_match_route_";
 const MATCH_DOMAIN_KEY: &str = "_match_domain_";
 const MATCH_SERVICE_KEY: &str = "_match_service_";
 const MATCH_ROUTE_PREFIX_KEY: &str = "_match_route_prefix_";
+const MATCH_PATH_KEY: &str = "_match_path_";
+const MATCH_HEADER_KEY: &str = "_match_header_";
+const MATCH_METHOD_KEY: &str = "_match_method_";
 
 pub type SharedRuleMatcher<PluginConfig> = Rc<RefCell<RuleMatcher<PluginConfig>>>;
 
@@ -52,12 +57,68 @@ struct HostMatcher {
     host: String,
 }
 
+#[derive(Debug)]
+enum StringMatcher {
+    Exact(String),
+    Prefix(String),
+    Regex(Regex),
+}
+
+impl StringMatcher {
+    fn parse(config: &Value) -> Result<Option<Self>, WasmRustError> {
+        if let Some(exact) = config["exact"].as_str() {
+            return Ok(Some(StringMatcher::Exact(exact.to_string())));
+        }
+        if let Some(prefix) = config["prefix"].as_str() {
+            return Ok(Some(StringMatcher::Prefix(prefix.to_string())));
+        }
+        if let Some(regex) = config["regex"].as_str() {
+            // the regex needs to match the whole value
+            return match Regex::new(&format!("^(?:{})$", regex)) {
+                Ok(regex) => Ok(Some(StringMatcher::Regex(regex))),
+                Err(err) => Err(WasmRustError::new(format!(
+                    "invalid regex {}: {}",
+                    regex, err
+                ))),
+            };
+        }
+        Ok(None)
+    }
+    fn is_match(&self, value: &str) -> bool {
+        match self {
+            StringMatcher::Exact(exact) => value == exact,
+            StringMatcher::Prefix(prefix) => value.starts_with(prefix.as_str()),
+            StringMatcher::Regex(regex) => regex.is_match(value),
+        }
+    }
+}
+
+impl PartialEq for StringMatcher {
+    fn eq(&self, other: &Self) -> bool {
+        match (self, other) {
+            (StringMatcher::Exact(a), StringMatcher::Exact(b)) => a == b,
+            (StringMatcher::Prefix(a), StringMatcher::Prefix(b)) => a == b,
+            (StringMatcher::Regex(a), StringMatcher::Regex(b)) => a.as_str() == b.as_str(),
+            _ => false,
+        }
+    }
+}
+
+#[derive(PartialEq, Debug)]
+struct HeaderMatcher {
+    name: String,
+    value: Option<StringMatcher>,
+}
+
 struct RuleConfig<PluginConfig> {
     category: Category,
     routes: HashSet<String>,
     hosts: Vec<HostMatcher>,
     route_prefixes: HashSet<String>,
     services: HashSet<String>,
+    paths: Vec<StringMatcher>,
+    headers: Vec<HeaderMatcher>,
+    methods: HashSet<String>,
     config: Rc<PluginConfig>,
 }
 
@@ -132,17 +193,20 @@ where
             let services = RuleMatcher::<PluginConfig>::parse_service_match_config(rule_json);
             let route_prefixes =
                 RuleMatcher::<PluginConfig>::parse_route_prefix_match_config(rule_json);
+            let paths = RuleMatcher::<PluginConfig>::parse_path_match_config(rule_json)?;
+            let headers = RuleMatcher::<PluginConfig>::parse_header_match_config(rule_json)?;
+            let methods = RuleMatcher::<PluginConfig>::parse_method_match_config(rule_json);
 
             let no_routes = routes.is_empty();
             let no_hosts = hosts.is_empty();
             let no_service = services.is_empty();
             let no_route_prefix = route_prefixes.is_empty();
-            if [no_routes, no_hosts, no_service, no_route_prefix]
+            let no_request_match = paths.is_empty() && headers.is_empty() && methods.is_empty();
+            let empty_count = [no_routes, no_hosts, no_service, no_route_prefix]
                 .iter()
                 .filter(|&x| *x)
-                .count()
-                != 3
-            {
+                .count();
+            if empty_count != 3 && (empty_count != 4 || no_request_match) {
                 return Err(WasmRustError::new("there is only one of  '_match_route_', '_match_domain_', '_match_service_' and '_match_route_prefix_' can present in configuration.".to_string()));
             }
 
@@ -152,8 +216,10 @@ where
                 Category::Host
             } else if !no_service {
                 Category::Service
-            } else {
+            } else if !no_route_prefix {
                 Category::RoutePrefix
+            } else {
+                Category::Any
             };
 
             self.rule_config.push(RuleConfig {
@@ -162,6 +228,9 @@ where
                 hosts,
                 route_prefixes,
                 services,
+                paths,
+                headers,
+                methods,
                 config: Rc::new(config),
             })
         }
@@ -176,16 +245,23 @@ where
         let service_name =
             String::from_utf8(get_property(vec!["cluster_name"]).unwrap_or_default())
                 .unwrap_or_else(|_| "".to_string());
-        self.get_match_config_by_args(&host, &route_name, &service_name)
+        self.get_match_config_by_args(&host, &route_name, &service_name, get_http_request_header)
     }
     fn get_match_config_by_args(
         &self,
         host: &str,
         route_name: &str,
         service_name: &str,
+        get_header: impl Fn(&str) -> Option<String>,
     ) -> Option<(i64, Rc<PluginConfig>)> {
         for (i, rule) in self.rule_config.iter().enumerate() {
+            if !self.request_match(rule, &get_header) {
+                continue;
+            }
             match rule.category {
+                Category::Any => {
+                    return Some((i as i64, rule.config.clone()));
+                }
                 Category::Host => {
                     if self.host_match(rule, host) {
                         return Some((i as i64, rule.config.clone()));
@@ -248,6 +324,48 @@ where
         Self::parse_match_config(MATCH_ROUTE_PREFIX_KEY, config)
     }
 
+    fn parse_method_match_config(config: &Value) -> HashSet<String> {
+        Self::parse_match_config(MATCH_METHOD_KEY, config)
+            .into_iter()
+            .map(|method| method.to_uppercase())
+            .collect()
+    }
+    fn parse_path_match_config(config: &Value) -> Result<Vec<StringMatcher>, WasmRustError> {
+        let empty_vec = Vec::new();
+        let items = config[MATCH_PATH_KEY].as_array().unwrap_or(&empty_vec);
+        let mut path_matchers = Vec::new();
+        for item in items {
+            match StringMatcher::parse(item)? {
+                Some(path_matcher) => path_matchers.push(path_matcher),
+                None => {
+                    return Err(WasmRustError::new(format!(
+                        "invalid '{}' item: {}",
+                        MATCH_PATH_KEY, item
+                    )))
+                }
+            }
+        }
+        Ok(path_matchers)
+    }
+    fn parse_header_match_config(config: &Value) -> Result<Vec<HeaderMatcher>, WasmRustError> {
+        let empty_vec = Vec::new();
+        let items = config[MATCH_HEADER_KEY].as_array().unwrap_or(&empty_vec);
+        let mut header_matchers = Vec::new();
+        for item in items {
+            let name = item["name"].as_str().unwrap_or("");
+            if name.is_empty() {
+                return Err(WasmRustError::new(format!(
+                    "invalid '{}' item: {}",
+                    MATCH_HEADER_KEY, item
+                )));
+            }
+            header_matchers.push(HeaderMatcher {
+                name: name.to_lowercase(),
+                value: StringMatcher::parse(item)?,
+            });
+        }
+        Ok(header_matchers)
+    }
     fn parse_host_match_config(config: &Value) -> Vec<HostMatcher> {
         let empty_vec = Vec::new();
         let keys = config[MATCH_DOMAIN_KEY].as_array().unwrap_or(&empty_vec);
@@ -299,6 +417,38 @@ where
         }
         false
     }
+    fn request_match(
+        &self,
+        rule: &RuleConfig<PluginConfig>,
+        get_header: impl Fn(&str) -> Option<String>,
+    ) -> bool {
+        if !rule.methods.is_empty() {
+            let method = get_header(":method").unwrap_or_default();
+            if !rule.methods.contains(&method) {
+                return false;
+            }
+        }
+        if !rule.paths.is_empty() {
+            let path = get_header(":path").unwrap_or_default();
+            let path = path.split('?').next().unwrap_or_default();
+            if !rule.paths.iter().any(|matcher| matcher.is_match(path)) {
+                return false;
+            }
+        }
+        for header in &rule.headers {
+            match get_header(&header.name) {
+                Some(value) => {
+                    if let Some(matcher) = &header.value {
+                        if !matcher.is_match(&value) {
+                            return false;
+                        }
+                    }
+                }
+                None => return false,
+            }
+        }
+        true
+    }
     fn service_match(&self, rule: &RuleConfig<PluginConfig>, service_name: &str) -> bool {
         let parts = service_name.split("|").collect::<Vec<&str>>();
         if parts.len() != 4 {
@@ -389,6 +539,9 @@ mod tests {
                     hosts: Vec::default(),
                     route_prefixes: HashSet::default(),
                     services: HashSet::default(),
+                    paths: Vec::default(),
+                    headers: Vec::default(),
+                    methods: HashSet::default(),
                 },
             }
         }
@@ -411,6 +564,21 @@ mod tests {
             self.config.services.insert(service_name.to_string());
             self
         }
+        fn add_path(mut self, path_matcher: StringMatcher) -> Self {
+            self.config.paths.push(path_matcher);
+            self
+        }
+        fn add_header(mut self, name: &str, value: Option<StringMatcher>) -> Self {
+            self.config.headers.push(HeaderMatcher {
+                name: name.to_string(),
+                value,
+            });
+            self
+        }
+        fn add_method(mut self, method: &str) -> Self {
+            self.config.methods.insert(method.to_string());
+            self
+        }
         fn config(self) -> RuleConfig<Config> {
             self.config
         }
@@ -609,6 +777,9 @@ mod tests {
                     || s.hosts != o.hosts
                     || s.route_prefixes != o.route_prefixes
                     || s.services != o.services
+                    || s.paths != o.paths
+                    || s.headers != o.headers
+                    || s.methods != o.methods
                 {
                     return false;
                 }
@@ -629,7 +800,12 @@ mod tests {
                 .rule_config(RuleConfigBuilder::new(Category::Host, Rc::new(CustomConfig::new("john", 18))).add_host(MatchType::Suffix, ".example.com").add_host(MatchType::Prefix, "www.").add_host(MatchType::Suffix, "").add_host(MatchType::Exact, "www.abc.com").config())
                 .rule_config(RuleConfigBuilder::new(Category::Route, Rc::new(CustomConfig::new("ann", 16))).add_route("test1").add_route("test2").config())
                 .rule_config(RuleConfigBuilder::new(Category::Service, Rc::new(CustomConfig::new("ann", 16))).add_service("test1.dns").add_service("test2.static:8080").config())
-                .rule_config(RuleConfigBuilder::new(Category::RoutePrefix, Rc::new(CustomConfig::new("ann", 16))).add_route_prefix("api1").add_route_prefix("api2").config())
+                .rule_config(RuleConfigBuilder::new(Category::RoutePrefix, Rc::new(CustomConfig::new("ann", 16))).add_route_prefix("api1").add_route_prefix("api2").config()),
+            ParseTestCase::new("request match config", r#"{"_rules_":[{"_match_domain_":["www.abc.com"],"_match_path_":[{"prefix":"/admin/"},{"regex":"/api/v[0-9]+"}],"name":"john", "age":18},{"_match_header_":[{"name":"X-Env","exact":"gray"},{"name":"x-user"}],"_match_method_":["post"],"name":"ann", "age":16}]}"#, "")
+                .rule_config(RuleConfigBuilder::new(Category::Host, Rc::new(CustomConfig::new("john", 18))).add_host(MatchType::Exact, "www.abc.com").add_path(StringMatcher::Prefix("/admin/".to_string())).add_path(StringMatcher::Regex(Regex::new("^(?:/api/v[0-9]+)$").unwrap())).config())
+                .rule_config(RuleConfigBuilder::new(Category::Any, Rc::new(CustomConfig::new("ann", 16))).add_header("x-env", Some(StringMatcher::Exact("gray".to_string()))).add_header("x-user", None).add_method("POST").config()),
+            ParseTestCase::new("invalid path match", r#"{"_rules_":[{"_match_path_":[{"suffix":"/admin"}]}]}"#, r#"invalid '_match_path_' item: {"suffix":"/admin"}"#),
+            ParseTestCase::new("invalid header match", r#"{"_rules_":[{"_match_route_":["test"],"_match_header_":[{"exact":"gray"}]}]}"#, r#"invalid '_match_header_' item: {"exact":"gray"}"#),
         ];
         for case in &cases {
             println!("test {} start", case.name);
@@ -657,15 +833,15 @@ mod tests {
             .unwrap(),
         );
         assert!(res.is_ok());
-        let config = rule.get_match_config_by_args("test", "test", "test");
+        let config = rule.get_match_config_by_args("test", "test", "test", |_| None);
         assert!(config.is_none());
-        let config = rule.get_match_config_by_args("test", "test1", "test");
+        let config = rule.get_match_config_by_args("test", "test1", "test", |_| None);
         assert!(config.is_some());
         let c = config.unwrap();
         assert_eq!(c.1.name, "ann");
         assert_eq!(c.1.age, 16);
 
-        let config = rule.get_match_config_by_args("test", "test2", "test");
+        let config = rule.get_match_config_by_args("test", "test2", "test", |_| None);
         assert!(config.is_some());
         let c = config.unwrap();
         assert_eq!(c.1.name, "ann");
@@ -680,14 +856,70 @@ mod tests {
             &serde_json::from_str(r#"{"_rules_":[{"_match_route_":["test1"]}]}"#).unwrap(),
         );
         assert!(res.is_ok());
-        let config = rule.get_match_config_by_args("test", "test", "test");
+        let config = rule.get_match_config_by_args("test", "test", "test", |_| None);
         assert!(config.is_none());
-        let config = rule.get_match_config_by_args("test", "test1", "test");
+        let config = rule.get_match_config_by_args("test", "test1", "test", |_| None);
         assert!(config.is_some());
         let c = config.unwrap();
         assert_eq!(c.1.name, "");
         assert_eq!(c.1.age, 0);
     }
+    #[test]
+    fn test_match_request_config() {
+        let mut rule: RuleMatcher<CustomConfig> = RuleMatcher::default();
+
+        let res = rule.parse_rule_config(
+            &serde_json::from_str(
+                r#"{"_rules_":[{"_match_route_":["test1"],"_match_path_":[{"prefix":"/admin/"}],"_match_method_":["POST"],"name":"ann", "age":16},{"_match_header_":[{"name":"x-env","regex":"gray|blue"}],"name":"john", "age":18}]}"#,
+            )
+            .unwrap(),
+        );
+        assert!(res.is_ok());
+        let headers = |method: &'static str, path: &'static str, env: Option<&'static str>| {
+            move |name: &str| match name {
+                ":method" => Some(method.to_string()),
+                ":path" => Some(path.to_string()),
+                "x-env" => env.map(|env| env.to_string()),
+                _ => None,
+            }
+        };
+        let config = rule.get_match_config_by_args(
+            "test",
+            "test1",
+            "test",
+            headers("POST", "/admin/users?a=1", None),
+        );
+        assert_eq!(config.unwrap().1.name, "ann");
+        let config = rule.get_match_config_by_args(
+            "test",
+            "test1",
+            "test",
+            headers("GET", "/admin/users", None),
+        );
+        assert!(config.is_none());
+        let config = rule.get_match_config_by_args(
+            "test",
+            "test1",
+            "test",
+            headers("POST", "/public", None),
+        );
+        assert!(config.is_none());
+        let config = rule.get_match_config_by_args(
+            "test",
+            "test1",
+            "test",
+            headers("GET", "/admin/users", Some("gray")),
+        );
+        assert_eq!(config.unwrap().1.name, "john");
+        let config = rule.get_match_config_by_args(
+            "test",
+            "test2",
+            "test",
+            headers("GET", "/", Some("grayscale")),
+        );
+        assert!(config.is_none());
+    }
+
     #[derive(Default, Clone, Deserialize, PartialEq, Eq)]
     struct CompleteConfig {
         // global config
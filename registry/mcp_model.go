@@ -17,6 +17,9 @@ package registry
 const (
 	JsonGoTemplateType = "json-go-template"
 
+	// DefaultToolPolicyKey is the key of the policy applied to the tools without their own policy
+	DefaultToolPolicyKey = "*"
+
 	IstioMcpAutoGeneratedPrefix        = "istio-autogenerated-mcp"
 	IstioMcpAutoGeneratedVsName        = IstioMcpAutoGeneratedPrefix + "-vs"
 	IstioMcpAutoGeneratedSeName        = IstioMcpAutoGeneratedPrefix + "-se"
@@ -50,6 +53,23 @@ type ServerConfig struct {
 	Name            string                 `json:"name,omitempty"`
 	Config          map[string]interface{} `json:"config,omitempty"`
 	SecuritySchemes []*SecuritySchemes     `json:"securitySchemes,omitempty"`
+	ToolPolicies    map[string]*ToolPolicy `json:"toolPolicies,omitempty"`
+	ConsumerGroups  map[string][]string    `json:"consumerGroups,omitempty"`
+	ClaimsHeader    string                 `json:"claimsHeader,omitempty"`
+}
+
+// ToolPolicy defines which callers can list and call a tool, callers matching deny are rejected,
+// and only callers matching allow are accepted if allow is set
+type ToolPolicy struct {
+	Allow *ToolPolicyRule `json:"allow,omitempty"`
+	Deny  *ToolPolicyRule `json:"deny,omitempty"`
+}
+
+// ToolPolicyRule matches the callers by consumer name, consumer group or JWT claim
+type ToolPolicyRule struct {
+	Consumers []string            `json:"consumers,omitempty"`
+	Groups    []string            `json:"groups,omitempty"`
+	Claims    map[string][]string `json:"claims,omitempty"`
 }
 
 type McpTool struct {
@@ -134,9 +154,12 @@ type ServiceRef struct {
 
 // McpToolConfig Struct for mcp tool json unmarshal
 type McpToolConfig struct {
-	Tools           []*ToolDescription    `json:"tools,omitempty"`
-	ToolsMeta       map[string]*ToolsMeta `json:"toolsMeta,omitempty"`
-	SecuritySchemes []*SecuritySchemes    `json:"securitySchemes,omitempty"`
+	Tools             []*ToolDescription    `json:"tools,omitempty"`
+	ToolsMeta         map[string]*ToolsMeta `json:"toolsMeta,omitempty"`
+	SecuritySchemes   []*SecuritySchemes    `json:"securitySchemes,omitempty"`
+	DefaultToolPolicy *ToolPolicy           `json:"defaultToolPolicy,omitempty"`
+	ConsumerGroups    map[string][]string   `json:"consumerGroups,omitempty"`
+	ClaimsHeader      string                `json:"claimsHeader,omitempty"`
 }
 
 type SecuritySchemes struct {
@@ -164,6 +187,7 @@ type ToolsMeta struct {
 	InvokeContext map[string]string      `json:"invokeContext,omitempty"`
 	Enabled       bool                   `json:"enabled,omitempty"`
 	Templates     map[string]interface{} `json:"templates,omitempty"`
+	Policy        *ToolPolicy            `json:"policy,omitempty"`
 }
 
 type JsonGoTemplate struct {
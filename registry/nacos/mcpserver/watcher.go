@@ -433,6 +433,11 @@ func (w *watcher) processToolConfig(dataId, data string, credentials map[string]
 	if len(toolsDescription.SecuritySchemes) > 0 {
 		rule.Server.SecuritySchemes = toolsDescription.SecuritySchemes
 	}
+	// process tool policies
+	toolPolicies := map[string]*provider.ToolPolicy{}
+	if toolsDescription.DefaultToolPolicy != nil {
+		toolPolicies[provider.DefaultToolPolicyKey] = toolsDescription.DefaultToolPolicy
+	}
 
 	allowTools := []string{}
 	for _, t := range toolsDescription.Tools {
@@ -442,6 +447,9 @@ func (w *watcher) processToolConfig(dataId, data string, credentials map[string]
 		if toolMeta != nil && toolMeta.Enabled {
 			allowTools = append(allowTools, t.Name)
 		}
+		if toolMeta != nil && toolMeta.Policy != nil {
+			toolPolicies[t.Name] = toolMeta.Policy
+		}
 		argsPosition, err := getArgsPositionFromToolMeta(toolMeta)
 		if err != nil {
 			mcpServerLog.Errorf("get args position from tool meta error:%v, tool name %v", err, t.Name)
@@ -496,6 +504,11 @@ func (w *watcher) processToolConfig(dataId, data string, credentials map[string]
 	}
 
 	rule.AllowTools = allowTools
+	if len(toolPolicies) > 0 {
+		rule.Server.ToolPolicies = toolPolicies
+		rule.Server.ConsumerGroups = toolsDescription.ConsumerGroups
+		rule.Server.ClaimsHeader = toolsDescription.ClaimsHeader
+	}
 	wasmPluginConfig := &config.Config{
 		Meta: config.Meta{
 			GroupVersionKind: gvk.WasmPlugin,
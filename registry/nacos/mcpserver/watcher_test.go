@@ -28,6 +28,7 @@ import (
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/stretchr/testify/mock"
 	wrappers "google.golang.org/protobuf/types/known/wrapperspb"
+	extensions "istio.io/api/extensions/v1alpha1"
 	"istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/constants"
@@ -583,3 +584,60 @@ func Test_Watcher(t *testing.T) {
 		})
 	}
 }
+
+func Test_ProcessToolConfigWithPolicy(t *testing.T) {
+	localCache := memory.NewCache()
+	w := newTestWatcher(localCache)
+	server := &provider.McpServer{Name: "users", Protocol: provider.HttpProtocol}
+	toolsConfig := `{
+		"tools": [
+			{"name": "get_user", "description": "get user", "inputSchema": {"type": "object"}},
+			{"name": "delete_user", "description": "delete user", "inputSchema": {"type": "object"}}
+		],
+		"toolsMeta": {
+			"get_user": {"enabled": true},
+			"delete_user": {
+				"enabled": true,
+				"policy": {
+					"allow": {"groups": ["admin"], "claims": {"roles": ["admin"]}},
+					"deny": {"consumers": ["guest"]}
+				}
+			}
+		},
+		"defaultToolPolicy": {"deny": {"consumers": ["blocked"]}},
+		"consumerGroups": {"alice": ["admin"]},
+		"claimsHeader": "x-jwt-payload"
+	}`
+	if err := w.processToolConfig("users-mcp-tools.json", toolsConfig, nil, server); err != nil {
+		t.Fatalf("process tool config error: %v", err)
+	}
+
+	wasm := localCache.GetAllConfigs(gvk.WasmPlugin)["wasm"]
+	if wasm == nil {
+		t.Fatalf("wasm plugin config is not generated")
+	}
+	rule := wasm.Spec.(*extensions.WasmPlugin).PluginConfig.AsMap()["_rules_"].([]interface{})[0].(map[string]interface{})
+	serverConfig := rule["server"].(map[string]interface{})
+	wantPolicies := map[string]interface{}{
+		"delete_user": map[string]interface{}{
+			"allow": map[string]interface{}{
+				"groups": []interface{}{"admin"},
+				"claims": map[string]interface{}{"roles": []interface{}{"admin"}},
+			},
+			"deny": map[string]interface{}{"consumers": []interface{}{"guest"}},
+		},
+		provider.DefaultToolPolicyKey: map[string]interface{}{
+			"deny": map[string]interface{}{"consumers": []interface{}{"blocked"}},
+		},
+	}
+	if !reflect.DeepEqual(serverConfig["toolPolicies"], wantPolicies) {
+		t.Errorf("toolPolicies is not equal, want %v\n, got %v", wantPolicies, serverConfig["toolPolicies"])
+	}
+	wantGroups := map[string]interface{}{"alice": []interface{}{"admin"}}
+	if !reflect.DeepEqual(serverConfig["consumerGroups"], wantGroups) {
+		t.Errorf("consumerGroups is not equal, want %v\n, got %v", wantGroups, serverConfig["consumerGroups"])
+	}
+	if serverConfig["claimsHeader"] != "x-jwt-payload" {
+		t.Errorf("claimsHeader is not equal, want x-jwt-payload\n, got %v", serverConfig["claimsHeader"])
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+
+	apiv1 "github.com/alibaba/higress/v2/api/networking/v1"
+	provider "github.com/alibaba/higress/v2/registry"
+	"github.com/higress-group/openapi-to-mcpserver/pkg/converter"
+	"github.com/higress-group/openapi-to-mcpserver/pkg/models"
+	"github.com/higress-group/openapi-to-mcpserver/pkg/parser"
+	"gopkg.in/yaml.v3"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// convertedServer is the REST-to-MCP server generated from the specification
+type convertedServer struct {
+	Server *provider.ServerConfig `json:"server"`
+	Tools  []*provider.McpTool    `json:"tools"`
+}
+
+// convertSpec converts the OpenAPI specification to the tools of the server, then applies the
+// operation filters and description overrides of the source
+func convertSpec(serverName string, spec []byte, source *apiv1.OpenApiSource) (*convertedServer, error) {
+	if err := validatePatterns(source.GetIncludeOperations()); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns(source.GetExcludeOperations()); err != nil {
+		return nil, err
+	}
+
+	p := parser.NewParser()
+	p.SetValidation(true)
+	if err := p.Parse(spec); err != nil {
+		return nil, fmt.Errorf("parse openapi specification error: %v", err)
+	}
+	c := converter.NewConverter(p, models.ConvertOptions{
+		ServerName:     serverName,
+		ToolNamePrefix: source.GetToolNamePrefix(),
+	})
+	mcpConfig, err := c.Convert()
+	if err != nil {
+		return nil, fmt.Errorf("convert openapi specification error: %v", err)
+	}
+
+	// the models of the converter only carry yaml tags, so go through yaml to reuse the json tags of the registry models
+	yamlData, err := yaml.Marshal(mcpConfig)
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := k8syaml.YAMLToJSON(yamlData)
+	if err != nil {
+		return nil, err
+	}
+	converted := &convertedServer{}
+	if err = json.Unmarshal(jsonData, converted); err != nil {
+		return nil, fmt.Errorf("unmarshal converted mcp config error: %v", err)
+	}
+	if converted.Server == nil {
+		converted.Server = &provider.ServerConfig{}
+	}
+	converted.Server.Name = serverName
+
+	tools := make([]*provider.McpTool, 0, len(converted.Tools))
+	for _, tool := range converted.Tools {
+		if !operationIncluded(tool.Name, source.GetIncludeOperations(), source.GetExcludeOperations()) {
+			continue
+		}
+		if description, ok := source.GetDescriptionOverrides()[tool.Name]; ok {
+			tool.Description = description
+		}
+		tools = append(tools, tool)
+	}
+	converted.Tools = tools
+	return converted, nil
+}
+
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid operation pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func operationIncluded(name string, include, exclude []string) bool {
+	if len(include) > 0 && !matchAny(name, include) {
+		return false
+	}
+	return !matchAny(name, exclude)
+}
+
+func matchAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
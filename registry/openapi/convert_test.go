@@ -0,0 +1,141 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/alibaba/higress/v2/api/networking/v1"
+)
+
+const petstoreSpec = `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: A list of pets
+    post:
+      operationId: createPet
+      summary: Create a pet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '201':
+          description: Created
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      summary: Get a pet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: A pet
+    delete:
+      operationId: deletePet
+      summary: Delete a pet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: Deleted
+`
+
+func toolNames(converted *convertedServer) []string {
+	var names []string
+	for _, tool := range converted.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestConvertSpec(t *testing.T) {
+	converted, err := convertSpec("petstore", []byte(petstoreSpec), &apiv1.OpenApiSource{})
+	require.NoError(t, err)
+	assert.Equal(t, "petstore", converted.Server.Name)
+	assert.ElementsMatch(t, []string{"listPets", "createPet", "getPet", "deletePet"}, toolNames(converted))
+
+	for _, tool := range converted.Tools {
+		if tool.Name != "getPet" {
+			continue
+		}
+		require.NotNil(t, tool.RequestTemplate)
+		assert.Equal(t, "GET", tool.RequestTemplate.Method)
+		assert.Contains(t, tool.RequestTemplate.URL, "https://petstore.example.com/v1/pets/")
+		require.Len(t, tool.Args, 1)
+		assert.Equal(t, "petId", tool.Args[0].Name)
+		assert.Equal(t, "path", tool.Args[0].Position)
+		assert.True(t, tool.Args[0].Required)
+	}
+}
+
+func TestConvertSpecWithFilters(t *testing.T) {
+	converted, err := convertSpec("petstore", []byte(petstoreSpec), &apiv1.OpenApiSource{
+		ToolNamePrefix:    "pet_",
+		IncludeOperations: []string{"pet_*Pet", "pet_listPets"},
+		ExcludeOperations: []string{"pet_delete*"},
+		DescriptionOverrides: map[string]string{
+			"pet_getPet": "Get the pet by its id",
+		},
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"pet_listPets", "pet_createPet", "pet_getPet"}, toolNames(converted))
+	for _, tool := range converted.Tools {
+		if tool.Name == "pet_getPet" {
+			assert.Equal(t, "Get the pet by its id", tool.Description)
+		}
+	}
+}
+
+func TestConvertSpecError(t *testing.T) {
+	_, err := convertSpec("petstore", []byte("not an openapi specification"), &apiv1.OpenApiSource{})
+	assert.Error(t, err)
+
+	_, err = convertSpec("petstore", []byte(petstoreSpec), &apiv1.OpenApiSource{
+		IncludeOperations: []string{"[pet"},
+	})
+	assert.Error(t, err)
+}
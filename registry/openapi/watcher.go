@@ -0,0 +1,514 @@
+// Copyright (c) 2022 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/alibaba/higress/v2/api/networking/v1"
+	"github.com/alibaba/higress/v2/pkg/common"
+	ingress "github.com/alibaba/higress/v2/pkg/ingress/kube/common"
+	"github.com/alibaba/higress/v2/pkg/ingress/kube/mcpserver"
+	"github.com/alibaba/higress/v2/pkg/kube"
+	provider "github.com/alibaba/higress/v2/registry"
+	"github.com/alibaba/higress/v2/registry/memory"
+)
+
+const (
+	DefaultRefreshInterval      = time.Second * 60
+	DefaultRefreshIntervalLimit = time.Second * 10
+	DefaultFetchTimeout         = time.Second * 10
+	// MaxSpecSize limits the size of the specification read from specUrl
+	MaxSpecSize = 16 << 20
+)
+
+var openapiLog = log.RegisterScope("OpenApi", "OpenAPI Mcp Server Watcher process.")
+
+type watcher struct {
+	provider.BaseWatcher
+	apiv1.RegistryConfig
+	cache      memory.Cache
+	client     kube.Client
+	httpClient *http.Client
+	mutex      sync.Mutex
+	stop       chan struct{}
+	isStop     bool
+	namespace  string
+	clusterId  string
+	// etag and specHash identify the last converted specification
+	etag     string
+	specHash string
+}
+
+type WatcherOption func(w *watcher)
+
+func NewWatcher(cache memory.Cache, opts ...WatcherOption) (provider.Watcher, error) {
+	w := &watcher{
+		cache:      cache,
+		httpClient: &http.Client{Timeout: DefaultFetchTimeout},
+		stop:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.OpenApiSource == nil || (w.OpenApiSource.SpecUrl == "" && w.OpenApiSource.SpecConfigMap == "") {
+		return nil, errors.New("specUrl or specConfigMap is required in openApiSource")
+	}
+	if w.OpenApiSource.SpecUrl == "" && w.OpenApiSource.SpecConfigMapKey == "" {
+		return nil, errors.New("specConfigMapKey is required when the specification is read from a configmap")
+	}
+	if w.Domain == "" || w.Port == 0 {
+		return nil, errors.New("domain and port of the api service are required")
+	}
+	if common.ParseProtocol(w.Protocol) == common.Unsupported {
+		return nil, fmt.Errorf("invalid protocol:%s", w.Protocol)
+	}
+	// validate the filters early, the specification is converted in the background
+	if err := validatePatterns(w.OpenApiSource.IncludeOperations); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns(w.OpenApiSource.ExcludeOperations); err != nil {
+		return nil, err
+	}
+	openapiLog.Infof("new openapi mcp server watcher with config Name:%s", w.Name)
+	return w, nil
+}
+
+func WithType(t string) WatcherOption {
+	return func(w *watcher) {
+		w.Type = t
+	}
+}
+
+func WithName(name string) WatcherOption {
+	return func(w *watcher) {
+		w.Name = name
+	}
+}
+
+func WithDomain(domain string) WatcherOption {
+	return func(w *watcher) {
+		w.Domain = domain
+	}
+}
+
+func WithPort(port uint32) WatcherOption {
+	return func(w *watcher) {
+		w.Port = port
+	}
+}
+
+func WithProtocol(protocol string) WatcherOption {
+	return func(w *watcher) {
+		w.Protocol = protocol
+		if w.Protocol == "" {
+			w.Protocol = string(common.HTTP)
+		}
+	}
+}
+
+func WithSNI(sni string) WatcherOption {
+	return func(w *watcher) {
+		w.Sni = sni
+	}
+}
+
+func WithMcpExportDomains(exportDomains []string) WatcherOption {
+	return func(w *watcher) {
+		w.McpServerExportDomains = exportDomains
+	}
+}
+
+func WithMcpBaseUrl(url string) WatcherOption {
+	return func(w *watcher) {
+		w.McpServerBaseUrl = url
+	}
+}
+
+func WithOpenApiSource(source *apiv1.OpenApiSource) WatcherOption {
+	return func(w *watcher) {
+		w.OpenApiSource = source
+	}
+}
+
+func WithNamespace(ns string) WatcherOption {
+	return func(w *watcher) {
+		w.namespace = ns
+	}
+}
+
+func WithClusterId(id string) WatcherOption {
+	return func(w *watcher) {
+		w.clusterId = id
+	}
+}
+
+func WithClient(client kube.Client) WatcherOption {
+	return func(w *watcher) {
+		w.client = client
+	}
+}
+
+func (w *watcher) Run() {
+	interval := time.Duration(w.OpenApiSource.RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	} else if interval < DefaultRefreshIntervalLimit {
+		interval = DefaultRefreshIntervalLimit
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.syncService()
+	if err := w.syncSpec(); err != nil {
+		openapiLog.Errorf("first sync of openapi specification failed, name:%s, err:%v", w.Name, err)
+	}
+	// the tools are retried on the next tick, do not block the other registries
+	w.Ready(true)
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.syncSpec(); err != nil {
+				openapiLog.Errorf("sync openapi specification failed, name:%s, err:%v", w.Name, err)
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+func (w *watcher) Stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.isStop {
+		return
+	}
+	w.isStop = true
+	close(w.stop)
+	w.cache.DeleteServiceWrapper(w.serviceHost())
+	w.cache.UpdateConfigCache(config.GroupVersionKind{}, w.dataId(), nil, true)
+	w.UpdateService()
+	w.Ready(false)
+}
+
+func (w *watcher) GetRegistryType() string {
+	return w.RegistryConfig.Type
+}
+
+func (w *watcher) serviceHost() string {
+	return strings.Join([]string{w.Name, w.Type}, common.DotSeparator)
+}
+
+func (w *watcher) dataId() string {
+	return strings.Join([]string{w.Type, w.Name}, "-")
+}
+
+// syncService registers the service the tools are forwarded to
+func (w *watcher) syncService() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	host := w.serviceHost()
+	serviceEntry := w.generateServiceEntry(host)
+	var destinationRuleWrapper *ingress.WrapperDestinationRule
+	if destinationRule := w.generateDestinationRule(serviceEntry); destinationRule != nil {
+		destinationRuleWrapper = &ingress.WrapperDestinationRule{
+			DestinationRule: destinationRule,
+			ServiceKey:      ingress.CreateMcpServiceKey(host, int32(w.Port)),
+		}
+	}
+	w.cache.UpdateServiceWrapper(host, &ingress.ServiceWrapper{
+		ServiceName:            w.Name,
+		ServiceEntry:           serviceEntry,
+		Suffix:                 w.Type,
+		RegistryType:           w.Type,
+		RegistryName:           w.Name,
+		DestinationRuleWrapper: destinationRuleWrapper,
+	})
+	w.UpdateService()
+}
+
+// syncSpec fetches the specification and regenerates the tools if it has changed
+func (w *watcher) syncSpec() error {
+	spec, etag, changed, err := w.fetchSpec()
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	hash := sha256.Sum256(spec)
+	specHash := hex.EncodeToString(hash[:])
+	if specHash == w.specHash {
+		w.etag = etag
+		return nil
+	}
+	// keep serving the previous tools if the new specification can not be converted
+	converted, err := convertSpec(w.Name, spec, w.OpenApiSource)
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.isStop {
+		return nil
+	}
+	dataId := w.dataId()
+	vs := w.buildVirtualService()
+	w.cache.UpdateConfigCache(gvk.VirtualService, dataId, vs, false)
+	w.cache.UpdateConfigCache(mcpserver.GvkMcpServer, dataId, w.buildMcpServer(), false)
+	w.cache.UpdateConfigCache(gvk.WasmPlugin, dataId, w.buildWasmPlugin(converted), false)
+	w.etag = etag
+	w.specHash = specHash
+	openapiLog.Infof("openapi specification of %s converted to %d tools", w.Name, len(converted.Tools))
+	w.UpdateService()
+	return nil
+}
+
+// fetchSpec returns the specification and its version, changed is false if the version is the same as the last one
+func (w *watcher) fetchSpec() ([]byte, string, bool, error) {
+	if w.OpenApiSource.SpecUrl != "" {
+		return w.fetchSpecFromUrl()
+	}
+	return w.fetchSpecFromConfigMap()
+}
+
+func (w *watcher) fetchSpecFromUrl() ([]byte, string, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, w.OpenApiSource.SpecUrl, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if w.etag != "" {
+		req.Header.Set("If-None-Match", w.etag)
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("fetch openapi specification from %s error: %v", w.OpenApiSource.SpecUrl, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, w.etag, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("fetch openapi specification from %s error: unexpected status code %d", w.OpenApiSource.SpecUrl, resp.StatusCode)
+	}
+	spec, err := io.ReadAll(io.LimitReader(resp.Body, MaxSpecSize+1))
+	if err != nil {
+		return nil, "", false, err
+	}
+	if len(spec) > MaxSpecSize {
+		return nil, "", false, fmt.Errorf("openapi specification from %s exceeds %d bytes", w.OpenApiSource.SpecUrl, MaxSpecSize)
+	}
+	return spec, resp.Header.Get("ETag"), true, nil
+}
+
+func (w *watcher) fetchSpecFromConfigMap() ([]byte, string, bool, error) {
+	if w.client == nil {
+		return nil, "", false, errors.New("kube client is not available to read the configmap")
+	}
+	cm, err := w.client.Kube().CoreV1().ConfigMaps(w.namespace).Get(context.Background(), w.OpenApiSource.SpecConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", false, fmt.Errorf("get configmap %s in namespace %s error: %v", w.OpenApiSource.SpecConfigMap, w.namespace, err)
+	}
+	if cm.ResourceVersion != "" && cm.ResourceVersion == w.etag {
+		return nil, w.etag, false, nil
+	}
+	key := w.OpenApiSource.SpecConfigMapKey
+	if data, ok := cm.Data[key]; ok {
+		return []byte(data), cm.ResourceVersion, true, nil
+	}
+	if data, ok := cm.BinaryData[key]; ok {
+		return data, cm.ResourceVersion, true, nil
+	}
+	return nil, "", false, fmt.Errorf("key %s not found in configmap %s", key, w.OpenApiSource.SpecConfigMap)
+}
+
+func (w *watcher) generateServiceEntry(host string) *v1alpha3.ServiceEntry {
+	protocol := string(common.ParseProtocol(w.Protocol))
+	endpoint := &v1alpha3.WorkloadEntry{
+		Address: w.Domain,
+	}
+	resolution := v1alpha3.ServiceEntry_DNS
+	if net.ParseIP(w.Domain) != nil {
+		resolution = v1alpha3.ServiceEntry_STATIC
+		endpoint.Ports = map[string]uint32{protocol: w.Port}
+	}
+	return &v1alpha3.ServiceEntry{
+		Hosts: []string{host},
+		Ports: []*v1alpha3.ServicePort{{
+			Number:   w.Port,
+			Name:     protocol,
+			Protocol: protocol,
+		}},
+		Location:   v1alpha3.ServiceEntry_MESH_INTERNAL,
+		Resolution: resolution,
+		Endpoints:  []*v1alpha3.WorkloadEntry{endpoint},
+	}
+}
+
+func (w *watcher) generateDestinationRule(se *v1alpha3.ServiceEntry) *v1alpha3.DestinationRule {
+	if !common.Protocol(se.Ports[0].Protocol).IsHTTPS() {
+		return nil
+	}
+	sni := w.Sni
+	if sni == "" && se.Resolution == v1alpha3.ServiceEntry_DNS {
+		sni = w.Domain
+	}
+	return &v1alpha3.DestinationRule{
+		Host: se.Hosts[0],
+		TrafficPolicy: &v1alpha3.TrafficPolicy{
+			PortLevelSettings: []*v1alpha3.TrafficPolicy_PortTrafficPolicy{
+				{
+					Port: &v1alpha3.PortSelector{
+						Number: se.Ports[0].Number,
+					},
+					Tls: &v1alpha3.ClientTLSSettings{
+						Mode: v1alpha3.ClientTLSSettings_SIMPLE,
+						Sni:  sni,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (w *watcher) routeName() string {
+	return fmt.Sprintf("%s-%s", provider.IstioMcpAutoGeneratedHttpRouteName, w.dataId())
+}
+
+// mcpPath is the path of the mcp server: /{base-path}/{registry-name}
+func (w *watcher) mcpPath() string {
+	mergePath := "/" + w.Name
+	if w.McpServerBaseUrl != "" && w.McpServerBaseUrl != "/" {
+		mergePath = strings.TrimSuffix(w.McpServerBaseUrl, "/") + mergePath
+	}
+	return mergePath
+}
+
+func (w *watcher) exportDomains() []string {
+	if len(w.McpServerExportDomains) == 0 {
+		return []string{"*"}
+	}
+	return w.McpServerExportDomains
+}
+
+func (w *watcher) buildVirtualService() *config.Config {
+	hosts := w.exportDomains()
+	var gateways []string
+	for _, host := range hosts {
+		cleanHost := ingress.CleanHost(host)
+		// namespace/name, name format: (istio cluster id)-host
+		gateways = append(gateways, w.namespace+"/"+
+			ingress.CreateConvertedName(w.clusterId, cleanHost),
+			ingress.CreateConvertedName(constants.IstioIngressGatewayName, cleanHost))
+	}
+	mergePath := w.mcpPath()
+	vs := &v1alpha3.VirtualService{
+		Hosts:    hosts,
+		Gateways: gateways,
+		Http: []*v1alpha3.HTTPRoute{{
+			Name: w.routeName(),
+			Match: []*v1alpha3.HTTPMatchRequest{
+				{
+					Uri: &v1alpha3.StringMatch{
+						MatchType: &v1alpha3.StringMatch_Exact{
+							Exact: mergePath,
+						},
+					},
+				},
+				{
+					Uri: &v1alpha3.StringMatch{
+						MatchType: &v1alpha3.StringMatch_Prefix{
+							Prefix: mergePath + "/",
+						},
+					},
+				},
+			},
+			Route: []*v1alpha3.HTTPRouteDestination{{
+				Destination: &v1alpha3.Destination{
+					Host: w.serviceHost(),
+				},
+			}},
+		}},
+	}
+	// we should rewrite host for dns service
+	if net.ParseIP(w.Domain) == nil {
+		vs.Http[0].Rewrite = &v1alpha3.HTTPRewrite{
+			Authority: w.Domain,
+		}
+	}
+	return &config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.VirtualService,
+			Name:             fmt.Sprintf("%s-%s", provider.IstioMcpAutoGeneratedVsName, w.dataId()),
+			Namespace:        w.namespace,
+		},
+		Spec: vs,
+	}
+}
+
+func (w *watcher) buildMcpServer() *config.Config {
+	name := fmt.Sprintf("%s-%s", provider.IstioMcpAutoGeneratedMcpServerName, w.dataId())
+	return &config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: mcpserver.GvkMcpServer,
+			Name:             name,
+			Namespace:        w.namespace,
+		},
+		Spec: &mcpserver.McpServer{
+			Name:           name,
+			Domains:        w.exportDomains(),
+			PathMatchType:  mcpserver.PrefixMatchType,
+			PathMatchValue: w.mcpPath(),
+			UpstreamType:   mcpserver.UpstreamTypeRest,
+		},
+	}
+}
+
+func (w *watcher) buildWasmPlugin(converted *convertedServer) *config.Config {
+	allowTools := make([]string, 0, len(converted.Tools))
+	for _, tool := range converted.Tools {
+		allowTools = append(allowTools, tool.Name)
+	}
+	return &config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.WasmPlugin,
+			Namespace:        w.namespace,
+		},
+		Spec: &provider.McpServerRule{
+			MatchRoute: []string{w.routeName()},
+			Server:     converted.Server,
+			Tools:      converted.Tools,
+			AllowTools: allowTools,
+		},
+	}
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/alibaba/higress/v2/registry/memory"
 	"github.com/alibaba/higress/v2/registry/nacos"
 	nacosv2 "github.com/alibaba/higress/v2/registry/nacos/v2"
+	"github.com/alibaba/higress/v2/registry/openapi"
 	"github.com/alibaba/higress/v2/registry/proxy"
 	"github.com/alibaba/higress/v2/registry/zookeeper"
 )
@@ -261,6 +262,22 @@ func (r *Reconciler) generateWatcherFromRegistryConfig(registry *apiv1.RegistryC
 			direct.WithSNI(registry.Sni),
 			direct.WithProxyName(registry.ProxyName),
 		)
+	case string(OpenAPI):
+		watcher, err = openapi.NewWatcher(
+			r.Cache,
+			openapi.WithType(registry.Type),
+			openapi.WithName(registry.Name),
+			openapi.WithDomain(registry.Domain),
+			openapi.WithPort(registry.Port),
+			openapi.WithProtocol(registry.Protocol),
+			openapi.WithSNI(registry.Sni),
+			openapi.WithMcpExportDomains(registry.McpServerExportDomains),
+			openapi.WithMcpBaseUrl(registry.McpServerBaseUrl),
+			openapi.WithOpenApiSource(registry.OpenApiSource),
+			openapi.WithClusterId(r.clusterId),
+			openapi.WithNamespace(r.namespace),
+			openapi.WithClient(r.client),
+		)
 	case string(Eureka):
 		watcher, err = eureka.NewWatcher(
 			r.Cache,
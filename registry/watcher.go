@@ -33,6 +33,7 @@ const (
 	Nacos3    ServiceRegistryType = "nacos3"
 	Static    ServiceRegistryType = "static"
 	DNS       ServiceRegistryType = "dns"
+	OpenAPI   ServiceRegistryType = "openapi"
 	Healthy   WatcherStatus       = "healthy"
 	UnHealthy WatcherStatus       = "unhealthy"
 